@@ -0,0 +1,173 @@
+// Package alias maps external references such as "user:123:EUR" to account ids.
+//
+// In hash mode the id is derived from the alias, nothing is stored.
+// In store mode aliases are registered in an embedded bbolt database
+// and point to any id, registering an alias without an id generates one.
+//
+// An account id field holds an alias only with Prefix, such as "alias:user:123:EUR",
+// so an alias that reads as hex is never taken for an id.
+package alias
+
+import (
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/tigerbeetle/tigerbeetle-go/pkg/types"
+	bolt "go.etcd.io/bbolt"
+)
+
+type Mode string
+
+const (
+	ModeHash  Mode = "hash"
+	ModeStore Mode = "store"
+)
+
+// Prefix marks an alias in an account id field.
+const Prefix = "alias:"
+
+// storeTimeout bounds the wait for the file lock of a store opened by another process.
+const storeTimeout = 5 * time.Second
+
+var (
+	ErrEmptyAlias   = errors.New("alias is empty")
+	ErrUnknownAlias = errors.New("unknown alias")
+	ErrConflict     = errors.New("alias is registered to a different id")
+	ErrUnknownMode  = errors.New("alias mode must be hash or store")
+)
+
+type Resolver interface {
+	// Resolve returns the id of alias or ErrUnknownAlias.
+	Resolve(alias string) (types.Uint128, error)
+	// Register maps alias to id and returns the id,
+	// a zero id derives or generates one. Registering the same pair again succeeds.
+	Register(alias string, id types.Uint128) (types.Uint128, error)
+	Close() error
+}
+
+// Cut returns the alias of an account id field, ok is false for an id without Prefix.
+func Cut(s string) (alias string, ok bool) {
+	return strings.CutPrefix(s, Prefix)
+}
+
+func validate(alias string) error {
+	if alias == "" {
+		return ErrEmptyAlias
+	}
+	return nil
+}
+
+// New opens the resolver of mode, path is the database file in store mode
+// and namespace seeds the derived ids in hash mode.
+func New(mode Mode, path string, namespace string) (Resolver, error) {
+	switch mode {
+	case ModeHash:
+		return &hashResolver{namespace: namespace}, nil
+	case ModeStore:
+		return openStore(path)
+	}
+	return nil, ErrUnknownMode
+}
+
+type hashResolver struct {
+	namespace string
+}
+
+func (r *hashResolver) id(alias string) types.Uint128 {
+	h := sha256.New()
+	h.Write([]byte(r.namespace))
+	h.Write([]byte("\x00alias\x00"))
+	h.Write([]byte(alias))
+	return types.BytesToUint128([16]byte(h.Sum(nil)[:16]))
+}
+
+func (r *hashResolver) Resolve(alias string) (types.Uint128, error) {
+	if err := validate(alias); err != nil {
+		return types.Uint128{}, err
+	}
+	return r.id(alias), nil
+}
+
+func (r *hashResolver) Register(alias string, id types.Uint128) (types.Uint128, error) {
+	derived, err := r.Resolve(alias)
+	if err != nil {
+		return types.Uint128{}, err
+	}
+	if id != (types.Uint128{}) && id != derived {
+		return types.Uint128{}, ErrConflict
+	}
+	return derived, nil
+}
+
+func (r *hashResolver) Close() error { return nil }
+
+var bucket = []byte("aliases")
+
+type storeResolver struct {
+	db *bolt.DB
+}
+
+func openStore(path string) (*storeResolver, error) {
+	if path == "" {
+		return nil, errors.New("alias store path is not set")
+	}
+	db, err := bolt.Open(path, 0o600, &bolt.Options{Timeout: storeTimeout})
+	if err != nil {
+		return nil, fmt.Errorf("opening alias store: %w", err)
+	}
+	err = db.Update(func(tx *bolt.Tx) error {
+		_, err := tx.CreateBucketIfNotExists(bucket)
+		return err
+	})
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+	return &storeResolver{db: db}, nil
+}
+
+func (r *storeResolver) Resolve(alias string) (types.Uint128, error) {
+	if err := validate(alias); err != nil {
+		return types.Uint128{}, err
+	}
+	var id types.Uint128
+	err := r.db.View(func(tx *bolt.Tx) error {
+		v := tx.Bucket(bucket).Get([]byte(alias))
+		if v == nil {
+			return ErrUnknownAlias
+		}
+		id = types.BytesToUint128([16]byte(v))
+		return nil
+	})
+	return id, err
+}
+
+func (r *storeResolver) Register(alias string, id types.Uint128) (types.Uint128, error) {
+	if err := validate(alias); err != nil {
+		return types.Uint128{}, err
+	}
+	err := r.db.Update(func(tx *bolt.Tx) error {
+		b := tx.Bucket(bucket)
+		if v := b.Get([]byte(alias)); v != nil {
+			existing := types.BytesToUint128([16]byte(v))
+			if id != (types.Uint128{}) && id != existing {
+				return ErrConflict
+			}
+			id = existing
+			return nil
+		}
+		if id == (types.Uint128{}) {
+			id = types.ID()
+		}
+		return b.Put([]byte(alias), id[:])
+	})
+	if err != nil {
+		return types.Uint128{}, err
+	}
+	return id, nil
+}
+
+func (r *storeResolver) Close() error { return r.db.Close() }
@@ -0,0 +1,77 @@
+package alias
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tigerbeetle/tigerbeetle-go/pkg/types"
+)
+
+func TestHash(t *testing.T) {
+	r, err := New(ModeHash, "", "a")
+	require.NoError(t, err)
+
+	id, err := r.Resolve("user:123:EUR")
+	require.NoError(t, err)
+	again, err := r.Register("user:123:EUR", types.Uint128{})
+	require.NoError(t, err)
+	assert.Equal(t, id, again)
+
+	_, err = r.Register("user:123:EUR", types.ToUint128(1))
+	assert.ErrorIs(t, err, ErrConflict)
+
+	other, _ := New(ModeHash, "", "b")
+	otherID, err := other.Resolve("user:123:EUR")
+	require.NoError(t, err)
+	assert.NotEqual(t, id, otherID)
+}
+
+func TestStore(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "aliases.db")
+	r, err := New(ModeStore, path, "")
+	require.NoError(t, err)
+
+	_, err = r.Resolve("user:1")
+	assert.ErrorIs(t, err, ErrUnknownAlias)
+
+	id, err := r.Register("user:1", types.Uint128{})
+	require.NoError(t, err)
+	assert.NotEqual(t, types.Uint128{}, id)
+
+	_, err = r.Register("user:2", types.ToUint128(7))
+	require.NoError(t, err)
+	_, err = r.Register("user:2", types.ToUint128(8))
+	assert.ErrorIs(t, err, ErrConflict)
+	same, err := r.Register("user:2", types.ToUint128(7))
+	require.NoError(t, err)
+	assert.Equal(t, types.ToUint128(7), same)
+	require.NoError(t, r.Close())
+
+	// Aliases are kept after reopening the store
+	r, err = New(ModeStore, path, "")
+	require.NoError(t, err)
+	defer r.Close()
+	resolved, err := r.Resolve("user:1")
+	require.NoError(t, err)
+	assert.Equal(t, id, resolved)
+}
+
+func TestValidate(t *testing.T) {
+	r, _ := New(ModeHash, "", "")
+	// An alias that reads as hex is still an alias
+	_, err := r.Register("cafe", types.Uint128{})
+	assert.NoError(t, err)
+	_, err = r.Resolve("")
+	assert.ErrorIs(t, err, ErrEmptyAlias)
+
+	a, ok := Cut("alias:cafe")
+	assert.True(t, ok)
+	assert.Equal(t, "cafe", a)
+	_, ok = Cut("cafe")
+	assert.False(t, ok)
+
+	_, err = New("file", "", "")
+	assert.ErrorIs(t, err, ErrUnknownMode)
+}
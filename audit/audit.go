@@ -0,0 +1,267 @@
+// Package audit writes an append-only, hash-chained log of write requests.
+//
+// Entries are json lines in files audit-000001.log, audit-000002.log, ... of a directory,
+// a new file is started once the current one reaches its max size.
+// Each entry holds the hash of the entry before it, across files, and its own hash over
+// the previous hash and the entry itself, so a changed, removed or reordered entry breaks the chain.
+package audit
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync"
+	"time"
+)
+
+const filePattern = "audit-*.log"
+
+// DefaultMaxSize is the size a file is rotated at when none is given.
+const DefaultMaxSize = 100 << 20
+
+type Entry struct {
+	Seq  uint64    `json:"seq"`
+	Time time.Time `json:"time"`
+	// Key is the name of the api key of the caller
+	Key string `json:"key,omitempty"`
+	// ClientCert is the common name of the client certificate of the caller
+	ClientCert string          `json:"client_cert,omitempty"`
+	IP         string          `json:"ip,omitempty"`
+	Method     string          `json:"method"`
+	Request    json.RawMessage `json:"request,omitempty"`
+	// RequestSHA256 is the hash of the request body as read, also for bodies not kept in Request such as imports
+	RequestSHA256  string          `json:"request_sha256,omitempty"`
+	IdempotencyKey string          `json:"idempotency_key,omitempty"`
+	Response       json.RawMessage `json:"response,omitempty"`
+	// Status is the grpc code or http status of the reply
+	Status    string `json:"status"`
+	Error     string `json:"error,omitempty"`
+	LatencyNs int64  `json:"latency_ns"`
+	Prev      string `json:"prev"`
+	Hash      string `json:"hash,omitempty"`
+}
+
+// hash returns the hash of e, which covers every field but Hash.
+func (e Entry) hash() (string, error) {
+	e.Hash = ""
+	b, err := json.Marshal(e)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(b)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// RawJSON returns b when it is json, otherwise b as a json string.
+func RawJSON(b []byte) json.RawMessage {
+	if len(b) == 0 {
+		return nil
+	}
+	if json.Valid(b) {
+		return b
+	}
+	s, _ := json.Marshal(string(b))
+	return s
+}
+
+type Log struct {
+	dir     string
+	maxSize int64
+
+	mu    sync.Mutex
+	file  *os.File
+	index int
+	size  int64
+	seq   uint64
+	prev  string
+}
+
+// Open continues the chain of the last file in dir, creating dir when needed.
+func Open(dir string, maxSize int64) (*Log, error) {
+	if maxSize <= 0 {
+		maxSize = DefaultMaxSize
+	}
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, err
+	}
+	files, err := files(dir)
+	if err != nil {
+		return nil, err
+	}
+	l := &Log{dir: dir, maxSize: maxSize}
+	if len(files) == 0 {
+		return l, l.rotate()
+	}
+	last := files[len(files)-1]
+	if _, err := fmt.Sscanf(filepath.Base(last), "audit-%06d.log", &l.index); err != nil {
+		return nil, fmt.Errorf("audit: %s: %w", last, err)
+	}
+	// A file is empty when the process stopped right after a rotation, the chain is then in the one before.
+	for i := len(files) - 1; i >= 0 && l.prev == ""; i-- {
+		size, err := l.readTail(files[i])
+		if err != nil {
+			return nil, err
+		}
+		if i == len(files)-1 {
+			l.size = size
+		}
+	}
+	l.file, err = os.OpenFile(last, os.O_WRONLY|os.O_APPEND, 0o600)
+	if err != nil {
+		return nil, err
+	}
+	return l, nil
+}
+
+// readTail sets the seq and hash of the last entry of path and returns the size of path.
+func (l *Log) readTail(path string) (int64, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return 0, err
+	}
+	lines := bytes.Split(bytes.TrimRight(b, "\n"), []byte("\n"))
+	if len(lines[len(lines)-1]) == 0 {
+		return int64(len(b)), nil
+	}
+	var e Entry
+	if err := json.Unmarshal(lines[len(lines)-1], &e); err != nil {
+		return 0, fmt.Errorf("audit: %s: last entry: %w", path, err)
+	}
+	l.seq, l.prev = e.Seq, e.Hash
+	return int64(len(b)), nil
+}
+
+func (l *Log) rotate() error {
+	if l.file != nil {
+		l.file.Close()
+		// Closed files are only read from now on.
+		os.Chmod(l.file.Name(), 0o400)
+	}
+	l.index++
+	f, err := os.OpenFile(filepath.Join(l.dir, fmt.Sprintf("audit-%06d.log", l.index)), os.O_WRONLY|os.O_APPEND|os.O_CREATE|os.O_EXCL, 0o600)
+	if err != nil {
+		return err
+	}
+	l.file, l.size = f, 0
+	return nil
+}
+
+// Append chains e to the log and writes it to disk before returning.
+func (l *Log) Append(e Entry) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.size >= l.maxSize {
+		if err := l.rotate(); err != nil {
+			return err
+		}
+	}
+	e.Seq = l.seq + 1
+	e.Prev = l.prev
+	hash, err := e.hash()
+	if err != nil {
+		return err
+	}
+	e.Hash = hash
+	b, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+	b = append(b, '\n')
+	if _, err := l.file.Write(b); err != nil {
+		return err
+	}
+	if err := l.file.Sync(); err != nil {
+		return err
+	}
+	l.seq, l.prev = e.Seq, e.Hash
+	l.size += int64(len(b))
+	return nil
+}
+
+func (l *Log) Close() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.file.Close()
+}
+
+func files(dir string) ([]string, error) {
+	files, err := filepath.Glob(filepath.Join(dir, filePattern))
+	if err != nil {
+		return nil, err
+	}
+	slices.Sort(files)
+	return files, nil
+}
+
+// Report is the result of Verify.
+type Report struct {
+	Files   int
+	Entries uint64
+	// FirstSeq is above 1 when older files were removed
+	FirstSeq uint64
+	LastHash string
+}
+
+var ErrBrokenChain = errors.New("audit chain is broken")
+
+// Verify checks the hash chain of every file in dir.
+func Verify(dir string) (*Report, error) {
+	files, err := files(dir)
+	if err != nil {
+		return nil, err
+	}
+	r := &Report{Files: len(files)}
+	var seq uint64
+	prev := ""
+	for _, path := range files {
+		f, err := os.Open(path)
+		if err != nil {
+			return r, err
+		}
+		scanner := bufio.NewScanner(f)
+		scanner.Buffer(nil, 64<<20)
+		for line := 1; scanner.Scan(); line++ {
+			var e Entry
+			if err := json.Unmarshal(scanner.Bytes(), &e); err != nil {
+				f.Close()
+				return r, fmt.Errorf("%w: %s:%d: %v", ErrBrokenChain, path, line, err)
+			}
+			if r.Entries == 0 {
+				// The chain may start after removed files.
+				r.FirstSeq, seq, prev = e.Seq, e.Seq-1, e.Prev
+			}
+			hash, err := e.hash()
+			if err != nil {
+				f.Close()
+				return r, err
+			}
+			switch {
+			case e.Seq != seq+1:
+				err = fmt.Errorf("seq %d follows %d", e.Seq, seq)
+			case e.Prev != prev:
+				err = fmt.Errorf("seq %d does not chain to the entry before it", e.Seq)
+			case e.Hash != hash:
+				err = fmt.Errorf("seq %d does not match its hash", e.Seq)
+			}
+			if err != nil {
+				f.Close()
+				return r, fmt.Errorf("%w: %s:%d: %v", ErrBrokenChain, path, line, err)
+			}
+			seq, prev = e.Seq, e.Hash
+			r.Entries++
+		}
+		f.Close()
+		if err := scanner.Err(); err != nil {
+			return r, err
+		}
+	}
+	r.LastHash = prev
+	return r, nil
+}
@@ -0,0 +1,100 @@
+package audit
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func write(t *testing.T, dir string, maxSize int64, n int) {
+	l, err := Open(dir, maxSize)
+	require.NoError(t, err)
+	for i := 0; i < n; i++ {
+		require.NoError(t, l.Append(Entry{
+			Key:      "backend",
+			Method:   "/tigerbeetle.TigerBeetle/CreateTransfers",
+			Request:  RawJSON([]byte(`{"transfers":[{"id":"1","amount":"10"}]}`)),
+			Response: RawJSON([]byte(`{"results":[]}`)),
+			Status:   "OK",
+		}))
+	}
+	require.NoError(t, l.Close())
+}
+
+func TestChain(t *testing.T) {
+	dir := t.TempDir()
+	write(t, dir, 600, 3)
+	// Reopening continues the chain of the last file.
+	write(t, dir, 600, 3)
+
+	files, err := files(dir)
+	require.NoError(t, err)
+	assert.Greater(t, len(files), 1, "should rotate")
+	r, err := Verify(dir)
+	require.NoError(t, err)
+	assert.EqualValues(t, 6, r.Entries)
+	assert.EqualValues(t, 1, r.FirstSeq)
+	assert.Len(t, r.LastHash, 64)
+
+	t.Run("should detect a changed entry", func(t *testing.T) {
+		tampered := t.TempDir()
+		copyDir(t, dir, tampered)
+		path := filepath.Join(tampered, "audit-000001.log")
+		b, err := os.ReadFile(path)
+		require.NoError(t, err)
+		require.NoError(t, os.WriteFile(path, []byte(strings.Replace(string(b), `"amount":"10"`, `"amount":"99"`, 1)), 0o600))
+		_, err = Verify(tampered)
+		assert.ErrorIs(t, err, ErrBrokenChain)
+		assert.ErrorContains(t, err, "audit-000001.log:1")
+	})
+
+	t.Run("should detect a removed entry", func(t *testing.T) {
+		tampered := t.TempDir()
+		copyDir(t, dir, tampered)
+		path := filepath.Join(tampered, "audit-000001.log")
+		b, err := os.ReadFile(path)
+		require.NoError(t, err)
+		lines := strings.SplitAfter(string(b), "\n")
+		require.NoError(t, os.WriteFile(path, []byte(lines[0]+strings.Join(lines[2:], "")), 0o600))
+		_, err = Verify(tampered)
+		assert.ErrorIs(t, err, ErrBrokenChain)
+	})
+
+	t.Run("should accept a chain without its oldest files", func(t *testing.T) {
+		trimmed := t.TempDir()
+		copyDir(t, dir, trimmed)
+		require.NoError(t, os.Remove(filepath.Join(trimmed, "audit-000001.log")))
+		r, err := Verify(trimmed)
+		require.NoError(t, err)
+		assert.Greater(t, r.FirstSeq, uint64(1))
+	})
+}
+
+func copyDir(t *testing.T, from, to string) {
+	files, err := files(from)
+	require.NoError(t, err)
+	for _, f := range files {
+		b, err := os.ReadFile(f)
+		require.NoError(t, err)
+		require.NoError(t, os.WriteFile(filepath.Join(to, filepath.Base(f)), b, 0o600))
+	}
+}
+
+func TestRawJSON(t *testing.T) {
+	assert.Equal(t, `{"a":1}`, string(RawJSON([]byte(`{"a":1}`))))
+	assert.Equal(t, `"bad request"`, string(RawJSON([]byte("bad request"))))
+	assert.Nil(t, RawJSON(nil))
+}
+
+func TestOpenAfterRotation(t *testing.T) {
+	dir := t.TempDir()
+	write(t, dir, 600, 2)
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "audit-000099.log"), nil, 0o600))
+	write(t, dir, 600, 1)
+	_, err := Verify(dir)
+	assert.NoError(t, err)
+}
@@ -0,0 +1,213 @@
+// Package auth checks api keys and the scopes they carry.
+//
+// Keys are stored as the hex sha256 of the key, in API_KEYS_FILE
+//
+//	keys:
+//	  - name: backend
+//	    hash: 9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08
+//	    scopes: [read, write-transfers]
+//
+// or in API_KEYS as name:hash:scope+scope, separated by commas.
+// Clients send the key as "Authorization: Bearer <key>", in a header or grpc metadata.
+//
+// A key with a client is also used for requests without a bearer token over a mutual TLS connection
+// whose client certificate has that common name, such a key needs no hash.
+//
+// Bearer tokens that are a JWT are checked by a Verifier instead, their scope claim holds the scopes
+// and their ledgers, codes and accounts claims restrict what the caller may touch.
+package auth
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/samber/lo"
+	"gopkg.in/yaml.v3"
+)
+
+type Scope string
+
+const (
+	ScopeRead           Scope = "read"
+	ScopeWriteAccounts  Scope = "write-accounts"
+	ScopeWriteTransfers Scope = "write-transfers"
+	// ScopeAdmin allows every other scope
+	ScopeAdmin Scope = "admin"
+)
+
+var scopes = []Scope{ScopeRead, ScopeWriteAccounts, ScopeWriteTransfers, ScopeAdmin}
+
+var (
+	ErrMissingKey   = errors.New("missing api key")
+	ErrInvalidKey   = errors.New("invalid api key")
+	ErrUnknownScope = errors.New("unknown scope")
+)
+
+type Key struct {
+	Name   string  `yaml:"name"`
+	Hash   string  `yaml:"hash"`
+	Scopes []Scope `yaml:"scopes"`
+	// Client is the common name of a client certificate that authenticates as this key
+	Client string `yaml:"client"`
+	// Restriction is set from the claims of a token, api keys are unrestricted
+	Restriction *Restriction `yaml:"-"`
+
+	hash []byte
+}
+
+// Allows reports whether the key carries scope, admin carries every scope.
+func (k *Key) Allows(scope Scope) bool {
+	return slices.Contains(k.Scopes, scope) || slices.Contains(k.Scopes, ScopeAdmin)
+}
+
+type Keys struct {
+	Keys []Key `yaml:"keys"`
+	// JWT verifies bearer tokens that are a JWT, nil when tokens are not accepted
+	JWT *Verifier `yaml:"-"`
+}
+
+// New loads the keys of path and env, nil when both are empty and auth is disabled.
+func New(path, env string) (*Keys, error) {
+	if path == "" && env == "" {
+		return nil, nil
+	}
+	keys := &Keys{}
+	if path != "" {
+		b, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		if err := yaml.Unmarshal(b, keys); err != nil {
+			return nil, fmt.Errorf("parsing api keys: %w", err)
+		}
+	}
+	if env != "" {
+		for _, entry := range strings.Split(env, ",") {
+			parts := strings.Split(strings.TrimSpace(entry), ":")
+			if len(parts) != 3 {
+				return nil, fmt.Errorf("api keys: %q is not name:hash:scopes", entry)
+			}
+			keys.Keys = append(keys.Keys, Key{
+				Name:   parts[0],
+				Hash:   parts[1],
+				Scopes: lo.Map(strings.Split(parts[2], "+"), func(s string, _ int) Scope { return Scope(s) }),
+			})
+		}
+	}
+	if err := keys.init(); err != nil {
+		return nil, err
+	}
+	return keys, nil
+}
+
+func (ks *Keys) init() error {
+	names := map[string]bool{}
+	clients := map[string]bool{}
+	for i := range ks.Keys {
+		k := &ks.Keys[i]
+		if k.Name == "" {
+			return fmt.Errorf("api keys[%d]: name is required", i)
+		}
+		if names[k.Name] {
+			return fmt.Errorf("api keys: duplicate name %q", k.Name)
+		}
+		names[k.Name] = true
+		if k.Client != "" {
+			if clients[k.Client] {
+				return fmt.Errorf("api keys: duplicate client %q", k.Client)
+			}
+			clients[k.Client] = true
+		}
+		if k.Hash != "" || k.Client == "" {
+			hash, err := hex.DecodeString(k.Hash)
+			if err != nil || len(hash) != sha256.Size {
+				return fmt.Errorf("api keys: %s: hash must be a hex sha256", k.Name)
+			}
+			k.hash = hash
+		}
+		for _, scope := range k.Scopes {
+			if !slices.Contains(scopes, scope) {
+				return fmt.Errorf("api keys: %s: %w %q", k.Name, ErrUnknownScope, scope)
+			}
+		}
+	}
+	return nil
+}
+
+// Authenticate returns the key of an Authorization value, an api key or a JWT.
+func (ks *Keys) Authenticate(authorization string) (*Key, error) {
+	token, ok := strings.CutPrefix(authorization, "Bearer ")
+	if !ok || token == "" {
+		return nil, ErrMissingKey
+	}
+	if ks.JWT != nil && strings.Count(token, ".") == 2 {
+		key, err := ks.JWT.Verify(token)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidKey, err)
+		}
+		return key, nil
+	}
+	hash := sha256.Sum256([]byte(token))
+	for i := range ks.Keys {
+		if subtle.ConstantTimeCompare(ks.Keys[i].hash, hash[:]) == 1 {
+			return &ks.Keys[i], nil
+		}
+	}
+	return nil, ErrInvalidKey
+}
+
+// AuthenticateClient returns the key of the common name of a verified client certificate.
+func (ks *Keys) AuthenticateClient(identity string) (*Key, error) {
+	if identity == "" {
+		return nil, ErrMissingKey
+	}
+	for i := range ks.Keys {
+		if ks.Keys[i].Client == identity {
+			return &ks.Keys[i], nil
+		}
+	}
+	return nil, ErrInvalidKey
+}
+
+// AuthenticateRequest returns the key of the Authorization value of a request,
+// or of its client certificate when it has no Authorization.
+func (ks *Keys) AuthenticateRequest(authorization, identity string) (*Key, error) {
+	if authorization == "" && identity != "" {
+		return ks.AuthenticateClient(identity)
+	}
+	return ks.Authenticate(authorization)
+}
+
+// Hash returns the hex sha256 of a key, as it is stored.
+func Hash(key string) string {
+	hash := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(hash[:])
+}
+
+// Generate returns a new random key.
+func Generate() string {
+	b := make([]byte, 32)
+	rand.Read(b)
+	return "tba_" + hex.EncodeToString(b)
+}
+
+type keyContext struct{}
+
+// WithKey returns ctx carrying the authenticated key.
+func WithKey(ctx context.Context, k *Key) context.Context {
+	return context.WithValue(ctx, keyContext{}, k)
+}
+
+// FromContext returns the authenticated key of ctx, nil without auth.
+func FromContext(ctx context.Context) *Key {
+	k, _ := ctx.Value(keyContext{}).(*Key)
+	return k
+}
@@ -0,0 +1,77 @@
+package auth
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAuthenticate(t *testing.T) {
+	keys, err := New("", "backend:"+Hash("secret")+":read+write-transfers, ops:"+Hash("root")+":admin")
+	require.NoError(t, err)
+
+	key, err := keys.Authenticate("Bearer secret")
+	require.NoError(t, err)
+	assert.Equal(t, "backend", key.Name)
+	assert.True(t, key.Allows(ScopeWriteTransfers))
+	assert.False(t, key.Allows(ScopeWriteAccounts))
+
+	key, err = keys.Authenticate("Bearer root")
+	require.NoError(t, err)
+	assert.True(t, key.Allows(ScopeWriteAccounts))
+
+	_, err = keys.Authenticate("secret")
+	assert.ErrorIs(t, err, ErrMissingKey)
+	_, err = keys.Authenticate("Bearer wrong")
+	assert.ErrorIs(t, err, ErrInvalidKey)
+}
+
+func TestNew(t *testing.T) {
+	keys, err := New("", "")
+	assert.NoError(t, err)
+	assert.Nil(t, keys)
+
+	_, err = New("", "a:"+Hash("x")+":write")
+	assert.ErrorIs(t, err, ErrUnknownScope)
+	_, err = New("", "a:abc:read")
+	assert.ErrorContains(t, err, "sha256")
+	_, err = New("", "a:"+Hash("x")+":read,a:"+Hash("y")+":read")
+	assert.ErrorContains(t, err, "duplicate")
+
+	keys, err = New("../api_keys.example.yaml", "")
+	require.NoError(t, err)
+	assert.Len(t, keys.Keys, 4)
+}
+
+func TestContext(t *testing.T) {
+	assert.Nil(t, FromContext(context.Background()))
+	k := &Key{Name: "a"}
+	assert.Equal(t, k, FromContext(WithKey(context.Background(), k)))
+}
+
+func TestAuthenticateClient(t *testing.T) {
+	keys := &Keys{Keys: []Key{
+		{Name: "backend", Client: "backend.internal", Scopes: []Scope{ScopeRead}},
+		{Name: "ops", Hash: Hash("root"), Client: "ops.internal", Scopes: []Scope{ScopeAdmin}},
+	}}
+	require.NoError(t, keys.init())
+
+	key, err := keys.AuthenticateRequest("", "backend.internal")
+	require.NoError(t, err)
+	assert.Equal(t, "backend", key.Name)
+
+	// The bearer token wins over the client certificate.
+	key, err = keys.AuthenticateRequest("Bearer root", "backend.internal")
+	require.NoError(t, err)
+	assert.Equal(t, "ops", key.Name)
+
+	_, err = keys.AuthenticateRequest("", "unknown.internal")
+	assert.ErrorIs(t, err, ErrInvalidKey)
+	_, err = keys.AuthenticateRequest("", "")
+	assert.ErrorIs(t, err, ErrMissingKey)
+	// Keys without a hash are never matched by a bearer token.
+	_, err = keys.Authenticate("Bearer ")
+	assert.ErrorIs(t, err, ErrMissingKey)
+}
@@ -0,0 +1,280 @@
+package auth
+
+import (
+	"crypto/ecdh"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"math/big"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/samber/lo"
+	"github.com/tigerbeetle/tigerbeetle-go/pkg/types"
+)
+
+var ErrUnknownKeyID = errors.New("unknown key id")
+
+// jwksMinRefresh is how long an unknown key id waits before the jwks url is fetched again.
+const jwksMinRefresh = 10 * time.Second
+
+type JWTOptions struct {
+	// JWKSFile or JWKSURL holds the public keys tokens are signed with
+	JWKSFile string
+	JWKSURL  string
+	// CacheTTL is how long the keys of JWKSURL are used before they are fetched again
+	CacheTTL time.Duration
+	// Issuer and Audience are checked when set
+	Issuer   string
+	Audience string
+}
+
+// Verifier checks RS256, ES256 and EdDSA bearer tokens against a jwks.
+type Verifier struct {
+	opts   JWTOptions
+	client *http.Client
+
+	// mu guards keys and fetched, it is never held during a fetch.
+	mu      sync.RWMutex
+	keys    map[string]any
+	fetched time.Time
+	// refreshing is closed when the running fetch is done, nil without one.
+	refreshing chan struct{}
+}
+
+// NewVerifier loads the jwks of opts, a url is fetched once to fail early.
+func NewVerifier(opts JWTOptions) (*Verifier, error) {
+	v := &Verifier{opts: opts, client: &http.Client{Timeout: 10 * time.Second}}
+	var b []byte
+	var err error
+	if opts.JWKSFile != "" {
+		b, err = os.ReadFile(opts.JWKSFile)
+	} else {
+		b, err = v.fetch()
+	}
+	if err != nil {
+		return nil, err
+	}
+	if v.keys, err = ParseJWKS(b); err != nil {
+		return nil, err
+	}
+	v.fetched = time.Now()
+	return v, nil
+}
+
+func (v *Verifier) fetch() ([]byte, error) {
+	res, err := v.client.Get(v.opts.JWKSURL)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("jwks: %s returned %s", v.opts.JWKSURL, res.Status)
+	}
+	return io.ReadAll(res.Body)
+}
+
+// key returns the public key of kid, the jwks url is fetched again once the cache expires
+// or, at most every jwksMinRefresh, for an unknown kid.
+// A known kid is served from the cache while the fetch runs, an unknown kid waits for it.
+func (v *Verifier) key(kid string) (any, error) {
+	v.mu.RLock()
+	keys, age := v.keys, time.Since(v.fetched)
+	v.mu.RUnlock()
+	if v.opts.JWKSURL != "" && v.opts.JWKSFile == "" {
+		_, known := keys[kid]
+		if age > v.opts.CacheTTL || (!known && age > jwksMinRefresh) {
+			done := v.refresh()
+			if !known {
+				<-done
+				v.mu.RLock()
+				keys = v.keys
+				v.mu.RUnlock()
+			}
+		}
+	}
+	if kid == "" && len(keys) == 1 {
+		return lo.Values(keys)[0], nil
+	}
+	k, ok := keys[kid]
+	if !ok {
+		return nil, fmt.Errorf("%w %q", ErrUnknownKeyID, kid)
+	}
+	return k, nil
+}
+
+// refresh fetches the jwks url without holding mu, callers during a fetch share it.
+// The returned channel is closed once the fetch is done.
+func (v *Verifier) refresh() <-chan struct{} {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	if v.refreshing != nil {
+		return v.refreshing
+	}
+	done := make(chan struct{})
+	v.refreshing = done
+	go func() {
+		defer close(done)
+		keys, err := v.fetchKeys()
+		v.mu.Lock()
+		defer v.mu.Unlock()
+		if err != nil {
+			// Keep the cached keys while the url is down.
+			slog.Warn("unable to refresh jwks", "url", v.opts.JWKSURL, "err", err)
+		} else {
+			v.keys = keys
+		}
+		v.fetched = time.Now()
+		v.refreshing = nil
+	}()
+	return done
+}
+
+func (v *Verifier) fetchKeys() (map[string]any, error) {
+	b, err := v.fetch()
+	if err != nil {
+		return nil, err
+	}
+	return ParseJWKS(b)
+}
+
+// Claims are the claims of a token, ledgers, codes and accounts restrict what the caller may touch.
+type Claims struct {
+	jwt.RegisteredClaims
+	// Scope holds the scopes separated by spaces, as in OAuth 2.0
+	Scope    string         `json:"scope"`
+	Ledgers  []uint32       `json:"ledgers,omitempty"`
+	Codes    []uint16       `json:"codes,omitempty"`
+	Accounts []AccountClaim `json:"accounts,omitempty"`
+}
+
+// AccountClaim is an inclusive range of hex account ids.
+type AccountClaim struct {
+	Min string `json:"min"`
+	Max string `json:"max"`
+}
+
+// Verify checks the signature and claims of token and returns it as a key named after its subject.
+func (v *Verifier) Verify(token string) (*Key, error) {
+	claims := &Claims{}
+	opts := []jwt.ParserOption{
+		jwt.WithValidMethods([]string{"RS256", "ES256", "EdDSA"}),
+		jwt.WithExpirationRequired(),
+	}
+	if v.opts.Issuer != "" {
+		opts = append(opts, jwt.WithIssuer(v.opts.Issuer))
+	}
+	if v.opts.Audience != "" {
+		opts = append(opts, jwt.WithAudience(v.opts.Audience))
+	}
+	_, err := jwt.ParseWithClaims(token, claims, func(t *jwt.Token) (any, error) {
+		kid, _ := t.Header["kid"].(string)
+		return v.key(kid)
+	}, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	key := &Key{
+		Name:        "jwt:" + claims.Subject,
+		Scopes:      lo.Map(strings.Fields(claims.Scope), func(s string, _ int) Scope { return Scope(s) }),
+		Restriction: &Restriction{Ledgers: claims.Ledgers, Codes: claims.Codes},
+	}
+	for i, a := range claims.Accounts {
+		first, err := types.HexStringToUint128(a.Min)
+		if err != nil {
+			return nil, fmt.Errorf("accounts[%d].min: %w", i, err)
+		}
+		last, err := types.HexStringToUint128(a.Max)
+		if err != nil {
+			return nil, fmt.Errorf("accounts[%d].max: %w", i, err)
+		}
+		key.Restriction.Accounts = append(key.Restriction.Accounts, AccountRange{Min: first, Max: last})
+	}
+	return key, nil
+}
+
+type jwk struct {
+	Kid string `json:"kid"`
+	Kty string `json:"kty"`
+	Crv string `json:"crv"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+}
+
+// ParseJWKS returns the public keys of a jwks by kid, keys of an unsupported type are skipped.
+func ParseJWKS(b []byte) (map[string]any, error) {
+	var set struct {
+		Keys []jwk `json:"keys"`
+	}
+	if err := json.Unmarshal(b, &set); err != nil {
+		return nil, fmt.Errorf("parsing jwks: %w", err)
+	}
+	keys := map[string]any{}
+	for i, k := range set.Keys {
+		key, err := k.publicKey()
+		if err != nil {
+			return nil, fmt.Errorf("jwks keys[%d]: %w", i, err)
+		}
+		if key != nil {
+			keys[k.Kid] = key
+		}
+	}
+	if len(keys) == 0 {
+		return nil, errors.New("jwks has no supported keys")
+	}
+	return keys, nil
+}
+
+func (k jwk) publicKey() (any, error) {
+	decode := base64.RawURLEncoding.DecodeString
+	switch {
+	case k.Kty == "RSA":
+		n, err := decode(k.N)
+		if err != nil {
+			return nil, fmt.Errorf("n: %w", err)
+		}
+		e, err := decode(k.E)
+		if err != nil {
+			return nil, fmt.Errorf("e: %w", err)
+		}
+		return &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: int(new(big.Int).SetBytes(e).Int64())}, nil
+	case k.Kty == "EC" && k.Crv == "P-256":
+		x, err := decode(k.X)
+		if err != nil {
+			return nil, fmt.Errorf("x: %w", err)
+		}
+		y, err := decode(k.Y)
+		if err != nil {
+			return nil, fmt.Errorf("y: %w", err)
+		}
+		// ecdh rejects points that are not on the curve.
+		if _, err := ecdh.P256().NewPublicKey(append(append([]byte{4}, x...), y...)); err != nil {
+			return nil, err
+		}
+		return &ecdsa.PublicKey{Curve: elliptic.P256(), X: new(big.Int).SetBytes(x), Y: new(big.Int).SetBytes(y)}, nil
+	case k.Kty == "OKP" && k.Crv == "Ed25519":
+		x, err := decode(k.X)
+		if err != nil {
+			return nil, fmt.Errorf("x: %w", err)
+		}
+		if len(x) != ed25519.PublicKeySize {
+			return nil, errors.New("x: invalid ed25519 key size")
+		}
+		return ed25519.PublicKey(x), nil
+	}
+	return nil, nil
+}
@@ -0,0 +1,206 @@
+package auth
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tigerbeetle/tigerbeetle-go/pkg/types"
+)
+
+func b64(b []byte) string { return base64.RawURLEncoding.EncodeToString(b) }
+
+func toJWK(kid string, pub crypto.PublicKey) map[string]string {
+	switch k := pub.(type) {
+	case *rsa.PublicKey:
+		return map[string]string{"kid": kid, "kty": "RSA", "n": b64(k.N.Bytes()), "e": b64(big.NewInt(int64(k.E)).Bytes())}
+	case *ecdsa.PublicKey:
+		return map[string]string{"kid": kid, "kty": "EC", "crv": "P-256", "x": b64(k.X.FillBytes(make([]byte, 32))), "y": b64(k.Y.FillBytes(make([]byte, 32)))}
+	case ed25519.PublicKey:
+		return map[string]string{"kid": kid, "kty": "OKP", "crv": "Ed25519", "x": b64(k)}
+	}
+	panic("unsupported key")
+}
+
+func jwks(t *testing.T, keys ...map[string]string) []byte {
+	b, err := json.Marshal(map[string]any{"keys": keys})
+	require.NoError(t, err)
+	return b
+}
+
+func sign(t *testing.T, method jwt.SigningMethod, kid string, key crypto.PrivateKey, claims Claims) string {
+	if claims.ExpiresAt == nil {
+		claims.ExpiresAt = jwt.NewNumericDate(time.Now().Add(time.Hour))
+	}
+	token := jwt.NewWithClaims(method, claims)
+	token.Header["kid"] = kid
+	s, err := token.SignedString(key)
+	require.NoError(t, err)
+	return s
+}
+
+func TestVerify(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	edPub, edKey, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	path := filepath.Join(t.TempDir(), "jwks.json")
+	require.NoError(t, os.WriteFile(path, jwks(t,
+		toJWK("rsa", &rsaKey.PublicKey),
+		toJWK("ec", &ecKey.PublicKey),
+		toJWK("ed", edPub),
+	), 0o600))
+	v, err := NewVerifier(JWTOptions{JWKSFile: path, Issuer: "issuer"})
+	require.NoError(t, err)
+	keys := &Keys{JWT: v}
+
+	claims := Claims{
+		RegisteredClaims: jwt.RegisteredClaims{Subject: "tenant-a", Issuer: "issuer"},
+		Scope:            "read write-transfers",
+		Ledgers:          []uint32{1},
+		Accounts:         []AccountClaim{{Min: "100", Max: "1ff"}},
+	}
+	for _, tc := range []struct {
+		method jwt.SigningMethod
+		kid    string
+		key    crypto.PrivateKey
+	}{
+		{jwt.SigningMethodRS256, "rsa", rsaKey},
+		{jwt.SigningMethodES256, "ec", ecKey},
+		{jwt.SigningMethodEdDSA, "ed", edKey},
+	} {
+		t.Run(tc.method.Alg(), func(t *testing.T) {
+			key, err := keys.Authenticate("Bearer " + sign(t, tc.method, tc.kid, tc.key, claims))
+			require.NoError(t, err)
+			assert.Equal(t, "jwt:tenant-a", key.Name)
+			assert.True(t, key.Allows(ScopeWriteTransfers))
+			assert.False(t, key.Allows(ScopeWriteAccounts))
+			assert.True(t, key.Restriction.AllowsAccountID(types.ToUint128(0x1ff)))
+			assert.False(t, key.Restriction.AllowsAccountID(types.ToUint128(0x200)))
+			assert.False(t, key.Restriction.AllowsLedger(2))
+		})
+	}
+
+	t.Run("should reject invalid tokens", func(t *testing.T) {
+		expired := claims
+		expired.ExpiresAt = jwt.NewNumericDate(time.Now().Add(-time.Minute))
+		_, err := keys.Authenticate("Bearer " + sign(t, jwt.SigningMethodRS256, "rsa", rsaKey, expired))
+		assert.ErrorIs(t, err, ErrInvalidKey)
+
+		other := claims
+		other.Issuer = "other"
+		_, err = keys.Authenticate("Bearer " + sign(t, jwt.SigningMethodRS256, "rsa", rsaKey, other))
+		assert.ErrorIs(t, err, ErrInvalidKey)
+
+		// Signed by the rsa key, but claiming the ec key.
+		_, err = keys.Authenticate("Bearer " + sign(t, jwt.SigningMethodRS256, "ec", rsaKey, claims))
+		assert.ErrorIs(t, err, ErrInvalidKey)
+
+		_, err = keys.Authenticate("Bearer " + sign(t, jwt.SigningMethodRS256, "unknown", rsaKey, claims))
+		assert.ErrorIs(t, err, ErrInvalidKey)
+	})
+}
+
+func TestVerifierURL(t *testing.T) {
+	first, _, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	secondPub, second, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	var rotated atomic.Bool
+	var fetches atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fetches.Add(1)
+		if rotated.Load() {
+			w.Write(jwks(t, toJWK("second", secondPub)))
+			return
+		}
+		w.Write(jwks(t, toJWK("first", first)))
+	}))
+	defer srv.Close()
+
+	v, err := NewVerifier(JWTOptions{JWKSURL: srv.URL, CacheTTL: time.Hour})
+	require.NoError(t, err)
+	token := sign(t, jwt.SigningMethodEdDSA, "second", second, Claims{Scope: "read"})
+	_, err = v.Verify(token)
+	assert.ErrorIs(t, err, ErrUnknownKeyID)
+	assert.Equal(t, int32(1), fetches.Load(), "an unknown kid is not fetched again right away")
+
+	rotated.Store(true)
+	v.opts.CacheTTL = 0
+	key, err := v.Verify(token)
+	require.NoError(t, err)
+	assert.Equal(t, []Scope{ScopeRead}, key.Scopes)
+	assert.Equal(t, int32(2), fetches.Load())
+}
+
+func TestVerifierSlowURL(t *testing.T) {
+	pub, priv, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	var slow atomic.Bool
+	var fetches atomic.Int32
+	unblock := make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fetches.Add(1)
+		if slow.Load() {
+			<-unblock
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Write(jwks(t, toJWK("first", pub)))
+	}))
+	defer srv.Close()
+	defer close(unblock)
+
+	v, err := NewVerifier(JWTOptions{JWKSURL: srv.URL, CacheTTL: time.Hour})
+	require.NoError(t, err)
+	slow.Store(true)
+	v.opts.CacheTTL = 0
+	token := sign(t, jwt.SigningMethodEdDSA, "first", priv, Claims{Scope: "read"})
+
+	// The expired cache is still served while the url hangs, and a single fetch runs.
+	for range 3 {
+		_, err = v.Verify(token)
+		require.NoError(t, err)
+	}
+	assert.Eventually(t, func() bool { return fetches.Load() == 2 }, time.Second, time.Millisecond)
+	assert.Equal(t, int32(2), fetches.Load())
+
+	// A failed fetch keeps the last good keys.
+	unblock <- struct{}{}
+	assert.Eventually(t, func() bool {
+		v.mu.RLock()
+		defer v.mu.RUnlock()
+		return v.refreshing == nil
+	}, time.Second, time.Millisecond)
+	v.mu.RLock()
+	assert.Contains(t, v.keys, "first")
+	v.mu.RUnlock()
+}
+
+func TestParseJWKSInvalid(t *testing.T) {
+	_, err := ParseJWKS([]byte(`{"keys":[{"kty":"EC","crv":"P-256","x":"AQ","y":"AQ"}]}`))
+	assert.Error(t, err)
+	_, err = ParseJWKS([]byte(`{"keys":[{"kty":"oct","k":"c2VjcmV0"}]}`))
+	assert.ErrorContains(t, err, "no supported keys")
+}
@@ -0,0 +1,61 @@
+package auth
+
+import (
+	"slices"
+
+	"github.com/tigerbeetle/tigerbeetle-go/pkg/types"
+)
+
+// AccountRange is an inclusive range of account ids.
+type AccountRange struct {
+	Min types.Uint128
+	Max types.Uint128
+}
+
+func (r AccountRange) Contains(id types.Uint128) bool {
+	v, lo, hi := id.BigInt(), r.Min.BigInt(), r.Max.BigInt()
+	return v.Cmp(&lo) >= 0 && v.Cmp(&hi) <= 0
+}
+
+// Restriction limits the ledgers, codes and account ids a caller may touch,
+// an empty field allows all of them. A nil Restriction allows everything.
+type Restriction struct {
+	Ledgers  []uint32
+	Codes    []uint16
+	Accounts []AccountRange
+}
+
+// Restricted reports whether r limits anything.
+func (r *Restriction) Restricted() bool {
+	return r != nil && (len(r.Ledgers) > 0 || len(r.Codes) > 0 || len(r.Accounts) > 0)
+}
+
+func (r *Restriction) AllowsLedger(ledger uint32) bool {
+	return r == nil || len(r.Ledgers) == 0 || slices.Contains(r.Ledgers, ledger)
+}
+
+func (r *Restriction) AllowsCode(code uint16) bool {
+	return r == nil || len(r.Codes) == 0 || slices.Contains(r.Codes, code)
+}
+
+func (r *Restriction) AllowsAccountID(id types.Uint128) bool {
+	return r == nil || len(r.Accounts) == 0 || slices.ContainsFunc(r.Accounts, func(a AccountRange) bool {
+		return a.Contains(id)
+	})
+}
+
+func (r *Restriction) AllowsAccount(a types.Account) bool {
+	return r.AllowsLedger(a.Ledger) && r.AllowsCode(a.Code) && r.AllowsAccountID(a.ID)
+}
+
+// AllowsTransfer reports whether the caller may create t, both of its accounts must be allowed.
+func (r *Restriction) AllowsTransfer(t types.Transfer) bool {
+	return r.AllowsLedger(t.Ledger) && r.AllowsCode(t.Code) &&
+		r.AllowsAccountID(t.DebitAccountID) && r.AllowsAccountID(t.CreditAccountID)
+}
+
+// SeesTransfer reports whether the caller may read t, one of its accounts must be allowed.
+func (r *Restriction) SeesTransfer(t types.Transfer) bool {
+	return r.AllowsLedger(t.Ledger) && r.AllowsCode(t.Code) &&
+		(r.AllowsAccountID(t.DebitAccountID) || r.AllowsAccountID(t.CreditAccountID))
+}
@@ -0,0 +1,229 @@
+// Package backup writes a logical copy of all accounts and transfers to a directory
+// and restores it into a fresh cluster with the imported flags.
+//
+// An archive is a directory holding:
+//
+//	manifest.json    version, creation time and a checksum per file
+//	accounts.ndjson  one accountRecord per line in timestamp order
+//	transfers.ndjson one transferRecord per line in timestamp order
+//	expired.ndjson   one expiredRecord per pending transfer that had expired at export
+//
+// All 128 bit values are kept as strings so no precision is lost.
+package backup
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/tigerbeetle/tigerbeetle-go/pkg/types"
+)
+
+const (
+	Version = 1
+
+	ManifestFile  = "manifest.json"
+	AccountsFile  = "accounts.ndjson"
+	TransfersFile = "transfers.ndjson"
+	ExpiredFile   = "expired.ndjson"
+)
+
+var ErrChecksum = errors.New("checksum mismatch")
+
+type Manifest struct {
+	Version   int       `json:"version"`
+	CreatedAt time.Time `json:"created_at"`
+	ClusterID uint64    `json:"cluster_id"`
+	// TimestampMax is the timestamp of the last event the archive can hold
+	TimestampMax uint64 `json:"timestamp_max,omitempty"`
+	Files        []File `json:"files"`
+}
+
+type File struct {
+	Name   string `json:"name"`
+	Count  uint64 `json:"count"`
+	SHA256 string `json:"sha256"`
+}
+
+func (m *Manifest) File(name string) (File, bool) {
+	for _, f := range m.Files {
+		if f.Name == name {
+			return f, true
+		}
+	}
+	return File{}, false
+}
+
+func ReadManifest(dir string) (*Manifest, error) {
+	b, err := os.ReadFile(filepath.Join(dir, ManifestFile))
+	if err != nil {
+		return nil, err
+	}
+	var m Manifest
+	if err := json.Unmarshal(b, &m); err != nil {
+		return nil, err
+	}
+	if m.Version != Version {
+		return nil, fmt.Errorf("unsupported backup version %d", m.Version)
+	}
+	return &m, nil
+}
+
+func writeManifest(dir string, m *Manifest) error {
+	b, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(dir, ManifestFile), b, 0o644)
+}
+
+type accountRecord struct {
+	ID             string `json:"id"`
+	DebitsPending  string `json:"debits_pending"`
+	DebitsPosted   string `json:"debits_posted"`
+	CreditsPending string `json:"credits_pending"`
+	CreditsPosted  string `json:"credits_posted"`
+	UserData128    string `json:"user_data128"`
+	UserData64     uint64 `json:"user_data64"`
+	UserData32     uint32 `json:"user_data32"`
+	Ledger         uint32 `json:"ledger"`
+	Code           uint16 `json:"code"`
+	Flags          uint16 `json:"flags"`
+	Timestamp      uint64 `json:"timestamp"`
+}
+
+type transferRecord struct {
+	ID              string `json:"id"`
+	DebitAccountID  string `json:"debit_account_id"`
+	CreditAccountID string `json:"credit_account_id"`
+	Amount          string `json:"amount"`
+	PendingID       string `json:"pending_id"`
+	UserData128     string `json:"user_data128"`
+	UserData64      uint64 `json:"user_data64"`
+	UserData32      uint32 `json:"user_data32"`
+	Timeout         uint32 `json:"timeout"`
+	Ledger          uint32 `json:"ledger"`
+	Code            uint16 `json:"code"`
+	Flags           uint16 `json:"flags"`
+	Timestamp       uint64 `json:"timestamp"`
+}
+
+type expiredRecord struct {
+	ID string `json:"id"`
+}
+
+func decimal(v types.Uint128) string {
+	b := v.BigInt()
+	return b.String()
+}
+
+func parseDecimal(s string) (types.Uint128, error) {
+	b, ok := new(big.Int).SetString(s, 10)
+	if !ok || b.Sign() < 0 || b.BitLen() > 128 {
+		return types.Uint128{}, fmt.Errorf("invalid u128 %q", s)
+	}
+	return types.BigIntToUint128(*b), nil
+}
+
+func accountToRecord(a types.Account) accountRecord {
+	return accountRecord{
+		ID:             a.ID.String(),
+		DebitsPending:  decimal(a.DebitsPending),
+		DebitsPosted:   decimal(a.DebitsPosted),
+		CreditsPending: decimal(a.CreditsPending),
+		CreditsPosted:  decimal(a.CreditsPosted),
+		UserData128:    a.UserData128.String(),
+		UserData64:     a.UserData64,
+		UserData32:     a.UserData32,
+		Ledger:         a.Ledger,
+		Code:           a.Code,
+		Flags:          a.Flags,
+		Timestamp:      a.Timestamp,
+	}
+}
+
+func transferToRecord(t types.Transfer) transferRecord {
+	return transferRecord{
+		ID:              t.ID.String(),
+		DebitAccountID:  t.DebitAccountID.String(),
+		CreditAccountID: t.CreditAccountID.String(),
+		Amount:          decimal(t.Amount),
+		PendingID:       t.PendingID.String(),
+		UserData128:     t.UserData128.String(),
+		UserData64:      t.UserData64,
+		UserData32:      t.UserData32,
+		Timeout:         t.Timeout,
+		Ledger:          t.Ledger,
+		Code:            t.Code,
+		Flags:           t.Flags,
+		Timestamp:       t.Timestamp,
+	}
+}
+
+// toImportedAccount returns the account as it is created on restore.
+// Balances start at zero and are rebuilt by the restored transfers,
+// which also close the account again if it was closed.
+func (r accountRecord) toImportedAccount() (types.Account, error) {
+	id, err := types.HexStringToUint128(r.ID)
+	if err != nil {
+		return types.Account{}, err
+	}
+	userData128, err := types.HexStringToUint128(r.UserData128)
+	if err != nil {
+		return types.Account{}, err
+	}
+	flags := types.Account{Flags: r.Flags}.AccountFlags()
+	flags.Linked = false
+	flags.Closed = false
+	flags.Imported = true
+	return types.Account{
+		ID:          id,
+		UserData128: userData128,
+		UserData64:  r.UserData64,
+		UserData32:  r.UserData32,
+		Ledger:      r.Ledger,
+		Code:        r.Code,
+		Flags:       flags.ToUint16(),
+		Timestamp:   r.Timestamp,
+	}, nil
+}
+
+// toImportedTransfer returns the transfer as it is created on restore.
+// Linked chains were already committed as a whole, so every transfer is restored on its own.
+// Imported transfers can not have a timeout, restored pending transfers do not expire,
+// the ones that had expired at export are in ExpiredFile and skipped.
+func (r transferRecord) toImportedTransfer() (types.Transfer, error) {
+	var t types.Transfer
+	var err error
+	for _, f := range []struct {
+		dst *types.Uint128
+		src string
+	}{
+		{&t.ID, r.ID},
+		{&t.DebitAccountID, r.DebitAccountID},
+		{&t.CreditAccountID, r.CreditAccountID},
+		{&t.PendingID, r.PendingID},
+		{&t.UserData128, r.UserData128},
+	} {
+		if *f.dst, err = types.HexStringToUint128(f.src); err != nil {
+			return types.Transfer{}, err
+		}
+	}
+	if t.Amount, err = parseDecimal(r.Amount); err != nil {
+		return types.Transfer{}, err
+	}
+	flags := types.Transfer{Flags: r.Flags}.TransferFlags()
+	flags.Linked = false
+	flags.Imported = true
+	t.UserData64 = r.UserData64
+	t.UserData32 = r.UserData32
+	t.Ledger = r.Ledger
+	t.Code = r.Code
+	t.Flags = flags.ToUint16()
+	t.Timestamp = r.Timestamp
+	return t, nil
+}
@@ -0,0 +1,176 @@
+package backup
+
+import (
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	tigerbeetle_go "github.com/tigerbeetle/tigerbeetle-go"
+	"github.com/tigerbeetle/tigerbeetle-go/pkg/types"
+)
+
+// fakeClient serves queries from its accounts and transfers and records created events in order.
+type fakeClient struct {
+	tigerbeetle_go.Client
+	accounts  []types.Account
+	transfers []types.Transfer
+	created   []any
+}
+
+func (c *fakeClient) QueryAccounts(filter types.QueryFilter) ([]types.Account, error) {
+	res := []types.Account{}
+	for _, a := range c.accounts {
+		if a.Timestamp >= filter.TimestampMin && (filter.TimestampMax == 0 || a.Timestamp <= filter.TimestampMax) && len(res) < int(filter.Limit) {
+			res = append(res, a)
+		}
+	}
+	return res, nil
+}
+
+func (c *fakeClient) QueryTransfers(filter types.QueryFilter) ([]types.Transfer, error) {
+	res := []types.Transfer{}
+	for _, t := range c.transfers {
+		if t.Timestamp >= filter.TimestampMin && (filter.TimestampMax == 0 || t.Timestamp <= filter.TimestampMax) && len(res) < int(filter.Limit) {
+			res = append(res, t)
+		}
+	}
+	return res, nil
+}
+
+func (c *fakeClient) CreateAccounts(accounts []types.Account) ([]types.AccountEventResult, error) {
+	for _, a := range accounts {
+		c.created = append(c.created, a)
+	}
+	return []types.AccountEventResult{}, nil
+}
+
+func (c *fakeClient) CreateTransfers(transfers []types.Transfer) ([]types.TransferEventResult, error) {
+	for _, t := range transfers {
+		c.created = append(c.created, t)
+	}
+	return []types.TransferEventResult{}, nil
+}
+
+func TestExportRestore(t *testing.T) {
+	large, _ := new(big.Int).SetString("100000000000000000000000", 10)
+	source := &fakeClient{
+		accounts: []types.Account{
+			{ID: types.ToUint128(1), Ledger: 1, Code: 1, Timestamp: 10, DebitsPosted: types.BigIntToUint128(*large)},
+			{ID: types.ToUint128(2), Ledger: 1, Code: 1, Timestamp: 11, Flags: types.AccountFlags{Closed: true}.ToUint16()},
+			{ID: types.ToUint128(3), Ledger: 1, Code: 1, Timestamp: 30},
+		},
+		transfers: []types.Transfer{
+			{ID: types.ToUint128(100), DebitAccountID: types.ToUint128(1), CreditAccountID: types.ToUint128(2), Amount: types.BigIntToUint128(*large), Ledger: 1, Code: 1, Timestamp: 20, Flags: types.TransferFlags{Linked: true}.ToUint16()},
+			{ID: types.ToUint128(101), DebitAccountID: types.ToUint128(1), CreditAccountID: types.ToUint128(2), Amount: types.ToUint128(1), Ledger: 1, Code: 1, Timestamp: 21, Flags: types.TransferFlags{Pending: true, ClosingCredit: true}.ToUint16()},
+			{ID: types.ToUint128(102), DebitAccountID: types.ToUint128(3), CreditAccountID: types.ToUint128(1), Amount: types.ToUint128(5), Ledger: 1, Code: 1, Timestamp: 31},
+		},
+	}
+
+	dir := filepath.Join(t.TempDir(), "backup")
+	manifest, err := Export(source, dir, Options{ClusterID: 7, BatchSize: 2})
+	require.NoError(t, err)
+	assert.Equal(t, Version, manifest.Version)
+	assert.Equal(t, uint64(7), manifest.ClusterID)
+	accounts, _ := manifest.File(AccountsFile)
+	transfers, _ := manifest.File(TransfersFile)
+	assert.Equal(t, uint64(3), accounts.Count)
+	assert.Equal(t, uint64(3), transfers.Count)
+
+	target := &fakeClient{}
+	report, err := Restore(target, dir, Options{BatchSize: 2})
+	require.NoError(t, err)
+	assert.Equal(t, uint64(3), report.Accounts)
+	assert.Equal(t, uint64(3), report.Transfers)
+	assert.Empty(t, report.Rejected)
+
+	timestamps := []uint64{}
+	for _, v := range target.created {
+		switch v := v.(type) {
+		case types.Account:
+			timestamps = append(timestamps, v.Timestamp)
+			flags := v.AccountFlags()
+			assert.True(t, flags.Imported)
+			assert.False(t, flags.Closed)
+			assert.Equal(t, types.Uint128{}, v.DebitsPosted)
+		case types.Transfer:
+			timestamps = append(timestamps, v.Timestamp)
+			flags := v.TransferFlags()
+			assert.True(t, flags.Imported)
+			assert.False(t, flags.Linked)
+		}
+	}
+	assert.Equal(t, []uint64{10, 11, 20, 21, 30, 31}, timestamps)
+
+	first := target.created[2].(types.Transfer)
+	assert.Equal(t, types.BigIntToUint128(*large), first.Amount)
+	assert.True(t, target.created[3].(types.Transfer).TransferFlags().ClosingCredit)
+}
+
+func TestExportExpiredPending(t *testing.T) {
+	now := uint64(time.Now().UnixNano())
+	hour := uint64(time.Hour)
+	pending := types.TransferFlags{Pending: true}.ToUint16()
+	source := &fakeClient{
+		accounts: []types.Account{
+			{ID: types.ToUint128(1), Ledger: 1, Code: 1, Timestamp: now - 5*hour},
+			{ID: types.ToUint128(2), Ledger: 1, Code: 1, Timestamp: now - 5*hour + 1},
+		},
+		transfers: []types.Transfer{
+			// Expired
+			{ID: types.ToUint128(100), DebitAccountID: types.ToUint128(1), CreditAccountID: types.ToUint128(2), Amount: types.ToUint128(1), Ledger: 1, Code: 1, Timestamp: now - 4*hour, Timeout: 60, Flags: pending},
+			// Voided before it expired
+			{ID: types.ToUint128(101), DebitAccountID: types.ToUint128(1), CreditAccountID: types.ToUint128(2), Amount: types.ToUint128(1), Ledger: 1, Code: 1, Timestamp: now - 3*hour, Timeout: 60, Flags: pending},
+			{ID: types.ToUint128(102), PendingID: types.ToUint128(101), Ledger: 1, Code: 1, Timestamp: now - 3*hour + 1, Flags: types.TransferFlags{VoidPendingTransfer: true}.ToUint16()},
+			// Not expired yet
+			{ID: types.ToUint128(103), DebitAccountID: types.ToUint128(1), CreditAccountID: types.ToUint128(2), Amount: types.ToUint128(1), Ledger: 1, Code: 1, Timestamp: now - hour, Timeout: 7200, Flags: pending},
+			// After the export started
+			{ID: types.ToUint128(104), DebitAccountID: types.ToUint128(1), CreditAccountID: types.ToUint128(2), Amount: types.ToUint128(1), Ledger: 1, Code: 1, Timestamp: now + hour},
+		},
+	}
+	dir := filepath.Join(t.TempDir(), "backup")
+	manifest, err := Export(source, dir, Options{})
+	require.NoError(t, err)
+	transfers, _ := manifest.File(TransfersFile)
+	expired, _ := manifest.File(ExpiredFile)
+	assert.Equal(t, uint64(4), transfers.Count)
+	assert.Equal(t, uint64(1), expired.Count)
+
+	target := &fakeClient{}
+	report, err := Restore(target, dir, Options{})
+	require.NoError(t, err)
+	assert.Equal(t, uint64(3), report.Transfers)
+	assert.Equal(t, []Rejection{{
+		Kind:   "transfer",
+		ID:     types.ToUint128(100).String(),
+		Code:   uint32(types.TransferPendingTransferExpired),
+		Result: "TransferPendingTransferExpired",
+	}}, report.Rejected)
+}
+
+func TestRestoreChecksum(t *testing.T) {
+	source := &fakeClient{
+		accounts: []types.Account{{ID: types.ToUint128(1), Ledger: 1, Code: 1, Timestamp: 10}},
+	}
+	dir := filepath.Join(t.TempDir(), "backup")
+	_, err := Export(source, dir, Options{})
+	require.NoError(t, err)
+
+	path := filepath.Join(dir, AccountsFile)
+	b, err := os.ReadFile(path)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(path, append(b, b...), 0o644))
+
+	target := &fakeClient{}
+	_, err = Restore(target, dir, Options{})
+	assert.ErrorIs(t, err, ErrChecksum)
+	assert.Empty(t, target.created)
+}
+
+func TestExportExistingDir(t *testing.T) {
+	_, err := Export(&fakeClient{}, t.TempDir(), Options{})
+	assert.ErrorIs(t, err, os.ErrExist)
+}
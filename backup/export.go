@@ -0,0 +1,192 @@
+package backup
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"hash"
+	"io"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"time"
+
+	"github.com/lil5/tigerbeetle_api/config"
+	"github.com/lil5/tigerbeetle_api/metrics"
+	tigerbeetle_go "github.com/tigerbeetle/tigerbeetle-go"
+	"github.com/tigerbeetle/tigerbeetle-go/pkg/types"
+)
+
+type Options struct {
+	ClusterID uint64
+	// BatchSize is the amount of rows per TigerBeetle request, defaults to config.TB_MAX_BATCH_SIZE.
+	BatchSize int
+}
+
+// NewOptions returns the options of the configured cluster.
+func NewOptions() Options {
+	return Options{
+		ClusterID: config.Config.TbClusterID,
+		BatchSize: config.TB_MAX_BATCH_SIZE,
+	}
+}
+
+func (o Options) batchSize() int {
+	if o.BatchSize <= 0 {
+		return config.TB_MAX_BATCH_SIZE
+	}
+	return o.BatchSize
+}
+
+// ndjsonWriter writes one json value per line while hashing the output.
+type ndjsonWriter struct {
+	f     *os.File
+	w     *bufio.Writer
+	hash  hash.Hash
+	enc   *json.Encoder
+	count uint64
+}
+
+func createNDJSON(path string) (*ndjsonWriter, error) {
+	f, err := os.Create(path)
+	if err != nil {
+		return nil, err
+	}
+	h := sha256.New()
+	w := bufio.NewWriter(io.MultiWriter(f, h))
+	return &ndjsonWriter{f: f, w: w, hash: h, enc: json.NewEncoder(w)}, nil
+}
+
+func (w *ndjsonWriter) Write(v any) error {
+	w.count++
+	return w.enc.Encode(v)
+}
+
+func (w *ndjsonWriter) Close(name string) (File, error) {
+	if err := w.w.Flush(); err != nil {
+		w.f.Close()
+		return File{}, err
+	}
+	if err := w.f.Sync(); err != nil {
+		w.f.Close()
+		return File{}, err
+	}
+	if err := w.f.Close(); err != nil {
+		return File{}, err
+	}
+	return File{Name: name, Count: w.count, SHA256: hex.EncodeToString(w.hash.Sum(nil))}, nil
+}
+
+// Export writes every account and transfer to dir, which must not exist yet.
+// Both are read up to the time Export starts, so the archive is a consistent snapshot
+// and every account a transfer refers to is part of it.
+// Pending transfers that expired by then, without a post or void, are listed in ExpiredFile.
+func Export(tb tigerbeetle_go.Client, dir string, opts Options) (*Manifest, error) {
+	if err := os.Mkdir(dir, 0o755); err != nil {
+		return nil, err
+	}
+	limit := uint32(opts.batchSize())
+	now := time.Now().UTC()
+	manifest := &Manifest{
+		Version:      Version,
+		CreatedAt:    now,
+		ClusterID:    opts.ClusterID,
+		TimestampMax: uint64(now.UnixNano()),
+	}
+
+	aw, err := createNDJSON(filepath.Join(dir, AccountsFile))
+	if err != nil {
+		return nil, err
+	}
+	filter := types.QueryFilter{TimestampMax: manifest.TimestampMax, Limit: limit}
+	for {
+		metrics.TotalTbQueryAccountsCall.Inc()
+		res, err := tb.QueryAccounts(filter)
+		if err != nil {
+			aw.Close(AccountsFile)
+			return nil, err
+		}
+		for _, a := range res {
+			if err := aw.Write(accountToRecord(a)); err != nil {
+				aw.Close(AccountsFile)
+				return nil, err
+			}
+		}
+		if len(res) < int(limit) {
+			break
+		}
+		filter.TimestampMin = res[len(res)-1].Timestamp + 1
+	}
+	f, err := aw.Close(AccountsFile)
+	if err != nil {
+		return nil, err
+	}
+	manifest.Files = append(manifest.Files, f)
+
+	tw, err := createNDJSON(filepath.Join(dir, TransfersFile))
+	if err != nil {
+		return nil, err
+	}
+	// A pending transfer can not be posted or voided once expired, so one that has
+	// expired by TimestampMax stays expired unless a later transfer resolved it first.
+	expired := map[types.Uint128]bool{}
+	filter = types.QueryFilter{TimestampMax: manifest.TimestampMax, Limit: limit}
+	for {
+		metrics.TotalTbQueryTransfersCall.Inc()
+		res, err := tb.QueryTransfers(filter)
+		if err != nil {
+			tw.Close(TransfersFile)
+			return nil, err
+		}
+		for _, t := range res {
+			if err := tw.Write(transferToRecord(t)); err != nil {
+				tw.Close(TransfersFile)
+				return nil, err
+			}
+			flags := t.TransferFlags()
+			switch {
+			case flags.Pending && t.Timeout > 0 && t.Timestamp+uint64(t.Timeout)*uint64(time.Second) <= manifest.TimestampMax:
+				expired[t.ID] = true
+			case flags.PostPendingTransfer || flags.VoidPendingTransfer:
+				delete(expired, t.PendingID)
+			}
+		}
+		if len(res) < int(limit) {
+			break
+		}
+		filter.TimestampMin = res[len(res)-1].Timestamp + 1
+	}
+	f, err = tw.Close(TransfersFile)
+	if err != nil {
+		return nil, err
+	}
+	manifest.Files = append(manifest.Files, f)
+
+	ew, err := createNDJSON(filepath.Join(dir, ExpiredFile))
+	if err != nil {
+		return nil, err
+	}
+	ids := slices.SortedFunc(maps.Keys(expired), func(a, b types.Uint128) int {
+		return bytes.Compare(a[:], b[:])
+	})
+	for _, id := range ids {
+		if err := ew.Write(expiredRecord{ID: id.String()}); err != nil {
+			ew.Close(ExpiredFile)
+			return nil, err
+		}
+	}
+	f, err = ew.Close(ExpiredFile)
+	if err != nil {
+		return nil, err
+	}
+	manifest.Files = append(manifest.Files, f)
+
+	// The manifest is written last, an archive without one is incomplete.
+	if err := writeManifest(dir, manifest); err != nil {
+		return nil, err
+	}
+	return manifest, nil
+}
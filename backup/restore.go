@@ -0,0 +1,267 @@
+package backup
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/lil5/tigerbeetle_api/metrics"
+	"github.com/lil5/tigerbeetle_api/proto"
+	tigerbeetle_go "github.com/tigerbeetle/tigerbeetle-go"
+	"github.com/tigerbeetle/tigerbeetle-go/pkg/types"
+)
+
+// Rejection is an account or transfer that TigerBeetle refused to restore.
+type Rejection struct {
+	Kind   string `json:"kind"`
+	ID     string `json:"id"`
+	Code   uint32 `json:"code"`
+	Result string `json:"result"`
+}
+
+type RestoreReport struct {
+	Accounts  uint64      `json:"accounts"`
+	Transfers uint64      `json:"transfers"`
+	Rejected  []Rejection `json:"rejected"`
+}
+
+// Verify checks the checksum and row count of every file in the manifest.
+func Verify(dir string) (*Manifest, error) {
+	m, err := ReadManifest(dir)
+	if err != nil {
+		return nil, err
+	}
+	for _, name := range []string{AccountsFile, TransfersFile, ExpiredFile} {
+		want, ok := m.File(name)
+		// Archives of earlier releases have no expired file.
+		if !ok && name == ExpiredFile {
+			continue
+		}
+		if !ok {
+			return nil, fmt.Errorf("%s missing from manifest", name)
+		}
+		got, err := checksum(filepath.Join(dir, name))
+		if err != nil {
+			return nil, err
+		}
+		if got.SHA256 != want.SHA256 || got.Count != want.Count {
+			return nil, fmt.Errorf("%s: %w", name, ErrChecksum)
+		}
+	}
+	return m, nil
+}
+
+func checksum(path string) (File, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return File{}, err
+	}
+	defer f.Close()
+	h := sha256.New()
+	s := bufio.NewScanner(io.TeeReader(f, h))
+	s.Buffer(make([]byte, 64*1024), 1024*1024)
+	var count uint64
+	for s.Scan() {
+		count++
+	}
+	if err := s.Err(); err != nil {
+		return File{}, err
+	}
+	return File{Name: filepath.Base(path), Count: count, SHA256: hex.EncodeToString(h.Sum(nil))}, nil
+}
+
+// recordReader reads one record at a time so archives larger than memory can be restored.
+type recordReader[T any] struct {
+	f    *os.File
+	dec  *json.Decoder
+	next *T
+}
+
+func openRecords[T any](path string) (*recordReader[T], error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	r := &recordReader[T]{f: f, dec: json.NewDecoder(bufio.NewReader(f))}
+	if err := r.advance(); err != nil {
+		f.Close()
+		return nil, err
+	}
+	return r, nil
+}
+
+// advance decodes the next record, next is nil at the end of the file.
+func (r *recordReader[T]) advance() error {
+	var v T
+	if err := r.dec.Decode(&v); err != nil {
+		if err == io.EOF {
+			r.next = nil
+			return nil
+		}
+		return err
+	}
+	r.next = &v
+	return nil
+}
+
+func (r *recordReader[T]) Close() error { return r.f.Close() }
+
+// readExpired returns the ids of ExpiredFile, none when the archive has no such file.
+func readExpired(dir string, m *Manifest) (map[string]bool, error) {
+	expired := map[string]bool{}
+	if _, ok := m.File(ExpiredFile); !ok {
+		return expired, nil
+	}
+	r, err := openRecords[expiredRecord](filepath.Join(dir, ExpiredFile))
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+	for r.next != nil {
+		expired[r.next.ID] = true
+		if err := r.advance(); err != nil {
+			return nil, err
+		}
+	}
+	return expired, nil
+}
+
+// Restore verifies the archive in dir and creates its accounts and transfers
+// in the original timestamp order. The target cluster is expected to be empty,
+// imported events must have a timestamp later than any existing event.
+// Pending transfers that had expired at export are skipped and reported with TransferPendingTransferExpired.
+func Restore(tb tigerbeetle_go.Client, dir string, opts Options) (*RestoreReport, error) {
+	m, err := Verify(dir)
+	if err != nil {
+		return nil, err
+	}
+	expired, err := readExpired(dir, m)
+	if err != nil {
+		return nil, err
+	}
+	accounts, err := openRecords[accountRecord](filepath.Join(dir, AccountsFile))
+	if err != nil {
+		return nil, err
+	}
+	defer accounts.Close()
+	transfers, err := openRecords[transferRecord](filepath.Join(dir, TransfersFile))
+	if err != nil {
+		return nil, err
+	}
+	defer transfers.Close()
+
+	batchSize := opts.batchSize()
+	report := &RestoreReport{Rejected: []Rejection{}}
+	var accountBatch []types.Account
+	var transferBatch []types.Transfer
+
+	flushAccounts := func() error {
+		if len(accountBatch) == 0 {
+			return nil
+		}
+		metrics.TotalTbCreateAccountsCall.Inc()
+		res, err := tb.CreateAccounts(accountBatch)
+		if err != nil {
+			return err
+		}
+		metrics.TotalCreateAccountsTxErr.Add(float64(len(res)))
+		for _, r := range res {
+			report.Rejected = append(report.Rejected, Rejection{
+				Kind:   "account",
+				ID:     accountBatch[r.Index].ID.String(),
+				Code:   uint32(r.Result),
+				Result: proto.CreateAccountResult(r.Result).String(),
+			})
+		}
+		report.Accounts += uint64(len(accountBatch) - len(res))
+		accountBatch = accountBatch[:0]
+		return nil
+	}
+	flushTransfers := func() error {
+		if len(transferBatch) == 0 {
+			return nil
+		}
+		metrics.TotalTbCreateTransfersCall.Inc()
+		metrics.TotalCreateTransferTx.Add(float64(len(transferBatch)))
+		res, err := tb.CreateTransfers(transferBatch)
+		if err != nil {
+			return err
+		}
+		metrics.TotalCreateTransferTxErr.Add(float64(len(res)))
+		for _, r := range res {
+			report.Rejected = append(report.Rejected, Rejection{
+				Kind:   "transfer",
+				ID:     transferBatch[r.Index].ID.String(),
+				Code:   uint32(r.Result),
+				Result: proto.CreateTransferResult(r.Result).String(),
+			})
+		}
+		report.Transfers += uint64(len(transferBatch) - len(res))
+		transferBatch = transferBatch[:0]
+		return nil
+	}
+
+	// Accounts and transfers share one timestamp sequence, a batch of one kind
+	// is flushed before an event of the other kind is queued.
+	for accounts.next != nil || transfers.next != nil {
+		if transfers.next == nil || (accounts.next != nil && accounts.next.Timestamp < transfers.next.Timestamp) {
+			if err := flushTransfers(); err != nil {
+				return report, err
+			}
+			a, err := accounts.next.toImportedAccount()
+			if err != nil {
+				return report, fmt.Errorf("account %s: %w", accounts.next.ID, err)
+			}
+			accountBatch = append(accountBatch, a)
+			if len(accountBatch) >= batchSize {
+				if err := flushAccounts(); err != nil {
+					return report, err
+				}
+			}
+			if err := accounts.advance(); err != nil {
+				return report, err
+			}
+		} else {
+			if expired[transfers.next.ID] {
+				report.Rejected = append(report.Rejected, Rejection{
+					Kind:   "transfer",
+					ID:     transfers.next.ID,
+					Code:   uint32(types.TransferPendingTransferExpired),
+					Result: proto.CreateTransferResult_TransferPendingTransferExpired.String(),
+				})
+				if err := transfers.advance(); err != nil {
+					return report, err
+				}
+				continue
+			}
+			if err := flushAccounts(); err != nil {
+				return report, err
+			}
+			t, err := transfers.next.toImportedTransfer()
+			if err != nil {
+				return report, fmt.Errorf("transfer %s: %w", transfers.next.ID, err)
+			}
+			transferBatch = append(transferBatch, t)
+			if len(transferBatch) >= batchSize {
+				if err := flushTransfers(); err != nil {
+					return report, err
+				}
+			}
+			if err := transfers.advance(); err != nil {
+				return report, err
+			}
+		}
+	}
+	if err := flushAccounts(); err != nil {
+		return report, err
+	}
+	if err := flushTransfers(); err != nil {
+		return report, err
+	}
+	return report, nil
+}
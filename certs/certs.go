@@ -0,0 +1,153 @@
+// Package certs serves TLS certificates that are reloaded when their files change.
+//
+// New connections use the reloaded certificate, open connections keep the one they were made with.
+package certs
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+
+	"github.com/fsnotify/fsnotify"
+	"github.com/samber/lo"
+)
+
+type Reloader struct {
+	certFile     string
+	keyFile      string
+	clientCAFile string
+
+	cert     atomic.Pointer[tls.Certificate]
+	clientCA atomic.Pointer[x509.CertPool]
+	watcher  *fsnotify.Watcher
+}
+
+// New loads the certificate of certFile and keyFile, nil when certFile is empty and TLS is disabled.
+// With clientCAFile set clients must present a certificate signed by it.
+func New(certFile, keyFile, clientCAFile string) (*Reloader, error) {
+	if certFile == "" {
+		if clientCAFile != "" {
+			return nil, errors.New("a client ca requires a certificate")
+		}
+		return nil, nil
+	}
+	r := &Reloader{certFile: certFile, keyFile: keyFile, clientCAFile: clientCAFile}
+	if err := r.load(); err != nil {
+		return nil, err
+	}
+
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return nil, err
+	}
+	// Directories are watched as files are often replaced by a rename, as with kubernetes secrets.
+	dirs := lo.Uniq(lo.FilterMap([]string{certFile, keyFile, clientCAFile}, func(f string, _ int) (string, bool) {
+		return filepath.Dir(f), f != ""
+	}))
+	for _, dir := range dirs {
+		if err := watcher.Add(dir); err != nil {
+			watcher.Close()
+			return nil, fmt.Errorf("watching %s: %w", dir, err)
+		}
+	}
+	r.watcher = watcher
+	go r.watch()
+	return r, nil
+}
+
+func (r *Reloader) load() error {
+	cert, err := tls.LoadX509KeyPair(r.certFile, r.keyFile)
+	if err != nil {
+		return fmt.Errorf("loading certificate: %w", err)
+	}
+	if r.clientCAFile != "" {
+		b, err := os.ReadFile(r.clientCAFile)
+		if err != nil {
+			return fmt.Errorf("loading client ca: %w", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(b) {
+			return errors.New("loading client ca: no certificates found")
+		}
+		r.clientCA.Store(pool)
+	}
+	r.cert.Store(&cert)
+	return nil
+}
+
+func (r *Reloader) watch() {
+	for {
+		select {
+		case event, ok := <-r.watcher.Events:
+			if !ok {
+				return
+			}
+			if event.Has(fsnotify.Chmod) {
+				continue
+			}
+			// A half written pair fails to load, the next event of the other file reloads it.
+			if err := r.load(); err != nil {
+				slog.Warn("unable to reload certificate, keeping the previous one", "err", err)
+				continue
+			}
+			slog.Info("Certificate reloaded", "file", event.Name)
+		case err, ok := <-r.watcher.Errors:
+			if !ok {
+				return
+			}
+			slog.Warn("certificate watcher", "err", err)
+		}
+	}
+}
+
+// Config returns a tls.Config that always uses the last loaded certificate and client ca.
+// The client ca is checked by VerifyConnection, which also runs on resumed sessions,
+// so a reloaded ca applies to new connections.
+func (r *Reloader) Config() *tls.Config {
+	cfg := &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		GetCertificate: func(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+			return r.cert.Load(), nil
+		},
+	}
+	if r.clientCAFile != "" {
+		cfg.ClientAuth = tls.RequireAnyClientCert
+		cfg.VerifyConnection = r.verifyClient
+	}
+	return cfg
+}
+
+func (r *Reloader) verifyClient(state tls.ConnectionState) error {
+	certs := state.PeerCertificates
+	if len(certs) == 0 {
+		return errors.New("no client certificate")
+	}
+	intermediates := x509.NewCertPool()
+	for _, cert := range certs[1:] {
+		intermediates.AddCert(cert)
+	}
+	_, err := certs[0].Verify(x509.VerifyOptions{
+		Roots:         r.clientCA.Load(),
+		Intermediates: intermediates,
+		KeyUsages:     []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	})
+	return err
+}
+
+func (r *Reloader) Close() {
+	r.watcher.Close()
+}
+
+// Identity returns the common name of the client certificate of state, empty without one.
+// The certificate is only present once the handshake verified it against the client ca.
+func Identity(state *tls.ConnectionState) string {
+	if state == nil || len(state.PeerCertificates) == 0 {
+		return ""
+	}
+	return state.PeerCertificates[0].Subject.CommonName
+}
@@ -0,0 +1,166 @@
+package certs
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"errors"
+	"io"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testCert struct {
+	cert *x509.Certificate
+	key  *ecdsa.PrivateKey
+	pem  []byte
+}
+
+func issue(t *testing.T, cn string, parent *testCert, usage x509.ExtKeyUsage) *testCert {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(time.Now().UnixNano()),
+		Subject:      pkix.Name{CommonName: cn},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		DNSNames:     []string{"localhost"},
+		ExtKeyUsage:  []x509.ExtKeyUsage{usage},
+	}
+	signer, signerKey := tmpl, key
+	if parent == nil {
+		tmpl.IsCA = true
+		tmpl.BasicConstraintsValid = true
+		tmpl.KeyUsage = x509.KeyUsageCertSign
+	} else {
+		signer, signerKey = parent.cert, parent.key
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, signer, &key.PublicKey, signerKey)
+	require.NoError(t, err)
+	cert, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+	return &testCert{cert: cert, key: key, pem: pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})}
+}
+
+func (c *testCert) write(t *testing.T, dir string) {
+	der, err := x509.MarshalECPrivateKey(c.key)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "key.pem"), pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: der}), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "cert.pem"), c.pem, 0o600))
+}
+
+func (c *testCert) tls() tls.Certificate {
+	return tls.Certificate{Certificate: [][]byte{c.cert.Raw}, PrivateKey: c.key}
+}
+
+// handshake connects a client with cert to cfg and returns what each side saw.
+// A non nil cache lets the client resume the sessions of earlier handshakes.
+func handshake(t *testing.T, cfg *tls.Config, roots *x509.CertPool, cert *tls.Certificate, cache tls.ClientSessionCache) (server tls.ConnectionState, peer *x509.Certificate, err error) {
+	l, err := tls.Listen("tcp", "127.0.0.1:0", cfg)
+	require.NoError(t, err)
+	defer l.Close()
+	done := make(chan tls.ConnectionState, 1)
+	go func() {
+		conn, err := l.Accept()
+		if err != nil {
+			done <- tls.ConnectionState{}
+			return
+		}
+		defer conn.Close()
+		tlsConn := conn.(*tls.Conn)
+		tlsConn.Handshake()
+		done <- tlsConn.ConnectionState()
+	}()
+
+	clientCfg := &tls.Config{RootCAs: roots, ServerName: "localhost", ClientSessionCache: cache}
+	if cert != nil {
+		clientCfg.Certificates = []tls.Certificate{*cert}
+	}
+	conn, err := tls.DialWithDialer(&net.Dialer{Timeout: 5 * time.Second}, "tcp", l.Addr().String(), clientCfg)
+	if err == nil {
+		peer = conn.ConnectionState().PeerCertificates[0]
+		// TLS 1.3 clients only learn of a rejected certificate on their first read.
+		conn.SetReadDeadline(time.Now().Add(time.Second))
+		_, err = conn.Read(make([]byte, 1))
+		if errors.Is(err, io.EOF) || errors.Is(err, os.ErrDeadlineExceeded) {
+			err = nil
+		}
+		conn.Close()
+	}
+	return <-done, peer, err
+}
+
+func TestReloader(t *testing.T) {
+	ca := issue(t, "ca", nil, x509.ExtKeyUsageAny)
+	dir := t.TempDir()
+	issue(t, "server-1", ca, x509.ExtKeyUsageServerAuth).write(t, dir)
+	caFile := filepath.Join(dir, "ca.pem")
+	require.NoError(t, os.WriteFile(caFile, ca.pem, 0o600))
+	roots := x509.NewCertPool()
+	roots.AddCert(ca.cert)
+
+	r, err := New(filepath.Join(dir, "cert.pem"), filepath.Join(dir, "key.pem"), caFile)
+	require.NoError(t, err)
+	defer r.Close()
+	cfg := r.Config()
+
+	client := issue(t, "backend", ca, x509.ExtKeyUsageClientAuth).tls()
+	state, peer, err := handshake(t, cfg, roots, &client, nil)
+	require.NoError(t, err)
+	assert.Equal(t, "server-1", peer.Subject.CommonName)
+	assert.Equal(t, "backend", Identity(&state))
+
+	t.Run("should reject clients without a certificate of the ca", func(t *testing.T) {
+		_, _, err := handshake(t, cfg, roots, nil, nil)
+		assert.Error(t, err)
+		other := issue(t, "other", issue(t, "other-ca", nil, x509.ExtKeyUsageAny), x509.ExtKeyUsageClientAuth).tls()
+		_, _, err = handshake(t, cfg, roots, &other, nil)
+		assert.Error(t, err)
+	})
+
+	t.Run("should reload the certificate when its files change", func(t *testing.T) {
+		issue(t, "server-2", ca, x509.ExtKeyUsageServerAuth).write(t, dir)
+		require.Eventually(t, func() bool {
+			return r.cert.Load().Leaf != nil && r.cert.Load().Leaf.Subject.CommonName == "server-2"
+		}, 5*time.Second, 10*time.Millisecond)
+		_, peer, err := handshake(t, cfg, roots, &client, nil)
+		require.NoError(t, err)
+		assert.Equal(t, "server-2", peer.Subject.CommonName)
+	})
+
+	t.Run("should reject resumed sessions after the client ca changes", func(t *testing.T) {
+		cache := tls.NewLRUClientSessionCache(1)
+		_, _, err := handshake(t, cfg, roots, &client, cache)
+		require.NoError(t, err)
+		state, _, err := handshake(t, cfg, roots, &client, cache)
+		require.NoError(t, err)
+		require.True(t, state.DidResume)
+
+		otherCA := issue(t, "other-ca", nil, x509.ExtKeyUsageAny)
+		require.NoError(t, os.WriteFile(caFile, otherCA.pem, 0o600))
+		require.Eventually(t, func() bool {
+			return !r.clientCA.Load().Equal(roots)
+		}, 5*time.Second, 10*time.Millisecond)
+		_, _, err = handshake(t, cfg, roots, &client, cache)
+		assert.Error(t, err)
+	})
+}
+
+func TestNewDisabled(t *testing.T) {
+	r, err := New("", "", "")
+	assert.NoError(t, err)
+	assert.Nil(t, r)
+	_, err = New("", "", "ca.pem")
+	assert.Error(t, err)
+}
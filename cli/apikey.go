@@ -0,0 +1,34 @@
+package cli
+
+import (
+	"flag"
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"github.com/lil5/tigerbeetle_api/auth"
+)
+
+// ApiKey generates a new api key and prints it with the API_KEYS entry that stores its hash.
+func ApiKey(args []string) int {
+	fs := flag.NewFlagSet("api-key", flag.ContinueOnError)
+	name := fs.String("name", "", "name of the key")
+	scopes := fs.String("scopes", string(auth.ScopeRead), "scopes of the key, separated by +")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if *name == "" || strings.ContainsAny(*name, ":,") {
+		slog.Error("-name is required and must not contain : or ,")
+		return 2
+	}
+	entry := fmt.Sprintf("%s:%s:%s", *name, auth.Hash(""), *scopes)
+	if _, err := auth.New("", entry); err != nil {
+		slog.Error("invalid api key", "error", err)
+		return 2
+	}
+
+	key := auth.Generate()
+	fmt.Println("key:", key)
+	fmt.Printf("API_KEYS entry: %s:%s:%s\n", *name, auth.Hash(key), *scopes)
+	return 0
+}
@@ -0,0 +1,38 @@
+package cli
+
+import (
+	"flag"
+	"fmt"
+	"log/slog"
+
+	"github.com/lil5/tigerbeetle_api/audit"
+	"github.com/lil5/tigerbeetle_api/config"
+)
+
+// AuditVerify checks the hash chain of an audit log and prints its last hash,
+// which can be kept elsewhere to detect a rewritten log later.
+func AuditVerify(args []string) int {
+	fs := flag.NewFlagSet("audit-verify", flag.ContinueOnError)
+	dir := fs.String("dir", "", "directory of the audit log, defaults to AUDIT_DIR")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if *dir == "" {
+		*dir = config.Config.AuditDir
+	}
+	if *dir == "" {
+		slog.Error("-dir is required")
+		return 2
+	}
+	report, err := audit.Verify(*dir)
+	if err != nil {
+		slog.Error("audit log verification failed", "error", err)
+		return 1
+	}
+	if report.FirstSeq > 1 {
+		fmt.Printf("chain starts at seq %d, earlier files were removed\n", report.FirstSeq)
+	}
+	fmt.Printf("ok: %d entries in %d files\n", report.Entries, report.Files)
+	fmt.Println("last hash:", report.LastHash)
+	return 0
+}
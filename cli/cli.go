@@ -0,0 +1,41 @@
+// Package cli holds the subcommands of the tigerbeetle_api binary.
+package cli
+
+import (
+	"fmt"
+	"os"
+	"sort"
+)
+
+var commands = map[string]func(args []string) int{
+	"api-key":      ApiKey,
+	"audit-verify": AuditVerify,
+	"import":       Import,
+	"export":       Export,
+	"restore":      Restore,
+	"reconcile":    Reconcile,
+}
+
+// Run runs the subcommand name and returns its exit code.
+func Run(name string, args []string) int {
+	cmd, ok := commands[name]
+	if !ok {
+		usage()
+		return 2
+	}
+	return cmd(args)
+}
+
+func usage() {
+	names := make([]string, 0, len(commands))
+	for name := range commands {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	fmt.Fprintln(os.Stderr, "Usage: tigerbeetle_api [command] [flags]")
+	fmt.Fprintln(os.Stderr, "Without a command the server is started.")
+	fmt.Fprintln(os.Stderr, "Commands:")
+	for _, name := range names {
+		fmt.Fprintln(os.Stderr, "  "+name)
+	}
+}
@@ -0,0 +1,85 @@
+package cli
+
+import (
+	"encoding/csv"
+	"flag"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/lil5/tigerbeetle_api/grpc"
+	"github.com/lil5/tigerbeetle_api/importer"
+)
+
+// Import loads accounts or transfers from a csv or ndjson file.
+// Rejected rows are written to stdout as csv.
+func Import(args []string) int {
+	fs := flag.NewFlagSet("import", flag.ContinueOnError)
+	kind := fs.String("kind", string(importer.KindTransfers), "accounts or transfers")
+	file := fs.String("file", "", "input file, - reads stdin")
+	format := fs.String("format", "", "csv or ndjson, defaults to the file extension")
+	columns := fs.String("columns", "", "csv column mapping, header:field,header:field")
+	imported := fs.Bool("imported", false, "set the imported flag on every row and keep its timestamp")
+	checkpoint := fs.String("checkpoint", "", "progress file, defaults to <file>.checkpoint, - disables")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if *file == "" {
+		slog.Error("-file is required")
+		return 2
+	}
+
+	if *format == "" {
+		*format = strings.TrimPrefix(filepath.Ext(*file), ".")
+	}
+	cols, err := importer.ParseColumns(*columns)
+	if err != nil {
+		slog.Error(err.Error())
+		return 2
+	}
+	w := csv.NewWriter(os.Stdout)
+	w.Write([]string{"row", "code", "result", "error"})
+	opts := importer.Options{
+		Kind:       importer.Kind(*kind),
+		Format:     importer.Format(*format),
+		Columns:    cols,
+		Imported:   *imported,
+		Checkpoint: *checkpoint,
+		OnReject: func(rej importer.Rejection) {
+			w.Write([]string{strconv.FormatUint(rej.Row, 10), strconv.FormatUint(uint64(rej.Code), 10), rej.Result, rej.Error})
+		},
+	}
+
+	var r io.Reader = os.Stdin
+	if *file != "-" {
+		f, err := os.Open(*file)
+		if err != nil {
+			slog.Error("unable to open file", "error", err)
+			return 1
+		}
+		defer f.Close()
+		r = f
+		if opts.Checkpoint == "" {
+			opts.Checkpoint = *file + ".checkpoint"
+		}
+	}
+	if opts.Checkpoint == "-" {
+		opts.Checkpoint = ""
+	}
+
+	app := grpc.NewApp()
+	defer app.Close()
+	report, err := importer.Run(app.TB, r, opts)
+	w.Flush()
+	if report != nil {
+		slog.Info("Import finished", "rows", report.Rows, "skipped", report.Skipped, "imported", report.Imported, "rejected", report.RejectedRows)
+	}
+	if err != nil {
+		slog.Error("import failed", "error", err, "checkpoint", opts.Checkpoint)
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,84 @@
+package cli
+
+import (
+	"flag"
+	"io"
+	"log/slog"
+	"os"
+	"time"
+
+	"github.com/lil5/tigerbeetle_api/grpc"
+	"github.com/lil5/tigerbeetle_api/importer"
+	"github.com/lil5/tigerbeetle_api/reconcile"
+)
+
+// Reconcile matches a csv statement file to the transfers in TigerBeetle.
+// The report is written to stdout as csv, the exit code is 3 when anything did not match
+// or a row could not be parsed.
+func Reconcile(args []string) int {
+	fs := flag.NewFlagSet("reconcile", flag.ContinueOnError)
+	file := fs.String("file", "", "csv statement with amount, reference and date columns, - reads stdin")
+	matchBy := fs.String("match-by", string(reconcile.MatchByID), "id, user_data128 or user_data64")
+	ledger := fs.Uint("ledger", 0, "only expect transfers of this ledger in the file")
+	code := fs.Uint("code", 0, "only expect transfers of this code in the file")
+	columns := fs.String("columns", "", "csv column mapping, header:field,header:field")
+	tz := fs.String("tz", "UTC", "time zone of the dates in the file")
+	maxScan := fs.Uint64("max-scan", 0, "maximum transfers read from the window, 0 is no limit")
+	maxRows := fs.Uint64("max-rows", 0, "maximum rows of the file, 0 is no limit")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if *file == "" {
+		slog.Error("-file is required")
+		return 2
+	}
+	cols, err := importer.ParseColumns(*columns)
+	if err != nil {
+		slog.Error(err.Error())
+		return 2
+	}
+	loc, err := time.LoadLocation(*tz)
+	if err != nil {
+		slog.Error("invalid time zone", "error", err)
+		return 2
+	}
+
+	var r io.Reader = os.Stdin
+	if *file != "-" {
+		f, err := os.Open(*file)
+		if err != nil {
+			slog.Error("unable to open file", "error", err)
+			return 1
+		}
+		defer f.Close()
+		r = f
+	}
+
+	app := grpc.NewApp()
+	defer app.Close()
+	report, err := reconcile.Run(app.TB, r, reconcile.Options{
+		MatchBy:  reconcile.MatchBy(*matchBy),
+		Ledger:   uint32(*ledger),
+		Code:     uint16(*code),
+		Columns:  cols,
+		Location: loc,
+		MaxScan:  *maxScan,
+		MaxRows:  *maxRows,
+	})
+	if err != nil {
+		slog.Error("reconcile failed", "error", err)
+		return 1
+	}
+	if err := reconcile.WriteCSV(os.Stdout, report); err != nil {
+		slog.Error("unable to write report", "error", err)
+		return 1
+	}
+	slog.Info("Reconcile finished", "matched", report.Matched, "missing_in_ledger", report.MissingInLedger, "missing_in_file", report.MissingInFile, "amount_mismatch", report.AmountMismatch, "invalid", report.Invalid)
+	if report.Truncated {
+		slog.Warn("Reconcile stopped at -max-scan, later transfers were not checked", "last_timestamp", report.LastTimestamp)
+	}
+	if report.MissingInLedger+report.MissingInFile+report.AmountMismatch+report.Invalid > 0 {
+		return 3
+	}
+	return 0
+}
@@ -11,6 +11,9 @@ import (
 
 var Config config
 
+// TB_MAX_BATCH_SIZE is the most events of one TigerBeetle request, set in the TigerBeetle server.
+const TB_MAX_BATCH_SIZE = 8190
+
 type config struct {
 	Host string
 	Port string
@@ -34,6 +37,59 @@ type config struct {
 	IsDryRun bool
 
 	PrometheusAddr string
+
+	AggregateMaxScan   uint64
+	AggregateMaxGroups uint32
+
+	ReconcileMaxScan uint64
+
+	// BalanceReplayMaxScan caps the transfers replayed for a balance at a timestamp
+	BalanceReplayMaxScan uint64
+
+	ImportCheckpointDir string
+
+	BackupDir string
+
+	IdempotencyNamespace string
+
+	AliasMode      string
+	AliasStore     string
+	AliasNamespace string
+
+	RegistryFile string
+
+	LimitsFile string
+
+	ApiKeysFile string
+	ApiKeys     string
+
+	JwksFile     string
+	JwksUrl      string
+	JwksCacheTTL time.Duration
+	JwtIssuer    string
+	JwtAudience  string
+
+	TlsCertFile     string
+	TlsKeyFile      string
+	TlsClientCAFile string
+
+	QuotasFile string
+
+	// TrustedProxies are the addresses or cidrs whose X-Forwarded-For and X-Real-IP headers are used for the client ip,
+	// none by default.
+	TrustedProxies []string
+
+	AuditDir     string
+	AuditMaxSize int64
+	// AuditFailOpen lets a write request succeed when its audit entry fails to write
+	AuditFailOpen bool
+
+	MaxBodySize     int64
+	MaxRequestItems int
+	GrpcMaxMsgSize  int
+
+	TracingExporter string
+	TracingEndpoint string
 }
 
 func NewConfig() (ok bool) {
@@ -58,6 +114,13 @@ func NewConfig() (ok bool) {
 	}
 	tbAddresses := strings.Split(tbAddressesArr, ",")
 
+	var trustedProxies []string
+	for _, proxy := range strings.Split(os.Getenv("TRUSTED_PROXIES"), ",") {
+		if proxy = strings.TrimSpace(proxy); proxy != "" {
+			trustedProxies = append(trustedProxies, proxy)
+		}
+	}
+
 	tbClusterId, _ := strconv.ParseUint(os.Getenv("TB_CLUSTER_ID"), 10, 64)
 
 	isBuffered := os.Getenv("IS_BUFFERED") == "true"
@@ -86,6 +149,63 @@ func NewConfig() (ok bool) {
 		prometheusAddr = ":9323"
 	}
 
+	aggregateMaxScan, _ := strconv.ParseUint(os.Getenv("AGGREGATE_MAX_SCAN"), 10, 64)
+	if aggregateMaxScan == 0 {
+		aggregateMaxScan = 1_000_000
+	}
+	aggregateMaxGroups, _ := strconv.ParseUint(os.Getenv("AGGREGATE_MAX_GROUPS"), 10, 32)
+	if aggregateMaxGroups == 0 {
+		aggregateMaxGroups = 10_000
+	}
+
+	reconcileMaxScan, _ := strconv.ParseUint(os.Getenv("RECONCILE_MAX_SCAN"), 10, 64)
+	if reconcileMaxScan == 0 {
+		reconcileMaxScan = 1_000_000
+	}
+
+	balanceReplayMaxScan, _ := strconv.ParseUint(os.Getenv("BALANCE_REPLAY_MAX_SCAN"), 10, 64)
+	if balanceReplayMaxScan == 0 {
+		balanceReplayMaxScan = 1_000_000
+	}
+
+	aliasNamespace := os.Getenv("ALIAS_NAMESPACE")
+	if aliasNamespace == "" {
+		aliasNamespace = "tigerbeetle_api"
+	}
+
+	idempotencyNamespace := os.Getenv("IDEMPOTENCY_NAMESPACE")
+	if idempotencyNamespace == "" {
+		idempotencyNamespace = "tigerbeetle_api"
+	}
+
+	jwksCacheTTL := 5 * time.Minute
+	if v := os.Getenv("JWKS_CACHE_TTL"); v != "" {
+		var err error
+		jwksCacheTTL, err = time.ParseDuration(v)
+		if err != nil {
+			slog.Error("JWKS_CACHE_TTL is invalid duration", "error", err)
+			return false
+		}
+	}
+
+	auditMaxSize, _ := strconv.ParseInt(os.Getenv("AUDIT_MAX_SIZE"), 10, 64)
+	if auditMaxSize <= 0 {
+		auditMaxSize = 100 << 20
+	}
+
+	maxBodySize, _ := strconv.ParseInt(os.Getenv("MAX_BODY_SIZE"), 10, 64)
+	if maxBodySize <= 0 {
+		maxBodySize = 16 << 20
+	}
+	maxRequestItems, _ := strconv.Atoi(os.Getenv("MAX_REQUEST_ITEMS"))
+	if maxRequestItems <= 0 {
+		maxRequestItems = 100_000
+	}
+	grpcMaxMsgSize, _ := strconv.Atoi(os.Getenv("GRPC_MAX_MSG_SIZE"))
+	if grpcMaxMsgSize <= 0 {
+		grpcMaxMsgSize = 16 << 20
+	}
+
 	Config = config{
 		Host: os.Getenv("HOST"),
 		Port: os.Getenv("PORT"),
@@ -109,6 +229,55 @@ func NewConfig() (ok bool) {
 		IsDryRun: os.Getenv("IS_DRY_RUN") == "true",
 
 		PrometheusAddr: prometheusAddr,
+
+		AggregateMaxScan:   aggregateMaxScan,
+		AggregateMaxGroups: uint32(aggregateMaxGroups),
+
+		ReconcileMaxScan: reconcileMaxScan,
+
+		BalanceReplayMaxScan: balanceReplayMaxScan,
+
+		ImportCheckpointDir: os.Getenv("IMPORT_CHECKPOINT_DIR"),
+
+		BackupDir: os.Getenv("BACKUP_DIR"),
+
+		IdempotencyNamespace: idempotencyNamespace,
+
+		AliasMode:      os.Getenv("ALIAS_MODE"),
+		AliasStore:     os.Getenv("ALIAS_STORE"),
+		AliasNamespace: aliasNamespace,
+
+		RegistryFile: os.Getenv("REGISTRY_FILE"),
+
+		LimitsFile: os.Getenv("LIMITS_FILE"),
+
+		ApiKeysFile: os.Getenv("API_KEYS_FILE"),
+		ApiKeys:     os.Getenv("API_KEYS"),
+
+		JwksFile:     os.Getenv("JWKS_FILE"),
+		JwksUrl:      os.Getenv("JWKS_URL"),
+		JwksCacheTTL: jwksCacheTTL,
+		JwtIssuer:    os.Getenv("JWT_ISSUER"),
+		JwtAudience:  os.Getenv("JWT_AUDIENCE"),
+
+		TlsCertFile:     os.Getenv("TLS_CERT_FILE"),
+		TlsKeyFile:      os.Getenv("TLS_KEY_FILE"),
+		TlsClientCAFile: os.Getenv("TLS_CLIENT_CA_FILE"),
+
+		QuotasFile: os.Getenv("QUOTAS_FILE"),
+
+		TrustedProxies: trustedProxies,
+
+		AuditDir:      os.Getenv("AUDIT_DIR"),
+		AuditMaxSize:  auditMaxSize,
+		AuditFailOpen: os.Getenv("AUDIT_FAIL_OPEN") == "true",
+
+		MaxBodySize:     maxBodySize,
+		MaxRequestItems: maxRequestItems,
+		GrpcMaxMsgSize:  grpcMaxMsgSize,
+
+		TracingExporter: os.Getenv("TRACING_EXPORTER"),
+		TracingEndpoint: os.Getenv("TRACING_ENDPOINT"),
 	}
 
 	slog.Info(fmt.Sprintf("%+v", Config))
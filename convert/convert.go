@@ -0,0 +1,261 @@
+// Package convert maps between the proto messages of the api and the types of the TigerBeetle client.
+package convert
+
+import (
+	"log/slog"
+
+	"github.com/lil5/tigerbeetle_api/proto"
+	"github.com/samber/lo"
+	"github.com/tigerbeetle/tigerbeetle-go/pkg/types"
+)
+
+func AccountToProtoAccount(tbAccount types.Account) *proto.Account {
+	tbFlags := tbAccount.AccountFlags()
+	pFlags := proto.AccountFlags{
+		Linked:                     lo.ToPtr(tbFlags.Linked),
+		DebitsMustNotExceedCredits: lo.ToPtr(tbFlags.DebitsMustNotExceedCredits),
+		CreditsMustNotExceedDebits: lo.ToPtr(tbFlags.CreditsMustNotExceedDebits),
+		History:                    lo.ToPtr(tbFlags.History),
+		Imported:                   lo.ToPtr(tbFlags.Imported),
+		Closed:                     lo.ToPtr(tbFlags.Closed),
+	}
+	return &proto.Account{
+		Id:             tbAccount.ID.String(),
+		DebitsPending:  lo.ToPtr(tbAccount.DebitsPending.BigInt()).Uint64(),
+		DebitsPosted:   lo.ToPtr(tbAccount.DebitsPosted.BigInt()).Uint64(),
+		CreditsPending: lo.ToPtr(tbAccount.CreditsPending.BigInt()).Uint64(),
+		CreditsPosted:  lo.ToPtr(tbAccount.CreditsPosted.BigInt()).Uint64(),
+		UserData128:    tbAccount.UserData128.String(),
+		UserData64:     tbAccount.UserData64,
+		UserData32:     tbAccount.UserData32,
+		Ledger:         tbAccount.Ledger,
+		Code:           uint32(tbAccount.Code),
+		Flags:          &pFlags,
+		Timestamp:      uint64(tbAccount.Timestamp),
+	}
+}
+
+func TransferToProtoTransfer(tbTransfer types.Transfer) *proto.Transfer {
+	tbFlags := tbTransfer.TransferFlags()
+	pFlags := &proto.TransferFlags{
+		Linked:              lo.ToPtr(tbFlags.Linked),
+		Pending:             lo.ToPtr(tbFlags.Pending),
+		PostPendingTransfer: lo.ToPtr(tbFlags.PostPendingTransfer),
+		VoidPendingTransfer: lo.ToPtr(tbFlags.VoidPendingTransfer),
+		BalancingDebit:      lo.ToPtr(tbFlags.BalancingDebit),
+		BalancingCredit:     lo.ToPtr(tbFlags.BalancingCredit),
+		Imported:            lo.ToPtr(tbFlags.Imported),
+		ClosingDebit:        lo.ToPtr(tbFlags.ClosingDebit),
+		ClosingCredit:       lo.ToPtr(tbFlags.ClosingCredit),
+	}
+	var pendingId string
+	emptyUint128 := types.Uint128{}
+	if tbTransfer.PendingID != emptyUint128 {
+		pendingId = tbTransfer.PendingID.String()
+	}
+	return &proto.Transfer{
+		Id:              tbTransfer.ID.String(),
+		DebitAccountId:  tbTransfer.DebitAccountID.String(),
+		CreditAccountId: tbTransfer.CreditAccountID.String(),
+		Amount:          lo.ToPtr(tbTransfer.Amount.BigInt()).Int64(),
+		PendingId:       lo.If[*string](pendingId == "", nil).Else(&pendingId),
+		UserData128:     tbTransfer.UserData128.String(),
+		UserData64:      tbTransfer.UserData64,
+		UserData32:      tbTransfer.UserData32,
+		Ledger:          tbTransfer.Ledger,
+		Code:            uint32(tbTransfer.Code),
+		TransferFlags:   pFlags,
+		Timestamp:       &tbTransfer.Timestamp,
+	}
+}
+
+// AccountFromProtoToTigerbeetle converts an account to be created.
+// The timestamp is only kept for imported accounts, as TigerBeetle rejects it otherwise.
+func AccountFromProtoToTigerbeetle(pAccount *proto.Account) (*types.Account, error) {
+	id, err := HexStringToUint128(pAccount.Id)
+	if err != nil {
+		return nil, err
+	}
+	userData128, err := types.HexStringToUint128(pAccount.UserData128)
+	if err != nil {
+		return nil, err
+	}
+	flags := types.AccountFlags{}
+	if pAccount.Flags != nil {
+		flags.Linked = lo.FromPtrOr(pAccount.Flags.Linked, false)
+		flags.DebitsMustNotExceedCredits = lo.FromPtrOr(pAccount.Flags.DebitsMustNotExceedCredits, false)
+		flags.CreditsMustNotExceedDebits = lo.FromPtrOr(pAccount.Flags.CreditsMustNotExceedDebits, false)
+		flags.History = lo.FromPtrOr(pAccount.Flags.History, false)
+		flags.Imported = lo.FromPtrOr(pAccount.Flags.Imported, false)
+		flags.Closed = lo.FromPtrOr(pAccount.Flags.Closed, false)
+	}
+	var timestamp uint64
+	if flags.Imported {
+		timestamp = pAccount.Timestamp
+	}
+	return &types.Account{
+		ID:             *id,
+		DebitsPending:  types.ToUint128(uint64(pAccount.DebitsPending)),
+		DebitsPosted:   types.ToUint128(uint64(pAccount.DebitsPosted)),
+		CreditsPending: types.ToUint128(uint64(pAccount.CreditsPending)),
+		CreditsPosted:  types.ToUint128(uint64(pAccount.CreditsPosted)),
+		UserData128:    userData128,
+		UserData64:     uint64(pAccount.UserData64),
+		UserData32:     uint32(pAccount.UserData32),
+		Ledger:         uint32(pAccount.Ledger),
+		Code:           uint16(pAccount.Code),
+		Flags:          flags.ToUint16(),
+		Timestamp:      timestamp,
+	}, nil
+}
+
+// TransferFromProtoToTigerbeetle converts a transfer to be created.
+// The timestamp is only kept for imported transfers, as TigerBeetle rejects it otherwise.
+func TransferFromProtoToTigerbeetle(pTransfer *proto.Transfer) (*types.Transfer, error) {
+	id, err := HexStringToUint128(pTransfer.Id)
+	if err != nil {
+		return nil, err
+	}
+	flags := types.TransferFlags{}
+	if pTransfer.TransferFlags != nil {
+		flags.Linked = lo.FromPtrOr(pTransfer.TransferFlags.Linked, false)
+		flags.Pending = lo.FromPtrOr(pTransfer.TransferFlags.Pending, false)
+		flags.PostPendingTransfer = lo.FromPtrOr(pTransfer.TransferFlags.PostPendingTransfer, false)
+		flags.VoidPendingTransfer = lo.FromPtrOr(pTransfer.TransferFlags.VoidPendingTransfer, false)
+		flags.BalancingDebit = lo.FromPtrOr(pTransfer.TransferFlags.BalancingDebit, false)
+		flags.BalancingCredit = lo.FromPtrOr(pTransfer.TransferFlags.BalancingCredit, false)
+		flags.Imported = lo.FromPtrOr(pTransfer.TransferFlags.Imported, false)
+		flags.ClosingDebit = lo.FromPtrOr(pTransfer.TransferFlags.ClosingDebit, false)
+		flags.ClosingCredit = lo.FromPtrOr(pTransfer.TransferFlags.ClosingCredit, false)
+	}
+	debitAccountID, err := HexStringToUint128(pTransfer.DebitAccountId)
+	if err != nil {
+		return nil, err
+	}
+	creditAccountID, err := HexStringToUint128(pTransfer.CreditAccountId)
+	if err != nil {
+		return nil, err
+	}
+	pendingID, err := HexStringToUint128(lo.FromPtrOr(pTransfer.PendingId, ""))
+	if err != nil {
+		return nil, err
+	}
+	userData128, err := types.HexStringToUint128(pTransfer.UserData128)
+	if err != nil {
+		return nil, err
+	}
+	var timestamp uint64
+	if flags.Imported {
+		timestamp = lo.FromPtrOr(pTransfer.Timestamp, 0)
+	}
+	return &types.Transfer{
+		ID:              *id,
+		DebitAccountID:  *debitAccountID,
+		CreditAccountID: *creditAccountID,
+		Amount:          types.ToUint128(uint64(pTransfer.Amount)),
+		PendingID:       *pendingID,
+		UserData128:     userData128,
+		UserData64:      uint64(pTransfer.UserData64),
+		UserData32:      uint32(pTransfer.UserData32),
+		Timeout:         0,
+		Ledger:          uint32(pTransfer.Ledger),
+		Code:            uint16(pTransfer.Code),
+		Flags:           flags.ToUint16(),
+		Timestamp:       timestamp,
+	}, nil
+}
+
+func AccountFilterFromProtoToTigerbeetle(pAccountFilter *proto.AccountFilter) (*types.AccountFilter, error) {
+	accountID, err := HexStringToUint128(pAccountFilter.AccountId)
+	if err != nil {
+		return nil, err
+	}
+
+	var tbFlags types.AccountFilterFlags
+	if pAccountFilter.Flags != nil {
+		tbFlags = types.AccountFilterFlags{
+			Debits:   lo.FromPtrOr(pAccountFilter.Flags.Debits, false),
+			Credits:  lo.FromPtrOr(pAccountFilter.Flags.Credits, false),
+			Reversed: lo.FromPtrOr(pAccountFilter.Flags.Reversed, false),
+		}
+	}
+
+	return &types.AccountFilter{
+		AccountID:    *accountID,
+		TimestampMin: uint64(lo.FromPtrOr(pAccountFilter.TimestampMin, 0)),
+		TimestampMax: uint64(lo.FromPtrOr(pAccountFilter.TimestampMax, 0)),
+		Limit:        uint32(pAccountFilter.Limit),
+		Flags:        tbFlags.ToUint32(),
+	}, nil
+}
+
+func AccountBalanceFromTigerbeetleToProto(tbBalance types.AccountBalance) *proto.AccountBalance {
+	return &proto.AccountBalance{
+		DebitsPending:  lo.ToPtr(tbBalance.DebitsPending.BigInt()).Uint64(),
+		DebitsPosted:   lo.ToPtr(tbBalance.DebitsPosted.BigInt()).Uint64(),
+		CreditsPending: lo.ToPtr(tbBalance.CreditsPending.BigInt()).Uint64(),
+		CreditsPosted:  lo.ToPtr(tbBalance.CreditsPosted.BigInt()).Uint64(),
+		Timestamp:      tbBalance.Timestamp,
+	}
+}
+
+func HexStringToUint128(hex string) (*types.Uint128, error) {
+	if hex == "" {
+		return &types.Uint128{0}, nil
+	}
+
+	res, err := types.HexStringToUint128(hex)
+	if err != nil {
+		slog.Error("hex string to Uint128 failed", "hex", hex, "error", err)
+		return nil, err
+	}
+	return &res, nil
+
+}
+
+func ResultsToReply(results []types.TransferEventResult, transfers []types.Transfer, err error) []*proto.CreateTransfersReplyItem {
+	replies := make([]*proto.CreateTransfersReplyItem, 0, len(results))
+	for _, r := range results {
+		replies = append(replies, &proto.CreateTransfersReplyItem{
+			Index:  int32(r.Index),
+			Result: proto.CreateTransferResult(r.Result),
+			Id:     transfers[r.Index].ID.String(),
+		})
+	}
+	return replies
+}
+
+func QueryFilterFromProtoToTigerbeetle(pFilter *proto.QueryFilter) (*types.QueryFilter, error) {
+	if pFilter == nil {
+		return nil, nil
+	}
+
+	var userData128 types.Uint128
+	if pFilter.UserData128 != nil && *pFilter.UserData128 != "" {
+		var err error
+		userData128, err = types.HexStringToUint128(*pFilter.UserData128)
+		if err != nil {
+			slog.Error("invalid UserData128 hex string", "hex", *pFilter.UserData128, "error", err)
+			return nil, err
+		}
+	}
+
+	var flags types.QueryFilterFlags
+	if pFilter.Flags != nil {
+		flags = types.QueryFilterFlags{
+			Reversed: lo.FromPtrOr(pFilter.Flags.Reversed, false),
+		}
+	}
+
+	return &types.QueryFilter{
+		UserData128:  userData128,
+		UserData64:   lo.FromPtrOr(pFilter.UserData64, 0),
+		UserData32:   lo.FromPtrOr(pFilter.UserData32, 0),
+		Code:         uint16(lo.FromPtrOr(pFilter.Code, 0)),
+		Ledger:       lo.FromPtrOr(pFilter.Ledger, 0),
+		TimestampMin: lo.FromPtrOr(pFilter.TimestampMin, 0),
+		TimestampMax: lo.FromPtrOr(pFilter.TimestampMax, 0),
+		Limit:        pFilter.Limit,
+		Flags:        flags.ToUint32(),
+	}, nil
+}
@@ -0,0 +1,100 @@
+// Package decimal converts between integer amounts in minor units and decimal strings
+// with a fixed asset scale, without floating point.
+package decimal
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/tigerbeetle/tigerbeetle-go/pkg/types"
+)
+
+var (
+	ErrInvalid         = errors.New("invalid decimal")
+	ErrExcessPrecision = errors.New("decimal has more digits after the point than the asset scale")
+	ErrOverflow        = errors.New("decimal does not fit in 128 bits")
+)
+
+// Parse converts a non-negative decimal such as "12.34" to minor units, 1234 with scale 2.
+// Digits beyond the scale are rejected, even when they are zeros.
+func Parse(s string, scale uint32) (*big.Int, error) {
+	whole, frac, hasPoint := strings.Cut(s, ".")
+	if whole == "" || (hasPoint && frac == "") || !digits(whole) || !digits(frac) {
+		return nil, fmt.Errorf("%w %q", ErrInvalid, s)
+	}
+	if uint32(len(frac)) > scale {
+		return nil, fmt.Errorf("%w %q, scale %d", ErrExcessPrecision, s, scale)
+	}
+	v, _ := new(big.Int).SetString(whole+frac+strings.Repeat("0", int(scale)-len(frac)), 10)
+	return v, nil
+}
+
+// ParseUint128 parses like Parse and checks the result fits in a u128 amount.
+func ParseUint128(s string, scale uint32) (types.Uint128, error) {
+	v, err := Parse(s, scale)
+	if err != nil {
+		return types.Uint128{}, err
+	}
+	if v.BitLen() > 128 {
+		return types.Uint128{}, fmt.Errorf("%w %q", ErrOverflow, s)
+	}
+	return types.BigIntToUint128(*v), nil
+}
+
+// ParseRat parses a non-negative decimal exactly, with as many digits after the point as given.
+func ParseRat(s string) (*big.Rat, error) {
+	_, frac, _ := strings.Cut(s, ".")
+	v, err := Parse(s, uint32(len(frac)))
+	if err != nil {
+		return nil, err
+	}
+	return new(big.Rat).SetFrac(v, pow10(uint32(len(frac)))), nil
+}
+
+// Convert multiplies minor units with fromScale by rate, returning minor units with toScale.
+// The result is rounded down.
+func Convert(v *big.Int, rate *big.Rat, fromScale, toScale uint32) *big.Int {
+	r := new(big.Rat).SetInt(v)
+	r.Mul(r, rate)
+	r.Mul(r, new(big.Rat).SetFrac(pow10(toScale), pow10(fromScale)))
+	return new(big.Int).Quo(r.Num(), r.Denom())
+}
+
+// pow10 returns 10^n.
+func pow10(n uint32) *big.Int {
+	return new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(n)), nil)
+}
+
+func digits(s string) bool {
+	for _, c := range s {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// Format writes minor units as a decimal with exactly scale digits after the point.
+func Format(v *big.Int, scale uint32) string {
+	s := new(big.Int).Abs(v).String()
+	sign := ""
+	if v.Sign() < 0 {
+		sign = "-"
+	}
+	if scale == 0 {
+		return sign + s
+	}
+	if len(s) <= int(scale) {
+		s = strings.Repeat("0", int(scale)-len(s)+1) + s
+	}
+	point := len(s) - int(scale)
+	return sign + s[:point] + "." + s[point:]
+}
+
+// FormatUint128 formats a u128 amount.
+func FormatUint128(v types.Uint128, scale uint32) string {
+	b := v.BigInt()
+	return Format(&b, scale)
+}
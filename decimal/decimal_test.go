@@ -0,0 +1,77 @@
+package decimal
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tigerbeetle/tigerbeetle-go/pkg/types"
+)
+
+func TestParse(t *testing.T) {
+	for _, tc := range []struct {
+		in    string
+		scale uint32
+		out   string
+	}{
+		{"12.34", 2, "1234"},
+		{"12.3", 2, "1230"},
+		{"12", 2, "1200"},
+		{"0.05", 2, "5"},
+		{"007", 0, "7"},
+		{"340282366920938463463374607431768211455", 0, "340282366920938463463374607431768211455"},
+	} {
+		v, err := Parse(tc.in, tc.scale)
+		require.NoError(t, err, tc.in)
+		assert.Equal(t, tc.out, v.String(), tc.in)
+	}
+
+	for _, in := range []string{"", ".5", "5.", "-1", "+1", "1e3", "1,5", "1.2.3", " 1"} {
+		_, err := Parse(in, 2)
+		assert.ErrorIs(t, err, ErrInvalid, in)
+	}
+	_, err := Parse("12.345", 2)
+	assert.ErrorIs(t, err, ErrExcessPrecision)
+	_, err = Parse("12.340", 2)
+	assert.ErrorIs(t, err, ErrExcessPrecision)
+	_, err = Parse("1.0", 0)
+	assert.ErrorIs(t, err, ErrExcessPrecision)
+
+	_, err = ParseUint128("340282366920938463463374607431768211456", 0)
+	assert.ErrorIs(t, err, ErrOverflow)
+	v, err := ParseUint128("1.5", 3)
+	require.NoError(t, err)
+	assert.Equal(t, types.ToUint128(1500), v)
+}
+
+func TestParseRat(t *testing.T) {
+	v, err := ParseRat("1.0850")
+	require.NoError(t, err)
+	assert.Equal(t, "217/200", v.String())
+	_, err = ParseRat("1.")
+	assert.ErrorIs(t, err, ErrInvalid)
+
+	assert.Equal(t, "10850", Convert(big.NewInt(10000), v, 2, 2).String())
+	assert.Equal(t, "108", Convert(big.NewInt(10000), v, 2, 0).String())
+	assert.Equal(t, "1085000", Convert(big.NewInt(100), v, 0, 4).String())
+}
+
+func TestFormat(t *testing.T) {
+	for _, tc := range []struct {
+		in    int64
+		scale uint32
+		out   string
+	}{
+		{1234, 2, "12.34"},
+		{5, 2, "0.05"},
+		{0, 2, "0.00"},
+		{100, 2, "1.00"},
+		{-5, 2, "-0.05"},
+		{-1234, 0, "-1234"},
+		{7, 0, "7"},
+	} {
+		assert.Equal(t, tc.out, Format(big.NewInt(tc.in), tc.scale))
+	}
+	assert.Equal(t, "12.34", FormatUint128(types.ToUint128(1234), 2))
+}
@@ -0,0 +1,169 @@
+package grpc
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"math/big"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/lil5/tigerbeetle_api/config"
+	"github.com/lil5/tigerbeetle_api/proto"
+	"github.com/samber/lo"
+	"github.com/tigerbeetle/tigerbeetle-go/pkg/types"
+)
+
+// errStopScan ends a paged scan early without it being reported as an error.
+var errStopScan = errors.New("stop scan")
+
+type aggregateKey struct {
+	Code       uint16
+	Ledger     uint32
+	UserData32 uint32
+	Bucket     uint64
+}
+
+type aggregateGroup struct {
+	Count uint64
+	Sum   big.Int
+}
+
+func bucketSize(bucket proto.AggregateBucket) uint64 {
+	switch bucket {
+	case proto.AggregateBucket_AggregateBucketHour:
+		return uint64(time.Hour)
+	case proto.AggregateBucket_AggregateBucketDay:
+		return uint64(24 * time.Hour)
+	}
+	return 0
+}
+
+// AggregateTransfers sums and counts the amounts moved by the transfers matching the filter,
+// pending and voiding transfers are left out.
+// The scan is capped by AGGREGATE_MAX_SCAN and AGGREGATE_MAX_GROUPS,
+// when it is cut short the reply is truncated and last_timestamp tells where to resume.
+func (s *App) AggregateTransfers(ctx context.Context, in *proto.AggregateTransfersRequest) (*proto.AggregateTransfersReply, error) {
+	if in.Filter == nil {
+		return nil, errors.New("filter is required")
+	}
+	if in.Filter.Flags != nil && lo.FromPtrOr(in.Filter.Flags.Reversed, false) {
+		return nil, errors.New("reversed is not supported for aggregation")
+	}
+	tbFilter, err := QueryFilterFromProtoToTigerbeetle(in.Filter)
+	if err != nil {
+		if in.Filter.UserData128 != nil && strings.Contains(err.Error(), "hex") {
+			return nil, errors.New("invalid UserData128: " + err.Error())
+		}
+		return nil, err
+	}
+
+	r := restriction(ctx)
+	if err := restrictQueryFilter(r, tbFilter); err != nil {
+		return nil, err
+	}
+
+	maxScan := config.Config.AggregateMaxScan
+	if in.MaxScan != 0 && in.MaxScan < maxScan {
+		maxScan = in.MaxScan
+	}
+	maxGroups := config.Config.AggregateMaxGroups
+	if in.Limit != 0 && in.Limit < maxGroups {
+		maxGroups = in.Limit
+	}
+	groupBy := lo.SliceToMap(in.GroupBy, func(g proto.GroupBy) (proto.GroupBy, bool) { return g, true })
+	size := bucketSize(in.Bucket)
+
+	groups := map[aggregateKey]*aggregateGroup{}
+	var scanned, lastTimestamp uint64
+	truncated := false
+	// One transfer past max_scan tells a complete scan from a truncated one.
+	err = s.eachQueryTransfers(ctx, *tbFilter, maxScan+1, func(transfers []types.Transfer) error {
+		for _, t := range transfers {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+			if scanned >= maxScan {
+				truncated = true
+				return errStopScan
+			}
+			// Pending and voiding transfers move nothing, the post of a pending transfer is summed with the amount it posted.
+			// They still count towards the scan, as do hidden transfers.
+			if flags := t.TransferFlags(); !r.SeesTransfer(t) || flags.Pending || flags.VoidPendingTransfer {
+				scanned++
+				lastTimestamp = t.Timestamp
+				continue
+			}
+
+			key := aggregateKey{}
+			if groupBy[proto.GroupBy_GroupByCode] {
+				key.Code = t.Code
+			}
+			if groupBy[proto.GroupBy_GroupByLedger] {
+				key.Ledger = t.Ledger
+			}
+			if groupBy[proto.GroupBy_GroupByUserData32] {
+				key.UserData32 = t.UserData32
+			}
+			if size > 0 {
+				key.Bucket = t.Timestamp - t.Timestamp%size
+			}
+			group, ok := groups[key]
+			if !ok {
+				if uint32(len(groups)) >= maxGroups {
+					truncated = true
+					return errStopScan
+				}
+				group = &aggregateGroup{}
+				groups[key] = group
+			}
+			group.Count++
+			addUint128(&group.Sum, t.Amount)
+			scanned++
+			lastTimestamp = t.Timestamp
+		}
+		return nil
+	})
+	if err != nil && err != errStopScan {
+		return nil, err
+	}
+
+	keys := lo.Keys(groups)
+	slices.SortFunc(keys, func(a, b aggregateKey) int {
+		return cmp.Or(
+			cmp.Compare(a.Bucket, b.Bucket),
+			cmp.Compare(a.Ledger, b.Ledger),
+			cmp.Compare(a.Code, b.Code),
+			cmp.Compare(a.UserData32, b.UserData32),
+		)
+	})
+	pGroups := make([]*proto.AggregateTransfersGroup, 0, len(keys))
+	for _, key := range keys {
+		group := groups[key]
+		pGroup := &proto.AggregateTransfersGroup{
+			Count: group.Count,
+			Sum:   group.Sum.String(),
+		}
+		if groupBy[proto.GroupBy_GroupByCode] {
+			pGroup.Code = lo.ToPtr(uint32(key.Code))
+		}
+		if groupBy[proto.GroupBy_GroupByLedger] {
+			pGroup.Ledger = lo.ToPtr(key.Ledger)
+		}
+		if groupBy[proto.GroupBy_GroupByUserData32] {
+			pGroup.UserData32 = lo.ToPtr(key.UserData32)
+		}
+		if size > 0 {
+			pGroup.Bucket = lo.ToPtr(key.Bucket)
+		}
+		pGroups = append(pGroups, pGroup)
+	}
+
+	return &proto.AggregateTransfersReply{
+		Groups:        pGroups,
+		Scanned:       scanned,
+		Truncated:     truncated,
+		LastTimestamp: lastTimestamp,
+	}, nil
+}
@@ -0,0 +1,116 @@
+package grpc
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/lil5/tigerbeetle_api/config"
+	"github.com/lil5/tigerbeetle_api/proto"
+	"github.com/samber/lo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/tigerbeetle/tigerbeetle-go/pkg/types"
+)
+
+func TestAggregateTransfers(t *testing.T) {
+	mockClient := new(MockTigerBeetleClient)
+	app := &App{TB: mockClient}
+
+	config.Config.AggregateMaxScan = 1_000
+	config.Config.AggregateMaxGroups = 1_000
+
+	hour := uint64(time.Hour)
+	transfers := []types.Transfer{
+		{ID: types.ToUint128(1), Code: 1, Ledger: 1, Amount: types.ToUint128(10), Timestamp: 1},
+		{ID: types.ToUint128(2), Code: 2, Ledger: 1, Amount: types.ToUint128(20), Timestamp: 2},
+		{ID: types.ToUint128(3), Code: 1, Ledger: 1, Amount: types.ToUint128(30), Timestamp: hour + 3},
+	}
+
+	t.Run("should return error when filter is nil", func(t *testing.T) {
+		_, err := app.AggregateTransfers(context.Background(), &proto.AggregateTransfersRequest{})
+		assert.Error(t, err)
+	})
+
+	t.Run("should return error when reversed", func(t *testing.T) {
+		_, err := app.AggregateTransfers(context.Background(), &proto.AggregateTransfersRequest{
+			Filter: &proto.QueryFilter{Flags: &proto.QueryFilterFlags{Reversed: lo.ToPtr(true)}},
+		})
+		assert.Error(t, err)
+	})
+
+	t.Run("should group by code and hour", func(t *testing.T) {
+		mockClient.On("QueryTransfers", mock.MatchedBy(func(f types.QueryFilter) bool {
+			return f.Ledger == 1
+		})).Return(transfers, nil).Once()
+
+		resp, err := app.AggregateTransfers(context.Background(), &proto.AggregateTransfersRequest{
+			Filter:  &proto.QueryFilter{Ledger: lo.ToPtr(uint32(1))},
+			GroupBy: []proto.GroupBy{proto.GroupBy_GroupByCode},
+			Bucket:  proto.AggregateBucket_AggregateBucketHour,
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, uint64(3), resp.Scanned)
+		assert.False(t, resp.Truncated)
+		assert.Len(t, resp.Groups, 3)
+		assert.Equal(t, uint64(0), *resp.Groups[0].Bucket)
+		assert.Equal(t, uint32(1), *resp.Groups[0].Code)
+		assert.Equal(t, "10", resp.Groups[0].Sum)
+		assert.Equal(t, hour, *resp.Groups[2].Bucket)
+		assert.Nil(t, resp.Groups[0].Ledger)
+		mockClient.AssertExpectations(t)
+	})
+
+	t.Run("should stop at max scan", func(t *testing.T) {
+		// The page is not larger than needed to find out the scan is truncated
+		mockClient.On("QueryTransfers", mock.MatchedBy(func(f types.QueryFilter) bool {
+			return f.Limit == 3
+		})).Return(transfers, nil).Once()
+
+		resp, err := app.AggregateTransfers(context.Background(), &proto.AggregateTransfersRequest{
+			Filter:  &proto.QueryFilter{},
+			MaxScan: 2,
+		})
+		assert.NoError(t, err)
+		assert.True(t, resp.Truncated)
+		assert.Equal(t, uint64(2), resp.Scanned)
+		assert.Equal(t, uint64(2), resp.LastTimestamp)
+		assert.Len(t, resp.Groups, 1)
+		assert.Equal(t, "30", resp.Groups[0].Sum)
+		mockClient.AssertExpectations(t)
+	})
+
+	t.Run("should stop at group limit", func(t *testing.T) {
+		mockClient.On("QueryTransfers", mock.AnythingOfType("types.QueryFilter")).Return(transfers, nil).Once()
+
+		resp, err := app.AggregateTransfers(context.Background(), &proto.AggregateTransfersRequest{
+			Filter:  &proto.QueryFilter{},
+			GroupBy: []proto.GroupBy{proto.GroupBy_GroupByCode},
+			Limit:   1,
+		})
+		assert.NoError(t, err)
+		assert.True(t, resp.Truncated)
+		assert.Len(t, resp.Groups, 1)
+		assert.Equal(t, uint64(1), resp.LastTimestamp)
+		mockClient.AssertExpectations(t)
+	})
+
+	t.Run("should sum posted amounts only", func(t *testing.T) {
+		mockClient.On("QueryTransfers", mock.AnythingOfType("types.QueryFilter")).Return([]types.Transfer{
+			{ID: types.ToUint128(10), Code: 1, Amount: types.ToUint128(100), Flags: types.TransferFlags{Pending: true}.ToUint16(), Timestamp: 1},
+			{ID: types.ToUint128(11), Code: 1, Amount: types.ToUint128(60), PendingID: types.ToUint128(10), Flags: types.TransferFlags{PostPendingTransfer: true}.ToUint16(), Timestamp: 2},
+			{ID: types.ToUint128(12), Code: 1, Amount: types.ToUint128(50), Flags: types.TransferFlags{Pending: true}.ToUint16(), Timestamp: 3},
+			{ID: types.ToUint128(13), Code: 1, Amount: types.ToUint128(50), PendingID: types.ToUint128(12), Flags: types.TransferFlags{VoidPendingTransfer: true}.ToUint16(), Timestamp: 4},
+			{ID: types.ToUint128(14), Code: 1, Amount: types.ToUint128(5), Timestamp: 5},
+		}, nil).Once()
+
+		resp, err := app.AggregateTransfers(context.Background(), &proto.AggregateTransfersRequest{Filter: &proto.QueryFilter{}})
+		assert.NoError(t, err)
+		assert.Equal(t, uint64(5), resp.Scanned)
+		assert.Equal(t, uint64(5), resp.LastTimestamp)
+		assert.Len(t, resp.Groups, 1)
+		assert.Equal(t, uint64(2), resp.Groups[0].Count)
+		assert.Equal(t, "65", resp.Groups[0].Sum)
+		mockClient.AssertExpectations(t)
+	})
+}
@@ -0,0 +1,99 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/lil5/tigerbeetle_api/alias"
+	"github.com/lil5/tigerbeetle_api/proto"
+	"github.com/tigerbeetle/tigerbeetle-go/pkg/types"
+)
+
+var ErrAliasesDisabled = errors.New("aliases are disabled, ALIAS_MODE is not set")
+
+// resolveAlias replaces an alias in an account id field, such as "alias:user:123:EUR",
+// by the hex id it refers to. Fields without alias.Prefix are left as is.
+func (s *App) resolveAlias(id *string) error {
+	a, ok := alias.Cut(*id)
+	if !ok {
+		return nil
+	}
+	if s.Aliases == nil {
+		return ErrAliasesDisabled
+	}
+	v, err := s.Aliases.Resolve(a)
+	if err != nil {
+		return fmt.Errorf("%s: %w", *id, err)
+	}
+	*id = v.String()
+	return nil
+}
+
+// accountID parses an account id that may be an alias.
+func (s *App) accountID(id string) (*types.Uint128, error) {
+	if err := s.resolveAlias(&id); err != nil {
+		return nil, err
+	}
+	return HexStringToUint128(id)
+}
+
+// resolveTransferAliases resolves the debit and credit account of each transfer.
+func (s *App) resolveTransferAliases(transfers []*proto.Transfer) error {
+	for _, t := range transfers {
+		if err := s.resolveAlias(&t.DebitAccountId); err != nil {
+			return err
+		}
+		if err := s.resolveAlias(&t.CreditAccountId); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *App) RegisterAlias(ctx context.Context, in *proto.RegisterAliasRequest) (*proto.RegisterAliasReply, error) {
+	if s.Aliases == nil {
+		return nil, ErrAliasesDisabled
+	}
+	var id types.Uint128
+	if in.Id != nil {
+		v, err := HexStringToUint128(*in.Id)
+		if err != nil {
+			return nil, err
+		}
+		id = *v
+	}
+	if r := restriction(ctx); r != nil && (id == (types.Uint128{}) || !r.AllowsAccountID(id)) {
+		return nil, errForbidden("a caller limited to accounts must register an alias to one of its accounts")
+	}
+	id, err := s.Aliases.Register(in.Alias, id)
+	if err != nil {
+		return nil, err
+	}
+	return &proto.RegisterAliasReply{Alias: &proto.Alias{Alias: in.Alias, Id: id.String()}}, nil
+}
+
+func (s *App) LookupAliases(ctx context.Context, in *proto.LookupAliasesRequest) (*proto.LookupAliasesReply, error) {
+	if s.Aliases == nil {
+		return nil, ErrAliasesDisabled
+	}
+	if err := checkItems("aliases", len(in.Aliases)); err != nil {
+		return nil, err
+	}
+	r := restriction(ctx)
+	res := []*proto.Alias{}
+	for _, a := range in.Aliases {
+		id, err := s.Aliases.Resolve(a)
+		if errors.Is(err, alias.ErrUnknownAlias) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", a, err)
+		}
+		if !r.AllowsAccountID(id) {
+			continue
+		}
+		res = append(res, &proto.Alias{Alias: a, Id: id.String()})
+	}
+	return &proto.LookupAliasesReply{Aliases: res}, nil
+}
@@ -0,0 +1,78 @@
+package grpc
+
+import (
+	"context"
+	"testing"
+
+	"github.com/lil5/tigerbeetle_api/alias"
+	"github.com/lil5/tigerbeetle_api/config"
+	"github.com/lil5/tigerbeetle_api/proto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/tigerbeetle/tigerbeetle-go/pkg/types"
+)
+
+func TestAliases(t *testing.T) {
+	config.Config.IsBuffered = false
+	config.Config.IsDryRun = false
+	aliases, err := alias.New(alias.ModeHash, "", "test")
+	require.NoError(t, err)
+	wallet, _ := aliases.Resolve("user:1:EUR")
+
+	t.Run("should resolve debit and credit aliases", func(t *testing.T) {
+		mockClient := new(MockTigerBeetleClient)
+		app := &App{TB: mockClient, Aliases: aliases}
+		mockClient.On("CreateTransfers", mock.MatchedBy(func(transfers []types.Transfer) bool {
+			return transfers[0].DebitAccountID == wallet && transfers[0].CreditAccountID == types.ToUint128(0x20)
+		})).Return([]types.TransferEventResult{}, nil)
+
+		_, err := app.CreateTransfers(context.Background(), &proto.CreateTransfersRequest{Transfers: []*proto.Transfer{
+			{Id: "1", DebitAccountId: "alias:user:1:EUR", CreditAccountId: "20", Amount: 1, Ledger: 1, Code: 1},
+		}})
+		require.NoError(t, err)
+		mockClient.AssertExpectations(t)
+	})
+
+	t.Run("should resolve account ids", func(t *testing.T) {
+		mockClient := new(MockTigerBeetleClient)
+		app := &App{TB: mockClient, Aliases: aliases}
+		mockClient.On("LookupAccounts", []types.Uint128{wallet}).Return([]types.Account{}, nil)
+
+		_, err := app.LookupAccounts(context.Background(), &proto.LookupAccountsRequest{AccountIds: []string{"alias:user:1:EUR"}})
+		require.NoError(t, err)
+		mockClient.AssertExpectations(t)
+	})
+
+	t.Run("should not resolve aliases while disabled", func(t *testing.T) {
+		app := &App{TB: new(MockTigerBeetleClient)}
+		_, err := app.LookupAccounts(context.Background(), &proto.LookupAccountsRequest{AccountIds: []string{"alias:user:1:EUR"}})
+		assert.ErrorIs(t, err, ErrAliasesDisabled)
+
+		_, err = app.RegisterAlias(context.Background(), &proto.RegisterAliasRequest{Alias: "user:1:EUR"})
+		assert.ErrorIs(t, err, ErrAliasesDisabled)
+	})
+
+	t.Run("should take hex looking values without the prefix as ids", func(t *testing.T) {
+		cafe, _ := aliases.Resolve("cafe")
+		mockClient := new(MockTigerBeetleClient)
+		app := &App{TB: mockClient, Aliases: aliases}
+		mockClient.On("LookupAccounts", []types.Uint128{types.ToUint128(0xcafe), cafe}).Return([]types.Account{}, nil)
+
+		_, err := app.LookupAccounts(context.Background(), &proto.LookupAccountsRequest{AccountIds: []string{"cafe", "alias:cafe"}})
+		require.NoError(t, err)
+		mockClient.AssertExpectations(t)
+	})
+
+	t.Run("should register and look up aliases", func(t *testing.T) {
+		app := &App{Aliases: aliases}
+		reply, err := app.RegisterAlias(context.Background(), &proto.RegisterAliasRequest{Alias: "user:1:EUR"})
+		require.NoError(t, err)
+		assert.Equal(t, wallet.String(), reply.Alias.Id)
+
+		lookup, err := app.LookupAliases(context.Background(), &proto.LookupAliasesRequest{Aliases: []string{"user:1:EUR"}})
+		require.NoError(t, err)
+		require.Len(t, lookup.Aliases, 1)
+		assert.Equal(t, wallet.String(), lookup.Aliases[0].Id)
+	})
+}
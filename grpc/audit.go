@@ -0,0 +1,100 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"time"
+
+	"github.com/lil5/tigerbeetle_api/audit"
+	"github.com/lil5/tigerbeetle_api/auth"
+	"github.com/lil5/tigerbeetle_api/config"
+	"github.com/lil5/tigerbeetle_api/metrics"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	protobuf "google.golang.org/protobuf/proto"
+)
+
+// OpenAudit opens the audit log of the config, nil when AUDIT_DIR is not set.
+func OpenAudit() (*audit.Log, error) {
+	if config.Config.AuditDir == "" {
+		return nil, nil
+	}
+	return audit.Open(config.Config.AuditDir, config.Config.AuditMaxSize)
+}
+
+// ErrAuditFailed is the error of a request whose audit entry could not be written, the request itself may have run.
+var ErrAuditFailed = errors.New("the audit entry of the request could not be written, it may have been applied")
+
+// AppendAudit writes e to l, a failure is logged and counted.
+// Unless AUDIT_FAIL_OPEN is set the failure is returned as ErrAuditFailed, for the request to fail with it.
+func AppendAudit(l *audit.Log, e audit.Entry) error {
+	err := l.Append(e)
+	if err == nil {
+		return nil
+	}
+	metrics.TotalAuditErrors.Inc()
+	slog.Error("unable to write audit entry", "method", e.Method, "err", err)
+	if config.Config.AuditFailOpen {
+		return nil
+	}
+	return ErrAuditFailed
+}
+
+// isWrite reports if method changes the cluster and is audited.
+func isWrite(method string) bool {
+	scope, ok := MethodScopes[method]
+	return ok && scope != auth.ScopeRead
+}
+
+// UnaryAuditInterceptor writes every write method to l,
+// it must run after UnaryAuthInterceptor to see the api key.
+func UnaryAuditInterceptor(l *audit.Log) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		if !isWrite(info.FullMethod) {
+			return handler(ctx, req)
+		}
+		start := time.Now()
+		// Handlers fill in aliases, idempotency keys and inherited fields, the request is kept as sent.
+		request := marshalAudit(req)
+		resp, err := handler(ctx, req)
+		e := audit.Entry{
+			Time:       start.UTC(),
+			ClientCert: peerIdentity(ctx),
+			IP:         peerIP(ctx),
+			Method:     info.FullMethod,
+			Request:    request,
+			Status:     status.Code(err).String(),
+			LatencyNs:  time.Since(start).Nanoseconds(),
+		}
+		if key := auth.FromContext(ctx); key != nil {
+			e.Key = key.Name
+		}
+		if key := requestIdempotencyKey(ctx, nil); key != nil {
+			e.IdempotencyKey = *key
+		}
+		if err != nil {
+			e.Error = status.Convert(err).Message()
+		} else {
+			e.Response = marshalAudit(resp)
+		}
+		if auditErr := AppendAudit(l, e); auditErr != nil {
+			return nil, status.Error(codes.Internal, auditErr.Error())
+		}
+		return resp, err
+	}
+}
+
+func marshalAudit(m any) []byte {
+	msg, ok := m.(protobuf.Message)
+	if !ok || msg == nil {
+		return nil
+	}
+	b, err := protojson.Marshal(msg)
+	if err != nil {
+		return nil
+	}
+	return audit.RawJSON(b)
+}
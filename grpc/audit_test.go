@@ -0,0 +1,87 @@
+package grpc
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/lil5/tigerbeetle_api/audit"
+	"github.com/lil5/tigerbeetle_api/auth"
+	"github.com/lil5/tigerbeetle_api/config"
+	"github.com/lil5/tigerbeetle_api/proto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestUnaryAuditInterceptor(t *testing.T) {
+	dir := t.TempDir()
+	l, err := audit.Open(dir, 0)
+	require.NoError(t, err)
+	interceptor := UnaryAuditInterceptor(l)
+	ctx := auth.WithKey(context.Background(), &auth.Key{Name: "backend"})
+	handler := func(ctx context.Context, req any) (any, error) {
+		return &proto.CreateTransfersReply{}, nil
+	}
+
+	_, err = interceptor(ctx, &proto.CreateTransfersRequest{Transfers: []*proto.Transfer{{Id: "1", Amount: 10}}},
+		&grpc.UnaryServerInfo{FullMethod: proto.TigerBeetle_CreateTransfers_FullMethodName}, handler)
+	require.NoError(t, err)
+	_, err = interceptor(ctx, &proto.LookupAccountsRequest{AccountIds: []string{"1"}},
+		&grpc.UnaryServerInfo{FullMethod: proto.TigerBeetle_LookupAccounts_FullMethodName}, handler)
+	require.NoError(t, err)
+	require.NoError(t, l.Close())
+
+	r, err := audit.Verify(dir)
+	require.NoError(t, err)
+	assert.EqualValues(t, 1, r.Entries, "should only audit writes")
+}
+
+func TestUnaryAuditInterceptorRequestAsSent(t *testing.T) {
+	dir := t.TempDir()
+	l, err := audit.Open(dir, 0)
+	require.NoError(t, err)
+	handler := func(ctx context.Context, req any) (any, error) {
+		req.(*proto.CreateTransfersRequest).Transfers[0].DebitAccountId = "rewritten"
+		return &proto.CreateTransfersReply{}, nil
+	}
+	_, err = UnaryAuditInterceptor(l)(context.Background(), &proto.CreateTransfersRequest{Transfers: []*proto.Transfer{{Id: "1", DebitAccountId: "@alice"}}},
+		&grpc.UnaryServerInfo{FullMethod: proto.TigerBeetle_CreateTransfers_FullMethodName}, handler)
+	require.NoError(t, err)
+	require.NoError(t, l.Close())
+
+	b, err := os.ReadFile(filepath.Join(dir, "audit-000001.log"))
+	require.NoError(t, err)
+	var e audit.Entry
+	require.NoError(t, json.Unmarshal(b, &e))
+	assert.Contains(t, string(e.Request), "@alice")
+	assert.NotContains(t, string(e.Request), "rewritten")
+}
+
+func TestUnaryAuditInterceptorFailClosed(t *testing.T) {
+	l, err := audit.Open(t.TempDir(), 0)
+	require.NoError(t, err)
+	// A closed log fails every write
+	require.NoError(t, l.Close())
+	handler := func(ctx context.Context, req any) (any, error) {
+		return &proto.CreateTransfersReply{}, nil
+	}
+	call := func() (any, error) {
+		return UnaryAuditInterceptor(l)(context.Background(), &proto.CreateTransfersRequest{},
+			&grpc.UnaryServerInfo{FullMethod: proto.TigerBeetle_CreateTransfers_FullMethodName}, handler)
+	}
+
+	resp, err := call()
+	assert.Nil(t, resp)
+	assert.Equal(t, codes.Internal, status.Code(err))
+
+	config.Config.AuditFailOpen = true
+	t.Cleanup(func() { config.Config.AuditFailOpen = false })
+	resp, err = call()
+	assert.NoError(t, err)
+	assert.NotNil(t, resp)
+}
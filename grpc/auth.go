@@ -0,0 +1,148 @@
+package grpc
+
+import (
+	"context"
+
+	"github.com/lil5/tigerbeetle_api/auth"
+	"github.com/lil5/tigerbeetle_api/certs"
+	"github.com/lil5/tigerbeetle_api/config"
+	"github.com/lil5/tigerbeetle_api/proto"
+	"github.com/samber/lo"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+// MethodScopes is the scope an api key needs for each method,
+// methods not listed here need admin.
+var MethodScopes = map[string]auth.Scope{
+	proto.TigerBeetle_GetID_FullMethodName:               auth.ScopeRead,
+	proto.TigerBeetle_LookupAccounts_FullMethodName:      auth.ScopeRead,
+	proto.TigerBeetle_LookupTransfers_FullMethodName:     auth.ScopeRead,
+	proto.TigerBeetle_GetAccountTransfers_FullMethodName: auth.ScopeRead,
+	proto.TigerBeetle_GetAccountBalances_FullMethodName:  auth.ScopeRead,
+	proto.TigerBeetle_QueryTransfers_FullMethodName:      auth.ScopeRead,
+	proto.TigerBeetle_QueryAccounts_FullMethodName:       auth.ScopeRead,
+	proto.TigerBeetle_GetBalancesAt_FullMethodName:       auth.ScopeRead,
+	proto.TigerBeetle_LedgerSummary_FullMethodName:       auth.ScopeRead,
+	proto.TigerBeetle_GetAccountStatement_FullMethodName: auth.ScopeRead,
+	proto.TigerBeetle_AggregateTransfers_FullMethodName:  auth.ScopeRead,
+	proto.TigerBeetle_LookupAliases_FullMethodName:       auth.ScopeRead,
+	proto.TigerBeetle_ListLedgers_FullMethodName:         auth.ScopeRead,
+
+	proto.TigerBeetle_CreateAccounts_FullMethodName: auth.ScopeWriteAccounts,
+	proto.TigerBeetle_RegisterAlias_FullMethodName:  auth.ScopeWriteAccounts,
+	proto.TigerBeetle_CloseAccount_FullMethodName:   auth.ScopeWriteAccounts,
+	proto.TigerBeetle_ReopenAccount_FullMethodName:  auth.ScopeWriteAccounts,
+
+	proto.TigerBeetle_CreateTransfers_FullMethodName:        auth.ScopeWriteTransfers,
+	proto.TigerBeetle_CreateCompoundTransfer_FullMethodName: auth.ScopeWriteTransfers,
+	proto.TigerBeetle_Exchange_FullMethodName:               auth.ScopeWriteTransfers,
+
+	proto.TigerBeetle_ExportLedger_FullMethodName:  auth.ScopeAdmin,
+	proto.TigerBeetle_RestoreLedger_FullMethodName: auth.ScopeAdmin,
+}
+
+func methodScope(method string) auth.Scope {
+	if scope, ok := MethodScopes[method]; ok {
+		return scope
+	}
+	return auth.ScopeAdmin
+}
+
+// publicMethods need no api key, so health probes keep working.
+var publicMethods = map[string]bool{
+	healthpb.Health_Check_FullMethodName: true,
+	healthpb.Health_Watch_FullMethodName: true,
+}
+
+// LoadAuth loads the api keys and the jwt verifier of the config, nil when neither is set.
+func LoadAuth() (*auth.Keys, error) {
+	keys, err := auth.New(config.Config.ApiKeysFile, config.Config.ApiKeys)
+	if err != nil {
+		return nil, err
+	}
+	if config.Config.JwksFile == "" && config.Config.JwksUrl == "" {
+		return keys, nil
+	}
+	verifier, err := auth.NewVerifier(auth.JWTOptions{
+		JWKSFile: config.Config.JwksFile,
+		JWKSURL:  config.Config.JwksUrl,
+		CacheTTL: config.Config.JwksCacheTTL,
+		Issuer:   config.Config.JwtIssuer,
+		Audience: config.Config.JwtAudience,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if keys == nil {
+		keys = &auth.Keys{}
+	}
+	keys.JWT = verifier
+	return keys, nil
+}
+
+// peerIdentity returns the common name of the client certificate of a mutual TLS connection.
+func peerIdentity(ctx context.Context) string {
+	p, ok := peer.FromContext(ctx)
+	if !ok {
+		return ""
+	}
+	info, ok := p.AuthInfo.(credentials.TLSInfo)
+	if !ok {
+		return ""
+	}
+	return certs.Identity(&info.State)
+}
+
+// authorize checks the api key in the authorization metadata of ctx, or the key of its client certificate,
+// carries the scope of method.
+func authorize(keys *auth.Keys, ctx context.Context, method string) (context.Context, error) {
+	if publicMethods[method] {
+		return ctx, nil
+	}
+	md, _ := metadata.FromIncomingContext(ctx)
+	key, err := keys.AuthenticateRequest(lo.FirstOrEmpty(md.Get("authorization")), peerIdentity(ctx))
+	if err != nil {
+		return nil, status.Error(codes.Unauthenticated, err.Error())
+	}
+	scope := methodScope(method)
+	if !key.Allows(scope) {
+		return nil, status.Errorf(codes.PermissionDenied, "api key %s lacks scope %s", key.Name, scope)
+	}
+	return auth.WithKey(ctx, key), nil
+}
+
+func UnaryAuthInterceptor(keys *auth.Keys) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		ctx, err := authorize(keys, ctx, info.FullMethod)
+		if err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+func StreamAuthInterceptor(keys *auth.Keys) grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		ctx, err := authorize(keys, ss.Context(), info.FullMethod)
+		if err != nil {
+			return err
+		}
+		return handler(srv, &authServerStream{ServerStream: ss, ctx: ctx})
+	}
+}
+
+// authServerStream passes the context with the authenticated key to stream handlers.
+type authServerStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *authServerStream) Context() context.Context {
+	return s.ctx
+}
@@ -0,0 +1,50 @@
+package grpc
+
+import (
+	"context"
+	"testing"
+
+	"github.com/lil5/tigerbeetle_api/auth"
+	"github.com/lil5/tigerbeetle_api/proto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+func TestUnaryAuthInterceptor(t *testing.T) {
+	keys, err := auth.New("", "reader:"+auth.Hash("r")+":read")
+	require.NoError(t, err)
+	interceptor := UnaryAuthInterceptor(keys)
+	call := func(method, authorization string) (any, error) {
+		ctx := context.Background()
+		if authorization != "" {
+			ctx = metadata.NewIncomingContext(ctx, metadata.Pairs("authorization", authorization))
+		}
+		return interceptor(ctx, nil, &grpc.UnaryServerInfo{FullMethod: method}, func(ctx context.Context, req any) (any, error) {
+			if k := auth.FromContext(ctx); k != nil {
+				return k.Name, nil
+			}
+			return "", nil
+		})
+	}
+
+	name, err := call(proto.TigerBeetle_LookupAccounts_FullMethodName, "Bearer r")
+	require.NoError(t, err)
+	assert.Equal(t, "reader", name)
+
+	_, err = call(proto.TigerBeetle_CreateTransfers_FullMethodName, "Bearer r")
+	assert.Equal(t, codes.PermissionDenied, status.Code(err))
+
+	_, err = call(proto.TigerBeetle_LookupAccounts_FullMethodName, "Bearer x")
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+
+	_, err = call(proto.TigerBeetle_LookupAccounts_FullMethodName, "")
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+
+	_, err = call(healthpb.Health_Check_FullMethodName, "")
+	assert.NoError(t, err)
+}
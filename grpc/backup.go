@@ -0,0 +1,72 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"regexp"
+
+	"github.com/lil5/tigerbeetle_api/backup"
+	"github.com/lil5/tigerbeetle_api/config"
+	"github.com/lil5/tigerbeetle_api/proto"
+	"github.com/samber/lo"
+)
+
+var (
+	ErrBackupDisabled    = errors.New("backups are disabled, BACKUP_DIR is not set")
+	ErrInvalidBackupName = errors.New("invalid backup name")
+)
+
+var backupNameRegexp = regexp.MustCompile(`^[A-Za-z0-9_.-]+$`)
+
+func backupPath(name string) (string, error) {
+	if config.Config.BackupDir == "" {
+		return "", ErrBackupDisabled
+	}
+	if !backupNameRegexp.MatchString(name) || name == "." || name == ".." {
+		return "", ErrInvalidBackupName
+	}
+	return filepath.Join(config.Config.BackupDir, name), nil
+}
+
+func (s *App) ExportLedger(ctx context.Context, in *proto.ExportLedgerRequest) (*proto.ExportLedgerReply, error) {
+	if restriction(ctx) != nil {
+		return nil, errClusterWide
+	}
+	dir, err := backupPath(in.Name)
+	if err != nil {
+		return nil, err
+	}
+	manifest, err := backup.Export(s.tb(ctx), dir, backup.NewOptions())
+	if err != nil {
+		return nil, err
+	}
+	accounts, _ := manifest.File(backup.AccountsFile)
+	transfers, _ := manifest.File(backup.TransfersFile)
+	return &proto.ExportLedgerReply{
+		Name:      in.Name,
+		Accounts:  accounts.Count,
+		Transfers: transfers.Count,
+	}, nil
+}
+
+func (s *App) RestoreLedger(ctx context.Context, in *proto.RestoreLedgerRequest) (*proto.RestoreLedgerReply, error) {
+	if restriction(ctx) != nil {
+		return nil, errClusterWide
+	}
+	dir, err := backupPath(in.Name)
+	if err != nil {
+		return nil, err
+	}
+	report, err := backup.Restore(s.tb(ctx), dir, backup.NewOptions())
+	if err != nil {
+		return nil, err
+	}
+	return &proto.RestoreLedgerReply{
+		Accounts:  report.Accounts,
+		Transfers: report.Transfers,
+		Rejected: lo.Map(report.Rejected, func(r backup.Rejection, _ int) *proto.RestoreLedgerRejection {
+			return &proto.RestoreLedgerRejection{Kind: r.Kind, Id: r.ID, Code: r.Code, Result: r.Result}
+		}),
+	}, nil
+}
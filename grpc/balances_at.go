@@ -0,0 +1,331 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/lil5/tigerbeetle_api/config"
+	"github.com/lil5/tigerbeetle_api/metrics"
+	"github.com/lil5/tigerbeetle_api/proto"
+	"github.com/samber/lo"
+	"github.com/tigerbeetle/tigerbeetle-go/pkg/types"
+)
+
+var (
+	ErrBalanceOverflow = errors.New("balance does not fit in 64 bits")
+	ErrReplayLimit     = errors.New("account has more transfers to replay than BALANCE_REPLAY_MAX_SCAN")
+)
+
+// balance is an account balance held in arbitrary precision while it is being derived.
+type balance struct {
+	DebitsPending  big.Int
+	DebitsPosted   big.Int
+	CreditsPending big.Int
+	CreditsPosted  big.Int
+	Timestamp      uint64
+}
+
+func balanceFromAccount(a types.Account) *balance {
+	return &balance{
+		DebitsPending:  a.DebitsPending.BigInt(),
+		DebitsPosted:   a.DebitsPosted.BigInt(),
+		CreditsPending: a.CreditsPending.BigInt(),
+		CreditsPosted:  a.CreditsPosted.BigInt(),
+		Timestamp:      a.Timestamp,
+	}
+}
+
+func balanceFromAccountBalance(a types.AccountBalance) *balance {
+	return &balance{
+		DebitsPending:  a.DebitsPending.BigInt(),
+		DebitsPosted:   a.DebitsPosted.BigInt(),
+		CreditsPending: a.CreditsPending.BigInt(),
+		CreditsPosted:  a.CreditsPosted.BigInt(),
+		Timestamp:      a.Timestamp,
+	}
+}
+
+// Net returns credits_posted - debits_posted.
+func (b *balance) Net() *big.Int {
+	return new(big.Int).Sub(&b.CreditsPosted, &b.DebitsPosted)
+}
+
+// PendingNet returns credits_pending - debits_pending.
+func (b *balance) PendingNet() *big.Int {
+	return new(big.Int).Sub(&b.CreditsPending, &b.DebitsPending)
+}
+
+// toProto fails with ErrBalanceOverflow when a total does not fit in the 64 bit fields of BalanceAt.
+func (b *balance) toProto(accountID types.Uint128, source proto.BalanceSource) (*proto.BalanceAt, error) {
+	net := b.Net()
+	for _, v := range []*big.Int{&b.DebitsPending, &b.DebitsPosted, &b.CreditsPending, &b.CreditsPosted} {
+		if !v.IsUint64() {
+			return nil, fmt.Errorf("account %s: %w", accountID, ErrBalanceOverflow)
+		}
+	}
+	if !net.IsInt64() {
+		return nil, fmt.Errorf("account %s: %w", accountID, ErrBalanceOverflow)
+	}
+	return &proto.BalanceAt{
+		AccountId:      accountID.String(),
+		DebitsPending:  b.DebitsPending.Uint64(),
+		DebitsPosted:   b.DebitsPosted.Uint64(),
+		CreditsPending: b.CreditsPending.Uint64(),
+		CreditsPosted:  b.CreditsPosted.Uint64(),
+		Balance:        net.Int64(),
+		Timestamp:      b.Timestamp,
+		Source:         source,
+	}, nil
+}
+
+// balanceReplay rebuilds the balance of a single account by applying its transfers in timestamp order.
+//
+// Pending transfers that expired through a timeout leave no transfer behind,
+// their amount is released once the replay passes timestamp + timeout.
+type balanceReplay struct {
+	accountID types.Uint128
+	balance   balance
+	pending   map[types.Uint128]types.Transfer
+	// nextExpiry is the earliest expiry of the pending transfers, 0 when none expires
+	nextExpiry uint64
+}
+
+func newBalanceReplay(accountID types.Uint128) *balanceReplay {
+	return &balanceReplay{
+		accountID: accountID,
+		pending:   map[types.Uint128]types.Transfer{},
+	}
+}
+
+// expiresAt returns the timestamp a pending transfer expires at, 0 when it has no timeout.
+func expiresAt(t types.Transfer) uint64 {
+	if t.Timeout == 0 {
+		return 0
+	}
+	return t.Timestamp + uint64(t.Timeout)*uint64(time.Second)
+}
+
+// Expire releases the pending transfers that expired at or before timestamp.
+func (r *balanceReplay) Expire(timestamp uint64) {
+	if r.nextExpiry == 0 || r.nextExpiry > timestamp {
+		return
+	}
+	r.nextExpiry = 0
+	for id, p := range r.pending {
+		at := expiresAt(p)
+		if at == 0 {
+			continue
+		}
+		if at > timestamp {
+			r.nextExpiry = nextExpiry(r.nextExpiry, at)
+			continue
+		}
+		delete(r.pending, id)
+		amount := p.Amount.BigInt()
+		if p.DebitAccountID == r.accountID {
+			r.balance.DebitsPending.Sub(&r.balance.DebitsPending, &amount)
+		} else {
+			r.balance.CreditsPending.Sub(&r.balance.CreditsPending, &amount)
+		}
+	}
+}
+
+// Hold tracks pending transfers that were created before the replay started,
+// their amounts are in the balance it starts from already.
+func (r *balanceReplay) Hold(pending map[types.Uint128]types.Transfer) {
+	for id, p := range pending {
+		r.pending[id] = p
+		if at := expiresAt(p); at != 0 {
+			r.nextExpiry = nextExpiry(r.nextExpiry, at)
+		}
+	}
+}
+
+func nextExpiry(current, at uint64) uint64 {
+	if current == 0 || at < current {
+		return at
+	}
+	return current
+}
+
+// Apply applies t after releasing the pending transfers that expired before it.
+func (r *balanceReplay) Apply(t types.Transfer) {
+	r.Expire(t.Timestamp)
+	isDebit := t.DebitAccountID == r.accountID
+	amount := t.Amount.BigInt()
+	flags := t.TransferFlags()
+	r.balance.Timestamp = t.Timestamp
+
+	switch {
+	case flags.Pending:
+		r.pending[t.ID] = t
+		if at := expiresAt(t); at != 0 {
+			r.nextExpiry = nextExpiry(r.nextExpiry, at)
+		}
+		if isDebit {
+			r.balance.DebitsPending.Add(&r.balance.DebitsPending, &amount)
+		} else {
+			r.balance.CreditsPending.Add(&r.balance.CreditsPending, &amount)
+		}
+	case flags.PostPendingTransfer, flags.VoidPendingTransfer:
+		p, ok := r.pending[t.PendingID]
+		if !ok {
+			// The pending transfer was created before the replayed range.
+			return
+		}
+		delete(r.pending, t.PendingID)
+		pendingAmount := p.Amount.BigInt()
+		if isDebit {
+			r.balance.DebitsPending.Sub(&r.balance.DebitsPending, &pendingAmount)
+			if flags.PostPendingTransfer {
+				r.balance.DebitsPosted.Add(&r.balance.DebitsPosted, &amount)
+			}
+		} else {
+			r.balance.CreditsPending.Sub(&r.balance.CreditsPending, &pendingAmount)
+			if flags.PostPendingTransfer {
+				r.balance.CreditsPosted.Add(&r.balance.CreditsPosted, &amount)
+			}
+		}
+	default:
+		if isDebit {
+			r.balance.DebitsPosted.Add(&r.balance.DebitsPosted, &amount)
+		} else {
+			r.balance.CreditsPosted.Add(&r.balance.CreditsPosted, &amount)
+		}
+	}
+}
+
+// balanceAt returns the balance of the account as of timestamp, inclusive.
+func (s *App) balanceAt(ctx context.Context, account types.Account, timestamp uint64) (*balance, proto.BalanceSource, error) {
+	replay, source, err := s.replayAt(ctx, account, timestamp)
+	if err != nil {
+		return nil, source, err
+	}
+	return &replay.balance, source, nil
+}
+
+// replayAt returns the replay of the account as of timestamp, inclusive, with the pending transfers still open then.
+// Accounts with the history flag start from their latest balance snapshot,
+// all other accounts replay their transfers.
+func (s *App) replayAt(ctx context.Context, account types.Account, timestamp uint64) (*balanceReplay, proto.BalanceSource, error) {
+	replay := newBalanceReplay(account.ID)
+	if account.AccountFlags().History {
+		metrics.TotalTbGetAccountBalancesCall.Inc()
+		res, err := s.tb(ctx).GetAccountBalances(types.AccountFilter{
+			AccountID:    account.ID,
+			TimestampMax: timestamp,
+			Limit:        1,
+			Flags: types.AccountFilterFlags{
+				Debits:   true,
+				Credits:  true,
+				Reversed: true,
+			}.ToUint32(),
+		})
+		if err != nil {
+			return nil, proto.BalanceSource_BalanceSourceHistory, err
+		}
+		if len(res) == 0 {
+			return replay, proto.BalanceSource_BalanceSourceHistory, nil
+		}
+		replay.balance = *balanceFromAccountBalance(res[0])
+		// An expiry leaves no snapshot behind, the pending transfers open at the snapshot
+		// are released when they expired before timestamp.
+		if replay.balance.DebitsPending.Sign() != 0 || replay.balance.CreditsPending.Sign() != 0 {
+			open, err := s.openPendings(ctx, account.ID, res[0].Timestamp)
+			if err != nil {
+				return nil, proto.BalanceSource_BalanceSourceHistory, err
+			}
+			replay.Hold(open)
+			replay.Expire(timestamp)
+		}
+		return replay, proto.BalanceSource_BalanceSourceHistory, nil
+	}
+
+	if err := s.replayTransfers(ctx, replay, timestamp); err != nil {
+		return nil, proto.BalanceSource_BalanceSourceTransfers, err
+	}
+	replay.Expire(timestamp)
+	return replay, proto.BalanceSource_BalanceSourceTransfers, nil
+}
+
+// replayTransfers applies the transfers of the account of replay up to timestamp, inclusive.
+// More than BALANCE_REPLAY_MAX_SCAN transfers fail with ErrReplayLimit.
+func (s *App) replayTransfers(ctx context.Context, replay *balanceReplay, timestamp uint64) error {
+	var read uint64
+	return s.eachAccountTransfers(ctx, types.AccountFilter{
+		AccountID:    replay.accountID,
+		TimestampMax: timestamp,
+		Flags: types.AccountFilterFlags{
+			Debits:  true,
+			Credits: true,
+		}.ToUint32(),
+	}, func(transfers []types.Transfer) error {
+		read += uint64(len(transfers))
+		if limit := config.Config.BalanceReplayMaxScan; limit > 0 && read > limit {
+			return fmt.Errorf("account %s: %w", replay.accountID, ErrReplayLimit)
+		}
+		for _, t := range transfers {
+			replay.Apply(t)
+		}
+		return nil
+	})
+}
+
+// openPendings returns the pending transfers of the account that are still open at timestamp, inclusive.
+// A balance snapshot holds their amounts but not when they expire, so they are found by replaying the transfers up to timestamp.
+func (s *App) openPendings(ctx context.Context, accountID types.Uint128, timestamp uint64) (map[types.Uint128]types.Transfer, error) {
+	replay := newBalanceReplay(accountID)
+	if err := s.replayTransfers(ctx, replay, timestamp); err != nil {
+		return nil, err
+	}
+	replay.Expire(timestamp)
+	return replay.pending, nil
+}
+
+func (s *App) GetBalancesAt(ctx context.Context, in *proto.GetBalancesAtRequest) (*proto.GetBalancesAtReply, error) {
+	if len(in.AccountIds) == 0 {
+		return nil, ErrZeroAccounts
+	}
+	if err := checkItems("account_ids", len(in.AccountIds)); err != nil {
+		return nil, err
+	}
+	ids := []types.Uint128{}
+	for _, inID := range in.AccountIds {
+		id, err := s.accountID(inID)
+		if err != nil {
+			return nil, err
+		}
+		ids = append(ids, *id)
+	}
+
+	accounts, err := s.lookupAccounts(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	if r := restriction(ctx); r != nil {
+		accounts = lo.Filter(accounts, func(a types.Account, _ int) bool { return r.AllowsAccount(a) })
+	}
+
+	balances := make([]*proto.BalanceAt, 0, len(accounts))
+	for _, account := range accounts {
+		b, source := balanceFromAccount(account), proto.BalanceSource_BalanceSourceCurrent
+		if in.Timestamp != 0 {
+			if account.Timestamp > in.Timestamp {
+				// The account did not exist yet.
+				continue
+			}
+			if b, source, err = s.balanceAt(ctx, account, in.Timestamp); err != nil {
+				return nil, err
+			}
+		}
+		pb, err := s.balanceToProto(b, account, source)
+		if err != nil {
+			return nil, err
+		}
+		balances = append(balances, pb)
+	}
+	return &proto.GetBalancesAtReply{Balances: balances}, nil
+}
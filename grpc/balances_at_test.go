@@ -0,0 +1,165 @@
+package grpc
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/lil5/tigerbeetle_api/config"
+	"github.com/lil5/tigerbeetle_api/proto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/tigerbeetle/tigerbeetle-go/pkg/types"
+)
+
+func TestGetBalancesAt(t *testing.T) {
+	mockClient := new(MockTigerBeetleClient)
+	app := &App{TB: mockClient}
+
+	accountID := types.ToUint128(1)
+	otherID := types.ToUint128(2)
+
+	t.Run("should return error when no accounts are given", func(t *testing.T) {
+		_, err := app.GetBalancesAt(context.Background(), &proto.GetBalancesAtRequest{})
+		assert.ErrorIs(t, err, ErrZeroAccounts)
+	})
+
+	t.Run("should return current balance without timestamp", func(t *testing.T) {
+		mockClient.On("LookupAccounts", []types.Uint128{accountID}).
+			Return([]types.Account{{ID: accountID, CreditsPosted: types.ToUint128(50), DebitsPosted: types.ToUint128(20)}}, nil).Once()
+
+		resp, err := app.GetBalancesAt(context.Background(), &proto.GetBalancesAtRequest{AccountIds: []string{"1"}})
+		assert.NoError(t, err)
+		assert.Len(t, resp.Balances, 1)
+		assert.Equal(t, int64(30), resp.Balances[0].Balance)
+		assert.Equal(t, proto.BalanceSource_BalanceSourceCurrent, resp.Balances[0].Source)
+		mockClient.AssertExpectations(t)
+	})
+
+	t.Run("should use latest history snapshot", func(t *testing.T) {
+		mockClient.On("LookupAccounts", []types.Uint128{accountID}).
+			Return([]types.Account{{
+				ID:        accountID,
+				Flags:     types.AccountFlags{History: true}.ToUint16(),
+				Timestamp: 10,
+			}}, nil).Once()
+		mockClient.On("GetAccountBalances", mock.MatchedBy(func(f types.AccountFilter) bool {
+			return f.AccountID == accountID && f.TimestampMax == 100 && f.Limit == 1
+		})).Return([]types.AccountBalance{{
+			DebitsPosted:  types.ToUint128(5),
+			CreditsPosted: types.ToUint128(15),
+			Timestamp:     90,
+		}}, nil).Once()
+
+		resp, err := app.GetBalancesAt(context.Background(), &proto.GetBalancesAtRequest{AccountIds: []string{"1"}, Timestamp: 100})
+		assert.NoError(t, err)
+		assert.Len(t, resp.Balances, 1)
+		assert.Equal(t, uint64(15), resp.Balances[0].CreditsPosted)
+		assert.Equal(t, int64(10), resp.Balances[0].Balance)
+		assert.Equal(t, uint64(90), resp.Balances[0].Timestamp)
+		assert.Equal(t, proto.BalanceSource_BalanceSourceHistory, resp.Balances[0].Source)
+		mockClient.AssertExpectations(t)
+	})
+
+	t.Run("should expire pending transfers after the snapshot", func(t *testing.T) {
+		second := uint64(time.Second)
+		mockClient.On("LookupAccounts", []types.Uint128{accountID}).
+			Return([]types.Account{{ID: accountID, Flags: types.AccountFlags{History: true}.ToUint16(), Timestamp: 10}}, nil).Once()
+		mockClient.On("GetAccountBalances", mock.Anything).Return([]types.AccountBalance{{
+			DebitsPending: types.ToUint128(40),
+			Timestamp:     90,
+		}}, nil).Once()
+		mockClient.On("GetAccountTransfers", mock.MatchedBy(func(f types.AccountFilter) bool {
+			return f.TimestampMax == 90
+		})).Return([]types.Transfer{
+			{ID: types.ToUint128(7), DebitAccountID: accountID, CreditAccountID: otherID, Amount: types.ToUint128(40), Timeout: 1, Flags: types.TransferFlags{Pending: true}.ToUint16(), Timestamp: 90},
+		}, nil).Once()
+
+		resp, err := app.GetBalancesAt(context.Background(), &proto.GetBalancesAtRequest{AccountIds: []string{"1"}, Timestamp: 90 + 2*second})
+		assert.NoError(t, err)
+		assert.Equal(t, uint64(0), resp.Balances[0].DebitsPending)
+		assert.Equal(t, proto.BalanceSource_BalanceSourceHistory, resp.Balances[0].Source)
+		mockClient.AssertExpectations(t)
+	})
+
+	t.Run("should replay transfers without history", func(t *testing.T) {
+		pendingID := types.ToUint128(11)
+		voidedID := types.ToUint128(12)
+		mockClient.On("LookupAccounts", []types.Uint128{accountID}).
+			Return([]types.Account{{ID: accountID, Timestamp: 1}}, nil).Once()
+		mockClient.On("GetAccountTransfers", mock.AnythingOfType("types.AccountFilter")).
+			Return([]types.Transfer{
+				{ID: types.ToUint128(10), DebitAccountID: otherID, CreditAccountID: accountID, Amount: types.ToUint128(100), Timestamp: 2},
+				{ID: pendingID, DebitAccountID: accountID, CreditAccountID: otherID, Amount: types.ToUint128(40), Flags: types.TransferFlags{Pending: true}.ToUint16(), Timestamp: 3},
+				{ID: voidedID, DebitAccountID: accountID, CreditAccountID: otherID, Amount: types.ToUint128(7), Flags: types.TransferFlags{Pending: true}.ToUint16(), Timestamp: 4},
+				{ID: types.ToUint128(13), DebitAccountID: accountID, CreditAccountID: otherID, Amount: types.ToUint128(30), PendingID: pendingID, Flags: types.TransferFlags{PostPendingTransfer: true}.ToUint16(), Timestamp: 5},
+				{ID: types.ToUint128(14), DebitAccountID: accountID, CreditAccountID: otherID, PendingID: voidedID, Flags: types.TransferFlags{VoidPendingTransfer: true}.ToUint16(), Timestamp: 6},
+			}, nil).Once()
+
+		resp, err := app.GetBalancesAt(context.Background(), &proto.GetBalancesAtRequest{AccountIds: []string{"1"}, Timestamp: 100})
+		assert.NoError(t, err)
+		assert.Len(t, resp.Balances, 1)
+		b := resp.Balances[0]
+		assert.Equal(t, uint64(100), b.CreditsPosted)
+		assert.Equal(t, uint64(30), b.DebitsPosted)
+		assert.Equal(t, uint64(0), b.DebitsPending)
+		assert.Equal(t, int64(70), b.Balance)
+		assert.Equal(t, uint64(6), b.Timestamp)
+		assert.Equal(t, proto.BalanceSource_BalanceSourceTransfers, b.Source)
+		mockClient.AssertExpectations(t)
+	})
+
+	t.Run("should release expired pending transfers", func(t *testing.T) {
+		second := uint64(time.Second)
+		mockClient.On("LookupAccounts", []types.Uint128{accountID}).
+			Return([]types.Account{{ID: accountID, Timestamp: 1}}, nil).Once()
+		mockClient.On("GetAccountTransfers", mock.AnythingOfType("types.AccountFilter")).
+			Return([]types.Transfer{
+				{ID: types.ToUint128(20), DebitAccountID: accountID, CreditAccountID: otherID, Amount: types.ToUint128(40), Timeout: 10, Flags: types.TransferFlags{Pending: true}.ToUint16(), Timestamp: 2},
+				{ID: types.ToUint128(21), DebitAccountID: accountID, CreditAccountID: otherID, Amount: types.ToUint128(5), Timeout: 60, Flags: types.TransferFlags{Pending: true}.ToUint16(), Timestamp: 3},
+				{ID: types.ToUint128(22), DebitAccountID: otherID, CreditAccountID: accountID, Amount: types.ToUint128(1), Flags: types.TransferFlags{Pending: true}.ToUint16(), Timestamp: 4},
+			}, nil).Once()
+
+		resp, err := app.GetBalancesAt(context.Background(), &proto.GetBalancesAtRequest{AccountIds: []string{"1"}, Timestamp: 2 + 10*second})
+		assert.NoError(t, err)
+		assert.Len(t, resp.Balances, 1)
+		assert.Equal(t, uint64(5), resp.Balances[0].DebitsPending)
+		assert.Equal(t, uint64(1), resp.Balances[0].CreditsPending)
+		mockClient.AssertExpectations(t)
+	})
+
+	t.Run("should omit accounts created after timestamp", func(t *testing.T) {
+		mockClient.On("LookupAccounts", []types.Uint128{accountID}).
+			Return([]types.Account{{ID: accountID, CreditsPosted: types.ToUint128(50), Timestamp: 200}}, nil).Once()
+
+		resp, err := app.GetBalancesAt(context.Background(), &proto.GetBalancesAtRequest{AccountIds: []string{"1"}, Timestamp: 100})
+		assert.NoError(t, err)
+		assert.Empty(t, resp.Balances)
+		mockClient.AssertExpectations(t)
+	})
+
+	t.Run("should fail on balances over 64 bits", func(t *testing.T) {
+		mockClient.On("LookupAccounts", []types.Uint128{accountID}).
+			Return([]types.Account{{ID: accountID, CreditsPosted: types.Uint128{8: 1}}}, nil).Once()
+
+		_, err := app.GetBalancesAt(context.Background(), &proto.GetBalancesAtRequest{AccountIds: []string{"1"}})
+		assert.ErrorIs(t, err, ErrBalanceOverflow)
+		mockClient.AssertExpectations(t)
+	})
+
+	t.Run("should fail past BALANCE_REPLAY_MAX_SCAN", func(t *testing.T) {
+		config.Config.BalanceReplayMaxScan = 1
+		t.Cleanup(func() { config.Config.BalanceReplayMaxScan = 0 })
+		mockClient.On("LookupAccounts", []types.Uint128{accountID}).
+			Return([]types.Account{{ID: accountID, Timestamp: 1}}, nil).Once()
+		mockClient.On("GetAccountTransfers", mock.AnythingOfType("types.AccountFilter")).
+			Return([]types.Transfer{
+				{ID: types.ToUint128(30), DebitAccountID: otherID, CreditAccountID: accountID, Amount: types.ToUint128(1), Timestamp: 2},
+				{ID: types.ToUint128(31), DebitAccountID: otherID, CreditAccountID: accountID, Amount: types.ToUint128(1), Timestamp: 3},
+			}, nil).Once()
+
+		_, err := app.GetBalancesAt(context.Background(), &proto.GetBalancesAtRequest{AccountIds: []string{"1"}, Timestamp: 100})
+		assert.ErrorIs(t, err, ErrReplayLimit)
+		mockClient.AssertExpectations(t)
+	})
+}
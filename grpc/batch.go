@@ -0,0 +1,118 @@
+package grpc
+
+import (
+	"context"
+
+	"github.com/lil5/tigerbeetle_api/config"
+	"github.com/lil5/tigerbeetle_api/metrics"
+	"github.com/tigerbeetle/tigerbeetle-go/pkg/types"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// checkItems rejects a request field of more than MAX_REQUEST_ITEMS events.
+func checkItems(field string, n int) error {
+	if limit := config.Config.MaxRequestItems; limit > 0 && n > limit {
+		return status.Errorf(codes.InvalidArgument, "%s: %d items exceed the maximum of %d per request", field, n, limit)
+	}
+	return nil
+}
+
+// batchRanges splits n events into consecutive [start, end) ranges of at most size events.
+// A linked chain is never split, as TigerBeetle only applies a chain as a whole within one batch.
+func batchRanges(n, size int, linked func(i int) bool) ([][2]int, error) {
+	var ranges [][2]int
+	for start := 0; start < n; {
+		end := min(start+size, n)
+		if end < n {
+			// Move the end back to the start of the chain that crosses it.
+			for end > start && linked(end-1) {
+				end--
+			}
+			if end == start {
+				return nil, status.Errorf(codes.InvalidArgument, "the linked chain at index %d is longer than the batch size of %d", start, size)
+			}
+		}
+		ranges = append(ranges, [2]int{start, end})
+		start = end
+	}
+	return ranges, nil
+}
+
+// createTransfers creates transfers in batches of at most TB_MAX_BATCH_SIZE,
+// the indexes of the results are those of transfers.
+// A failing batch fails the request while earlier batches stay created,
+// a retry with the same ids reports those as exists.
+func (s *App) createTransfers(ctx context.Context, transfers []types.Transfer) ([]types.TransferEventResult, error) {
+	ranges, err := batchRanges(len(transfers), TB_MAX_BATCH_SIZE, func(i int) bool { return transfers[i].TransferFlags().Linked })
+	if err != nil {
+		return nil, err
+	}
+	var results []types.TransferEventResult
+	for _, r := range ranges {
+		metrics.TotalTbCreateTransfersCall.Inc()
+		metrics.TotalCreateTransferTx.Add(float64(r[1] - r[0]))
+		if config.Config.IsDryRun {
+			continue
+		}
+		res, err := s.tb(ctx).CreateTransfers(transfers[r[0]:r[1]])
+		if err != nil {
+			return nil, err
+		}
+		metrics.TotalCreateTransferTxErr.Add(float64(len(res)))
+		for _, e := range res {
+			e.Index += uint32(r[0])
+			results = append(results, e)
+		}
+	}
+	return results, nil
+}
+
+// createAccounts creates accounts in batches as createTransfers does.
+func (s *App) createAccounts(ctx context.Context, accounts []types.Account) ([]types.AccountEventResult, error) {
+	ranges, err := batchRanges(len(accounts), TB_MAX_BATCH_SIZE, func(i int) bool { return accounts[i].AccountFlags().Linked })
+	if err != nil {
+		return nil, err
+	}
+	var results []types.AccountEventResult
+	for _, r := range ranges {
+		metrics.TotalTbCreateAccountsCall.Inc()
+		res, err := s.tb(ctx).CreateAccounts(accounts[r[0]:r[1]])
+		if err != nil {
+			return nil, err
+		}
+		for _, e := range res {
+			e.Index += uint32(r[0])
+			results = append(results, e)
+		}
+	}
+	return results, nil
+}
+
+// lookupAccounts looks up ids in batches of at most TB_MAX_BATCH_SIZE.
+func (s *App) lookupAccounts(ctx context.Context, ids []types.Uint128) ([]types.Account, error) {
+	var accounts []types.Account
+	for start := 0; start < len(ids); start += TB_MAX_BATCH_SIZE {
+		metrics.TotalTbLookupAccountsCall.Inc()
+		res, err := s.tb(ctx).LookupAccounts(ids[start:min(start+TB_MAX_BATCH_SIZE, len(ids))])
+		if err != nil {
+			return nil, err
+		}
+		accounts = append(accounts, res...)
+	}
+	return accounts, nil
+}
+
+// lookupTransfers looks up ids in batches of at most TB_MAX_BATCH_SIZE.
+func (s *App) lookupTransfers(ctx context.Context, ids []types.Uint128) ([]types.Transfer, error) {
+	var transfers []types.Transfer
+	for start := 0; start < len(ids); start += TB_MAX_BATCH_SIZE {
+		metrics.TotalTbLookupTransfersCall.Inc()
+		res, err := s.tb(ctx).LookupTransfers(ids[start:min(start+TB_MAX_BATCH_SIZE, len(ids))])
+		if err != nil {
+			return nil, err
+		}
+		transfers = append(transfers, res...)
+	}
+	return transfers, nil
+}
@@ -0,0 +1,85 @@
+package grpc
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/lil5/tigerbeetle_api/config"
+	"github.com/lil5/tigerbeetle_api/proto"
+	"github.com/samber/lo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/tigerbeetle/tigerbeetle-go/pkg/types"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestBatchRanges(t *testing.T) {
+	linkedAt := func(idx ...int) func(int) bool {
+		return func(i int) bool { return lo.Contains(idx, i) }
+	}
+	ranges, err := batchRanges(10, 4, linkedAt())
+	require.NoError(t, err)
+	assert.Equal(t, [][2]int{{0, 4}, {4, 8}, {8, 10}}, ranges)
+
+	// The chain 2-4 crosses the first end and moves it back.
+	ranges, err = batchRanges(10, 4, linkedAt(2, 3))
+	require.NoError(t, err)
+	assert.Equal(t, [][2]int{{0, 2}, {2, 6}, {6, 10}}, ranges)
+
+	_, err = batchRanges(10, 4, linkedAt(0, 1, 2, 3))
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+}
+
+func TestCreateTransfersSplit(t *testing.T) {
+	config.Config.IsBuffered = false
+	config.Config.IsDryRun = false
+	n := TB_MAX_BATCH_SIZE + 5
+	transfers := make([]*proto.Transfer, n)
+	for i := range transfers {
+		transfers[i] = &proto.Transfer{Id: fmt.Sprintf("%x", i+1), DebitAccountId: "1", CreditAccountId: "2", Amount: 1, Ledger: 1, Code: 1}
+	}
+	// A chain across the batch boundary goes to the second batch as a whole.
+	transfers[TB_MAX_BATCH_SIZE-2].TransferFlags = &proto.TransferFlags{Linked: lo.ToPtr(true)}
+	transfers[TB_MAX_BATCH_SIZE-1].TransferFlags = &proto.TransferFlags{Linked: lo.ToPtr(true)}
+
+	mockClient := new(MockTigerBeetleClient)
+	app := &App{TB: mockClient}
+	mockClient.On("CreateTransfers", mock.MatchedBy(func(t []types.Transfer) bool { return len(t) == TB_MAX_BATCH_SIZE-2 })).
+		Return([]types.TransferEventResult{{Index: 1, Result: types.TransferExists}}, nil).Once()
+	mockClient.On("CreateTransfers", mock.MatchedBy(func(t []types.Transfer) bool { return len(t) == 7 })).
+		Return([]types.TransferEventResult{{Index: 3, Result: types.TransferExceedsCredits}}, nil).Once()
+
+	reply, err := app.CreateTransfers(context.Background(), &proto.CreateTransfersRequest{Transfers: transfers})
+	require.NoError(t, err)
+	mockClient.AssertExpectations(t)
+	require.Len(t, reply.Results, 2)
+	assert.EqualValues(t, 1, reply.Results[0].Index)
+	assert.EqualValues(t, TB_MAX_BATCH_SIZE-2+3, reply.Results[1].Index)
+}
+
+func TestCheckItems(t *testing.T) {
+	config.Config.MaxRequestItems = 2
+	defer func() { config.Config.MaxRequestItems = 0 }()
+	app := &App{TB: new(MockTigerBeetleClient)}
+	_, err := app.LookupAccounts(context.Background(), &proto.LookupAccountsRequest{AccountIds: []string{"1", "2", "3"}})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	assert.ErrorContains(t, err, "account_ids")
+
+	legs := []*proto.Transfer{{Amount: 1}, {Amount: 1}, {Amount: 1}}
+	_, err = app.CreateCompoundTransfer(context.Background(), &proto.CreateCompoundTransferRequest{Legs: legs})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	assert.ErrorContains(t, err, "legs")
+
+	_, err = app.GetBalancesAt(context.Background(), &proto.GetBalancesAtRequest{AccountIds: []string{"1", "2", "3"}})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+}
+
+func TestCompoundChainSize(t *testing.T) {
+	app := &App{TB: new(MockTigerBeetleClient)}
+	legs := make([]*proto.Transfer, TB_MAX_BATCH_SIZE+1)
+	_, err := app.CreateCompoundTransfer(context.Background(), &proto.CreateCompoundTransferRequest{Legs: legs})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+}
@@ -0,0 +1,139 @@
+package grpc
+
+import (
+	"context"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/charithe/timedbuf/v2"
+	"github.com/lil5/tigerbeetle_api/config"
+	"github.com/lil5/tigerbeetle_api/metrics"
+	"github.com/lil5/tigerbeetle_api/tracing"
+	tigerbeetle_go "github.com/tigerbeetle/tigerbeetle-go"
+	"github.com/tigerbeetle/tigerbeetle-go/pkg/types"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// bufferShard is the state of one of the BUFFER_CLUSTER buffers.
+type bufferShard struct {
+	name string
+	// put serializes the Put calls of the shard, so queued is exact before each of them.
+	put sync.Mutex
+	// queued is the number of payloads in the buffer
+	queued atomic.Int64
+	// full is set while a Put into a full buffer runs, the flush it starts is by size.
+	full atomic.Bool
+}
+
+// newBuffers returns the BUFFER_CLUSTER buffers that send their transfers to tb, and the state of each.
+func newBuffers(tb tigerbeetle_go.Client) ([]*timedbuf.TimedBuf[TimedPayload], []*bufferShard) {
+	tbufs := make([]*timedbuf.TimedBuf[TimedPayload], config.Config.BufferCluster)
+	shards := make([]*bufferShard, config.Config.BufferCluster)
+	for i := range config.Config.BufferCluster {
+		shard := &bufferShard{name: strconv.Itoa(i)}
+		shards[i] = shard
+		tbufs[i] = timedbuf.New(config.Config.BufferSize, config.Config.BufferDelay, func(payloads []TimedPayload) {
+			shard.flush(tb, payloads)
+		})
+	}
+	return tbufs, shards
+}
+
+// enqueue puts payload in buf, the buffer of shard.
+// A Put into a full buffer flushes it before adding the payload.
+func (shard *bufferShard) enqueue(buf *timedbuf.TimedBuf[TimedPayload], payload TimedPayload) {
+	shard.put.Lock()
+	defer shard.put.Unlock()
+	// BUFFER_DELAY may flush the full buffer just before Put does, it is still reported as full.
+	shard.full.Store(shard.queued.Load() >= int64(config.Config.BufferSize))
+	shard.queued.Add(1)
+	// Counted before Put, which may flush at once.
+	metrics.BufferPendingPayloads.WithLabelValues(shard.name).Inc()
+	metrics.BufferPendingTransfers.WithLabelValues(shard.name).Add(float64(len(payload.Transfers)))
+	buf.Put(payload)
+	shard.full.Store(false)
+}
+
+// flush sends payloads to tb, it is called by the buffer of shard when it is full,
+// on BUFFER_DELAY and on Close.
+func (shard *bufferShard) flush(tb tigerbeetle_go.Client, payloads []TimedPayload) {
+	// The maximum batch size is set in the TigerBeetle server, see config.TB_MAX_BATCH_SIZE.
+	bufSizeFull := float64(config.Config.BufferSize)
+	bufSize80 := bufSizeFull * 0.8
+
+	lenPayloads := float64(len(payloads))
+	pending := 0
+	for _, payload := range payloads {
+		metrics.BufferWait.Observe(time.Since(payload.Enqueued).Seconds())
+		pending += len(payload.Transfers)
+	}
+	shard.queued.Add(-int64(len(payloads)))
+	metrics.BufferPendingPayloads.WithLabelValues(shard.name).Sub(lenPayloads)
+	metrics.BufferPendingTransfers.WithLabelValues(shard.name).Sub(float64(pending))
+	reason := "timer"
+	if shard.full.Swap(false) {
+		reason = "size"
+	}
+	metrics.TotalBufferFlushes.WithLabelValues(shard.name, reason).Inc()
+
+	metrics.TotalBufferCount.Inc()
+	if lenPayloads == bufSizeFull {
+		metrics.TotalBufferContentsFull.Inc()
+	} else if lenPayloads >= bufSize80 {
+		metrics.TotalBufferContentsGte80.Inc()
+	} else {
+		metrics.TotalBufferContentsLt80.Inc()
+		// slog.Info("Buffer contents less than 80%", "contents %", int((lenPayloads/bufSizeFull)*100))
+	}
+
+	// Payloads are sent in batches of at most TB_MAX_BATCH_SIZE transfers,
+	// each payload receives only the results of its own transfers.
+	for len(payloads) > 0 {
+		n, size := 0, 0
+		for n < len(payloads) && (n == 0 || size+len(payloads[n].Transfers) <= TB_MAX_BATCH_SIZE) {
+			size += len(payloads[n].Transfers)
+			n++
+		}
+		batch := payloads[:n]
+		payloads = payloads[n:]
+
+		transfers := make([]types.Transfer, 0, size)
+		links := make([]trace.Link, 0, len(batch))
+		for _, payload := range batch {
+			transfers = append(transfers, payload.Transfers...)
+			if payload.Span.IsValid() {
+				links = append(links, trace.Link{SpanContext: payload.Span})
+			}
+		}
+		// The flush serves many requests, so it starts a trace of its own linked to each of them.
+		ctx, span := tracing.Start(context.Background(), "buffer.flush", trace.WithNewRoot(), trace.WithLinks(links...),
+			trace.WithAttributes(tracing.CountKey.Int(len(transfers)), attribute.Int("buffer.payloads", len(batch))))
+		metrics.TotalCreateTransferTx.Add(float64(len(transfers)))
+		metrics.TotalTbCreateTransfersCall.Inc()
+		metrics.BufferFillRatio.WithLabelValues(shard.name).Set(float64(len(transfers)) / TB_MAX_BATCH_SIZE)
+		var results []types.TransferEventResult
+		var err error
+		if !config.Config.IsDryRun {
+			results, err = tracing.Client(ctx, tb).CreateTransfers(transfers)
+		}
+		metrics.TotalCreateTransferTxErr.Add(float64(len(results)))
+		tracing.End(span, err)
+
+		offset := 0
+		for _, payload := range batch {
+			end := offset + len(payload.Transfers)
+			res := TimedPayloadResponse{Error: err, Flush: span.SpanContext()}
+			for _, r := range results {
+				if int(r.Index) >= offset && int(r.Index) < end {
+					r.Index -= uint32(offset)
+					res.Results = append(res.Results, r)
+				}
+			}
+			payload.c <- res
+			offset = end
+		}
+	}
+}
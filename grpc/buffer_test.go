@@ -0,0 +1,70 @@
+package grpc
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/lil5/tigerbeetle_api/config"
+	"github.com/lil5/tigerbeetle_api/metrics"
+	"github.com/lil5/tigerbeetle_api/proto"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/tigerbeetle/tigerbeetle-go/pkg/types"
+)
+
+func TestBufferFlush(t *testing.T) {
+	config.Config.IsBuffered = true
+	config.Config.IsDryRun = false
+	config.Config.BufferCluster = 1
+	config.Config.BufferSize = 2
+	// Only a full buffer and Close flush during the test
+	config.Config.BufferDelay = time.Hour
+	t.Cleanup(func() { config.Config.IsBuffered = false })
+
+	mockClient := new(MockTigerBeetleClient)
+	mockClient.On("CreateTransfers", mock.Anything).Return([]types.TransferEventResult{}, nil)
+	tbufs, shards := newBuffers(mockClient)
+	app := &App{TB: mockClient, TBuf: tbufs[0], TBufs: tbufs, shards: shards}
+
+	flushes := func(reason string) float64 {
+		return testutil.ToFloat64(metrics.TotalBufferFlushes.WithLabelValues("0", reason))
+	}
+	assertPending := func(payloads, transfers float64) {
+		t.Helper()
+		assert.Equal(t, payloads, testutil.ToFloat64(metrics.BufferPendingPayloads.WithLabelValues("0")))
+		assert.Equal(t, transfers, testutil.ToFloat64(metrics.BufferPendingTransfers.WithLabelValues("0")))
+	}
+	sizeBefore, timerBefore := flushes("size"), flushes("timer")
+	create := func(wg *sync.WaitGroup, id string) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := app.CreateTransfers(context.Background(), &proto.CreateTransfersRequest{Transfers: []*proto.Transfer{
+				{Id: id, DebitAccountId: "1", CreditAccountId: "2", Amount: 1, Ledger: 1, Code: 1},
+			}})
+			assert.NoError(t, err)
+		}()
+	}
+
+	var first, last sync.WaitGroup
+	create(&first, "1")
+	create(&first, "2")
+	require.Eventually(t, func() bool { return shards[0].queued.Load() == 2 }, time.Second, time.Millisecond)
+	assertPending(2, 2)
+
+	// The third request finds the buffer full and flushes the first two
+	create(&last, "3")
+	first.Wait()
+	assert.Equal(t, sizeBefore+1, flushes("size"))
+	assertPending(1, 1)
+
+	tbufs[0].Close()
+	last.Wait()
+	assert.Equal(t, timerBefore+1, flushes("timer"))
+	assertPending(0, 0)
+	assert.Equal(t, int64(0), shards[0].queued.Load())
+}
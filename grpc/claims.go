@@ -0,0 +1,108 @@
+package grpc
+
+import (
+	"context"
+
+	"github.com/lil5/tigerbeetle_api/auth"
+	"github.com/lil5/tigerbeetle_api/metrics"
+	"github.com/samber/lo"
+	"github.com/tigerbeetle/tigerbeetle-go/pkg/types"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// errClusterWide rejects operations on the whole cluster, such as backups, for restricted callers.
+var errClusterWide = status.Error(codes.PermissionDenied, "the caller is limited to ledgers, codes or accounts")
+
+// restriction returns the ledgers, codes and accounts the caller of ctx may touch,
+// nil when the caller is unrestricted.
+func restriction(ctx context.Context) *auth.Restriction {
+	if k := auth.FromContext(ctx); k != nil && k.Restriction.Restricted() {
+		return k.Restriction
+	}
+	return nil
+}
+
+func errForbidden(format string, a ...any) error {
+	return status.Errorf(codes.PermissionDenied, format, a...)
+}
+
+// authorizeTransfers checks the caller may create each transfer,
+// a post or void is checked against the pending transfer it refers to.
+func (s *App) authorizeTransfers(ctx context.Context, r *auth.Restriction, transfers []types.Transfer, field string) error {
+	if r == nil {
+		return nil
+	}
+	pendingIDs := lo.FilterMap(transfers, func(t types.Transfer, _ int) (types.Uint128, bool) {
+		return t.PendingID, t.PendingID != (types.Uint128{})
+	})
+	pending := map[types.Uint128]types.Transfer{}
+	if len(pendingIDs) > 0 {
+		metrics.TotalTbLookupTransfersCall.Inc()
+		res, err := s.tb(ctx).LookupTransfers(lo.Uniq(pendingIDs))
+		if err != nil {
+			return err
+		}
+		pending = lo.KeyBy(res, func(t types.Transfer) types.Uint128 { return t.ID })
+	}
+	for i, t := range transfers {
+		if t.PendingID != (types.Uint128{}) {
+			p, ok := pending[t.PendingID]
+			if !ok {
+				// TigerBeetle reports the missing pending transfer.
+				continue
+			}
+			t = p
+		}
+		if !r.AllowsTransfer(t) {
+			return errForbidden("%s[%d]: outside the ledgers, codes or accounts of the caller", field, i)
+		}
+	}
+	return nil
+}
+
+// authorizeAccount checks the caller may read account id, an unknown account has nothing to read.
+func (s *App) authorizeAccount(ctx context.Context, r *auth.Restriction, id types.Uint128) error {
+	if r == nil {
+		return nil
+	}
+	if !r.AllowsAccountID(id) {
+		return errForbidden("account %s is outside the accounts of the caller", id)
+	}
+	if len(r.Ledgers) == 0 && len(r.Codes) == 0 {
+		return nil
+	}
+	metrics.TotalTbLookupAccountsCall.Inc()
+	accounts, err := s.tb(ctx).LookupAccounts([]types.Uint128{id})
+	if err != nil {
+		return err
+	}
+	if len(accounts) > 0 && !r.AllowsAccount(accounts[0]) {
+		return errForbidden("account %s is outside the ledgers or codes of the caller", id)
+	}
+	return nil
+}
+
+// restrictQueryFilter sets the ledger and code of filter when the caller is limited to a single one,
+// and checks the caller may query them. Results still need to be filtered on accounts.
+func restrictQueryFilter(r *auth.Restriction, filter *types.QueryFilter) error {
+	if r == nil {
+		return nil
+	}
+	if filter.Ledger == 0 && len(r.Ledgers) == 1 {
+		filter.Ledger = r.Ledgers[0]
+	}
+	if filter.Code == 0 && len(r.Codes) == 1 {
+		filter.Code = r.Codes[0]
+	}
+	if filter.Ledger == 0 && len(r.Ledgers) > 0 {
+		return errForbidden("filter.ledger is required, the caller is limited to ledgers %v", r.Ledgers)
+	}
+	if filter.Code == 0 && len(r.Codes) > 0 {
+		return errForbidden("filter.code is required, the caller is limited to codes %v", r.Codes)
+	}
+	if !r.AllowsLedger(filter.Ledger) || !r.AllowsCode(filter.Code) {
+		return errForbidden("filter is outside the ledgers or codes of the caller")
+	}
+	return nil
+}
@@ -0,0 +1,100 @@
+package grpc
+
+import (
+	"context"
+	"testing"
+
+	"github.com/lil5/tigerbeetle_api/auth"
+	"github.com/lil5/tigerbeetle_api/config"
+	"github.com/lil5/tigerbeetle_api/proto"
+	"github.com/samber/lo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/tigerbeetle/tigerbeetle-go/pkg/types"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestRestrictedCaller(t *testing.T) {
+	config.Config.IsBuffered = false
+	config.Config.IsDryRun = false
+	ctx := auth.WithKey(context.Background(), &auth.Key{
+		Name:   "jwt:tenant-a",
+		Scopes: []auth.Scope{auth.ScopeAdmin},
+		Restriction: &auth.Restriction{
+			Ledgers:  []uint32{1, 2},
+			Accounts: []auth.AccountRange{{Min: types.ToUint128(0x100), Max: types.ToUint128(0x1ff)}},
+		},
+	})
+
+	t.Run("should reject transfers touching other accounts", func(t *testing.T) {
+		mockClient := new(MockTigerBeetleClient)
+		app := &App{TB: mockClient}
+		_, err := app.CreateTransfers(ctx, &proto.CreateTransfersRequest{Transfers: []*proto.Transfer{
+			{Id: "1", DebitAccountId: "100", CreditAccountId: "101", Amount: 1, Ledger: 1, Code: 1},
+			{Id: "2", DebitAccountId: "100", CreditAccountId: "200", Amount: 1, Ledger: 1, Code: 1},
+		}})
+		assert.Equal(t, codes.PermissionDenied, status.Code(err))
+		assert.ErrorContains(t, err, "transfers[1]")
+		mockClient.AssertNotCalled(t, "CreateTransfers", mock.Anything)
+	})
+
+	t.Run("should check posts against their pending transfer", func(t *testing.T) {
+		mockClient := new(MockTigerBeetleClient)
+		app := &App{TB: mockClient}
+		mockClient.On("LookupTransfers", []types.Uint128{types.ToUint128(9)}).Return([]types.Transfer{
+			{ID: types.ToUint128(9), DebitAccountID: types.ToUint128(0x300), CreditAccountID: types.ToUint128(0x100), Ledger: 1, Code: 1},
+		}, nil)
+		_, err := app.CreateTransfers(ctx, &proto.CreateTransfersRequest{Transfers: []*proto.Transfer{
+			{Id: "1", PendingId: lo.ToPtr("9"), TransferFlags: &proto.TransferFlags{PostPendingTransfer: lo.ToPtr(true)}},
+		}})
+		assert.Equal(t, codes.PermissionDenied, status.Code(err))
+		mockClient.AssertNotCalled(t, "CreateTransfers", mock.Anything)
+	})
+
+	t.Run("should hide other accounts from lookups", func(t *testing.T) {
+		mockClient := new(MockTigerBeetleClient)
+		app := &App{TB: mockClient}
+		mockClient.On("LookupAccounts", mock.Anything).Return([]types.Account{
+			{ID: types.ToUint128(0x100), Ledger: 1, Code: 1},
+			{ID: types.ToUint128(0x200), Ledger: 1, Code: 1},
+			{ID: types.ToUint128(0x101), Ledger: 3, Code: 1},
+		}, nil)
+		reply, err := app.LookupAccounts(ctx, &proto.LookupAccountsRequest{AccountIds: []string{"100", "200", "101"}})
+		require.NoError(t, err)
+		require.Len(t, reply.Accounts, 1)
+		assert.Equal(t, "100", reply.Accounts[0].Id)
+	})
+
+	t.Run("should require a ledger in query filters", func(t *testing.T) {
+		mockClient := new(MockTigerBeetleClient)
+		app := &App{TB: mockClient}
+		_, err := app.QueryAccounts(ctx, &proto.QueryAccountsRequest{Filter: &proto.QueryFilter{Limit: 10}})
+		assert.Equal(t, codes.PermissionDenied, status.Code(err))
+		_, err = app.QueryAccounts(ctx, &proto.QueryAccountsRequest{Filter: &proto.QueryFilter{Ledger: lo.ToPtr[uint32](3), Limit: 10}})
+		assert.Equal(t, codes.PermissionDenied, status.Code(err))
+
+		mockClient.On("QueryAccounts", mock.Anything).Return([]types.Account{
+			{ID: types.ToUint128(0x100), Ledger: 2, Code: 1},
+			{ID: types.ToUint128(0x200), Ledger: 2, Code: 1},
+		}, nil)
+		reply, err := app.QueryAccounts(ctx, &proto.QueryAccountsRequest{Filter: &proto.QueryFilter{Ledger: lo.ToPtr[uint32](2), Limit: 10}})
+		require.NoError(t, err)
+		assert.Len(t, reply.Accounts, 1)
+	})
+
+	t.Run("should reject transfers of other accounts", func(t *testing.T) {
+		mockClient := new(MockTigerBeetleClient)
+		app := &App{TB: mockClient}
+		_, err := app.GetAccountTransfers(ctx, &proto.GetAccountTransfersRequest{Filter: &proto.AccountFilter{AccountId: "200", Limit: 10}})
+		assert.Equal(t, codes.PermissionDenied, status.Code(err))
+		mockClient.AssertNotCalled(t, "GetAccountTransfers", mock.Anything)
+	})
+
+	t.Run("should reject cluster wide operations", func(t *testing.T) {
+		app := &App{TB: new(MockTigerBeetleClient)}
+		_, err := app.ExportLedger(ctx, &proto.ExportLedgerRequest{Name: "backup"})
+		assert.Equal(t, codes.PermissionDenied, status.Code(err))
+	})
+}
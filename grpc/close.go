@@ -0,0 +1,167 @@
+package grpc
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"slices"
+
+	"github.com/lil5/tigerbeetle_api/auth"
+	"github.com/lil5/tigerbeetle_api/proto"
+	"github.com/samber/lo"
+	"github.com/tigerbeetle/tigerbeetle-go/pkg/types"
+)
+
+var (
+	ErrTransferNotFound   = errors.New("transfer not found")
+	ErrNotClosingTransfer = errors.New("not a pending closing transfer")
+)
+
+// amountMax lets a balancing transfer move the whole balance.
+var amountMax = types.BytesToUint128([16]byte(bytes.Repeat([]byte{0xff}, 16)))
+
+// CloseAccount closes an account with a pending closing_debit transfer to sweep_account_id,
+// linked after a balancing transfer that empties the account when sweep is set.
+// The account is reopened by voiding the closing transfer, see ReopenAccount.
+// A sweep moves money, it needs the write-transfers scope and is checked against the spending limits.
+func (s *App) CloseAccount(ctx context.Context, in *proto.CloseAccountRequest) (*proto.CloseAccountReply, error) {
+	if k := auth.FromContext(ctx); in.Sweep && k != nil && !k.Allows(auth.ScopeWriteTransfers) {
+		return nil, errForbidden("sweep needs the %s scope", auth.ScopeWriteTransfers)
+	}
+	accountID, err := s.accountID(in.AccountId)
+	if err != nil {
+		return nil, fmt.Errorf("account_id: %w", err)
+	}
+	sweepID, err := s.accountID(in.SweepAccountId)
+	if err != nil {
+		return nil, fmt.Errorf("sweep_account_id: %w", err)
+	}
+	accounts, err := s.tb(ctx).LookupAccounts([]types.Uint128{*accountID})
+	if err != nil {
+		return nil, err
+	}
+	if len(accounts) == 0 {
+		return nil, ErrAccountNotFound
+	}
+	account := accounts[0]
+	// The sweep is index 0 and the closing transfer index 1, with or without a sweep.
+	key := requestIdempotencyKey(ctx, in.IdempotencyKey)
+	newID := func(index int) types.Uint128 {
+		if key != nil {
+			return requestIdempotencyID(*key, opCloseAccount, index)
+		}
+		return types.ID()
+	}
+	code := uint16(lo.CoalesceOrEmpty(in.Code, uint32(account.Code)))
+
+	transfers := []types.Transfer{}
+	// The limits see the sweep with the balance it moves instead of amountMax.
+	var swept types.Uint128
+	if in.Sweep {
+		sweep := types.Transfer{
+			ID:     newID(0),
+			Amount: amountMax,
+			Ledger: account.Ledger,
+			Code:   code,
+		}
+		credits, debits := account.CreditsPosted.BigInt(), account.DebitsPosted.BigInt()
+		net := new(big.Int).Sub(&credits, &debits)
+		swept = types.BigIntToUint128(*net.Abs(net))
+		switch credits.Cmp(&debits) {
+		case 1:
+			sweep.DebitAccountID, sweep.CreditAccountID = *accountID, *sweepID
+			sweep.Flags = types.TransferFlags{Linked: true, BalancingDebit: true}.ToUint16()
+			transfers = append(transfers, sweep)
+		case -1:
+			sweep.DebitAccountID, sweep.CreditAccountID = *sweepID, *accountID
+			sweep.Flags = types.TransferFlags{Linked: true, BalancingCredit: true}.ToUint16()
+			transfers = append(transfers, sweep)
+		}
+	}
+	closing := types.Transfer{
+		ID:              newID(1),
+		DebitAccountID:  *accountID,
+		CreditAccountID: *sweepID,
+		Ledger:          account.Ledger,
+		Code:            code,
+		Flags:           types.TransferFlags{Pending: true, ClosingDebit: true}.ToUint16(),
+	}
+	transfers = append(transfers, closing)
+	for i, t := range transfers {
+		if err := s.validateTransfer(t); err != nil {
+			return nil, fmt.Errorf("transfers[%d]: %w", i, err)
+		}
+	}
+	if err := s.authorizeTransfers(ctx, restriction(ctx), transfers, "transfers"); err != nil {
+		return nil, err
+	}
+
+	checked := slices.Clone(transfers)
+	if len(checked) == 2 {
+		checked[0].Amount = swept
+	}
+	reply, err := s.createLimitedChain(ctx, transfers, checked, lo.Times(len(transfers), func(int) bool {
+		return key != nil
+	}))
+	if err != nil {
+		return nil, err
+	}
+	out := &proto.CloseAccountReply{
+		Ok:                reply.Ok,
+		ClosingTransferId: closing.ID.String(),
+		FailedLegIndex:    reply.FailedLegIndex,
+		Result:            reply.Result,
+	}
+	if len(transfers) == 2 {
+		out.SweepTransferId = lo.ToPtr(transfers[0].ID.String())
+	}
+	return out, nil
+}
+
+// ReopenAccount voids the pending closing transfer of a closed account.
+func (s *App) ReopenAccount(ctx context.Context, in *proto.ReopenAccountRequest) (*proto.ReopenAccountReply, error) {
+	closingID, err := HexStringToUint128(in.ClosingTransferId)
+	if err != nil {
+		return nil, fmt.Errorf("closing_transfer_id: %w", err)
+	}
+	res, err := s.tb(ctx).LookupTransfers([]types.Uint128{*closingID})
+	if err != nil {
+		return nil, err
+	}
+	if len(res) == 0 {
+		return nil, ErrTransferNotFound
+	}
+	closing := res[0]
+	if flags := closing.TransferFlags(); !flags.Pending || !(flags.ClosingDebit || flags.ClosingCredit) {
+		return nil, ErrNotClosingTransfer
+	}
+	if !restriction(ctx).AllowsTransfer(closing) {
+		return nil, errForbidden("closing transfer is outside the ledgers, codes or accounts of the caller")
+	}
+
+	key := requestIdempotencyKey(ctx, in.IdempotencyKey)
+	id := types.ID()
+	if key != nil {
+		id = requestIdempotencyID(*key, opReopenAccount, 0)
+	}
+	void := types.Transfer{
+		ID:              id,
+		DebitAccountID:  closing.DebitAccountID,
+		CreditAccountID: closing.CreditAccountID,
+		PendingID:       closing.ID,
+		Ledger:          closing.Ledger,
+		Code:            closing.Code,
+		Flags:           types.TransferFlags{VoidPendingTransfer: true}.ToUint16(),
+	}
+	reply, err := s.createChain(ctx, []types.Transfer{void}, []bool{key != nil})
+	if err != nil {
+		return nil, err
+	}
+	return &proto.ReopenAccountReply{
+		Ok:             reply.Ok,
+		VoidTransferId: id.String(),
+		Result:         reply.Result,
+	}, nil
+}
@@ -0,0 +1,154 @@
+package grpc
+
+import (
+	"context"
+	"testing"
+
+	"github.com/lil5/tigerbeetle_api/auth"
+	"github.com/lil5/tigerbeetle_api/config"
+	"github.com/lil5/tigerbeetle_api/limits"
+	"github.com/lil5/tigerbeetle_api/proto"
+	"github.com/samber/lo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/tigerbeetle/tigerbeetle-go/pkg/types"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestCloseAccount(t *testing.T) {
+	config.Config.IsDryRun = false
+
+	t.Run("should sweep the balance before closing", func(t *testing.T) {
+		mockClient := new(MockTigerBeetleClient)
+		app := &App{TB: mockClient}
+		mockClient.On("LookupAccounts", mock.Anything).Return([]types.Account{
+			{ID: types.ToUint128(1), Ledger: 1, Code: 7, CreditsPosted: types.ToUint128(100), DebitsPosted: types.ToUint128(40)},
+		}, nil)
+		var sent []types.Transfer
+		mockClient.On("CreateTransfers", mock.Anything).Run(func(args mock.Arguments) {
+			sent = args.Get(0).([]types.Transfer)
+		}).Return([]types.TransferEventResult{}, nil)
+
+		reply, err := app.CloseAccount(context.Background(), &proto.CloseAccountRequest{AccountId: "1", SweepAccountId: "2", Sweep: true})
+		require.NoError(t, err)
+		assert.True(t, reply.Ok)
+		require.Len(t, sent, 2)
+
+		sweep := sent[0]
+		assert.Equal(t, reply.GetSweepTransferId(), sweep.ID.String())
+		assert.Equal(t, types.ToUint128(1), sweep.DebitAccountID)
+		assert.Equal(t, types.ToUint128(2), sweep.CreditAccountID)
+		assert.Equal(t, amountMax, sweep.Amount)
+		assert.True(t, sweep.TransferFlags().Linked)
+		assert.True(t, sweep.TransferFlags().BalancingDebit)
+
+		closing := sent[1]
+		assert.Equal(t, reply.ClosingTransferId, closing.ID.String())
+		assert.Equal(t, types.ToUint128(1), closing.DebitAccountID)
+		assert.Equal(t, uint16(7), closing.Code)
+		assert.False(t, closing.TransferFlags().Linked)
+		assert.True(t, closing.TransferFlags().Pending)
+		assert.True(t, closing.TransferFlags().ClosingDebit)
+	})
+
+	t.Run("should sweep a debit balance from the sweep account", func(t *testing.T) {
+		mockClient := new(MockTigerBeetleClient)
+		app := &App{TB: mockClient}
+		mockClient.On("LookupAccounts", mock.Anything).Return([]types.Account{
+			{ID: types.ToUint128(1), Ledger: 1, Code: 7, DebitsPosted: types.ToUint128(40)},
+		}, nil)
+		mockClient.On("CreateTransfers", mock.MatchedBy(func(transfers []types.Transfer) bool {
+			return transfers[0].DebitAccountID == types.ToUint128(2) && transfers[0].TransferFlags().BalancingCredit
+		})).Return([]types.TransferEventResult{}, nil)
+
+		_, err := app.CloseAccount(context.Background(), &proto.CloseAccountRequest{AccountId: "1", SweepAccountId: "2", Sweep: true})
+		require.NoError(t, err)
+		mockClient.AssertExpectations(t)
+	})
+
+	t.Run("should close without sweeping and replay by key", func(t *testing.T) {
+		mockClient := new(MockTigerBeetleClient)
+		app := &App{TB: mockClient}
+		mockClient.On("LookupAccounts", mock.Anything).Return([]types.Account{
+			{ID: types.ToUint128(1), Ledger: 1, Code: 7, CreditsPosted: types.ToUint128(100)},
+		}, nil)
+		mockClient.On("CreateTransfers", mock.MatchedBy(func(transfers []types.Transfer) bool {
+			return len(transfers) == 1 && transfers[0].ID == requestIdempotencyID("close-1", opCloseAccount, 1)
+		})).Return([]types.TransferEventResult{{Index: 0, Result: types.TransferExists}}, nil)
+
+		reply, err := app.CloseAccount(context.Background(), &proto.CloseAccountRequest{AccountId: "1", SweepAccountId: "2", IdempotencyKey: lo.ToPtr("close-1")})
+		require.NoError(t, err)
+		assert.True(t, reply.Ok)
+		assert.Nil(t, reply.SweepTransferId)
+		mockClient.AssertExpectations(t)
+	})
+
+	t.Run("should reject unknown accounts", func(t *testing.T) {
+		mockClient := new(MockTigerBeetleClient)
+		app := &App{TB: mockClient}
+		mockClient.On("LookupAccounts", mock.Anything).Return([]types.Account{}, nil)
+		_, err := app.CloseAccount(context.Background(), &proto.CloseAccountRequest{AccountId: "1", SweepAccountId: "2"})
+		assert.ErrorIs(t, err, ErrAccountNotFound)
+	})
+
+	t.Run("should refuse a sweep without the write-transfers scope", func(t *testing.T) {
+		mockClient := new(MockTigerBeetleClient)
+		app := &App{TB: mockClient}
+		ctx := auth.WithKey(context.Background(), &auth.Key{Name: "accounts", Scopes: []auth.Scope{auth.ScopeWriteAccounts}})
+
+		_, err := app.CloseAccount(ctx, &proto.CloseAccountRequest{AccountId: "1", SweepAccountId: "2", Sweep: true})
+		assert.Equal(t, codes.PermissionDenied, status.Code(err))
+		mockClient.AssertNotCalled(t, "CreateTransfers", mock.Anything)
+	})
+
+	t.Run("should check the sweep against spending limits", func(t *testing.T) {
+		lim, err := limits.Parse([]byte("limits:\n  - {name: daily, window: 24h, amount: 50}\n"))
+		require.NoError(t, err)
+		mockClient := new(MockTigerBeetleClient)
+		app := &App{TB: mockClient, Limits: lim}
+		mockClient.On("LookupAccounts", mock.Anything).Return([]types.Account{
+			{ID: types.ToUint128(1), Ledger: 1, Code: 7, CreditsPosted: types.ToUint128(100), DebitsPosted: types.ToUint128(40)},
+		}, nil)
+		mockClient.On("GetAccountTransfers", mock.Anything).Return([]types.Transfer{}, nil)
+		ctx := auth.WithKey(context.Background(), &auth.Key{Name: "both", Scopes: []auth.Scope{auth.ScopeWriteAccounts, auth.ScopeWriteTransfers}})
+
+		reply, err := app.CloseAccount(ctx, &proto.CloseAccountRequest{AccountId: "1", SweepAccountId: "2", Sweep: true})
+		require.NoError(t, err)
+		assert.False(t, reply.Ok)
+		assert.Equal(t, lo.ToPtr(int32(0)), reply.FailedLegIndex)
+		assert.Equal(t, proto.CreateTransferResult_TransferSpendingLimitExceeded, reply.Result)
+		mockClient.AssertNotCalled(t, "CreateTransfers", mock.Anything)
+	})
+}
+
+func TestReopenAccount(t *testing.T) {
+	config.Config.IsDryRun = false
+
+	t.Run("should void the closing transfer", func(t *testing.T) {
+		mockClient := new(MockTigerBeetleClient)
+		app := &App{TB: mockClient}
+		mockClient.On("LookupTransfers", mock.Anything).Return([]types.Transfer{{
+			ID: types.ToUint128(9), DebitAccountID: types.ToUint128(1), CreditAccountID: types.ToUint128(2), Ledger: 1, Code: 7,
+			Flags: types.TransferFlags{Pending: true, ClosingDebit: true}.ToUint16(),
+		}}, nil)
+		mockClient.On("CreateTransfers", mock.MatchedBy(func(transfers []types.Transfer) bool {
+			return transfers[0].PendingID == types.ToUint128(9) && transfers[0].TransferFlags().VoidPendingTransfer
+		})).Return([]types.TransferEventResult{}, nil)
+
+		reply, err := app.ReopenAccount(context.Background(), &proto.ReopenAccountRequest{ClosingTransferId: "9"})
+		require.NoError(t, err)
+		assert.True(t, reply.Ok)
+		assert.NotEmpty(t, reply.VoidTransferId)
+		mockClient.AssertExpectations(t)
+	})
+
+	t.Run("should reject other transfers", func(t *testing.T) {
+		mockClient := new(MockTigerBeetleClient)
+		app := &App{TB: mockClient}
+		mockClient.On("LookupTransfers", mock.Anything).Return([]types.Transfer{{ID: types.ToUint128(9), Flags: types.TransferFlags{Pending: true}.ToUint16()}}, nil)
+		_, err := app.ReopenAccount(context.Background(), &proto.ReopenAccountRequest{ClosingTransferId: "9"})
+		assert.ErrorIs(t, err, ErrNotClosingTransfer)
+	})
+}
@@ -0,0 +1,213 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/lil5/tigerbeetle_api/config"
+	"github.com/lil5/tigerbeetle_api/decimal"
+	"github.com/lil5/tigerbeetle_api/metrics"
+	"github.com/lil5/tigerbeetle_api/proto"
+	"github.com/samber/lo"
+	"github.com/tigerbeetle/tigerbeetle-go/pkg/types"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+var ErrUnbalancedLegs = errors.New("legs do not add up to the total of their ledger")
+
+// CreateCompoundTransfer submits legs as a single linked chain,
+// either all legs are created or none.
+func (s *App) CreateCompoundTransfer(ctx context.Context, in *proto.CreateCompoundTransferRequest) (*proto.CreateCompoundTransferReply, error) {
+	return s.createCompound(ctx, in, opCompoundTransfer)
+}
+
+// createCompound is CreateCompoundTransfer with the operation the leg ids of an idempotency key are derived for.
+func (s *App) createCompound(ctx context.Context, in *proto.CreateCompoundTransferRequest, operation string) (*proto.CreateCompoundTransferReply, error) {
+	if len(in.Legs) == 0 {
+		return nil, ErrZeroTransfers
+	}
+	if err := checkItems("legs", len(in.Legs)); err != nil {
+		return nil, err
+	}
+	// A linked chain must fit in a single batch.
+	if len(in.Legs) > TB_MAX_BATCH_SIZE {
+		return nil, status.Errorf(codes.InvalidArgument, "legs: %d legs exceed the maximum of %d per chain", len(in.Legs), TB_MAX_BATCH_SIZE)
+	}
+	if err := s.resolveTransferAliases(in.Legs); err != nil {
+		return nil, err
+	}
+	// Legs always derive their id from the index, even a single leg.
+	key := requestIdempotencyKey(ctx, in.IdempotencyKey)
+	transfers := make([]types.Transfer, 0, len(in.Legs))
+	keyed := make([]bool, len(in.Legs))
+	for i, leg := range in.Legs {
+		inheritLeg(leg, in)
+		transfer, err := TransferFromProtoToTigerbeetle(leg)
+		if err != nil {
+			return nil, fmt.Errorf("legs[%d]: %w", i, err)
+		}
+		keyed[i] = leg.IdempotencyKey != nil
+		if !keyed[i] && key != nil {
+			if transfer.ID, err = keyedID(requestIdempotencyID(*key, operation, i), transfer.ID); err != nil {
+				return nil, fmt.Errorf("legs[%d]: %w", i, err)
+			}
+			keyed[i] = true
+		}
+		if transfer.ID == (types.Uint128{}) {
+			transfer.ID = types.ID()
+		}
+		flags := transfer.TransferFlags()
+		flags.Linked = i < len(in.Legs)-1
+		transfer.Flags = flags.ToUint16()
+		if err := s.applyAmountDecimal(leg, transfer); err != nil {
+			return nil, fmt.Errorf("legs[%d]: %w", i, err)
+		}
+		if err := s.validateTransfer(*transfer); err != nil {
+			return nil, fmt.Errorf("legs[%d]: %w", i, err)
+		}
+		transfers = append(transfers, *transfer)
+	}
+	if err := s.authorizeTransfers(ctx, restriction(ctx), transfers, "legs"); err != nil {
+		return nil, err
+	}
+	if err := s.checkLegTotals(transfers, in.Totals); err != nil {
+		return nil, err
+	}
+	return s.createLimitedChain(ctx, transfers, transfers, keyed)
+}
+
+// createLimitedChain checks the spending limits of a linked chain and submits it with createChain,
+// a transfer over a limit rejects the whole chain and nothing is submitted.
+// checked is the chain as the limits see it, such as a sweep with the balance it moves.
+func (s *App) createLimitedChain(ctx context.Context, transfers, checked []types.Transfer, keyed []bool) (*proto.CreateCompoundTransferReply, error) {
+	defer s.lockLimits(checked)()
+	kept, rejected, err := s.checkLimits(ctx, checked)
+	if err != nil {
+		return nil, err
+	}
+	if kept != nil {
+		breach, _ := lo.Find(rejected, func(r types.TransferEventResult) bool {
+			return r.Result == ResultSpendingLimitExceeded
+		})
+		// Counted as tigerbeetle reports a failed chain, the other legs fail with it.
+		metrics.CountTransferResults(transfers, lo.Times(len(transfers), func(i int) types.TransferEventResult {
+			if i == int(breach.Index) {
+				return breach
+			}
+			return types.TransferEventResult{Index: uint32(i), Result: types.TransferLinkedEventFailed}
+		}))
+		return &proto.CreateCompoundTransferReply{
+			Ids: lo.Map(transfers, func(t types.Transfer, _ int) string {
+				return t.ID.String()
+			}),
+			FailedLegIndex: lo.ToPtr(int32(breach.Index)),
+			Result:         proto.CreateTransferResult_TransferSpendingLimitExceeded,
+		}, nil
+	}
+	return s.createChain(ctx, transfers, keyed)
+}
+
+// createChain submits transfers, linked by the caller, directly instead of through the buffer
+// so the chain is never split. keyed marks the transfers with an idempotency key.
+func (s *App) createChain(ctx context.Context, transfers []types.Transfer, keyed []bool) (*proto.CreateCompoundTransferReply, error) {
+	var results []types.TransferEventResult
+	metrics.TotalTbCreateTransfersCall.Inc()
+	metrics.TotalCreateTransferTx.Add(float64(len(transfers)))
+	if !config.Config.IsDryRun {
+		var err error
+		results, err = s.tb(ctx).CreateTransfers(transfers)
+		if err != nil {
+			return nil, err
+		}
+		metrics.TotalCreateTransferTxErr.Add(float64(len(results)))
+	}
+	metrics.CountTransferResults(transfers, results)
+
+	reply := &proto.CreateCompoundTransferReply{
+		Ok: true,
+		Ids: lo.Map(transfers, func(t types.Transfer, _ int) string {
+			return t.ID.String()
+		}),
+	}
+	// Every other transfer of a failed chain reports TransferLinkedEventFailed.
+	failed, ok := lo.Find(results, func(r types.TransferEventResult) bool {
+		return r.Result != types.TransferLinkedEventFailed
+	})
+	// A keyed chain that exists already is a replay of the same request.
+	if ok && !(failed.Result == types.TransferExists && keyed[failed.Index]) {
+		reply.Ok = false
+		reply.FailedLegIndex = lo.ToPtr(int32(failed.Index))
+		reply.Result = proto.CreateTransferResult(failed.Result)
+	}
+	return reply, nil
+}
+
+// inheritLeg fills the zero fields of leg with the shared metadata of the request.
+func inheritLeg(leg *proto.Transfer, in *proto.CreateCompoundTransferRequest) {
+	if leg.Ledger == 0 {
+		leg.Ledger = in.Ledger
+	}
+	if leg.Code == 0 {
+		leg.Code = in.Code
+	}
+	if leg.UserData128 == "" {
+		leg.UserData128 = in.UserData128
+	}
+	if leg.UserData64 == 0 {
+		leg.UserData64 = in.UserData64
+	}
+	if leg.UserData32 == 0 {
+		leg.UserData32 = in.UserData32
+	}
+}
+
+// checkLegTotals checks the legs of each ledger add up to its total, every ledger of the legs needs one.
+func (s *App) checkLegTotals(transfers []types.Transfer, totals []*proto.LedgerTotal) error {
+	if len(totals) == 0 {
+		return fmt.Errorf("%w: totals are required", ErrUnbalancedLegs)
+	}
+	expected := map[uint32]*big.Int{}
+	for i, total := range totals {
+		if _, ok := expected[total.Ledger]; ok {
+			return fmt.Errorf("totals[%d]: duplicate ledger %d", i, total.Ledger)
+		}
+		amount := new(big.Int).SetUint64(total.Amount)
+		if total.AmountDecimal != nil {
+			scale, ok := s.assetScale(total.Ledger)
+			if !ok {
+				return fmt.Errorf("totals[%d]: amount_decimal: ledger %d has no asset scale in the registry", i, total.Ledger)
+			}
+			var err error
+			if amount, err = decimal.Parse(*total.AmountDecimal, scale); err != nil {
+				return fmt.Errorf("totals[%d]: amount_decimal: %w", i, err)
+			}
+		}
+		expected[total.Ledger] = amount
+	}
+
+	sums := map[uint32]*big.Int{}
+	for _, t := range transfers {
+		sum, ok := sums[t.Ledger]
+		if !ok {
+			sum = new(big.Int)
+			sums[t.Ledger] = sum
+		}
+		amount := t.Amount.BigInt()
+		sum.Add(sum, &amount)
+	}
+	for ledger := range sums {
+		if _, ok := expected[ledger]; !ok {
+			return fmt.Errorf("%w: ledger %d has no total", ErrUnbalancedLegs, ledger)
+		}
+	}
+	for ledger, total := range expected {
+		sum := lo.ValueOr(sums, ledger, new(big.Int))
+		if sum.Cmp(total) != 0 {
+			return fmt.Errorf("%w: ledger %d, legs %s, total %s", ErrUnbalancedLegs, ledger, sum, total)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,123 @@
+package grpc
+
+import (
+	"context"
+	"testing"
+
+	"github.com/lil5/tigerbeetle_api/config"
+	"github.com/lil5/tigerbeetle_api/proto"
+	"github.com/samber/lo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/tigerbeetle/tigerbeetle-go/pkg/types"
+)
+
+func compoundRequest() *proto.CreateCompoundTransferRequest {
+	return &proto.CreateCompoundTransferRequest{
+		Ledger:     1,
+		Code:       1,
+		UserData64: 42,
+		Legs: []*proto.Transfer{
+			{DebitAccountId: "10", CreditAccountId: "20", Amount: 800},
+			{DebitAccountId: "10", CreditAccountId: "30", Amount: 150},
+			{DebitAccountId: "10", CreditAccountId: "40", Amount: 50, Code: 10},
+		},
+		Totals: []*proto.LedgerTotal{{Ledger: 1, Amount: 1000}},
+	}
+}
+
+func TestCreateCompoundTransfer(t *testing.T) {
+	config.Config.IsDryRun = false
+
+	t.Run("should link legs and share metadata", func(t *testing.T) {
+		mockClient := new(MockTigerBeetleClient)
+		app := &App{TB: mockClient}
+		var sent []types.Transfer
+		mockClient.On("CreateTransfers", mock.Anything).Run(func(args mock.Arguments) {
+			sent = args.Get(0).([]types.Transfer)
+		}).Return([]types.TransferEventResult{}, nil)
+
+		reply, err := app.CreateCompoundTransfer(context.Background(), compoundRequest())
+		require.NoError(t, err)
+		assert.True(t, reply.Ok)
+		assert.Nil(t, reply.FailedLegIndex)
+		require.Len(t, sent, 3)
+		require.Len(t, reply.Ids, 3)
+		for i, transfer := range sent {
+			assert.Equal(t, i < 2, transfer.TransferFlags().Linked)
+			assert.NotEqual(t, types.Uint128{}, transfer.ID)
+			assert.Equal(t, transfer.ID.String(), reply.Ids[i])
+			assert.Equal(t, uint32(1), transfer.Ledger)
+			assert.Equal(t, uint64(42), transfer.UserData64)
+		}
+		assert.Equal(t, uint16(1), sent[0].Code)
+		assert.Equal(t, uint16(10), sent[2].Code)
+	})
+
+	t.Run("should reject legs that do not add up", func(t *testing.T) {
+		mockClient := new(MockTigerBeetleClient)
+		app := &App{TB: mockClient}
+		in := compoundRequest()
+		in.Totals = []*proto.LedgerTotal{{Ledger: 1, Amount: 999}}
+		_, err := app.CreateCompoundTransfer(context.Background(), in)
+		assert.ErrorIs(t, err, ErrUnbalancedLegs)
+
+		in = compoundRequest()
+		in.Legs[2].Ledger = 2
+		in.Totals = []*proto.LedgerTotal{{Ledger: 1, Amount: 950}}
+		_, err = app.CreateCompoundTransfer(context.Background(), in)
+		assert.ErrorIs(t, err, ErrUnbalancedLegs)
+
+		in = compoundRequest()
+		in.Totals = nil
+		_, err = app.CreateCompoundTransfer(context.Background(), in)
+		assert.ErrorIs(t, err, ErrUnbalancedLegs)
+		mockClient.AssertNotCalled(t, "CreateTransfers", mock.Anything)
+
+		mockClient.On("CreateTransfers", mock.Anything).Return([]types.TransferEventResult{}, nil).Once()
+		app.Registry = testRegistry(t)
+		in = compoundRequest()
+		in.Totals = []*proto.LedgerTotal{{Ledger: 1, AmountDecimal: lo.ToPtr("10.00")}}
+		_, err = app.CreateCompoundTransfer(context.Background(), in)
+		assert.NoError(t, err)
+		mockClient.AssertExpectations(t)
+	})
+
+	t.Run("should report the failing leg", func(t *testing.T) {
+		mockClient := new(MockTigerBeetleClient)
+		app := &App{TB: mockClient}
+		mockClient.On("CreateTransfers", mock.Anything).Return([]types.TransferEventResult{
+			{Index: 0, Result: types.TransferLinkedEventFailed},
+			{Index: 1, Result: types.TransferExceedsCredits},
+			{Index: 2, Result: types.TransferLinkedEventFailed},
+		}, nil)
+
+		reply, err := app.CreateCompoundTransfer(context.Background(), compoundRequest())
+		require.NoError(t, err)
+		assert.False(t, reply.Ok)
+		assert.Equal(t, int32(1), reply.GetFailedLegIndex())
+		assert.Equal(t, proto.CreateTransferResult_TransferExceedsCredits, reply.Result)
+	})
+
+	t.Run("should derive ids from the idempotency key and accept replays", func(t *testing.T) {
+		mockClient := new(MockTigerBeetleClient)
+		app := &App{TB: mockClient}
+		mockClient.On("CreateTransfers", mock.MatchedBy(func(transfers []types.Transfer) bool {
+			return transfers[0].ID == requestIdempotencyID("payment", opCompoundTransfer, 0) &&
+				transfers[2].ID == requestIdempotencyID("payment", opCompoundTransfer, 2)
+		})).Return([]types.TransferEventResult{
+			{Index: 0, Result: types.TransferExists},
+			{Index: 1, Result: types.TransferLinkedEventFailed},
+			{Index: 2, Result: types.TransferLinkedEventFailed},
+		}, nil)
+
+		in := compoundRequest()
+		in.IdempotencyKey = lo.ToPtr("payment")
+		reply, err := app.CreateCompoundTransfer(context.Background(), in)
+		require.NoError(t, err)
+		assert.True(t, reply.Ok)
+		assert.Equal(t, requestIdempotencyID("payment", opCompoundTransfer, 1).String(), reply.Ids[1])
+		mockClient.AssertExpectations(t)
+	})
+}
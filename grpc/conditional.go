@@ -0,0 +1,155 @@
+package grpc
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/lil5/tigerbeetle_api/proto"
+	"github.com/tigerbeetle/tigerbeetle-go/pkg/types"
+)
+
+// Results of the conditions of a transfer, beyond the results of TigerBeetle.
+const (
+	ResultMinBalanceNotMet   = types.CreateTransferResult(proto.CreateTransferResult_TransferMinBalanceNotMet)
+	ResultMaxBalanceExceeded = types.CreateTransferResult(proto.CreateTransferResult_TransferMaxBalanceExceeded)
+)
+
+var (
+	ErrNoControlAccounts = errors.New("ledger has no control_account and operator_account in the registry")
+	ErrConditionalPost   = errors.New("conditions can not be set on post or void transfers")
+)
+
+type legRole int
+
+const (
+	legTransfer legRole = iota
+	legHelper
+	legMinCheck
+	legMaxCheck
+)
+
+// conditionLeg is a transfer of an expanded request, origin is the index of the transfer it belongs to.
+type conditionLeg struct {
+	origin int
+	role   legRole
+}
+
+// expandConditions wraps each transfer with require_min_balance or max_balance in the linked chain
+// of the TigerBeetle balance-conditional and balance-bounds recipes.
+// The chain ends with the linked flag of the transfer, so chains of the request are kept.
+// Without conditions transfers is returned as is with nil legs.
+func (s *App) expandConditions(in []*proto.Transfer, transfers []types.Transfer) ([]types.Transfer, []conditionLeg, error) {
+	conditional := false
+	for _, t := range in {
+		conditional = conditional || t.RequireMinBalance != nil || t.MaxBalance != nil
+	}
+	if !conditional {
+		return transfers, nil, nil
+	}
+
+	expanded := make([]types.Transfer, 0, len(transfers))
+	legs := make([]conditionLeg, 0, len(transfers))
+	add := func(t types.Transfer, origin int, role legRole) {
+		expanded = append(expanded, t)
+		legs = append(legs, conditionLeg{origin, role})
+	}
+	for i, t := range transfers {
+		minBalance, maxBalance := in[i].RequireMinBalance, in[i].MaxBalance
+		if minBalance == nil && maxBalance == nil {
+			add(t, i, legTransfer)
+			continue
+		}
+		flags := t.TransferFlags()
+		if flags.PostPendingTransfer || flags.VoidPendingTransfer {
+			return nil, nil, fmt.Errorf("transfers[%d]: %w", i, ErrConditionalPost)
+		}
+		control, operator, err := s.controlAccounts(t.Ledger)
+		if err != nil {
+			return nil, nil, fmt.Errorf("transfers[%d]: %w", i, err)
+		}
+		leg := func(debit, credit types.Uint128, amount types.Uint128, f types.TransferFlags) types.Transfer {
+			f.Linked = true
+			return types.Transfer{ID: types.ID(), DebitAccountID: debit, CreditAccountID: credit, Amount: amount, Ledger: t.Ledger, Code: t.Code, Flags: f.ToUint16()}
+		}
+		void := func(pending types.Transfer) types.Transfer {
+			v := leg(pending.DebitAccountID, pending.CreditAccountID, types.Uint128{}, types.TransferFlags{VoidPendingTransfer: true})
+			v.PendingID = pending.ID
+			return v
+		}
+		linked := flags.Linked
+
+		// The debit account can hold the minimum when it is reserved by a pending transfer.
+		if minBalance != nil {
+			check := leg(t.DebitAccountID, operator, types.ToUint128(*minBalance), types.TransferFlags{Pending: true})
+			add(check, i, legMinCheck)
+			add(void(check), i, legHelper)
+		}
+		if maxBalance != nil {
+			flags.Linked = true
+			t.Flags = flags.ToUint16()
+		}
+		add(t, i, legTransfer)
+
+		// The control account can take at most max_balance, then fails the balancing transfer of the whole balance.
+		if maxBalance != nil {
+			limit := types.ToUint128(*maxBalance)
+			add(leg(control, operator, limit, types.TransferFlags{}), i, legHelper)
+			check := leg(t.CreditAccountID, control, amountMax, types.TransferFlags{Pending: true, BalancingDebit: true})
+			add(check, i, legMaxCheck)
+			add(void(check), i, legHelper)
+			last := leg(operator, control, limit, types.TransferFlags{})
+			last.Flags = types.TransferFlags{Linked: linked}.ToUint16()
+			add(last, i, legHelper)
+		}
+	}
+	return expanded, legs, nil
+}
+
+// controlAccounts returns the control and operator account of a ledger.
+func (s *App) controlAccounts(ledger uint32) (control, operator types.Uint128, err error) {
+	if s.Registry == nil {
+		return control, operator, ErrNoControlAccounts
+	}
+	l, ok := s.Registry.Ledger(ledger)
+	if !ok || l.ControlAccount == "" || l.OperatorAccount == "" {
+		return control, operator, fmt.Errorf("%w: ledger %d", ErrNoControlAccounts, ledger)
+	}
+	c, err := s.accountID(l.ControlAccount)
+	if err != nil {
+		return control, operator, fmt.Errorf("control_account: %w", err)
+	}
+	o, err := s.accountID(l.OperatorAccount)
+	if err != nil {
+		return control, operator, fmt.Errorf("operator_account: %w", err)
+	}
+	return *c, *o, nil
+}
+
+// collapseConditionResults maps the results of an expanded request back to its transfers.
+// A transfer gets the first result of its legs that is not TransferLinkedEventFailed,
+// with the failed check of a condition reported as its own result.
+func collapseConditionResults(results []types.TransferEventResult, legs []conditionLeg) []types.TransferEventResult {
+	if legs == nil {
+		return results
+	}
+	collapsed := []types.TransferEventResult{}
+	at := map[int]int{}
+	for _, r := range results {
+		leg := legs[r.Index]
+		switch {
+		case leg.role == legMinCheck && r.Result == types.TransferExceedsCredits:
+			r.Result = ResultMinBalanceNotMet
+		case leg.role == legMaxCheck && r.Result == types.TransferExceedsDebits:
+			r.Result = ResultMaxBalanceExceeded
+		}
+		r.Index = uint32(leg.origin)
+		j, ok := at[leg.origin]
+		if !ok {
+			at[leg.origin] = len(collapsed)
+			collapsed = append(collapsed, r)
+		} else if collapsed[j].Result == types.TransferLinkedEventFailed {
+			collapsed[j] = r
+		}
+	}
+	return collapsed
+}
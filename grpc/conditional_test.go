@@ -0,0 +1,115 @@
+package grpc
+
+import (
+	"context"
+	"testing"
+
+	"github.com/lil5/tigerbeetle_api/config"
+	"github.com/lil5/tigerbeetle_api/metrics"
+	"github.com/lil5/tigerbeetle_api/proto"
+	"github.com/lil5/tigerbeetle_api/registry"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/samber/lo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/tigerbeetle/tigerbeetle-go/pkg/types"
+)
+
+func TestConditionalTransfers(t *testing.T) {
+	config.Config.IsBuffered = false
+	config.Config.IsDryRun = false
+	reg, err := registry.Parse([]byte(`
+ledgers:
+  - {id: 1, control_account: "c", operator_account: "0f"}
+  - {id: 2}
+codes:
+  - {id: 1}
+`))
+	require.NoError(t, err)
+	control, operator := types.ToUint128(0xc), types.ToUint128(0xf)
+
+	t.Run("should expand conditions into linked chains", func(t *testing.T) {
+		mockClient := new(MockTigerBeetleClient)
+		app := &App{TB: mockClient, Registry: reg}
+		var sent []types.Transfer
+		mockClient.On("CreateTransfers", mock.Anything).Run(func(args mock.Arguments) {
+			sent = args.Get(0).([]types.Transfer)
+		}).Return([]types.TransferEventResult{}, nil)
+
+		_, err := app.CreateTransfers(context.Background(), &proto.CreateTransfersRequest{Transfers: []*proto.Transfer{
+			{Id: "1", DebitAccountId: "a", CreditAccountId: "b", Amount: 10, Ledger: 1, Code: 1, RequireMinBalance: lo.ToPtr(uint64(50)), MaxBalance: lo.ToPtr(uint64(100))},
+			{Id: "2", DebitAccountId: "a", CreditAccountId: "b", Amount: 5, Ledger: 1, Code: 1},
+		}})
+		require.NoError(t, err)
+		require.Len(t, sent, 8)
+
+		minCheck := sent[0]
+		assert.Equal(t, types.ToUint128(0xa), minCheck.DebitAccountID)
+		assert.Equal(t, operator, minCheck.CreditAccountID)
+		assert.Equal(t, types.ToUint128(50), minCheck.Amount)
+		assert.True(t, minCheck.TransferFlags().Pending)
+		assert.Equal(t, minCheck.ID, sent[1].PendingID)
+		assert.True(t, sent[1].TransferFlags().VoidPendingTransfer)
+
+		assert.Equal(t, types.ToUint128(1), sent[2].ID)
+
+		assert.Equal(t, control, sent[3].DebitAccountID)
+		assert.Equal(t, types.ToUint128(100), sent[3].Amount)
+		maxCheck := sent[4]
+		assert.Equal(t, types.ToUint128(0xb), maxCheck.DebitAccountID)
+		assert.Equal(t, control, maxCheck.CreditAccountID)
+		assert.True(t, maxCheck.TransferFlags().BalancingDebit)
+		assert.Equal(t, maxCheck.ID, sent[5].PendingID)
+		assert.Equal(t, control, sent[6].CreditAccountID)
+
+		for i, transfer := range sent[:6] {
+			assert.True(t, transfer.TransferFlags().Linked, i)
+		}
+		assert.False(t, sent[6].TransferFlags().Linked)
+		assert.Equal(t, types.ToUint128(2), sent[7].ID)
+	})
+
+	t.Run("should report failed conditions on the transfer", func(t *testing.T) {
+		mockClient := new(MockTigerBeetleClient)
+		app := &App{TB: mockClient, Registry: reg}
+		mockClient.On("CreateTransfers", mock.Anything).Return([]types.TransferEventResult{
+			{Index: 0, Result: types.TransferExceedsCredits},
+			{Index: 1, Result: types.TransferLinkedEventFailed},
+			{Index: 2, Result: types.TransferLinkedEventFailed},
+			{Index: 3, Result: types.TransferLinkedEventFailed},
+			{Index: 4, Result: types.TransferLinkedEventFailed},
+			{Index: 5, Result: types.TransferExceedsDebits},
+			{Index: 6, Result: types.TransferLinkedEventFailed},
+			{Index: 7, Result: types.TransferLinkedEventFailed},
+		}, nil)
+		counted := func(result string) float64 {
+			return testutil.ToFloat64(metrics.TotalCreateTransferResults.WithLabelValues(result, "1"))
+		}
+		minBefore, maxBefore, linkedBefore := counted("TransferMinBalanceNotMet"), counted("TransferMaxBalanceExceeded"), counted("TransferLinkedEventFailed")
+
+		reply, err := app.CreateTransfers(context.Background(), &proto.CreateTransfersRequest{Transfers: []*proto.Transfer{
+			{Id: "1", DebitAccountId: "a", CreditAccountId: "b", Amount: 10, Ledger: 1, Code: 1, RequireMinBalance: lo.ToPtr(uint64(50))},
+			{Id: "2", DebitAccountId: "a", CreditAccountId: "b", Amount: 10, Ledger: 1, Code: 1, MaxBalance: lo.ToPtr(uint64(100))},
+		}})
+		require.NoError(t, err)
+		require.Len(t, reply.Results, 2)
+		assert.Equal(t, int32(0), reply.Results[0].Index)
+		assert.Equal(t, proto.CreateTransferResult_TransferMinBalanceNotMet, reply.Results[0].Result)
+		assert.Equal(t, "1", reply.Results[0].Id)
+		assert.Equal(t, int32(1), reply.Results[1].Index)
+		assert.Equal(t, proto.CreateTransferResult_TransferMaxBalanceExceeded, reply.Results[1].Result)
+		// Counted once per request transfer, the helper transfers are not counted
+		assert.Equal(t, minBefore+1, counted("TransferMinBalanceNotMet"))
+		assert.Equal(t, maxBefore+1, counted("TransferMaxBalanceExceeded"))
+		assert.Equal(t, linkedBefore, counted("TransferLinkedEventFailed"))
+	})
+
+	t.Run("should require control accounts", func(t *testing.T) {
+		app := &App{TB: new(MockTigerBeetleClient), Registry: reg}
+		_, err := app.CreateTransfers(context.Background(), &proto.CreateTransfersRequest{Transfers: []*proto.Transfer{
+			{Id: "1", DebitAccountId: "a", CreditAccountId: "b", Amount: 10, Ledger: 2, Code: 1, MaxBalance: lo.ToPtr(uint64(100))},
+		}})
+		assert.ErrorIs(t, err, ErrNoControlAccounts)
+	})
+}
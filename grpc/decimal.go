@@ -0,0 +1,74 @@
+package grpc
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/lil5/tigerbeetle_api/decimal"
+	"github.com/lil5/tigerbeetle_api/proto"
+	"github.com/samber/lo"
+	"github.com/tigerbeetle/tigerbeetle-go/pkg/types"
+)
+
+var ErrAmountDecimalConflict = errors.New("amount and amount_decimal differ")
+
+// assetScale returns the asset scale of a ledger in the registry.
+func (s *App) assetScale(ledger uint32) (uint32, bool) {
+	if s.Registry == nil {
+		return 0, false
+	}
+	l, ok := s.Registry.Ledger(ledger)
+	if !ok {
+		return 0, false
+	}
+	return l.AssetScale, true
+}
+
+// applyAmountDecimal replaces the amount of t by amount_decimal of in, when set.
+// An amount sent along with it must be equal.
+func (s *App) applyAmountDecimal(in *proto.Transfer, t *types.Transfer) error {
+	if in.AmountDecimal == nil {
+		return nil
+	}
+	scale, ok := s.assetScale(t.Ledger)
+	if !ok {
+		return fmt.Errorf("amount_decimal: ledger %d has no asset scale in the registry", t.Ledger)
+	}
+	amount, err := decimal.ParseUint128(*in.AmountDecimal, scale)
+	if err != nil {
+		return fmt.Errorf("amount_decimal: %w", err)
+	}
+	if in.Amount != 0 && types.ToUint128(uint64(in.Amount)) != amount {
+		return ErrAmountDecimalConflict
+	}
+	t.Amount = amount
+	return nil
+}
+
+// balanceToProto converts a derived balance of account, adding decimals with the asset scale of its ledger.
+func (s *App) balanceToProto(b *balance, account types.Account, source proto.BalanceSource) (*proto.BalanceAt, error) {
+	pb, err := b.toProto(account.ID, source)
+	if err != nil {
+		return nil, err
+	}
+	if scale, ok := s.assetScale(account.Ledger); ok {
+		pb.DebitsPendingDecimal = lo.ToPtr(decimal.Format(&b.DebitsPending, scale))
+		pb.DebitsPostedDecimal = lo.ToPtr(decimal.Format(&b.DebitsPosted, scale))
+		pb.CreditsPendingDecimal = lo.ToPtr(decimal.Format(&b.CreditsPending, scale))
+		pb.CreditsPostedDecimal = lo.ToPtr(decimal.Format(&b.CreditsPosted, scale))
+		pb.BalanceDecimal = lo.ToPtr(decimal.Format(b.Net(), scale))
+	}
+	return pb, nil
+}
+
+// accountBalanceToProto converts a historical balance of an account on ledger.
+func (s *App) accountBalanceToProto(b types.AccountBalance, ledger uint32) *proto.AccountBalance {
+	pb := AccountBalanceFromTigerbeetleToProto(b)
+	if scale, ok := s.assetScale(ledger); ok {
+		pb.DebitsPendingDecimal = lo.ToPtr(decimal.FormatUint128(b.DebitsPending, scale))
+		pb.DebitsPostedDecimal = lo.ToPtr(decimal.FormatUint128(b.DebitsPosted, scale))
+		pb.CreditsPendingDecimal = lo.ToPtr(decimal.FormatUint128(b.CreditsPending, scale))
+		pb.CreditsPostedDecimal = lo.ToPtr(decimal.FormatUint128(b.CreditsPosted, scale))
+	}
+	return pb
+}
@@ -0,0 +1,85 @@
+package grpc
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/lil5/tigerbeetle_api/config"
+	"github.com/lil5/tigerbeetle_api/decimal"
+	"github.com/lil5/tigerbeetle_api/proto"
+	"github.com/samber/lo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/tigerbeetle/tigerbeetle-go/pkg/types"
+)
+
+func TestAmountDecimal(t *testing.T) {
+	config.Config.IsBuffered = false
+	config.Config.IsDryRun = false
+	reg := testRegistry(t)
+
+	t.Run("should parse amount_decimal with the asset scale", func(t *testing.T) {
+		mockClient := new(MockTigerBeetleClient)
+		app := &App{TB: mockClient, Registry: reg}
+		large, _ := new(big.Int).SetString("1000000000000000000000", 10)
+		mockClient.On("CreateTransfers", mock.MatchedBy(func(transfers []types.Transfer) bool {
+			return transfers[0].Amount == types.ToUint128(1234) &&
+				transfers[1].Amount == types.BigIntToUint128(*large)
+		})).Return([]types.TransferEventResult{}, nil)
+
+		_, err := app.CreateTransfers(context.Background(), &proto.CreateTransfersRequest{Transfers: []*proto.Transfer{
+			{Id: "1", DebitAccountId: "1", CreditAccountId: "2", AmountDecimal: lo.ToPtr("12.34"), Ledger: 1, Code: 1},
+			{Id: "2", DebitAccountId: "1", CreditAccountId: "2", AmountDecimal: lo.ToPtr("10000000000000000000"), Ledger: 1, Code: 1},
+		}})
+		require.NoError(t, err)
+		mockClient.AssertExpectations(t)
+	})
+
+	t.Run("should reject invalid decimals", func(t *testing.T) {
+		app := &App{TB: new(MockTigerBeetleClient), Registry: reg}
+		for _, tc := range []struct {
+			transfer *proto.Transfer
+			err      error
+		}{
+			{&proto.Transfer{Id: "1", AmountDecimal: lo.ToPtr("12.345"), Ledger: 1, Code: 1}, decimal.ErrExcessPrecision},
+			{&proto.Transfer{Id: "1", AmountDecimal: lo.ToPtr("12.34"), Amount: 12, Ledger: 1, Code: 1}, ErrAmountDecimalConflict},
+			{&proto.Transfer{Id: "1", AmountDecimal: lo.ToPtr("-1"), Ledger: 1, Code: 1}, decimal.ErrInvalid},
+		} {
+			_, err := app.CreateTransfers(context.Background(), &proto.CreateTransfersRequest{Transfers: []*proto.Transfer{tc.transfer}})
+			assert.ErrorIs(t, err, tc.err)
+		}
+
+		_, err := app.CreateTransfers(context.Background(), &proto.CreateTransfersRequest{Transfers: []*proto.Transfer{
+			{Id: "1", AmountDecimal: lo.ToPtr("1"), Ledger: 2, Code: 1},
+		}})
+		assert.ErrorContains(t, err, "no asset scale")
+	})
+
+	t.Run("should format balances", func(t *testing.T) {
+		mockClient := new(MockTigerBeetleClient)
+		app := &App{TB: mockClient, Registry: reg}
+		mockClient.On("LookupAccounts", mock.Anything).Return([]types.Account{
+			{ID: types.ToUint128(1), Ledger: 1, Code: 1, DebitsPosted: types.ToUint128(1250), CreditsPosted: types.ToUint128(5)},
+		}, nil)
+
+		reply, err := app.LookupAccounts(context.Background(), &proto.LookupAccountsRequest{AccountIds: []string{"1"}})
+		require.NoError(t, err)
+		assert.Equal(t, "12.50", reply.Accounts[0].GetDebitsPostedDecimal())
+		assert.Equal(t, "0.05", reply.Accounts[0].GetCreditsPostedDecimal())
+
+		balances, err := app.GetBalancesAt(context.Background(), &proto.GetBalancesAtRequest{AccountIds: []string{"1"}})
+		require.NoError(t, err)
+		assert.Equal(t, "-12.45", balances.Balances[0].GetBalanceDecimal())
+	})
+
+	t.Run("should leave decimals out without a registry", func(t *testing.T) {
+		mockClient := new(MockTigerBeetleClient)
+		app := &App{TB: mockClient}
+		mockClient.On("LookupTransfers", mock.Anything).Return([]types.Transfer{{ID: types.ToUint128(1), Amount: types.ToUint128(5), Ledger: 1}}, nil)
+		reply, err := app.LookupTransfers(context.Background(), &proto.LookupTransfersRequest{TransferIds: []string{"1"}})
+		require.NoError(t, err)
+		assert.Nil(t, reply.Transfers[0].AmountDecimal)
+	})
+}
@@ -0,0 +1,123 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/lil5/tigerbeetle_api/decimal"
+	"github.com/lil5/tigerbeetle_api/proto"
+	"github.com/samber/lo"
+	"github.com/tigerbeetle/tigerbeetle-go/pkg/types"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+var (
+	ErrSameLedger         = errors.New("accounts are on the same ledger, create a transfer instead")
+	ErrZeroExchangeAmount = errors.New("exchange amount must not be zero")
+	ErrExchangeOverflow   = errors.New("exchange amount exceeds the 64 bits of source_amount and destination_amount")
+)
+
+// Exchange moves source_amount from the source account to the liquidity account of its ledger,
+// and the converted amount from the liquidity account of the destination ledger to the destination account.
+// Both legs are linked, see CreateCompoundTransfer.
+func (s *App) Exchange(ctx context.Context, in *proto.ExchangeRequest) (*proto.ExchangeReply, error) {
+	if s.Registry == nil {
+		return nil, ErrRegistryDisabled
+	}
+	sourceID, err := s.accountID(in.SourceAccountId)
+	if err != nil {
+		return nil, fmt.Errorf("source_account_id: %w", err)
+	}
+	destinationID, err := s.accountID(in.DestinationAccountId)
+	if err != nil {
+		return nil, fmt.Errorf("destination_account_id: %w", err)
+	}
+	rate, err := decimal.ParseRat(in.Rate)
+	if err != nil {
+		return nil, fmt.Errorf("rate: %w", err)
+	}
+
+	accounts, err := s.tb(ctx).LookupAccounts([]types.Uint128{*sourceID, *destinationID})
+	if err != nil {
+		return nil, err
+	}
+	source, ok := lo.Find(accounts, func(a types.Account) bool { return a.ID == *sourceID })
+	if !ok {
+		return nil, fmt.Errorf("source_account_id: %w", ErrAccountNotFound)
+	}
+	destination, ok := lo.Find(accounts, func(a types.Account) bool { return a.ID == *destinationID })
+	if !ok {
+		return nil, fmt.Errorf("destination_account_id: %w", ErrAccountNotFound)
+	}
+	if r := restriction(ctx); !r.AllowsAccount(source) || !r.AllowsAccount(destination) {
+		return nil, errForbidden("accounts are outside the ledgers, codes or accounts of the caller")
+	}
+	if source.Ledger == destination.Ledger {
+		return nil, ErrSameLedger
+	}
+	exchange, err := s.Registry.Exchange(source.Ledger, destination.Ledger)
+	if err != nil {
+		return nil, err
+	}
+	sourceScale, _ := s.assetScale(source.Ledger)
+	destinationScale, _ := s.assetScale(destination.Ledger)
+
+	sourceAmount := new(big.Int).SetUint64(in.SourceAmount)
+	if in.SourceAmountDecimal != nil {
+		if sourceAmount, err = decimal.Parse(*in.SourceAmountDecimal, sourceScale); err != nil {
+			return nil, fmt.Errorf("source_amount_decimal: %w", err)
+		}
+	}
+	destinationAmount := decimal.Convert(sourceAmount, rate, sourceScale, destinationScale)
+	if sourceAmount.Sign() == 0 || destinationAmount.Sign() == 0 {
+		return nil, ErrZeroExchangeAmount
+	}
+	// Checked before anything is created, the reply holds both amounts as uint64.
+	if !sourceAmount.IsUint64() || !destinationAmount.IsUint64() {
+		return nil, status.Errorf(codes.InvalidArgument, "%s: source %s, destination %s", ErrExchangeOverflow, sourceAmount, destinationAmount)
+	}
+
+	reply, err := s.createCompound(ctx, &proto.CreateCompoundTransferRequest{
+		Legs: []*proto.Transfer{
+			{
+				DebitAccountId:  sourceID.String(),
+				CreditAccountId: exchange.LiquidityAccounts[source.Ledger],
+				AmountDecimal:   lo.ToPtr(decimal.Format(sourceAmount, sourceScale)),
+				Ledger:          source.Ledger,
+			},
+			{
+				DebitAccountId:  exchange.LiquidityAccounts[destination.Ledger],
+				CreditAccountId: destinationID.String(),
+				AmountDecimal:   lo.ToPtr(decimal.Format(destinationAmount, destinationScale)),
+				Ledger:          destination.Ledger,
+			},
+		},
+		Totals: []*proto.LedgerTotal{
+			{Ledger: source.Ledger, AmountDecimal: lo.ToPtr(decimal.Format(sourceAmount, sourceScale))},
+			{Ledger: destination.Ledger, AmountDecimal: lo.ToPtr(decimal.Format(destinationAmount, destinationScale))},
+		},
+		Code:           lo.CoalesceOrEmpty(in.Code, uint32(exchange.Code)),
+		UserData128:    in.UserData128,
+		UserData64:     in.UserData64,
+		UserData32:     in.UserData32,
+		IdempotencyKey: in.IdempotencyKey,
+	}, opExchange)
+	if err != nil {
+		return nil, err
+	}
+	return &proto.ExchangeReply{
+		Ok:                       reply.Ok,
+		Ids:                      reply.Ids,
+		FailedLegIndex:           reply.FailedLegIndex,
+		Result:                   reply.Result,
+		SourceLedger:             source.Ledger,
+		DestinationLedger:        destination.Ledger,
+		SourceAmount:             sourceAmount.Uint64(),
+		DestinationAmount:        destinationAmount.Uint64(),
+		SourceAmountDecimal:      decimal.Format(sourceAmount, sourceScale),
+		DestinationAmountDecimal: decimal.Format(destinationAmount, destinationScale),
+	}, nil
+}
@@ -0,0 +1,95 @@
+package grpc
+
+import (
+	"context"
+	"math"
+	"testing"
+
+	"github.com/lil5/tigerbeetle_api/config"
+	"github.com/lil5/tigerbeetle_api/proto"
+	"github.com/lil5/tigerbeetle_api/registry"
+	"github.com/samber/lo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/tigerbeetle/tigerbeetle-go/pkg/types"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestExchange(t *testing.T) {
+	config.Config.IsDryRun = false
+	reg, err := registry.Parse([]byte(`
+ledgers:
+  - {id: 1, name: euro, currency: EUR, asset_scale: 2}
+  - {id: 3, name: yen, currency: JPY, asset_scale: 0}
+  - {id: 4, name: pound, currency: GBP, asset_scale: 2}
+codes:
+  - {id: 20, name: exchange}
+exchanges:
+  - liquidity_accounts: {1: "a1", 3: "a3"}
+    code: 20
+`))
+	require.NoError(t, err)
+	accounts := []types.Account{
+		{ID: types.ToUint128(10), Ledger: 1},
+		{ID: types.ToUint128(30), Ledger: 3},
+		{ID: types.ToUint128(40), Ledger: 4},
+	}
+
+	t.Run("should convert with the rate and asset scales", func(t *testing.T) {
+		mockClient := new(MockTigerBeetleClient)
+		app := &App{TB: mockClient, Registry: reg}
+		mockClient.On("LookupAccounts", mock.Anything).Return(accounts, nil)
+		var sent []types.Transfer
+		mockClient.On("CreateTransfers", mock.Anything).Run(func(args mock.Arguments) {
+			sent = args.Get(0).([]types.Transfer)
+		}).Return([]types.TransferEventResult{}, nil)
+
+		reply, err := app.Exchange(context.Background(), &proto.ExchangeRequest{
+			SourceAccountId:      "a",
+			DestinationAccountId: "1e",
+			SourceAmountDecimal:  lo.ToPtr("100.00"),
+			Rate:                 "161.875",
+		})
+		require.NoError(t, err)
+		assert.True(t, reply.Ok)
+		assert.Equal(t, "100.00", reply.SourceAmountDecimal)
+		assert.Equal(t, uint64(16187), reply.DestinationAmount)
+		require.Len(t, sent, 2)
+
+		assert.Equal(t, types.ToUint128(10), sent[0].DebitAccountID)
+		assert.Equal(t, types.ToUint128(0xa1), sent[0].CreditAccountID)
+		assert.Equal(t, types.ToUint128(10000), sent[0].Amount)
+		assert.Equal(t, uint32(1), sent[0].Ledger)
+		assert.True(t, sent[0].TransferFlags().Linked)
+
+		assert.Equal(t, types.ToUint128(0xa3), sent[1].DebitAccountID)
+		assert.Equal(t, types.ToUint128(30), sent[1].CreditAccountID)
+		assert.Equal(t, types.ToUint128(16187), sent[1].Amount)
+		assert.Equal(t, uint32(3), sent[1].Ledger)
+		assert.False(t, sent[1].TransferFlags().Linked)
+		assert.Equal(t, uint16(20), sent[1].Code)
+	})
+
+	t.Run("should reject invalid exchanges", func(t *testing.T) {
+		mockClient := new(MockTigerBeetleClient)
+		app := &App{TB: mockClient, Registry: reg}
+		mockClient.On("LookupAccounts", mock.Anything).Return(accounts, nil)
+
+		_, err := app.Exchange(context.Background(), &proto.ExchangeRequest{SourceAccountId: "a", DestinationAccountId: "28", SourceAmount: 1, Rate: "1"})
+		assert.ErrorIs(t, err, registry.ErrUnknownPair)
+		_, err = app.Exchange(context.Background(), &proto.ExchangeRequest{SourceAccountId: "a", DestinationAccountId: "a", SourceAmount: 1, Rate: "1"})
+		assert.ErrorIs(t, err, ErrSameLedger)
+		_, err = app.Exchange(context.Background(), &proto.ExchangeRequest{SourceAccountId: "1e", DestinationAccountId: "a", SourceAmount: 1, Rate: "0.001"})
+		assert.ErrorIs(t, err, ErrZeroExchangeAmount)
+		_, err = app.Exchange(context.Background(), &proto.ExchangeRequest{SourceAccountId: "a", DestinationAccountId: "ff", SourceAmount: 1, Rate: "1"})
+		assert.ErrorIs(t, err, ErrAccountNotFound)
+		_, err = app.Exchange(context.Background(), &proto.ExchangeRequest{SourceAccountId: "a", DestinationAccountId: "1e", SourceAmount: 1, Rate: "1,5"})
+		assert.ErrorContains(t, err, "rate")
+		_, err = app.Exchange(context.Background(), &proto.ExchangeRequest{SourceAccountId: "1e", DestinationAccountId: "a", SourceAmount: math.MaxUint64, Rate: "1000"})
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+		assert.ErrorContains(t, err, ErrExchangeOverflow.Error())
+		mockClient.AssertNotCalled(t, "CreateTransfers", mock.Anything)
+	})
+}
@@ -0,0 +1,94 @@
+package grpc
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+
+	"github.com/lil5/tigerbeetle_api/config"
+	"github.com/tigerbeetle/tigerbeetle-go/pkg/types"
+	"google.golang.org/grpc/metadata"
+)
+
+// IdempotencyKeyMetadata is the grpc metadata key of a request wide idempotency key,
+// the rest server maps the Idempotency-Key header to it.
+const IdempotencyKeyMetadata = "idempotency-key"
+
+var ErrIdempotencyKeyID = errors.New("id does not match the id derived from idempotency_key")
+
+// IdempotencyID derives a deterministic id from an idempotency key.
+// The namespace keeps keys of different deployments sharing a cluster apart.
+func IdempotencyID(key string) types.Uint128 {
+	h := sha256.New()
+	h.Write([]byte(config.Config.IdempotencyNamespace))
+	h.Write([]byte{0})
+	h.Write([]byte(key))
+	return types.BytesToUint128([16]byte(h.Sum(nil)[:16]))
+}
+
+// idempotencyID returns the id of an event with an idempotency key,
+// an id sent along with the key must be the derived id.
+func idempotencyID(key string, id types.Uint128) (types.Uint128, error) {
+	return keyedID(IdempotencyID(key), id)
+}
+
+// keyedID returns derived unless id is set to another id.
+func keyedID(derived, id types.Uint128) (types.Uint128, error) {
+	if id != (types.Uint128{}) && id != derived {
+		return types.Uint128{}, ErrIdempotencyKeyID
+	}
+	return derived, nil
+}
+
+// The operations a request wide key derives ids for, the same key sent to two of them derives different ids.
+const (
+	opCreateAccounts   = "create_accounts"
+	opCreateTransfers  = "create_transfers"
+	opCompoundTransfer = "compound_transfer"
+	opExchange         = "exchange"
+	opCloseAccount     = "close_account"
+	opReopenAccount    = "reopen_account"
+)
+
+// requestIdempotencyID derives the id of event index of operation from a request wide key.
+// The key is length prefixed and hashed apart from IdempotencyID,
+// so no other key, operation or index and no event key derives the same id.
+func requestIdempotencyID(key, operation string, index int) types.Uint128 {
+	h := sha256.New()
+	h.Write([]byte(config.Config.IdempotencyNamespace))
+	h.Write([]byte{1})
+	h.Write(binary.BigEndian.AppendUint64(nil, uint64(len(key))))
+	h.Write([]byte(key))
+	h.Write(binary.BigEndian.AppendUint64(nil, uint64(len(operation))))
+	h.Write([]byte(operation))
+	h.Write(binary.BigEndian.AppendUint64(nil, uint64(index)))
+	return types.BytesToUint128([16]byte(h.Sum(nil)[:16]))
+}
+
+// requestIdempotencyKey returns key, or the request wide key in ctx when key is nil.
+func requestIdempotencyKey(ctx context.Context, key *string) *string {
+	if key != nil {
+		return key
+	}
+	md, _ := metadata.FromIncomingContext(ctx)
+	values := md.Get(IdempotencyKeyMetadata)
+	if len(values) == 0 || values[0] == "" {
+		return nil
+	}
+	return &values[0]
+}
+
+// requestIdempotencyIDs returns the id of each of n events of operation
+// derived from the request wide key in ctx, nil without a key.
+func requestIdempotencyIDs(ctx context.Context, operation string, n int) []types.Uint128 {
+	key := requestIdempotencyKey(ctx, nil)
+	if key == nil {
+		return nil
+	}
+	ids := make([]types.Uint128, n)
+	for i := range ids {
+		ids[i] = requestIdempotencyID(*key, operation, i)
+	}
+	return ids
+}
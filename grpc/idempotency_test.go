@@ -0,0 +1,139 @@
+package grpc
+
+import (
+	"context"
+	"testing"
+
+	"github.com/lil5/tigerbeetle_api/config"
+	"github.com/lil5/tigerbeetle_api/proto"
+	"github.com/samber/lo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/tigerbeetle/tigerbeetle-go/pkg/types"
+	"google.golang.org/grpc/metadata"
+)
+
+func TestIdempotencyID(t *testing.T) {
+	config.Config.IdempotencyNamespace = "a"
+	id := IdempotencyID("order-1")
+	assert.Equal(t, id, IdempotencyID("order-1"))
+	assert.NotEqual(t, id, IdempotencyID("order-2"))
+	assert.NotEqual(t, types.Uint128{}, id)
+
+	config.Config.IdempotencyNamespace = "b"
+	assert.NotEqual(t, id, IdempotencyID("order-1"))
+
+	app := &App{}
+	reply, err := app.GetID(context.Background(), &proto.GetIDRequest{IdempotencyKey: lo.ToPtr("order-1")})
+	require.NoError(t, err)
+	assert.Equal(t, IdempotencyID("order-1").String(), reply.Id)
+}
+
+func TestRequestIdempotencyID(t *testing.T) {
+	config.Config.IdempotencyNamespace = "test"
+	id := requestIdempotencyID("a", opCreateTransfers, 1)
+	assert.Equal(t, id, requestIdempotencyID("a", opCreateTransfers, 1))
+	// Neither a key with the index in it, another operation nor the event key derive the same id
+	assert.NotEqual(t, id, requestIdempotencyID("a/1", opCreateTransfers, 0))
+	assert.NotEqual(t, requestIdempotencyID("a/1", opCreateTransfers, 1), requestIdempotencyID("a", "1/"+opCreateTransfers, 1))
+	assert.NotEqual(t, id, requestIdempotencyID("a", opCompoundTransfer, 1))
+	assert.NotEqual(t, id, IdempotencyID("a/1"))
+
+	t.Run("should not write derived keys into the request", func(t *testing.T) {
+		mockClient := new(MockTigerBeetleClient)
+		app := &App{TB: mockClient}
+		mockClient.On("CreateTransfers", mock.Anything).Return([]types.TransferEventResult{}, nil)
+
+		ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs(IdempotencyKeyMetadata, "batch-1"))
+		in := &proto.CreateCompoundTransferRequest{Legs: []*proto.Transfer{
+			{DebitAccountId: "1", CreditAccountId: "2", Amount: 1, Ledger: 1, Code: 1},
+		}, Totals: []*proto.LedgerTotal{{Ledger: 1, Amount: 1}}}
+		_, err := app.CreateCompoundTransfer(ctx, in)
+		require.NoError(t, err)
+		assert.Nil(t, in.IdempotencyKey)
+		assert.Nil(t, in.Legs[0].IdempotencyKey)
+	})
+}
+
+func TestCreateTransfersIdempotencyKey(t *testing.T) {
+	config.Config.IsBuffered = false
+	config.Config.IsDryRun = false
+	config.Config.IdempotencyNamespace = "test"
+
+	t.Run("should replay keyed transfers as success", func(t *testing.T) {
+		mockClient := new(MockTigerBeetleClient)
+		app := &App{TB: mockClient}
+		mockClient.On("CreateTransfers", mock.MatchedBy(func(transfers []types.Transfer) bool {
+			return transfers[0].ID == IdempotencyID("order-1") && transfers[1].ID == types.ToUint128(2)
+		})).Return([]types.TransferEventResult{
+			{Index: 0, Result: types.TransferExists},
+			{Index: 1, Result: types.TransferExists},
+		}, nil)
+
+		reply, err := app.CreateTransfers(context.Background(), &proto.CreateTransfersRequest{Transfers: []*proto.Transfer{
+			{IdempotencyKey: lo.ToPtr("order-1"), DebitAccountId: "1", CreditAccountId: "2", Amount: 1, Ledger: 1, Code: 1},
+			{Id: "2", DebitAccountId: "1", CreditAccountId: "2", Amount: 1, Ledger: 1, Code: 1},
+		}})
+		require.NoError(t, err)
+		require.Len(t, reply.Results, 1)
+		assert.Equal(t, int32(1), reply.Results[0].Index)
+		assert.Equal(t, proto.CreateTransferResult_TransferExists, reply.Results[0].Result)
+	})
+
+	t.Run("should report a replay with different fields", func(t *testing.T) {
+		mockClient := new(MockTigerBeetleClient)
+		app := &App{TB: mockClient}
+		mockClient.On("CreateTransfers", mock.Anything).Return([]types.TransferEventResult{
+			{Index: 0, Result: types.TransferExistsWithDifferentAmount},
+		}, nil)
+
+		reply, err := app.CreateTransfers(context.Background(), &proto.CreateTransfersRequest{Transfers: []*proto.Transfer{
+			{IdempotencyKey: lo.ToPtr("order-1"), DebitAccountId: "1", CreditAccountId: "2", Amount: 2, Ledger: 1, Code: 1},
+		}})
+		require.NoError(t, err)
+		require.Len(t, reply.Results, 1)
+		assert.Equal(t, IdempotencyID("order-1").String(), reply.Results[0].Id)
+	})
+
+	t.Run("should derive ids from the request key", func(t *testing.T) {
+		mockClient := new(MockTigerBeetleClient)
+		app := &App{TB: mockClient}
+		mockClient.On("CreateTransfers", mock.MatchedBy(func(transfers []types.Transfer) bool {
+			return transfers[0].ID == requestIdempotencyID("batch-1", opCreateTransfers, 0) &&
+				transfers[1].ID == IdempotencyID("own")
+		})).Return([]types.TransferEventResult{}, nil)
+
+		ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs(IdempotencyKeyMetadata, "batch-1"))
+		_, err := app.CreateTransfers(ctx, &proto.CreateTransfersRequest{Transfers: []*proto.Transfer{
+			{DebitAccountId: "1", CreditAccountId: "2", Amount: 1, Ledger: 1, Code: 1},
+			{IdempotencyKey: lo.ToPtr("own"), DebitAccountId: "1", CreditAccountId: "2", Amount: 1, Ledger: 1, Code: 1},
+		}})
+		require.NoError(t, err)
+		mockClient.AssertExpectations(t)
+	})
+
+	t.Run("should reject an id that does not match the key", func(t *testing.T) {
+		app := &App{TB: new(MockTigerBeetleClient)}
+		_, err := app.CreateTransfers(context.Background(), &proto.CreateTransfersRequest{Transfers: []*proto.Transfer{
+			{Id: "1", IdempotencyKey: lo.ToPtr("order-1"), DebitAccountId: "1", CreditAccountId: "2", Amount: 1, Ledger: 1, Code: 1},
+		}})
+		assert.ErrorIs(t, err, ErrIdempotencyKeyID)
+	})
+}
+
+func TestCreateAccountsIdempotencyKey(t *testing.T) {
+	config.Config.IdempotencyNamespace = "test"
+	mockClient := new(MockTigerBeetleClient)
+	app := &App{TB: mockClient}
+	mockClient.On("CreateAccounts", mock.MatchedBy(func(accounts []types.Account) bool {
+		return accounts[0].ID == requestIdempotencyID("customer-1", opCreateAccounts, 0)
+	})).Return([]types.AccountEventResult{{Index: 0, Result: types.AccountExists}}, nil)
+
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs(IdempotencyKeyMetadata, "customer-1"))
+	reply, err := app.CreateAccounts(ctx, &proto.CreateAccountsRequest{Accounts: []*proto.Account{
+		{Ledger: 1, Code: 1},
+	}})
+	require.NoError(t, err)
+	assert.Empty(t, reply.Results)
+}
@@ -0,0 +1,158 @@
+package grpc
+
+import (
+	"bytes"
+	"cmp"
+	"context"
+	"errors"
+	"math/big"
+	"slices"
+
+	"github.com/lil5/tigerbeetle_api/proto"
+	"github.com/samber/lo"
+	"github.com/tigerbeetle/tigerbeetle-go/pkg/types"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+var ErrZeroLedger = errors.New("ledger is required")
+
+type ledgerSummaryKey struct {
+	Code        uint16
+	UserData128 types.Uint128
+	UserData64  uint64
+	UserData32  uint32
+}
+
+type ledgerSummaryRow struct {
+	Accounts       uint64
+	DebitsPending  big.Int
+	DebitsPosted   big.Int
+	CreditsPending big.Int
+	CreditsPosted  big.Int
+}
+
+func (r *ledgerSummaryRow) Add(a types.Account) {
+	r.Accounts++
+	addUint128(&r.DebitsPending, a.DebitsPending)
+	addUint128(&r.DebitsPosted, a.DebitsPosted)
+	addUint128(&r.CreditsPending, a.CreditsPending)
+	addUint128(&r.CreditsPosted, a.CreditsPosted)
+}
+
+func (r *ledgerSummaryRow) Merge(o *ledgerSummaryRow) {
+	r.Accounts += o.Accounts
+	r.DebitsPending.Add(&r.DebitsPending, &o.DebitsPending)
+	r.DebitsPosted.Add(&r.DebitsPosted, &o.DebitsPosted)
+	r.CreditsPending.Add(&r.CreditsPending, &o.CreditsPending)
+	r.CreditsPosted.Add(&r.CreditsPosted, &o.CreditsPosted)
+}
+
+func (r *ledgerSummaryRow) toProto() *proto.LedgerSummaryRow {
+	return &proto.LedgerSummaryRow{
+		Accounts:       r.Accounts,
+		DebitsPending:  r.DebitsPending.String(),
+		DebitsPosted:   r.DebitsPosted.String(),
+		CreditsPending: r.CreditsPending.String(),
+		CreditsPosted:  r.CreditsPosted.String(),
+	}
+}
+
+func addUint128(sum *big.Int, v types.Uint128) {
+	b := v.BigInt()
+	sum.Add(sum, &b)
+}
+
+func compareUint128(a, b types.Uint128) int {
+	// Uint128 is little endian, compare from the most significant byte.
+	ab, bb := a.Bytes(), b.Bytes()
+	slices.Reverse(ab[:])
+	slices.Reverse(bb[:])
+	return bytes.Compare(ab[:], bb[:])
+}
+
+// LedgerSummary returns a trial balance of all accounts on a ledger.
+func (s *App) LedgerSummary(ctx context.Context, in *proto.LedgerSummaryRequest) (*proto.LedgerSummaryReply, error) {
+	if in.Ledger == 0 {
+		return nil, ErrZeroLedger
+	}
+	r := restriction(ctx)
+	if !r.AllowsLedger(in.Ledger) {
+		return nil, errForbidden("ledger %d is outside the ledgers of the caller", in.Ledger)
+	}
+	// A summary is of one ledger and always grouped by code, only user_data fields can be added.
+	for _, g := range in.GroupBy {
+		if g == proto.GroupBy_GroupByCode || g == proto.GroupBy_GroupByLedger {
+			return nil, status.Errorf(codes.InvalidArgument, "group_by: %s is not supported, rows are always grouped by code of a single ledger", g)
+		}
+	}
+	groupBy := lo.SliceToMap(in.GroupBy, func(g proto.GroupBy) (proto.GroupBy, bool) { return g, true })
+
+	rows := map[ledgerSummaryKey]*ledgerSummaryRow{}
+	err := s.eachQueryAccounts(ctx, types.QueryFilter{Ledger: in.Ledger}, func(accounts []types.Account) error {
+		for _, a := range accounts {
+			// A restricted caller only sums its own accounts.
+			if !r.AllowsAccount(a) {
+				continue
+			}
+			key := ledgerSummaryKey{Code: a.Code}
+			if groupBy[proto.GroupBy_GroupByUserData128] {
+				key.UserData128 = a.UserData128
+			}
+			if groupBy[proto.GroupBy_GroupByUserData64] {
+				key.UserData64 = a.UserData64
+			}
+			if groupBy[proto.GroupBy_GroupByUserData32] {
+				key.UserData32 = a.UserData32
+			}
+			row, ok := rows[key]
+			if !ok {
+				row = &ledgerSummaryRow{}
+				rows[key] = row
+			}
+			row.Add(a)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	keys := lo.Keys(rows)
+	slices.SortFunc(keys, func(a, b ledgerSummaryKey) int {
+		return cmp.Or(
+			cmp.Compare(a.Code, b.Code),
+			compareUint128(a.UserData128, b.UserData128),
+			cmp.Compare(a.UserData64, b.UserData64),
+			cmp.Compare(a.UserData32, b.UserData32),
+		)
+	})
+
+	total := &ledgerSummaryRow{}
+	pRows := make([]*proto.LedgerSummaryRow, 0, len(keys))
+	for _, key := range keys {
+		row := rows[key]
+		total.Merge(row)
+
+		pRow := row.toProto()
+		pRow.Code = lo.ToPtr(uint32(key.Code))
+		if groupBy[proto.GroupBy_GroupByUserData128] {
+			pRow.UserData128 = lo.ToPtr(key.UserData128.String())
+		}
+		if groupBy[proto.GroupBy_GroupByUserData64] {
+			pRow.UserData64 = lo.ToPtr(key.UserData64)
+		}
+		if groupBy[proto.GroupBy_GroupByUserData32] {
+			pRow.UserData32 = lo.ToPtr(key.UserData32)
+		}
+		pRows = append(pRows, pRow)
+	}
+
+	return &proto.LedgerSummaryReply{
+		Ledger: in.Ledger,
+		Rows:   pRows,
+		Total:  total.toProto(),
+		Balanced: total.DebitsPosted.Cmp(&total.CreditsPosted) == 0 &&
+			total.DebitsPending.Cmp(&total.CreditsPending) == 0,
+	}, nil
+}
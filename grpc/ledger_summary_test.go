@@ -0,0 +1,70 @@
+package grpc
+
+import (
+	"context"
+	"testing"
+
+	"github.com/lil5/tigerbeetle_api/proto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/tigerbeetle/tigerbeetle-go/pkg/types"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestLedgerSummary(t *testing.T) {
+	mockClient := new(MockTigerBeetleClient)
+	app := &App{TB: mockClient}
+
+	t.Run("should return error when ledger is zero", func(t *testing.T) {
+		_, err := app.LedgerSummary(context.Background(), &proto.LedgerSummaryRequest{})
+		assert.ErrorIs(t, err, ErrZeroLedger)
+	})
+
+	t.Run("should reject grouping by code or ledger", func(t *testing.T) {
+		for _, g := range []proto.GroupBy{proto.GroupBy_GroupByCode, proto.GroupBy_GroupByLedger} {
+			_, err := app.LedgerSummary(context.Background(), &proto.LedgerSummaryRequest{Ledger: 7, GroupBy: []proto.GroupBy{g}})
+			assert.Equal(t, codes.InvalidArgument, status.Code(err))
+		}
+	})
+
+	t.Run("should group by code and check balance", func(t *testing.T) {
+		mockClient.On("QueryAccounts", mock.MatchedBy(func(f types.QueryFilter) bool {
+			return f.Ledger == 7 && f.Limit == TB_MAX_BATCH_SIZE
+		})).Return([]types.Account{
+			{ID: types.ToUint128(1), Code: 2, DebitsPosted: types.ToUint128(100), Timestamp: 1},
+			{ID: types.ToUint128(2), Code: 1, CreditsPosted: types.ToUint128(60), Timestamp: 2},
+			{ID: types.ToUint128(3), Code: 1, CreditsPosted: types.ToUint128(40), Timestamp: 3},
+		}, nil).Once()
+
+		resp, err := app.LedgerSummary(context.Background(), &proto.LedgerSummaryRequest{Ledger: 7})
+		assert.NoError(t, err)
+		assert.Len(t, resp.Rows, 2)
+		assert.Equal(t, uint32(1), *resp.Rows[0].Code)
+		assert.Equal(t, uint64(2), resp.Rows[0].Accounts)
+		assert.Equal(t, "100", resp.Rows[0].CreditsPosted)
+		assert.Nil(t, resp.Rows[0].UserData32)
+		assert.Equal(t, "100", resp.Total.DebitsPosted)
+		assert.True(t, resp.Balanced)
+		mockClient.AssertExpectations(t)
+	})
+
+	t.Run("should sum beyond 64 bits", func(t *testing.T) {
+		u64Max := types.BytesToUint128([16]byte{0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff})
+		mockClient.On("QueryAccounts", mock.AnythingOfType("types.QueryFilter")).Return([]types.Account{
+			{ID: types.ToUint128(1), Code: 1, UserData32: 1, DebitsPosted: u64Max, Timestamp: 1},
+			{ID: types.ToUint128(2), Code: 1, UserData32: 2, DebitsPosted: u64Max, Timestamp: 2},
+		}, nil).Once()
+
+		resp, err := app.LedgerSummary(context.Background(), &proto.LedgerSummaryRequest{
+			Ledger:  7,
+			GroupBy: []proto.GroupBy{proto.GroupBy_GroupByUserData32},
+		})
+		assert.NoError(t, err)
+		assert.Len(t, resp.Rows, 2)
+		assert.Equal(t, uint32(2), *resp.Rows[1].UserData32)
+		assert.Equal(t, "36893488147419103230", resp.Total.DebitsPosted)
+		assert.False(t, resp.Balanced)
+		mockClient.AssertExpectations(t)
+	})
+}
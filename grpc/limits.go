@@ -0,0 +1,78 @@
+package grpc
+
+import (
+	"context"
+	"slices"
+	"time"
+
+	"github.com/lil5/tigerbeetle_api/proto"
+	"github.com/tigerbeetle/tigerbeetle-go/pkg/types"
+)
+
+// ResultSpendingLimitExceeded is the result of a transfer rejected by a spending limit.
+const ResultSpendingLimitExceeded = types.CreateTransferResult(proto.CreateTransferResult_TransferSpendingLimitExceeded)
+
+// lockLimits holds the debit accounts of transfers until the returned function is called,
+// it is called from before checkLimits until the transfers are submitted.
+func (s *App) lockLimits(transfers []types.Transfer) (unlock func()) {
+	if s.Limits == nil {
+		return func() {}
+	}
+	return s.Limits.Lock(transfers)
+}
+
+// checkLimits returns the indexes of the transfers to submit and the results of the transfers
+// rejected by a spending limit, the other transfers of their linked chain fail with them.
+// Kept is nil when every transfer is submitted.
+func (s *App) checkLimits(ctx context.Context, transfers []types.Transfer) (kept []int, rejected []types.TransferEventResult, err error) {
+	if s.Limits == nil {
+		return nil, nil, nil
+	}
+	breaches, err := s.Limits.Check(s.tb(ctx), transfers, time.Now())
+	if err != nil || len(breaches) == 0 {
+		return nil, nil, err
+	}
+	breached := map[int]bool{}
+	for _, b := range breaches {
+		breached[b.Index] = true
+	}
+	kept = []int{}
+	for start := 0; start < len(transfers); {
+		end := start
+		for end < len(transfers)-1 && transfers[end].TransferFlags().Linked {
+			end++
+		}
+		chainBreached := false
+		for i := start; i <= end; i++ {
+			chainBreached = chainBreached || breached[i]
+		}
+		for i := start; i <= end; i++ {
+			switch {
+			case breached[i]:
+				rejected = append(rejected, types.TransferEventResult{Index: uint32(i), Result: ResultSpendingLimitExceeded})
+			case chainBreached:
+				rejected = append(rejected, types.TransferEventResult{Index: uint32(i), Result: types.TransferLinkedEventFailed})
+			default:
+				kept = append(kept, i)
+			}
+		}
+		start = end + 1
+	}
+	return kept, rejected, nil
+}
+
+// mergeLimitResults maps the results of the kept transfers back to the request and adds the rejected transfers.
+func mergeLimitResults(results []types.TransferEventResult, kept []int, rejected []types.TransferEventResult) []types.TransferEventResult {
+	if kept == nil {
+		return results
+	}
+	merged := slices.Clone(rejected)
+	for _, r := range results {
+		r.Index = uint32(kept[r.Index])
+		merged = append(merged, r)
+	}
+	slices.SortFunc(merged, func(a, b types.TransferEventResult) int {
+		return int(a.Index) - int(b.Index)
+	})
+	return merged
+}
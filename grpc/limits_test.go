@@ -0,0 +1,89 @@
+package grpc
+
+import (
+	"context"
+	"testing"
+
+	"github.com/lil5/tigerbeetle_api/config"
+	"github.com/lil5/tigerbeetle_api/limits"
+	"github.com/lil5/tigerbeetle_api/proto"
+	"github.com/samber/lo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/tigerbeetle/tigerbeetle-go/pkg/types"
+)
+
+func TestSpendingLimits(t *testing.T) {
+	config.Config.IsBuffered = false
+	config.Config.IsDryRun = false
+	lim, err := limits.Parse([]byte("limits:\n  - {name: daily, window: 24h, amount: 100}\n"))
+	require.NoError(t, err)
+
+	t.Run("should reject breaching transfers and their chain", func(t *testing.T) {
+		mockClient := new(MockTigerBeetleClient)
+		app := &App{TB: mockClient, Limits: lim}
+		mockClient.On("GetAccountTransfers", mock.Anything).Return([]types.Transfer{}, nil)
+		mockClient.On("CreateTransfers", mock.MatchedBy(func(transfers []types.Transfer) bool {
+			return len(transfers) == 2 && transfers[0].ID == types.ToUint128(1) && transfers[1].ID == types.ToUint128(4)
+		})).Return([]types.TransferEventResult{{Index: 1, Result: types.TransferExceedsCredits}}, nil)
+
+		reply, err := app.CreateTransfers(context.Background(), &proto.CreateTransfersRequest{Transfers: []*proto.Transfer{
+			{Id: "1", DebitAccountId: "a", CreditAccountId: "b", Amount: 60, Ledger: 1, Code: 1},
+			{Id: "2", DebitAccountId: "b", CreditAccountId: "a", Amount: 1, Ledger: 1, Code: 1, TransferFlags: &proto.TransferFlags{Linked: lo.ToPtr(true)}},
+			{Id: "3", DebitAccountId: "a", CreditAccountId: "b", Amount: 60, Ledger: 1, Code: 1},
+			{Id: "4", DebitAccountId: "c", CreditAccountId: "b", Amount: 60, Ledger: 1, Code: 1},
+		}})
+		require.NoError(t, err)
+		mockClient.AssertExpectations(t)
+		require.Len(t, reply.Results, 3)
+		assert.Equal(t, int32(1), reply.Results[0].Index)
+		assert.Equal(t, proto.CreateTransferResult_TransferLinkedEventFailed, reply.Results[0].Result)
+		assert.Equal(t, int32(2), reply.Results[1].Index)
+		assert.Equal(t, proto.CreateTransferResult_TransferSpendingLimitExceeded, reply.Results[1].Result)
+		assert.Equal(t, "3", reply.Results[1].Id)
+		assert.Equal(t, int32(3), reply.Results[2].Index)
+		assert.Equal(t, proto.CreateTransferResult_TransferExceedsCredits, reply.Results[2].Result)
+	})
+
+	t.Run("should not submit when every transfer is rejected", func(t *testing.T) {
+		mockClient := new(MockTigerBeetleClient)
+		app := &App{TB: mockClient, Limits: lim}
+		mockClient.On("GetAccountTransfers", mock.Anything).Return([]types.Transfer{
+			{ID: types.ToUint128(9), Amount: types.ToUint128(100), Timestamp: 1},
+		}, nil)
+
+		reply, err := app.CreateTransfers(context.Background(), &proto.CreateTransfersRequest{Transfers: []*proto.Transfer{
+			{Id: "1", DebitAccountId: "a", CreditAccountId: "b", Amount: 1, Ledger: 1, Code: 1},
+		}})
+		require.NoError(t, err)
+		require.Len(t, reply.Results, 1)
+		assert.Equal(t, proto.CreateTransferResult_TransferSpendingLimitExceeded, reply.Results[0].Result)
+		mockClient.AssertNotCalled(t, "CreateTransfers", mock.Anything)
+	})
+}
+
+func TestCompoundSpendingLimits(t *testing.T) {
+	config.Config.IsDryRun = false
+	lim, err := limits.Parse([]byte("limits:\n  - {name: daily, window: 24h, amount: 100}\n"))
+	require.NoError(t, err)
+
+	mockClient := new(MockTigerBeetleClient)
+	app := &App{TB: mockClient, Limits: lim}
+	mockClient.On("GetAccountTransfers", mock.Anything).Return([]types.Transfer{}, nil)
+
+	reply, err := app.CreateCompoundTransfer(context.Background(), &proto.CreateCompoundTransferRequest{
+		Ledger: 1,
+		Code:   1,
+		Legs: []*proto.Transfer{
+			{DebitAccountId: "a", CreditAccountId: "b", Amount: 60},
+			{DebitAccountId: "a", CreditAccountId: "c", Amount: 60},
+		},
+		Totals: []*proto.LedgerTotal{{Ledger: 1, Amount: 120}},
+	})
+	require.NoError(t, err)
+	assert.False(t, reply.Ok)
+	assert.Equal(t, lo.ToPtr(int32(1)), reply.FailedLegIndex)
+	assert.Equal(t, proto.CreateTransferResult_TransferSpendingLimitExceeded, reply.Result)
+	mockClient.AssertNotCalled(t, "CreateTransfers", mock.Anything)
+}
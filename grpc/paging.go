@@ -0,0 +1,81 @@
+package grpc
+
+import (
+	"context"
+
+	"github.com/lil5/tigerbeetle_api/metrics"
+	"github.com/tigerbeetle/tigerbeetle-go/pkg/types"
+)
+
+// The paging helpers below walk through a filter in ascending timestamp order,
+// TB_MAX_BATCH_SIZE rows at a time, until TigerBeetle returns a short page.
+// A non-nil error from fn stops the iteration and is returned as is.
+
+func (s *App) eachAccountTransfers(ctx context.Context, filter types.AccountFilter, fn func([]types.Transfer) error) error {
+	filter.Limit = TB_MAX_BATCH_SIZE
+	for {
+		metrics.TotalTbGetAccountTransfersCall.Inc()
+		res, err := s.tb(ctx).GetAccountTransfers(filter)
+		if err != nil {
+			return err
+		}
+		if len(res) > 0 {
+			if err := fn(res); err != nil {
+				return err
+			}
+		}
+		if len(res) < TB_MAX_BATCH_SIZE {
+			return nil
+		}
+		filter.TimestampMin = res[len(res)-1].Timestamp + 1
+	}
+}
+
+func (s *App) eachQueryAccounts(ctx context.Context, filter types.QueryFilter, fn func([]types.Account) error) error {
+	filter.Limit = TB_MAX_BATCH_SIZE
+	for {
+		metrics.TotalTbQueryAccountsCall.Inc()
+		res, err := s.tb(ctx).QueryAccounts(filter)
+		if err != nil {
+			return err
+		}
+		if len(res) > 0 {
+			if err := fn(res); err != nil {
+				return err
+			}
+		}
+		if len(res) < TB_MAX_BATCH_SIZE {
+			return nil
+		}
+		filter.TimestampMin = res[len(res)-1].Timestamp + 1
+	}
+}
+
+// eachQueryTransfers reads at most max transfers, 0 reads all of them.
+func (s *App) eachQueryTransfers(ctx context.Context, filter types.QueryFilter, max uint64, fn func([]types.Transfer) error) error {
+	var read uint64
+	for {
+		filter.Limit = TB_MAX_BATCH_SIZE
+		if max > 0 {
+			if read >= max {
+				return nil
+			}
+			filter.Limit = uint32(min(TB_MAX_BATCH_SIZE, max-read))
+		}
+		metrics.TotalTbQueryTransfersCall.Inc()
+		res, err := s.tb(ctx).QueryTransfers(filter)
+		if err != nil {
+			return err
+		}
+		read += uint64(len(res))
+		if len(res) > 0 {
+			if err := fn(res); err != nil {
+				return err
+			}
+		}
+		if len(res) < int(filter.Limit) {
+			return nil
+		}
+		filter.TimestampMin = res[len(res)-1].Timestamp + 1
+	}
+}
@@ -0,0 +1,66 @@
+package grpc
+
+import (
+	"context"
+	"net"
+
+	"github.com/lil5/tigerbeetle_api/auth"
+	"github.com/lil5/tigerbeetle_api/quota"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+// peerIP returns the ip of the client of ctx.
+func peerIP(ctx context.Context) string {
+	p, ok := peer.FromContext(ctx)
+	if !ok || p.Addr == nil {
+		return ""
+	}
+	host, _, err := net.SplitHostPort(p.Addr.String())
+	if err != nil {
+		return p.Addr.String()
+	}
+	return host
+}
+
+// acquireQuota takes method from the quota of the client of ctx,
+// the returned metadata holds the retry-after of a rejection.
+func acquireQuota(q *quota.Quotas, ctx context.Context, method string) (func(), metadata.MD, error) {
+	if publicMethods[method] {
+		return func() {}, nil, nil
+	}
+	client := quota.ClientOf(auth.FromContext(ctx), peerIdentity(ctx), peerIP(ctx))
+	release, retryAfter, err := q.Acquire(client, string(methodScope(method)))
+	if err != nil {
+		return nil, metadata.Pairs("retry-after", quota.RetryAfter(retryAfter)), status.Error(codes.ResourceExhausted, err.Error())
+	}
+	return release, nil, nil
+}
+
+// UnaryQuotaInterceptor applies the quotas of q, it must run after UnaryAuthInterceptor to see the api key.
+func UnaryQuotaInterceptor(q *quota.Quotas) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		release, md, err := acquireQuota(q, ctx, info.FullMethod)
+		if err != nil {
+			grpc.SetHeader(ctx, md)
+			return nil, err
+		}
+		defer release()
+		return handler(ctx, req)
+	}
+}
+
+func StreamQuotaInterceptor(q *quota.Quotas) grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		release, md, err := acquireQuota(q, ss.Context(), info.FullMethod)
+		if err != nil {
+			ss.SetHeader(md)
+			return err
+		}
+		defer release()
+		return handler(srv, ss)
+	}
+}
@@ -0,0 +1,34 @@
+package grpc
+
+import (
+	"context"
+	"testing"
+
+	"github.com/lil5/tigerbeetle_api/proto"
+	"github.com/lil5/tigerbeetle_api/quota"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/status"
+)
+
+func TestUnaryQuotaInterceptor(t *testing.T) {
+	q, err := quota.Parse([]byte("default:\n  read: {rate: 1, burst: 1}\n"))
+	require.NoError(t, err)
+	interceptor := UnaryQuotaInterceptor(q)
+	call := func(method string) error {
+		_, err := interceptor(context.Background(), nil, &grpc.UnaryServerInfo{FullMethod: method}, func(ctx context.Context, req any) (any, error) {
+			return nil, nil
+		})
+		return err
+	}
+
+	require.NoError(t, call(proto.TigerBeetle_LookupAccounts_FullMethodName))
+	err = call(proto.TigerBeetle_QueryAccounts_FullMethodName)
+	assert.Equal(t, codes.ResourceExhausted, status.Code(err))
+	// Other operations and health checks have their own quota.
+	assert.NoError(t, call(proto.TigerBeetle_CreateTransfers_FullMethodName))
+	assert.NoError(t, call(healthpb.Health_Check_FullMethodName))
+}
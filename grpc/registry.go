@@ -0,0 +1,82 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+
+	"github.com/lil5/tigerbeetle_api/decimal"
+	"github.com/lil5/tigerbeetle_api/proto"
+	"github.com/lil5/tigerbeetle_api/registry"
+	"github.com/samber/lo"
+	"github.com/tigerbeetle/tigerbeetle-go/pkg/types"
+)
+
+var ErrRegistryDisabled = errors.New("the registry is disabled, REGISTRY_FILE is not set")
+
+func (s *App) validateAccount(a types.Account) error {
+	if s.Registry == nil {
+		return nil
+	}
+	return s.Registry.Validate(a.Ledger, a.Code)
+}
+
+// validateTransfer skips posting and voiding transfers without a ledger or code,
+// TigerBeetle takes those from the pending transfer.
+func (s *App) validateTransfer(t types.Transfer) error {
+	if s.Registry == nil {
+		return nil
+	}
+	flags := t.TransferFlags()
+	if (flags.PostPendingTransfer || flags.VoidPendingTransfer) && (t.Ledger == 0 || t.Code == 0) {
+		return nil
+	}
+	return s.Registry.Validate(t.Ledger, t.Code)
+}
+
+// accountToProto converts an account for a response, adding the names
+// and decimal balances from the registry.
+func (s *App) accountToProto(a types.Account) *proto.Account {
+	pa := AccountToProtoAccount(a)
+	if s.Registry == nil {
+		return pa
+	}
+	pa.LedgerName = s.Registry.LedgerName(a.Ledger)
+	pa.CodeName = s.Registry.CodeName(a.Code)
+	if scale, ok := s.assetScale(a.Ledger); ok {
+		pa.DebitsPendingDecimal = lo.ToPtr(decimal.FormatUint128(a.DebitsPending, scale))
+		pa.DebitsPostedDecimal = lo.ToPtr(decimal.FormatUint128(a.DebitsPosted, scale))
+		pa.CreditsPendingDecimal = lo.ToPtr(decimal.FormatUint128(a.CreditsPending, scale))
+		pa.CreditsPostedDecimal = lo.ToPtr(decimal.FormatUint128(a.CreditsPosted, scale))
+	}
+	return pa
+}
+
+// transferToProto converts a transfer for a response, adding the names
+// and decimal amount from the registry.
+func (s *App) transferToProto(t types.Transfer) *proto.Transfer {
+	pt := TransferToProtoTransfer(t)
+	if s.Registry == nil {
+		return pt
+	}
+	pt.LedgerName = s.Registry.LedgerName(t.Ledger)
+	pt.CodeName = s.Registry.CodeName(t.Code)
+	if scale, ok := s.assetScale(t.Ledger); ok {
+		pt.AmountDecimal = lo.ToPtr(decimal.FormatUint128(t.Amount, scale))
+	}
+	return pt
+}
+
+func (s *App) ListLedgers(ctx context.Context, in *proto.ListLedgersRequest) (*proto.ListLedgersReply, error) {
+	if s.Registry == nil {
+		return nil, ErrRegistryDisabled
+	}
+	r := restriction(ctx)
+	return &proto.ListLedgersReply{
+		Ledgers: lo.FilterMap(s.Registry.Ledgers, func(l registry.Ledger, _ int) (*proto.LedgerInfo, bool) {
+			return &proto.LedgerInfo{Id: l.ID, Name: l.Name, Currency: l.Currency, AssetScale: l.AssetScale}, r.AllowsLedger(l.ID)
+		}),
+		Codes: lo.FilterMap(s.Registry.Codes, func(c registry.Code, _ int) (*proto.CodeInfo, bool) {
+			return &proto.CodeInfo{Id: uint32(c.ID), Name: c.Name, Description: c.Description, Ledgers: c.Ledgers}, r.AllowsCode(c.ID)
+		}),
+	}, nil
+}
@@ -0,0 +1,81 @@
+package grpc
+
+import (
+	"context"
+	"testing"
+
+	"github.com/lil5/tigerbeetle_api/config"
+	"github.com/lil5/tigerbeetle_api/proto"
+	"github.com/lil5/tigerbeetle_api/registry"
+	"github.com/samber/lo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/tigerbeetle/tigerbeetle-go/pkg/types"
+)
+
+func testRegistry(t *testing.T) *registry.Registry {
+	reg, err := registry.Parse([]byte(`
+ledgers:
+  - {id: 1, name: euro, currency: EUR, asset_scale: 2}
+codes:
+  - {id: 1, name: customer}
+  - {id: 10, name: deposit, ledgers: [1]}
+`))
+	require.NoError(t, err)
+	return reg
+}
+
+func TestRegistry(t *testing.T) {
+	config.Config.IsBuffered = false
+	config.Config.IsDryRun = false
+	reg := testRegistry(t)
+
+	t.Run("should reject unknown codes", func(t *testing.T) {
+		app := &App{TB: new(MockTigerBeetleClient), Registry: reg}
+		_, err := app.CreateTransfers(context.Background(), &proto.CreateTransfersRequest{Transfers: []*proto.Transfer{
+			{Id: "1", DebitAccountId: "1", CreditAccountId: "2", Amount: 1, Ledger: 1, Code: 10},
+			{Id: "2", DebitAccountId: "1", CreditAccountId: "2", Amount: 1, Ledger: 1, Code: 11},
+		}})
+		assert.ErrorIs(t, err, registry.ErrUnknownCode)
+		assert.ErrorContains(t, err, "transfers[1]")
+
+		_, err = app.CreateAccounts(context.Background(), &proto.CreateAccountsRequest{Accounts: []*proto.Account{
+			{Id: "1", Ledger: 2, Code: 1},
+		}})
+		assert.ErrorIs(t, err, registry.ErrUnknownLedger)
+	})
+
+	t.Run("should allow posting without ledger and code", func(t *testing.T) {
+		mockClient := new(MockTigerBeetleClient)
+		app := &App{TB: mockClient, Registry: reg}
+		mockClient.On("CreateTransfers", mock.Anything).Return([]types.TransferEventResult{}, nil)
+		_, err := app.CreateTransfers(context.Background(), &proto.CreateTransfersRequest{Transfers: []*proto.Transfer{
+			{Id: "2", PendingId: lo.ToPtr("1"), TransferFlags: &proto.TransferFlags{PostPendingTransfer: lo.ToPtr(true)}},
+		}})
+		assert.NoError(t, err)
+	})
+
+	t.Run("should add names to responses", func(t *testing.T) {
+		mockClient := new(MockTigerBeetleClient)
+		app := &App{TB: mockClient, Registry: reg}
+		mockClient.On("LookupAccounts", mock.Anything).Return([]types.Account{{ID: types.ToUint128(1), Ledger: 1, Code: 1}}, nil)
+		reply, err := app.LookupAccounts(context.Background(), &proto.LookupAccountsRequest{AccountIds: []string{"1"}})
+		require.NoError(t, err)
+		assert.Equal(t, "euro", reply.Accounts[0].GetLedgerName())
+		assert.Equal(t, "customer", reply.Accounts[0].GetCodeName())
+	})
+
+	t.Run("should list the registry", func(t *testing.T) {
+		app := &App{Registry: reg}
+		reply, err := app.ListLedgers(context.Background(), &proto.ListLedgersRequest{})
+		require.NoError(t, err)
+		require.Len(t, reply.Ledgers, 1)
+		assert.Equal(t, "EUR", reply.Ledgers[0].Currency)
+		require.Len(t, reply.Codes, 2)
+		assert.Equal(t, []uint32{1}, reply.Codes[1].Ledgers)
+
+		_, err = (&App{}).ListLedgers(context.Background(), &proto.ListLedgersRequest{})
+		assert.ErrorIs(t, err, ErrRegistryDisabled)
+	})
+}
@@ -3,18 +3,26 @@ package grpc
 import (
 	"context"
 	"errors"
+	"fmt"
 	"log/slog"
 	"math/rand/v2"
 	"os"
+	"slices"
 	"strings"
+	"time"
 
 	"github.com/charithe/timedbuf/v2"
+	"github.com/lil5/tigerbeetle_api/alias"
 	"github.com/lil5/tigerbeetle_api/config"
+	"github.com/lil5/tigerbeetle_api/limits"
 	"github.com/lil5/tigerbeetle_api/metrics"
 	"github.com/lil5/tigerbeetle_api/proto"
+	"github.com/lil5/tigerbeetle_api/registry"
+	"github.com/lil5/tigerbeetle_api/tracing"
 	"github.com/samber/lo"
 	tigerbeetle_go "github.com/tigerbeetle/tigerbeetle-go"
 	"github.com/tigerbeetle/tigerbeetle-go/pkg/types"
+	"go.opentelemetry.io/otel/trace"
 )
 
 var (
@@ -22,23 +30,39 @@ var (
 	ErrZeroTransfers = errors.New("no transfers were specified")
 )
 
+// TimedPayloadResponse holds the results of the transfers of one payload,
+// indexed from the first transfer of that payload.
 type TimedPayloadResponse struct {
-	Replies []*proto.CreateTransfersReplyItem
+	Results []types.TransferEventResult
 	Error   error
+	// Flush is the span of the flush that sent the payload
+	Flush trace.SpanContext
 }
 type TimedPayload struct {
 	buf       *timedbuf.TimedBuf[TimedPayload]
 	c         chan TimedPayloadResponse
 	Transfers []types.Transfer
+	// Span is the span of the request of the payload, linked from the flush span
+	Span trace.SpanContext
+	// Enqueued is when the payload was put in the buffer
+	Enqueued time.Time
 }
 
 type App struct {
 	proto.UnimplementedTigerBeetleServer
 
 	TB tigerbeetle_go.Client
+	// Aliases is nil unless ALIAS_MODE is set
+	Aliases alias.Resolver
+	// Registry is nil unless REGISTRY_FILE is set
+	Registry *registry.Registry
+	// Limits is nil unless LIMITS_FILE is set
+	Limits *limits.Limits
 
 	TBuf  *timedbuf.TimedBuf[TimedPayload]
 	TBufs []*timedbuf.TimedBuf[TimedPayload]
+	// shards holds the state of each of TBufs
+	shards []*bufferShard
 }
 
 func (a *App) getRandomTBuf() *timedbuf.TimedBuf[TimedPayload] {
@@ -50,14 +74,22 @@ func (a *App) getRandomTBuf() *timedbuf.TimedBuf[TimedPayload] {
 	}
 }
 
+// tb returns the TigerBeetle client with its calls traced as children of the span of ctx.
+func (a *App) tb(ctx context.Context) tigerbeetle_go.Client {
+	return tracing.Client(ctx, a.TB)
+}
+
 func (a *App) Close() {
 	for _, b := range a.TBufs {
 		b.Close()
 	}
+	if a.Aliases != nil {
+		a.Aliases.Close()
+	}
 	a.TB.Close()
 }
 
-const TB_MAX_BATCH_SIZE = 8190
+const TB_MAX_BATCH_SIZE = config.TB_MAX_BATCH_SIZE
 
 func NewApp() *App {
 	tb, err := tigerbeetle_go.NewClient(types.Uint128{uint8(config.Config.TbClusterID)}, config.Config.TbAddresses)
@@ -65,73 +97,59 @@ func NewApp() *App {
 		slog.Error("unable to connect to tigerbeetle", "err", err)
 		os.Exit(1)
 	}
+	tb = metrics.Instrument(tb)
 
 	var tbuf *timedbuf.TimedBuf[TimedPayload]
 	var tbufs []*timedbuf.TimedBuf[TimedPayload]
+	var shards []*bufferShard
 	if config.Config.IsBuffered {
-		tbufs = make([]*timedbuf.TimedBuf[TimedPayload], config.Config.BufferCluster)
-
-		// The maximum batch size is set in the TigerBeetle server. The default is 8190.
-		bufSizeFull := float64(config.Config.BufferSize)
-		bufSize80 := bufSizeFull * 0.8
-
-		flushFunc := func(payloads []TimedPayload) {
-			lenPayloads := float64(len(payloads))
-			totalTransferSize := 0
-			indexBatch := 0
-			transferBatches := [][]types.Transfer{[]types.Transfer{}}
-			for _, payload := range payloads {
-				totalTransferSize += len(payload.Transfers)
-				if totalTransferSize > TB_MAX_BATCH_SIZE {
-					indexBatch++
-					transferBatches = append(transferBatches, []types.Transfer{})
-				}
-				transferBatches[indexBatch] = append(transferBatches[indexBatch], payload.Transfers...)
-			}
+		tbufs, shards = newBuffers(tb)
+		tbuf = tbufs[0]
+	}
 
-			metrics.TotalBufferCount.Inc()
-			if lenPayloads == bufSizeFull {
-				metrics.TotalBufferContentsFull.Inc()
-			} else if lenPayloads >= bufSize80 {
-				metrics.TotalBufferContentsGte80.Inc()
-			} else {
-				metrics.TotalBufferContentsLt80.Inc()
-				// slog.Info("Buffer contents less than 80%", "contents %", int((lenPayloads/bufSizeFull)*100))
-			}
+	var aliases alias.Resolver
+	if config.Config.AliasMode != "" {
+		aliases, err = alias.New(alias.Mode(config.Config.AliasMode), config.Config.AliasStore, config.Config.AliasNamespace)
+		if err != nil {
+			slog.Error("unable to open aliases", "err", err)
+			os.Exit(1)
+		}
+	}
 
-			var replies []*proto.CreateTransfersReplyItem
-			for _, transfers := range transferBatches {
-				metrics.TotalCreateTransferTx.Add(float64(len(transfers)))
-				metrics.TotalTbCreateTransfersCall.Inc()
-				if !config.Config.IsDryRun {
-					results, err := tb.CreateTransfers(transfers)
-					replies = ResultsToReply(results, transfers, err)
-				}
-				metrics.TotalCreateTransferTxErr.Add(float64(len(replies)))
-			}
-			res := TimedPayloadResponse{
-				Replies: replies,
-				Error:   err,
-			}
-			for _, payload := range payloads {
-				payload.c <- res
-			}
+	var reg *registry.Registry
+	if config.Config.RegistryFile != "" {
+		reg, err = registry.Load(config.Config.RegistryFile)
+		if err != nil {
+			slog.Error("unable to load registry", "err", err)
+			os.Exit(1)
 		}
-		for i := range config.Config.BufferCluster {
-			tbufs[i] = timedbuf.New(config.Config.BufferSize, config.Config.BufferDelay, flushFunc)
+	}
+
+	var lim *limits.Limits
+	if config.Config.LimitsFile != "" {
+		lim, err = limits.Load(config.Config.LimitsFile)
+		if err != nil {
+			slog.Error("unable to load limits", "err", err)
+			os.Exit(1)
 		}
-		tbuf = tbufs[0]
 	}
 
 	app := &App{
-		TB:    tb,
-		TBuf:  tbuf,
-		TBufs: tbufs,
+		TB:       tb,
+		Aliases:  aliases,
+		Registry: reg,
+		Limits:   lim,
+		TBuf:     tbuf,
+		TBufs:    tbufs,
+		shards:   shards,
 	}
 	return app
 }
 
 func (s *App) GetID(ctx context.Context, in *proto.GetIDRequest) (*proto.GetIDReply, error) {
+	if in.IdempotencyKey != nil {
+		return &proto.GetIDReply{Id: IdempotencyID(*in.IdempotencyKey).String()}, nil
+	}
 	return &proto.GetIDReply{Id: types.ID().String()}, nil
 }
 
@@ -139,46 +157,25 @@ func (s *App) CreateAccounts(ctx context.Context, in *proto.CreateAccountsReques
 	if len(in.Accounts) == 0 {
 		return nil, ErrZeroAccounts
 	}
-	accounts := []types.Account{}
-	for _, inAccount := range in.Accounts {
-		id, err := HexStringToUint128(inAccount.Id)
-		if err != nil {
-			return nil, err
-		}
-		userData128, err := types.HexStringToUint128(inAccount.UserData128)
-		if err != nil {
-			return nil, err
-		}
-		flags := types.AccountFlags{}
-		if inAccount.Flags != nil {
-			flags.Linked = lo.FromPtrOr(inAccount.Flags.Linked, false)
-			flags.DebitsMustNotExceedCredits = lo.FromPtrOr(inAccount.Flags.DebitsMustNotExceedCredits, false)
-			flags.CreditsMustNotExceedDebits = lo.FromPtrOr(inAccount.Flags.CreditsMustNotExceedDebits, false)
-			flags.History = lo.FromPtrOr(inAccount.Flags.History, false)
-		}
-		accounts = append(accounts, types.Account{
-			ID:             *id,
-			DebitsPending:  types.ToUint128(uint64(inAccount.DebitsPending)),
-			DebitsPosted:   types.ToUint128(uint64(inAccount.DebitsPosted)),
-			CreditsPending: types.ToUint128(uint64(inAccount.CreditsPending)),
-			CreditsPosted:  types.ToUint128(uint64(inAccount.CreditsPosted)),
-			UserData128:    userData128,
-			UserData64:     uint64(inAccount.UserData64),
-			UserData32:     uint32(inAccount.UserData32),
-			Ledger:         uint32(inAccount.Ledger),
-			Code:           uint16(inAccount.Code),
-			Flags:          flags.ToUint16(),
-		})
+	if err := checkItems("accounts", len(in.Accounts)); err != nil {
+		return nil, err
+	}
+	accounts, keyed, err := s.accountsFromRequest(ctx, in.Accounts)
+	if err != nil {
+		return nil, err
 	}
 
-	metrics.TotalTbCreateAccountsCall.Inc()
-	results, err := s.TB.CreateAccounts(accounts)
+	results, err := s.createAccounts(ctx, accounts)
 	if err != nil {
 		return nil, err
 	}
 
 	resArr := []*proto.CreateAccountsReplyItem{}
 	for _, r := range results {
+		// Creating a keyed account again is a replay of the same request.
+		if r.Result == types.AccountExists && keyed[r.Index] {
+			continue
+		}
 		resArr = append(resArr, &proto.CreateAccountsReplyItem{
 			Index:  int32(r.Index),
 			Result: proto.CreateAccountResult(r.Result),
@@ -194,108 +191,166 @@ func (s *App) CreateTransfers(ctx context.Context, in *proto.CreateTransfersRequ
 	if len(in.Transfers) == 0 {
 		return nil, ErrZeroTransfers
 	}
-	transfers := []types.Transfer{}
-	for _, inTransfer := range in.Transfers {
-		id, err := HexStringToUint128(inTransfer.Id)
-		if err != nil {
-			return nil, err
-		}
-		flags := types.TransferFlags{}
-		if inTransfer.TransferFlags != nil {
-			flags.Linked = lo.FromPtrOr(inTransfer.TransferFlags.Linked, false)
-			flags.Pending = lo.FromPtrOr(inTransfer.TransferFlags.Pending, false)
-			flags.PostPendingTransfer = lo.FromPtrOr(inTransfer.TransferFlags.PostPendingTransfer, false)
-			flags.VoidPendingTransfer = lo.FromPtrOr(inTransfer.TransferFlags.VoidPendingTransfer, false)
-			flags.BalancingDebit = lo.FromPtrOr(inTransfer.TransferFlags.BalancingDebit, false)
-			flags.BalancingCredit = lo.FromPtrOr(inTransfer.TransferFlags.BalancingCredit, false)
-		}
-		debitAccountID, err := HexStringToUint128(inTransfer.DebitAccountId)
-		if err != nil {
-			return nil, err
-		}
-		creditAccountID, err := HexStringToUint128(inTransfer.CreditAccountId)
-		if err != nil {
-			return nil, err
-		}
-		pendingID, err := HexStringToUint128(lo.FromPtrOr(inTransfer.PendingId, ""))
-		if err != nil {
-			return nil, err
-		}
-		userData128, err := types.HexStringToUint128(inTransfer.UserData128)
-		if err != nil {
-			return nil, err
-		}
-		transfers = append(transfers, types.Transfer{
-			ID:              *id,
-			DebitAccountID:  *debitAccountID,
-			CreditAccountID: *creditAccountID,
-			Amount:          types.ToUint128(uint64(inTransfer.Amount)),
-			PendingID:       *pendingID,
-			UserData128:     userData128,
-			UserData64:      uint64(inTransfer.UserData64),
-			UserData32:      uint32(inTransfer.UserData32),
-			Timeout:         0,
-			Ledger:          uint32(inTransfer.Ledger),
-			Code:            uint16(inTransfer.Code),
-			Flags:           flags.ToUint16(),
-			Timestamp:       0,
-		})
+	if err := checkItems("transfers", len(in.Transfers)); err != nil {
+		return nil, err
+	}
+	if err := s.resolveTransferAliases(in.Transfers); err != nil {
+		return nil, err
+	}
+	transfers, keyed, err := s.transfersFromRequest(ctx, in.Transfers)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.authorizeTransfers(ctx, restriction(ctx), transfers, "transfers"); err != nil {
+		return nil, err
+	}
+	defer s.lockLimits(transfers)()
+	kept, rejected, err := s.checkLimits(ctx, transfers)
+	if err != nil {
+		return nil, err
+	}
+	if kept != nil && len(kept) == 0 {
+		metrics.CountTransferResults(transfers, rejected)
+		return &proto.CreateTransfersReply{Results: ResultsToReply(rejected, transfers, nil)}, nil
+	}
+	inKept, transfersKept := in.Transfers, transfers
+	if kept != nil {
+		inKept = lo.Map(kept, func(i int, _ int) *proto.Transfer { return in.Transfers[i] })
+		transfersKept = lo.Map(kept, func(i int, _ int) types.Transfer { return transfers[i] })
+	}
+	expanded, legs, err := s.expandConditions(inKept, transfersKept)
+	if err != nil {
+		return nil, err
 	}
 
-	var err error
-	var replies []*proto.CreateTransfersReplyItem
-	if config.Config.IsBuffered {
+	var results []types.TransferEventResult
+	// A request of more transfers than fit in a batch is sent on its own.
+	if config.Config.IsBuffered && len(expanded) <= TB_MAX_BATCH_SIZE {
 		buf := s.getRandomTBuf()
+		shard := s.shards[slices.Index(s.TBufs, buf)]
 		c := make(chan TimedPayloadResponse)
-		buf.Put(TimedPayload{
+		// The enqueue span lasts until the flush replies, the time spent waiting for the buffer.
+		_, span := tracing.Start(ctx, "buffer.enqueue", trace.WithAttributes(tracing.CountKey.Int(len(expanded))))
+		shard.enqueue(buf, TimedPayload{
 			c:         c,
 			buf:       buf,
-			Transfers: transfers,
+			Transfers: expanded,
+			Span:      span.SpanContext(),
+			Enqueued:  time.Now(),
 		})
 		res := <-c
-		replies = res.Replies
+		span.AddLink(trace.Link{SpanContext: res.Flush})
+		tracing.End(span, res.Error)
+		results = res.Results
 		err = res.Error
 	} else {
-		metrics.TotalTbCreateTransfersCall.Inc()
-		metrics.TotalCreateTransferTx.Add(float64(len(transfers)))
-		if !config.Config.IsDryRun {
-			var results []types.TransferEventResult
-			results, err = s.TB.CreateTransfers(transfers)
-			replies = ResultsToReply(results, transfers, err)
-			metrics.TotalCreateTransferTxErr.Add(float64(len(results)))
-		}
+		results, err = s.createTransfers(ctx, expanded)
 	}
 
 	if err != nil {
 		return nil, err
 	}
+	results = collapseConditionResults(results, legs)
+	results = mergeLimitResults(results, kept, rejected)
+	metrics.CountTransferResults(transfers, results)
 
+	// Creating a keyed transfer again is a replay of the same request.
+	results = lo.Filter(results, func(r types.TransferEventResult, _ int) bool {
+		return !(r.Result == types.TransferExists && keyed[r.Index])
+	})
 	return &proto.CreateTransfersReply{
-		Results: replies,
+		Results: ResultsToReply(results, transfers, nil),
 	}, nil
 }
 
+// accountsFromRequest converts and validates the accounts of a request, traced as a validate span.
+// keyed tells which accounts have an idempotency key.
+func (s *App) accountsFromRequest(ctx context.Context, in []*proto.Account) (accounts []types.Account, keyed []bool, err error) {
+	_, span := tracing.Start(ctx, "validate", trace.WithAttributes(tracing.CountKey.Int(len(in))))
+	defer func() { tracing.End(span, err) }()
+	r := restriction(ctx)
+	ids := requestIdempotencyIDs(ctx, opCreateAccounts, len(in))
+	accounts = make([]types.Account, 0, len(in))
+	keyed = make([]bool, len(in))
+	for i, inAccount := range in {
+		account, err := AccountFromProtoToTigerbeetle(inAccount)
+		if err != nil {
+			return nil, nil, err
+		}
+		keyed[i] = inAccount.IdempotencyKey != nil
+		if !keyed[i] && ids != nil {
+			if account.ID, err = keyedID(ids[i], account.ID); err != nil {
+				return nil, nil, err
+			}
+			keyed[i] = true
+		}
+		if err := s.validateAccount(*account); err != nil {
+			return nil, nil, fmt.Errorf("accounts[%d]: %w", i, err)
+		}
+		if !r.AllowsAccount(*account) {
+			return nil, nil, errForbidden("accounts[%d]: outside the ledgers, codes or accounts of the caller", i)
+		}
+		accounts = append(accounts, *account)
+	}
+	return accounts, keyed, nil
+}
+
+// transfersFromRequest converts and validates the transfers of a request as accountsFromRequest does.
+func (s *App) transfersFromRequest(ctx context.Context, in []*proto.Transfer) (transfers []types.Transfer, keyed []bool, err error) {
+	_, span := tracing.Start(ctx, "validate", trace.WithAttributes(tracing.CountKey.Int(len(in))))
+	defer func() { tracing.End(span, err) }()
+	ids := requestIdempotencyIDs(ctx, opCreateTransfers, len(in))
+	transfers = make([]types.Transfer, 0, len(in))
+	keyed = make([]bool, len(in))
+	for i, inTransfer := range in {
+		transfer, err := TransferFromProtoToTigerbeetle(inTransfer)
+		if err != nil {
+			return nil, nil, err
+		}
+		keyed[i] = inTransfer.IdempotencyKey != nil
+		if !keyed[i] && ids != nil {
+			if transfer.ID, err = keyedID(ids[i], transfer.ID); err != nil {
+				return nil, nil, err
+			}
+			keyed[i] = true
+		}
+		if err := s.applyAmountDecimal(inTransfer, transfer); err != nil {
+			return nil, nil, fmt.Errorf("transfers[%d]: %w", i, err)
+		}
+		if err := s.validateTransfer(*transfer); err != nil {
+			return nil, nil, fmt.Errorf("transfers[%d]: %w", i, err)
+		}
+		transfers = append(transfers, *transfer)
+	}
+	return transfers, keyed, nil
+}
+
 func (s *App) LookupAccounts(ctx context.Context, in *proto.LookupAccountsRequest) (*proto.LookupAccountsReply, error) {
 	if len(in.AccountIds) == 0 {
 		return nil, ErrZeroAccounts
 	}
+	if err := checkItems("account_ids", len(in.AccountIds)); err != nil {
+		return nil, err
+	}
 	ids := []types.Uint128{}
 	for _, inID := range in.AccountIds {
-		id, err := HexStringToUint128(inID)
+		id, err := s.accountID(inID)
 		if err != nil {
 			return nil, err
 		}
 		ids = append(ids, *id)
 	}
 
-	metrics.TotalTbLookupAccountsCall.Inc()
-	res, err := s.TB.LookupAccounts(ids)
+	res, err := s.lookupAccounts(ctx, ids)
 	if err != nil {
 		return nil, err
 	}
+	if r := restriction(ctx); r != nil {
+		res = lo.Filter(res, func(a types.Account, _ int) bool { return r.AllowsAccount(a) })
+	}
 
 	pAccounts := lo.Map(res, func(a types.Account, _ int) *proto.Account {
-		return AccountToProtoAccount(a)
+		return s.accountToProto(a)
 	})
 	return &proto.LookupAccountsReply{Accounts: pAccounts}, nil
 }
@@ -304,6 +359,9 @@ func (s *App) LookupTransfers(ctx context.Context, in *proto.LookupTransfersRequ
 	if len(in.TransferIds) == 0 {
 		return nil, ErrZeroTransfers
 	}
+	if err := checkItems("transfer_ids", len(in.TransferIds)); err != nil {
+		return nil, err
+	}
 	ids := []types.Uint128{}
 	for _, inID := range in.TransferIds {
 		id, err := HexStringToUint128(inID)
@@ -313,14 +371,16 @@ func (s *App) LookupTransfers(ctx context.Context, in *proto.LookupTransfersRequ
 		ids = append(ids, *id)
 	}
 
-	metrics.TotalTbLookupTransfersCall.Inc()
-	res, err := s.TB.LookupTransfers(ids)
+	res, err := s.lookupTransfers(ctx, ids)
 	if err != nil {
 		return nil, err
 	}
+	if r := restriction(ctx); r != nil {
+		res = lo.Filter(res, func(t types.Transfer, _ int) bool { return r.SeesTransfer(t) })
+	}
 
 	pTransfers := lo.Map(res, func(a types.Transfer, _ int) *proto.Transfer {
-		return TransferToProtoTransfer(a)
+		return s.transferToProto(a)
 	})
 	return &proto.LookupTransfersReply{Transfers: pTransfers}, nil
 }
@@ -329,18 +389,28 @@ func (s *App) GetAccountTransfers(ctx context.Context, in *proto.GetAccountTrans
 	if in.Filter.AccountId == "" {
 		return nil, ErrZeroAccounts
 	}
+	if err := s.resolveAlias(&in.Filter.AccountId); err != nil {
+		return nil, err
+	}
 	tbFilter, err := AccountFilterFromProtoToTigerbeetle(in.Filter)
 	if err != nil {
 		return nil, err
 	}
+	r := restriction(ctx)
+	if err := s.authorizeAccount(ctx, r, tbFilter.AccountID); err != nil {
+		return nil, err
+	}
 	metrics.TotalTbGetAccountTransfersCall.Inc()
-	res, err := s.TB.GetAccountTransfers(*tbFilter)
+	res, err := s.tb(ctx).GetAccountTransfers(*tbFilter)
 	if err != nil {
 		return nil, err
 	}
+	if r != nil {
+		res = lo.Filter(res, func(t types.Transfer, _ int) bool { return r.SeesTransfer(t) })
+	}
 
 	pTransfers := lo.Map(res, func(v types.Transfer, _ int) *proto.Transfer {
-		return TransferToProtoTransfer(v)
+		return s.transferToProto(v)
 	})
 	return &proto.GetAccountTransfersReply{Transfers: pTransfers}, nil
 }
@@ -349,18 +419,36 @@ func (s *App) GetAccountBalances(ctx context.Context, in *proto.GetAccountBalanc
 	if in.Filter.AccountId == "" {
 		return nil, ErrZeroAccounts
 	}
+	if err := s.resolveAlias(&in.Filter.AccountId); err != nil {
+		return nil, err
+	}
 	tbFilter, err := AccountFilterFromProtoToTigerbeetle(in.Filter)
 	if err != nil {
 		return nil, err
 	}
+	if err := s.authorizeAccount(ctx, restriction(ctx), tbFilter.AccountID); err != nil {
+		return nil, err
+	}
 	metrics.TotalTbGetAccountBalancesCall.Inc()
-	res, err := s.TB.GetAccountBalances(*tbFilter)
+	res, err := s.tb(ctx).GetAccountBalances(*tbFilter)
 	if err != nil {
 		return nil, err
 	}
 
+	// The ledger of the account sets the asset scale of the decimal balances.
+	var ledger uint32
+	if s.Registry != nil && len(res) > 0 {
+		metrics.TotalTbLookupAccountsCall.Inc()
+		accounts, err := s.tb(ctx).LookupAccounts([]types.Uint128{tbFilter.AccountID})
+		if err != nil {
+			return nil, err
+		}
+		if len(accounts) > 0 {
+			ledger = accounts[0].Ledger
+		}
+	}
 	pBalances := lo.Map(res, func(v types.AccountBalance, _ int) *proto.AccountBalance {
-		return AccountBalanceFromTigerbeetleToProto(v)
+		return s.accountBalanceToProto(v, ledger)
 	})
 	return &proto.GetAccountBalancesReply{AccountBalances: pBalances}, nil
 }
@@ -378,14 +466,21 @@ func (s *App) QueryTransfers(ctx context.Context, in *proto.QueryTransfersReques
 		return nil, err
 	}
 
+	r := restriction(ctx)
+	if err := restrictQueryFilter(r, tbFilter); err != nil {
+		return nil, err
+	}
 	metrics.TotalTbQueryTransfersCall.Inc()
-	res, err := s.TB.QueryTransfers(*tbFilter)
+	res, err := s.tb(ctx).QueryTransfers(*tbFilter)
 	if err != nil {
 		return nil, err
 	}
+	if r != nil {
+		res = lo.Filter(res, func(t types.Transfer, _ int) bool { return r.SeesTransfer(t) })
+	}
 
 	pTransfers := lo.Map(res, func(v types.Transfer, _ int) *proto.Transfer {
-		return TransferToProtoTransfer(v)
+		return s.transferToProto(v)
 	})
 	return &proto.QueryTransfersReply{Transfers: pTransfers}, nil
 }
@@ -403,14 +498,21 @@ func (s *App) QueryAccounts(ctx context.Context, in *proto.QueryAccountsRequest)
 		return nil, err
 	}
 
+	r := restriction(ctx)
+	if err := restrictQueryFilter(r, tbFilter); err != nil {
+		return nil, err
+	}
 	metrics.TotalTbQueryAccountsCall.Inc()
-	res, err := s.TB.QueryAccounts(*tbFilter)
+	res, err := s.tb(ctx).QueryAccounts(*tbFilter)
 	if err != nil {
 		return nil, err
 	}
+	if r != nil {
+		res = lo.Filter(res, func(a types.Account, _ int) bool { return r.AllowsAccount(a) })
+	}
 
 	pAccounts := lo.Map(res, func(v types.Account, _ int) *proto.Account {
-		return AccountToProtoAccount(v)
+		return s.accountToProto(v)
 	})
 	return &proto.QueryAccountsReply{Accounts: pAccounts}, nil
 }
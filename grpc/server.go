@@ -1,18 +1,24 @@
 package grpc
 
 import (
+	"crypto/tls"
 	"fmt"
 	"log/slog"
 	"net"
 	"os"
 	"time"
 
+	"github.com/lil5/tigerbeetle_api/certs"
 	"github.com/lil5/tigerbeetle_api/config"
 	"github.com/lil5/tigerbeetle_api/metrics"
 	"github.com/lil5/tigerbeetle_api/proto"
+	"github.com/lil5/tigerbeetle_api/quota"
+	"github.com/lil5/tigerbeetle_api/tracing"
 	"github.com/piotrkowalczuk/promgrpc/v4"
 	"github.com/prometheus/client_golang/prometheus"
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
 	"google.golang.org/grpc/health"
 	healthpb "google.golang.org/grpc/health/grpc_health_v1"
 	"google.golang.org/grpc/keepalive"
@@ -30,10 +36,21 @@ func NewServer() {
 		os.Exit(1)
 	}
 
+	tracingShutdown, err := tracing.Init(config.Config.TracingExporter, config.Config.TracingEndpoint)
+	if err != nil {
+		slog.Error("unable to start tracing", "err", err)
+		os.Exit(1)
+	}
+	defer tracingShutdown()
+
 	ssh := promgrpc.ServerStatsHandler()
 
 	srvOpts := []grpc.ServerOption{
 		grpc.StatsHandler(ssh),
+		grpc.MaxRecvMsgSize(config.Config.GrpcMaxMsgSize),
+	}
+	if config.Config.TracingExporter != "" {
+		srvOpts = append(srvOpts, grpc.StatsHandler(otelgrpc.NewServerHandler()))
 	}
 	if config.Config.GrpcHighScale {
 		srvOpts = append(srvOpts,
@@ -45,6 +62,48 @@ func NewServer() {
 			grpc.MaxConcurrentStreams(50_000),
 		)
 	}
+	tlsCerts, err := certs.New(config.Config.TlsCertFile, config.Config.TlsKeyFile, config.Config.TlsClientCAFile)
+	if err != nil {
+		slog.Error("unable to load tls certificate", "err", err)
+		os.Exit(1)
+	}
+	var tlsConfig *tls.Config
+	if tlsCerts != nil {
+		defer tlsCerts.Close()
+		tlsConfig = tlsCerts.Config()
+		srvOpts = append(srvOpts, grpc.Creds(credentials.NewTLS(tlsConfig)))
+	}
+	keys, err := LoadAuth()
+	if err != nil {
+		slog.Error("unable to load auth", "err", err)
+		os.Exit(1)
+	}
+	if keys != nil {
+		srvOpts = append(srvOpts,
+			grpc.ChainUnaryInterceptor(UnaryAuthInterceptor(keys)),
+			grpc.ChainStreamInterceptor(StreamAuthInterceptor(keys)),
+		)
+	}
+	if config.Config.QuotasFile != "" {
+		quotas, err := quota.Load(config.Config.QuotasFile)
+		if err != nil {
+			slog.Error("unable to load quotas", "err", err)
+			os.Exit(1)
+		}
+		srvOpts = append(srvOpts,
+			grpc.ChainUnaryInterceptor(UnaryQuotaInterceptor(quotas)),
+			grpc.ChainStreamInterceptor(StreamQuotaInterceptor(quotas)),
+		)
+	}
+	auditLog, err := OpenAudit()
+	if err != nil {
+		slog.Error("unable to open audit log", "err", err)
+		os.Exit(1)
+	}
+	if auditLog != nil {
+		defer auditLog.Close()
+		srvOpts = append(srvOpts, grpc.ChainUnaryInterceptor(UnaryAuditInterceptor(auditLog)))
+	}
 	s := grpc.NewServer(srvOpts...)
 	prometheus.DefaultRegisterer.MustRegister(ssh)
 
@@ -62,10 +121,10 @@ func NewServer() {
 		reflection.Register(s)
 	}
 
-	prometheusDeferClose := metrics.Register(config.Config.PrometheusAddr)
+	prometheusDeferClose := metrics.Register(config.Config.PrometheusAddr, tlsConfig)
 	defer prometheusDeferClose()
 
-	slog.Info("GRPC server listening at", "address", lis.Addr())
+	slog.Info("GRPC server listening at", "address", lis.Addr(), "tls", tlsConfig != nil, "mtls", config.Config.TlsClientCAFile != "")
 	if err := s.Serve(lis); err != nil {
 		slog.Error("Failed to serve:", "error", err)
 		os.Exit(1)
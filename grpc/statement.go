@@ -0,0 +1,178 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/lil5/tigerbeetle_api/metrics"
+	"github.com/lil5/tigerbeetle_api/proto"
+	"github.com/tigerbeetle/tigerbeetle-go/pkg/types"
+)
+
+var ErrAccountNotFound = errors.New("account not found")
+
+// GetAccountStatement lists every transfer of an account between timestamp_min and timestamp_max,
+// inclusive, with the running balance after each of them.
+func (s *App) GetAccountStatement(ctx context.Context, in *proto.GetAccountStatementRequest) (*proto.GetAccountStatementReply, error) {
+	if in.AccountId == "" {
+		return nil, ErrZeroAccounts
+	}
+	if in.TimestampMax != 0 && in.TimestampMax < in.TimestampMin {
+		return nil, errors.New("timestamp_max must not be before timestamp_min")
+	}
+	id, err := s.accountID(in.AccountId)
+	if err != nil {
+		return nil, err
+	}
+
+	metrics.TotalTbLookupAccountsCall.Inc()
+	accounts, err := s.tb(ctx).LookupAccounts([]types.Uint128{*id})
+	if err != nil {
+		return nil, err
+	}
+	if len(accounts) == 0 {
+		return nil, ErrAccountNotFound
+	}
+	account := accounts[0]
+	if !restriction(ctx).AllowsAccount(account) {
+		return nil, errForbidden("account %s is outside the ledgers, codes or accounts of the caller", account.ID)
+	}
+
+	replay := newBalanceReplay(account.ID)
+	filter := types.AccountFilter{
+		AccountID:    account.ID,
+		TimestampMax: in.TimestampMax,
+		Flags: types.AccountFilterFlags{
+			Debits:  true,
+			Credits: true,
+		}.ToUint32(),
+	}
+	var opening *proto.BalanceAt
+	if account.AccountFlags().History && in.TimestampMin > 0 {
+		// Start from the snapshot just before the range instead of replaying all history,
+		// with the pending transfers still open then so they expire within the range.
+		var source proto.BalanceSource
+		if replay, source, err = s.replayAt(ctx, account, in.TimestampMin-1); err != nil {
+			return nil, err
+		}
+		if opening, err = s.balanceToProto(&replay.balance, account, source); err != nil {
+			return nil, err
+		}
+		filter.TimestampMin = in.TimestampMin
+	}
+
+	// The opening balance is the balance just before timestamp_min.
+	openingAt := in.TimestampMin
+	if openingAt > 0 {
+		openingAt--
+	}
+	closingAt := in.TimestampMax
+	if closingAt == 0 {
+		closingAt = uint64(time.Now().UnixNano())
+	}
+
+	lines := []*proto.StatementLine{}
+	err = s.eachAccountTransfers(ctx, filter, func(transfers []types.Transfer) error {
+		if err := s.resolvePending(ctx, replay, transfers); err != nil {
+			return err
+		}
+		for _, t := range transfers {
+			if t.Timestamp < in.TimestampMin {
+				replay.Apply(t)
+				continue
+			}
+			if opening == nil {
+				replay.Expire(openingAt)
+				var err error
+				if opening, err = s.balanceToProto(&replay.balance, account, proto.BalanceSource_BalanceSourceTransfers); err != nil {
+					return err
+				}
+			}
+
+			// Pending transfers that expired since the line before change the pending balance without a line of their own.
+			replay.Expire(t.Timestamp)
+			postedBefore, pendingBefore := replay.balance.Net(), replay.balance.PendingNet()
+			replay.Apply(t)
+			postedAfter, pendingAfter := replay.balance.Net(), replay.balance.PendingNet()
+			amounts := []*big.Int{
+				new(big.Int).Sub(postedAfter, postedBefore),
+				new(big.Int).Sub(pendingAfter, pendingBefore),
+				postedAfter,
+				pendingAfter,
+			}
+			for _, v := range amounts {
+				if !v.IsInt64() {
+					return fmt.Errorf("transfer %s: %w", t.ID, ErrBalanceOverflow)
+				}
+			}
+			lines = append(lines, &proto.StatementLine{
+				Transfer:       s.transferToProto(t),
+				PostedAmount:   amounts[0].Int64(),
+				PendingAmount:  amounts[1].Int64(),
+				Balance:        amounts[2].Int64(),
+				PendingBalance: amounts[3].Int64(),
+			})
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if opening == nil {
+		replay.Expire(openingAt)
+		if opening, err = s.balanceToProto(&replay.balance, account, proto.BalanceSource_BalanceSourceTransfers); err != nil {
+			return nil, err
+		}
+	}
+	replay.Expire(closingAt)
+	closing, err := s.balanceToProto(&replay.balance, account, proto.BalanceSource_BalanceSourceTransfers)
+	if err != nil {
+		return nil, err
+	}
+
+	return &proto.GetAccountStatementReply{
+		Account:        s.accountToProto(account),
+		TimestampMin:   in.TimestampMin,
+		TimestampMax:   in.TimestampMax,
+		OpeningBalance: opening,
+		Lines:          lines,
+		ClosingBalance: closing,
+	}, nil
+}
+
+// resolvePending looks up the pending transfers that are posted or voided in transfers
+// but were created before the replay started.
+func (s *App) resolvePending(ctx context.Context, replay *balanceReplay, transfers []types.Transfer) error {
+	inPage := map[types.Uint128]bool{}
+	ids := []types.Uint128{}
+	for _, t := range transfers {
+		flags := t.TransferFlags()
+		if flags.Pending {
+			inPage[t.ID] = true
+			continue
+		}
+		if !flags.PostPendingTransfer && !flags.VoidPendingTransfer {
+			continue
+		}
+		if _, ok := replay.pending[t.PendingID]; ok || inPage[t.PendingID] {
+			continue
+		}
+		ids = append(ids, t.PendingID)
+	}
+	if len(ids) == 0 {
+		return nil
+	}
+
+	metrics.TotalTbLookupTransfersCall.Inc()
+	res, err := s.tb(ctx).LookupTransfers(ids)
+	if err != nil {
+		return err
+	}
+	for _, p := range res {
+		replay.pending[p.ID] = p
+	}
+	return nil
+}
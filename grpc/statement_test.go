@@ -0,0 +1,143 @@
+package grpc
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/lil5/tigerbeetle_api/proto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/tigerbeetle/tigerbeetle-go/pkg/types"
+)
+
+func TestGetAccountStatement(t *testing.T) {
+	mockClient := new(MockTigerBeetleClient)
+	app := &App{TB: mockClient}
+
+	accountID := types.ToUint128(1)
+	otherID := types.ToUint128(2)
+	pendingFlags := types.TransferFlags{Pending: true}.ToUint16()
+
+	t.Run("should return error when account is not found", func(t *testing.T) {
+		mockClient.On("LookupAccounts", []types.Uint128{accountID}).Return([]types.Account{}, nil).Once()
+
+		_, err := app.GetAccountStatement(context.Background(), &proto.GetAccountStatementRequest{AccountId: "1"})
+		assert.ErrorIs(t, err, ErrAccountNotFound)
+		mockClient.AssertExpectations(t)
+	})
+
+	t.Run("should replay opening balance and running balances", func(t *testing.T) {
+		mockClient.On("LookupAccounts", []types.Uint128{accountID}).
+			Return([]types.Account{{ID: accountID, Ledger: 1, Code: 1, Timestamp: 1}}, nil).Once()
+		mockClient.On("GetAccountTransfers", mock.MatchedBy(func(f types.AccountFilter) bool {
+			return f.TimestampMin == 0 && f.TimestampMax == 20
+		})).Return([]types.Transfer{
+			{ID: types.ToUint128(10), DebitAccountID: otherID, CreditAccountID: accountID, Amount: types.ToUint128(100), Timestamp: 5},
+			{ID: types.ToUint128(11), DebitAccountID: accountID, CreditAccountID: otherID, Amount: types.ToUint128(40), Flags: pendingFlags, Timestamp: 12},
+			{ID: types.ToUint128(12), DebitAccountID: accountID, CreditAccountID: otherID, PendingID: types.ToUint128(11), Flags: types.TransferFlags{VoidPendingTransfer: true}.ToUint16(), Timestamp: 15},
+		}, nil).Once()
+
+		resp, err := app.GetAccountStatement(context.Background(), &proto.GetAccountStatementRequest{AccountId: "1", TimestampMin: 10, TimestampMax: 20})
+		assert.NoError(t, err)
+		assert.Equal(t, int64(100), resp.OpeningBalance.Balance)
+		assert.Len(t, resp.Lines, 2)
+		assert.Equal(t, int64(-40), resp.Lines[0].PendingAmount)
+		assert.Equal(t, int64(-40), resp.Lines[0].PendingBalance)
+		assert.Equal(t, int64(40), resp.Lines[1].PendingAmount)
+		assert.Equal(t, int64(0), resp.Lines[1].PendingBalance)
+		assert.Equal(t, int64(100), resp.ClosingBalance.Balance)
+		mockClient.AssertExpectations(t)
+	})
+
+	t.Run("should release expired pending transfers", func(t *testing.T) {
+		second := uint64(time.Second)
+		mockClient.On("LookupAccounts", []types.Uint128{accountID}).
+			Return([]types.Account{{ID: accountID, Ledger: 1, Code: 1, Timestamp: 1}}, nil).Once()
+		mockClient.On("GetAccountTransfers", mock.AnythingOfType("types.AccountFilter")).Return([]types.Transfer{
+			{ID: types.ToUint128(20), DebitAccountID: accountID, CreditAccountID: otherID, Amount: types.ToUint128(40), Timeout: 1, Flags: pendingFlags, Timestamp: 5},
+			{ID: types.ToUint128(21), DebitAccountID: accountID, CreditAccountID: otherID, Amount: types.ToUint128(7), Timeout: 10, Flags: pendingFlags, Timestamp: 5 + second},
+			{ID: types.ToUint128(22), DebitAccountID: otherID, CreditAccountID: accountID, Amount: types.ToUint128(3), Timestamp: 5 + 2*second},
+		}, nil).Once()
+
+		resp, err := app.GetAccountStatement(context.Background(), &proto.GetAccountStatementRequest{AccountId: "1", TimestampMax: 5 + 20*second})
+		assert.NoError(t, err)
+		assert.Len(t, resp.Lines, 3)
+		assert.Equal(t, int64(-40), resp.Lines[0].PendingBalance)
+		// The first pending transfer expired at the second line.
+		assert.Equal(t, int64(-7), resp.Lines[1].PendingAmount)
+		assert.Equal(t, int64(-7), resp.Lines[1].PendingBalance)
+		assert.Equal(t, int64(0), resp.Lines[2].PendingAmount)
+		assert.Equal(t, uint64(0), resp.ClosingBalance.DebitsPending)
+		mockClient.AssertExpectations(t)
+	})
+
+	t.Run("should fail on amounts over 64 bits", func(t *testing.T) {
+		mockClient.On("LookupAccounts", []types.Uint128{accountID}).
+			Return([]types.Account{{ID: accountID, Ledger: 1, Code: 1, Timestamp: 1}}, nil).Once()
+		mockClient.On("GetAccountTransfers", mock.AnythingOfType("types.AccountFilter")).Return([]types.Transfer{
+			{ID: types.ToUint128(30), DebitAccountID: otherID, CreditAccountID: accountID, Amount: types.Uint128{7: 0x80}, Timestamp: 5},
+		}, nil).Once()
+
+		_, err := app.GetAccountStatement(context.Background(), &proto.GetAccountStatementRequest{AccountId: "1"})
+		assert.ErrorIs(t, err, ErrBalanceOverflow)
+		mockClient.AssertExpectations(t)
+	})
+
+	t.Run("should start from history snapshot and resolve earlier pending transfers", func(t *testing.T) {
+		pendingID := types.ToUint128(20)
+		mockClient.On("LookupAccounts", []types.Uint128{accountID}).
+			Return([]types.Account{{ID: accountID, Flags: types.AccountFlags{History: true}.ToUint16(), Timestamp: 1}}, nil).Once()
+		mockClient.On("GetAccountBalances", mock.MatchedBy(func(f types.AccountFilter) bool {
+			return f.TimestampMax == 9
+		})).Return([]types.AccountBalance{{CreditsPosted: types.ToUint128(10), DebitsPending: types.ToUint128(30), Timestamp: 8}}, nil).Once()
+		mockClient.On("GetAccountTransfers", mock.MatchedBy(func(f types.AccountFilter) bool {
+			return f.TimestampMin == 0 && f.TimestampMax == 8
+		})).Return([]types.Transfer{}, nil).Once()
+		mockClient.On("GetAccountTransfers", mock.MatchedBy(func(f types.AccountFilter) bool {
+			return f.TimestampMin == 10
+		})).Return([]types.Transfer{
+			{ID: types.ToUint128(21), DebitAccountID: accountID, CreditAccountID: otherID, Amount: types.ToUint128(25), PendingID: pendingID, Flags: types.TransferFlags{PostPendingTransfer: true}.ToUint16(), Timestamp: 11},
+		}, nil).Once()
+		mockClient.On("LookupTransfers", []types.Uint128{pendingID}).Return([]types.Transfer{
+			{ID: pendingID, DebitAccountID: accountID, CreditAccountID: otherID, Amount: types.ToUint128(30), Flags: pendingFlags, Timestamp: 7},
+		}, nil).Once()
+
+		resp, err := app.GetAccountStatement(context.Background(), &proto.GetAccountStatementRequest{AccountId: "1", TimestampMin: 10})
+		assert.NoError(t, err)
+		assert.Equal(t, proto.BalanceSource_BalanceSourceHistory, resp.OpeningBalance.Source)
+		assert.Len(t, resp.Lines, 1)
+		assert.Equal(t, int64(-25), resp.Lines[0].PostedAmount)
+		assert.Equal(t, int64(30), resp.Lines[0].PendingAmount)
+		assert.Equal(t, int64(-15), resp.ClosingBalance.Balance)
+		assert.Equal(t, uint64(0), resp.ClosingBalance.DebitsPending)
+		mockClient.AssertExpectations(t)
+	})
+
+	t.Run("should expire pending transfers created before the range within it", func(t *testing.T) {
+		second := uint64(time.Second)
+		pending := types.Transfer{ID: types.ToUint128(40), DebitAccountID: accountID, CreditAccountID: otherID, Amount: types.ToUint128(30), Timeout: 1, Flags: pendingFlags, Timestamp: 7}
+		mockClient.On("LookupAccounts", []types.Uint128{accountID}).
+			Return([]types.Account{{ID: accountID, Flags: types.AccountFlags{History: true}.ToUint16(), Timestamp: 1}}, nil).Once()
+		mockClient.On("GetAccountBalances", mock.MatchedBy(func(f types.AccountFilter) bool {
+			return f.TimestampMax == 9
+		})).Return([]types.AccountBalance{{DebitsPending: types.ToUint128(30), Timestamp: 7}}, nil).Once()
+		mockClient.On("GetAccountTransfers", mock.MatchedBy(func(f types.AccountFilter) bool {
+			return f.TimestampMin == 0 && f.TimestampMax == 7
+		})).Return([]types.Transfer{pending}, nil).Once()
+		mockClient.On("GetAccountTransfers", mock.MatchedBy(func(f types.AccountFilter) bool {
+			return f.TimestampMin == 10
+		})).Return([]types.Transfer{
+			{ID: types.ToUint128(41), DebitAccountID: otherID, CreditAccountID: accountID, Amount: types.ToUint128(5), Timestamp: 7 + 2*second},
+		}, nil).Once()
+
+		resp, err := app.GetAccountStatement(context.Background(), &proto.GetAccountStatementRequest{AccountId: "1", TimestampMin: 10})
+		assert.NoError(t, err)
+		assert.Equal(t, uint64(30), resp.OpeningBalance.DebitsPending)
+		assert.Len(t, resp.Lines, 1)
+		// The pending transfer expired before the line, without a line of its own.
+		assert.Equal(t, int64(0), resp.Lines[0].PendingBalance)
+		assert.Equal(t, uint64(0), resp.ClosingBalance.DebitsPending)
+		mockClient.AssertExpectations(t)
+	})
+}
@@ -0,0 +1,377 @@
+// Package importer streams accounts or transfers from csv or ndjson into TigerBeetle.
+//
+// Rows are sent to TigerBeetle as they are, this is a raw restore path for admins:
+// the registry, aliases, spending limits, conditions, amount_decimal and the transfer buffer
+// of the api do not apply, and rows with an idempotency key are rejected.
+package importer
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+
+	"github.com/lil5/tigerbeetle_api/config"
+	"github.com/lil5/tigerbeetle_api/convert"
+	"github.com/lil5/tigerbeetle_api/metrics"
+	"github.com/lil5/tigerbeetle_api/proto"
+	"github.com/samber/lo"
+	tigerbeetle_go "github.com/tigerbeetle/tigerbeetle-go"
+	"github.com/tigerbeetle/tigerbeetle-go/pkg/types"
+)
+
+type Kind string
+
+const (
+	KindAccounts  Kind = "accounts"
+	KindTransfers Kind = "transfers"
+)
+
+type Format string
+
+const (
+	FormatCSV    Format = "csv"
+	FormatNDJSON Format = "ndjson"
+)
+
+var (
+	ErrUnknownKind   = errors.New("kind must be accounts or transfers")
+	ErrUnknownFormat = errors.New("format must be csv or ndjson")
+	// ErrIdempotencyKey is the error of a row with an idempotency key, rows are imported with their own id.
+	ErrIdempotencyKey = errors.New("idempotency_key is not supported by imports, set the id")
+)
+
+type Options struct {
+	Kind   Kind
+	Format Format
+	// Columns renames csv header columns to field names, a column renamed to "-" is ignored.
+	Columns map[string]string
+	// Imported sets the imported flag on every row so the timestamp of the row is kept.
+	Imported bool
+	// Checkpoint is the path of the progress file, empty disables checkpoints.
+	Checkpoint string
+	// BatchSize defaults to config.TB_MAX_BATCH_SIZE.
+	BatchSize int
+	// MaxRejected caps the rejections kept in the report, defaults to DefaultMaxRejected.
+	MaxRejected int
+	// OnReject is called with every rejection, including those past MaxRejected.
+	OnReject func(Rejection)
+}
+
+// DefaultMaxRejected is the number of rejections kept in a report when Options.MaxRejected is not set.
+const DefaultMaxRejected = 1000
+
+// Rejection is a row that could not be parsed or was refused by TigerBeetle.
+type Rejection struct {
+	Row    uint64 `json:"row"`
+	Code   uint32 `json:"code"`
+	Result string `json:"result"`
+	Error  string `json:"error,omitempty"`
+}
+
+type Report struct {
+	// Rows read, including skipped rows
+	Rows uint64 `json:"rows"`
+	// Rows skipped as they were committed before the checkpoint
+	Skipped  uint64      `json:"skipped"`
+	Imported uint64 `json:"imported"`
+	// Rows rejected, Rejected holds the first Options.MaxRejected of them
+	RejectedRows uint64      `json:"rejected_rows"`
+	Rejected     []Rejection `json:"rejected"`
+}
+
+type checkpoint struct {
+	Rows uint64 `json:"rows"`
+}
+
+func readCheckpoint(path string) (uint64, error) {
+	b, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+	var c checkpoint
+	if err := json.Unmarshal(b, &c); err != nil {
+		return 0, fmt.Errorf("invalid checkpoint %s: %w", path, err)
+	}
+	return c.Rows, nil
+}
+
+func writeCheckpoint(path string, rows uint64) error {
+	b, _ := json.Marshal(checkpoint{Rows: rows})
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, b, 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+// item is a parsed row waiting to be submitted.
+type item[T any] struct {
+	Row    uint64
+	Value  T
+	Linked bool
+}
+
+// result is a TigerBeetle result for the item at Index of a submitted batch.
+type result struct {
+	Index uint32
+	Code  uint32
+	Name  string
+}
+
+type importer[T any] struct {
+	opts   Options
+	report *Report
+	decode func(b []byte) (T, bool, error)
+	submit func(batch []T) ([]result, error)
+	batch  []item[T]
+	// broken is set while the rest of a linked run with an invalid row is rejected.
+	broken bool
+}
+
+// Run imports every row of r. The report is returned even when an error stops the import,
+// rows up to the last checkpoint are committed and will be skipped when run again.
+func Run(tb tigerbeetle_go.Client, r io.Reader, opts Options) (*Report, error) {
+	if opts.BatchSize <= 0 || opts.BatchSize > config.TB_MAX_BATCH_SIZE {
+		opts.BatchSize = config.TB_MAX_BATCH_SIZE
+	}
+	if opts.MaxRejected <= 0 {
+		opts.MaxRejected = DefaultMaxRejected
+	}
+	fields, ok := kindFields[opts.Kind]
+	if !ok {
+		return nil, ErrUnknownKind
+	}
+	var rows rowReader
+	switch opts.Format {
+	case FormatCSV:
+		var err error
+		rows, err = newCSVReader(r, fields, opts.Columns)
+		if err != nil {
+			return nil, err
+		}
+	case FormatNDJSON:
+		rows = newNDJSONReader(r)
+	default:
+		return nil, ErrUnknownFormat
+	}
+
+	report := &Report{Rejected: []Rejection{}}
+	switch opts.Kind {
+	case KindAccounts:
+		return report, (&importer[types.Account]{
+			opts:   opts,
+			report: report,
+			decode: func(b []byte) (types.Account, bool, error) {
+				var a proto.Account
+				if err := json.Unmarshal(b, &a); err != nil {
+					return types.Account{}, false, &rowError{err}
+				}
+				linked := a.Flags != nil && lo.FromPtrOr(a.Flags.Linked, false)
+				if a.IdempotencyKey != nil {
+					return types.Account{}, linked, ErrIdempotencyKey
+				}
+				if opts.Imported {
+					if a.Flags == nil {
+						a.Flags = &proto.AccountFlags{}
+					}
+					a.Flags.Imported = lo.ToPtr(true)
+				}
+				account, err := convert.AccountFromProtoToTigerbeetle(&a)
+				if err != nil {
+					return types.Account{}, linked, err
+				}
+				return *account, account.AccountFlags().Linked, nil
+			},
+			submit: func(batch []types.Account) ([]result, error) {
+				metrics.TotalTbCreateAccountsCall.Inc()
+				res, err := tb.CreateAccounts(batch)
+				if err != nil {
+					return nil, err
+				}
+				metrics.TotalCreateAccountsTxErr.Add(float64(len(res)))
+				return lo.Map(res, func(r types.AccountEventResult, _ int) result {
+					return result{Index: r.Index, Code: uint32(r.Result), Name: proto.CreateAccountResult(r.Result).String()}
+				}), nil
+			},
+		}).run(rows)
+	default:
+		return report, (&importer[types.Transfer]{
+			opts:   opts,
+			report: report,
+			decode: func(b []byte) (types.Transfer, bool, error) {
+				var t proto.Transfer
+				if err := json.Unmarshal(b, &t); err != nil {
+					return types.Transfer{}, false, &rowError{err}
+				}
+				linked := t.TransferFlags != nil && lo.FromPtrOr(t.TransferFlags.Linked, false)
+				if t.IdempotencyKey != nil {
+					return types.Transfer{}, linked, ErrIdempotencyKey
+				}
+				if opts.Imported {
+					if t.TransferFlags == nil {
+						t.TransferFlags = &proto.TransferFlags{}
+					}
+					t.TransferFlags.Imported = lo.ToPtr(true)
+				}
+				transfer, err := convert.TransferFromProtoToTigerbeetle(&t)
+				if err != nil {
+					return types.Transfer{}, linked, err
+				}
+				return *transfer, transfer.TransferFlags().Linked, nil
+			},
+			submit: func(batch []types.Transfer) ([]result, error) {
+				metrics.TotalTbCreateTransfersCall.Inc()
+				metrics.TotalCreateTransferTx.Add(float64(len(batch)))
+				res, err := tb.CreateTransfers(batch)
+				if err != nil {
+					return nil, err
+				}
+				metrics.TotalCreateTransferTxErr.Add(float64(len(res)))
+				return lo.Map(res, func(r types.TransferEventResult, _ int) result {
+					return result{Index: r.Index, Code: uint32(r.Result), Name: proto.CreateTransferResult(r.Result).String()}
+				}), nil
+			},
+		}).run(rows)
+	}
+}
+
+func (im *importer[T]) run(rows rowReader) error {
+	var skip uint64
+	if im.opts.Checkpoint != "" {
+		var err error
+		skip, err = readCheckpoint(im.opts.Checkpoint)
+		if err != nil {
+			return err
+		}
+		if skip > 0 {
+			slog.Info("Resuming import from checkpoint", "rows", skip)
+		}
+	}
+
+	for {
+		b, err := rows.Next()
+		if err == io.EOF {
+			break
+		}
+		var rowErr *rowError
+		if err != nil && !errors.As(err, &rowErr) {
+			return err
+		}
+		im.report.Rows++
+		if im.report.Rows <= skip {
+			im.report.Skipped++
+			continue
+		}
+		var v T
+		var linked bool
+		if err == nil {
+			v, linked, err = im.decode(b)
+		}
+		if err != nil {
+			// The linked flag of an unreadable row is unknown, it is taken to continue an open run.
+			if errors.As(err, &rowErr) {
+				linked = im.broken || len(im.batch) > 0 && im.batch[len(im.batch)-1].Linked
+			}
+			im.breakRun()
+			im.reject(Rejection{Row: im.report.Rows, Result: "invalid", Error: err.Error()})
+			im.broken = linked
+			continue
+		}
+		if im.broken {
+			im.reject(im.linkedFailed(im.report.Rows))
+			im.broken = linked
+			continue
+		}
+		im.batch = append(im.batch, item[T]{Row: im.report.Rows, Value: v, Linked: linked})
+		if len(im.batch) >= im.opts.BatchSize {
+			if err := im.flush(false); err != nil {
+				return err
+			}
+		}
+	}
+	return im.flush(true)
+}
+
+// flush submits the batch. Unless final, an open linked chain at the end
+// of the batch is kept back so it is not split over two requests.
+func (im *importer[T]) flush(final bool) error {
+	n := len(im.batch)
+	if !final {
+		for n > 0 && im.batch[n-1].Linked {
+			n--
+		}
+		if n == 0 {
+			// A single chain fills the batch, let TigerBeetle reject it.
+			n = len(im.batch)
+		}
+	}
+	if n == 0 {
+		return nil
+	}
+
+	batch := im.batch[:n]
+	results, err := im.submit(lo.Map(batch, func(it item[T], _ int) T { return it.Value }))
+	if err != nil {
+		return err
+	}
+	for _, r := range results {
+		im.reject(Rejection{
+			Row:    batch[r.Index].Row,
+			Code:   r.Code,
+			Result: r.Name,
+		})
+	}
+	im.report.Imported += uint64(n - len(results))
+
+	if im.opts.Checkpoint != "" {
+		if err := writeCheckpoint(im.opts.Checkpoint, batch[n-1].Row); err != nil {
+			return err
+		}
+	}
+	im.batch = append(im.batch[:0], im.batch[n:]...)
+	return nil
+}
+
+// breakRun rejects the rows of the open linked run at the end of the batch,
+// so that the rows around an invalid row do not join into a different chain.
+func (im *importer[T]) breakRun() {
+	n := len(im.batch)
+	for n > 0 && im.batch[n-1].Linked {
+		n--
+	}
+	for _, it := range im.batch[n:] {
+		im.reject(im.linkedFailed(it.Row))
+	}
+	im.batch = im.batch[:n]
+}
+
+// linkedFailed is the rejection of a row whose linked run has an invalid row, as TigerBeetle reports a failed chain.
+func (im *importer[T]) linkedFailed(row uint64) Rejection {
+	r := Rejection{
+		Row:    row,
+		Code:   uint32(proto.CreateTransferResult_TransferLinkedEventFailed),
+		Result: proto.CreateTransferResult_TransferLinkedEventFailed.String(),
+		Error:  "the linked run has an invalid row",
+	}
+	if im.opts.Kind == KindAccounts {
+		r.Code = uint32(proto.CreateAccountResult_AccountLinkedEventFailed)
+		r.Result = proto.CreateAccountResult_AccountLinkedEventFailed.String()
+	}
+	return r
+}
+
+func (im *importer[T]) reject(r Rejection) {
+	im.report.RejectedRows++
+	if len(im.report.Rejected) < im.opts.MaxRejected {
+		im.report.Rejected = append(im.report.Rejected, r)
+	}
+	if im.opts.OnReject != nil {
+		im.opts.OnReject(r)
+	}
+}
@@ -0,0 +1,163 @@
+package importer
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	tigerbeetle_go "github.com/tigerbeetle/tigerbeetle-go"
+	"github.com/tigerbeetle/tigerbeetle-go/pkg/types"
+)
+
+// fakeClient records created batches and rejects transfers with an amount of 13.
+type fakeClient struct {
+	tigerbeetle_go.Client
+	accounts  [][]types.Account
+	transfers [][]types.Transfer
+}
+
+func (c *fakeClient) CreateAccounts(accounts []types.Account) ([]types.AccountEventResult, error) {
+	c.accounts = append(c.accounts, accounts)
+	return []types.AccountEventResult{}, nil
+}
+
+func (c *fakeClient) CreateTransfers(transfers []types.Transfer) ([]types.TransferEventResult, error) {
+	c.transfers = append(c.transfers, transfers)
+	results := []types.TransferEventResult{}
+	for i, t := range transfers {
+		if t.Amount == types.ToUint128(13) {
+			results = append(results, types.TransferEventResult{Index: uint32(i), Result: types.TransferExceedsCredits})
+		}
+	}
+	return results, nil
+}
+
+func TestImportCSV(t *testing.T) {
+	client := &fakeClient{}
+	input := strings.Join([]string{
+		"ref,debit,credit,amount,ledger,code,ts,linked",
+		"a1,1,2,10,1,1,100,false",
+		"a2,1,2,13,1,1,101,false",
+		"a3,1,2,x,1,1,102,false",
+		"a4,1,2,20,1,1,103,true",
+		"a5,1,2,30,1,1,104,false",
+	}, "\n")
+
+	report, err := Run(client, strings.NewReader(input), Options{
+		Kind:   KindTransfers,
+		Format: FormatCSV,
+		Columns: map[string]string{
+			"ref":    "-",
+			"debit":  "debit_account_id",
+			"credit": "credit_account_id",
+			"ts":     "timestamp",
+		},
+		Imported:  true,
+		BatchSize: 3,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, uint64(5), report.Rows)
+	assert.Equal(t, uint64(3), report.Imported)
+	assert.Len(t, report.Rejected, 2)
+	assert.Equal(t, uint64(3), report.Rejected[0].Row)
+	assert.Equal(t, "invalid", report.Rejected[0].Result)
+	assert.Equal(t, uint64(2), report.Rejected[1].Row)
+	assert.Equal(t, "TransferExceedsCredits", report.Rejected[1].Result)
+
+	// The open linked chain of a4 is carried over to the next batch.
+	require.Len(t, client.transfers, 2)
+	assert.Len(t, client.transfers[0], 2)
+	assert.Len(t, client.transfers[1], 2)
+	assert.True(t, client.transfers[0][0].TransferFlags().Imported)
+	assert.Equal(t, uint64(100), client.transfers[0][0].Timestamp)
+}
+
+func TestImportNDJSONCheckpoint(t *testing.T) {
+	checkpoint := filepath.Join(t.TempDir(), "accounts.checkpoint")
+	input := `{"id":"1","ledger":1,"code":1}
+{"id":"2","ledger":1,"code":1}
+
+{"id":"3","ledger":1,"code":1}
+`
+	require.NoError(t, os.WriteFile(checkpoint, []byte(`{"rows":2}`), 0o644))
+
+	client := &fakeClient{}
+	report, err := Run(client, strings.NewReader(input), Options{
+		Kind:       KindAccounts,
+		Format:     FormatNDJSON,
+		Checkpoint: checkpoint,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, uint64(2), report.Skipped)
+	assert.Equal(t, uint64(1), report.Imported)
+	require.Len(t, client.accounts, 1)
+	assert.Equal(t, types.ToUint128(3), client.accounts[0][0].ID)
+	assert.Equal(t, uint64(0), client.accounts[0][0].Timestamp)
+
+	b, err := os.ReadFile(checkpoint)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"rows":3}`, string(b))
+}
+
+func TestImportUnknownColumn(t *testing.T) {
+	_, err := Run(&fakeClient{}, strings.NewReader("nope\n1\n"), Options{Kind: KindAccounts, Format: FormatCSV})
+	assert.Error(t, err)
+}
+
+func TestImportIdempotencyKey(t *testing.T) {
+	client := &fakeClient{}
+	report, err := Run(client, strings.NewReader(`{"idempotency_key":"k","debit_account_id":"1","credit_account_id":"2","amount":1,"ledger":1,"code":1}`), Options{
+		Kind:   KindTransfers,
+		Format: FormatNDJSON,
+	})
+	require.NoError(t, err)
+	require.Len(t, report.Rejected, 1)
+	assert.Equal(t, ErrIdempotencyKey.Error(), report.Rejected[0].Error)
+	assert.Empty(t, client.transfers)
+}
+
+func TestImportLinkedInvalid(t *testing.T) {
+	rows := []string{
+		`{"id":"1","debit_account_id":"1","credit_account_id":"2","amount":1,"ledger":1,"code":1,"transfer_flags":{"linked":true}}`,
+		`{"id":"2","idempotency_key":"k","debit_account_id":"1","credit_account_id":"2","amount":1,"ledger":1,"code":1,"transfer_flags":{"linked":true}}`,
+		`{"id":"3","debit_account_id":"1","credit_account_id":"2","amount":1,"ledger":1,"code":1}`,
+		`{"id":"4","debit_account_id":"1","credit_account_id":"2","amount":1,"ledger":1,"code":1,"transfer_flags":{"linked":true}}`,
+		`{"id":"5",`,
+		`{"id":"6","debit_account_id":"1","credit_account_id":"2","amount":1,"ledger":1,"code":1}`,
+		`{"id":"7","debit_account_id":"1","credit_account_id":"2","amount":1,"ledger":1,"code":1}`,
+	}
+
+	t.Run("should reject the whole linked run of an invalid row", func(t *testing.T) {
+		client := &fakeClient{}
+		var streamed []uint64
+		report, err := Run(client, strings.NewReader(strings.Join(rows, "\n")), Options{
+			Kind:     KindTransfers,
+			Format:   FormatNDJSON,
+			OnReject: func(r Rejection) { streamed = append(streamed, r.Row) },
+		})
+		require.NoError(t, err)
+		assert.Equal(t, uint64(1), report.Imported)
+		assert.Equal(t, uint64(6), report.RejectedRows)
+		assert.Equal(t, []uint64{1, 2, 3, 4, 5, 6}, streamed)
+		assert.Equal(t, "TransferLinkedEventFailed", report.Rejected[0].Result)
+		assert.Equal(t, "invalid", report.Rejected[1].Result)
+		assert.Equal(t, "TransferLinkedEventFailed", report.Rejected[2].Result)
+		require.Len(t, client.transfers, 1)
+		require.Len(t, client.transfers[0], 1)
+		assert.Equal(t, types.ToUint128(7), client.transfers[0][0].ID)
+	})
+
+	t.Run("should cap the rejections in the report", func(t *testing.T) {
+		report, err := Run(&fakeClient{}, strings.NewReader(strings.Join(rows, "\n")), Options{
+			Kind:        KindTransfers,
+			Format:      FormatNDJSON,
+			MaxRejected: 2,
+		})
+		require.NoError(t, err)
+		assert.Equal(t, uint64(6), report.RejectedRows)
+		assert.Len(t, report.Rejected, 2)
+	})
+}
@@ -0,0 +1,204 @@
+package importer
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+type fieldType int
+
+const (
+	fieldString fieldType = iota
+	fieldNumber
+	fieldFlag
+)
+
+// fields maps the json field names of proto.Account and proto.Transfer to their type.
+type fields struct {
+	FlagsKey string
+	Types    map[string]fieldType
+}
+
+var kindFields = map[Kind]fields{
+	KindAccounts: {
+		FlagsKey: "flags",
+		Types: map[string]fieldType{
+			"id":                             fieldString,
+			"user_data128":                   fieldString,
+			"debits_pending":                 fieldNumber,
+			"debits_posted":                  fieldNumber,
+			"credits_pending":                fieldNumber,
+			"credits_posted":                 fieldNumber,
+			"user_data64":                    fieldNumber,
+			"user_data32":                    fieldNumber,
+			"ledger":                         fieldNumber,
+			"code":                           fieldNumber,
+			"timestamp":                      fieldNumber,
+			"idempotency_key":                fieldString,
+			"linked":                         fieldFlag,
+			"debits_must_not_exceed_credits": fieldFlag,
+			"credits_must_not_exceed_debits": fieldFlag,
+			"history":                        fieldFlag,
+			"imported":                       fieldFlag,
+		},
+	},
+	KindTransfers: {
+		FlagsKey: "transfer_flags",
+		Types: map[string]fieldType{
+			"id":                    fieldString,
+			"debit_account_id":      fieldString,
+			"credit_account_id":     fieldString,
+			"pending_id":            fieldString,
+			"user_data128":          fieldString,
+			"amount":                fieldNumber,
+			"user_data64":           fieldNumber,
+			"user_data32":           fieldNumber,
+			"ledger":                fieldNumber,
+			"code":                  fieldNumber,
+			"timestamp":             fieldNumber,
+			"idempotency_key":       fieldString,
+			"linked":                fieldFlag,
+			"pending":               fieldFlag,
+			"post_pending_transfer": fieldFlag,
+			"void_pending_transfer": fieldFlag,
+			"balancing_debit":       fieldFlag,
+			"balancing_credit":      fieldFlag,
+			"imported":              fieldFlag,
+		},
+	},
+}
+
+// rowReader returns each row as the json encoding of a proto.Account or proto.Transfer.
+// A *rowError rejects the current row only, io.EOF ends the input,
+// any other error stops the import.
+type rowReader interface {
+	Next() ([]byte, error)
+}
+
+type rowError struct {
+	err error
+}
+
+func (e *rowError) Error() string { return e.err.Error() }
+func (e *rowError) Unwrap() error { return e.err }
+
+type ndjsonReader struct {
+	s *bufio.Scanner
+}
+
+func newNDJSONReader(r io.Reader) *ndjsonReader {
+	s := bufio.NewScanner(r)
+	s.Buffer(make([]byte, 64*1024), 1024*1024)
+	return &ndjsonReader{s: s}
+}
+
+func (r *ndjsonReader) Next() ([]byte, error) {
+	for r.s.Scan() {
+		line := bytes.TrimSpace(r.s.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+		return bytes.Clone(line), nil
+	}
+	if err := r.s.Err(); err != nil {
+		return nil, err
+	}
+	return nil, io.EOF
+}
+
+type csvReader struct {
+	r      *csv.Reader
+	fields fields
+	header []string
+}
+
+func newCSVReader(r io.Reader, f fields, columns map[string]string) (*csvReader, error) {
+	cr := csv.NewReader(r)
+	cr.ReuseRecord = true
+	cr.FieldsPerRecord = -1
+	header, err := cr.Read()
+	if err != nil {
+		return nil, fmt.Errorf("reading csv header: %w", err)
+	}
+	names := make([]string, len(header))
+	for i, col := range header {
+		col = strings.TrimSpace(col)
+		name := col
+		if mapped, ok := columns[col]; ok {
+			name = mapped
+		}
+		if name == "-" {
+			continue
+		}
+		if _, ok := f.Types[name]; !ok {
+			return nil, fmt.Errorf("unknown csv column %q", col)
+		}
+		names[i] = name
+	}
+	return &csvReader{r: cr, fields: f, header: names}, nil
+}
+
+func (r *csvReader) Next() ([]byte, error) {
+	record, err := r.r.Read()
+	if err != nil {
+		if _, ok := err.(*csv.ParseError); ok {
+			return nil, &rowError{err}
+		}
+		return nil, err
+	}
+
+	obj := map[string]any{}
+	flags := map[string]bool{}
+	for i, value := range record {
+		if i >= len(r.header) || r.header[i] == "" {
+			continue
+		}
+		value = strings.TrimSpace(value)
+		if value == "" {
+			continue
+		}
+		name := r.header[i]
+		switch r.fields.Types[name] {
+		case fieldString:
+			obj[name] = value
+		case fieldNumber:
+			if _, err := strconv.ParseUint(value, 10, 64); err != nil {
+				return nil, &rowError{fmt.Errorf("column %s: %w", name, err)}
+			}
+			obj[name] = json.Number(value)
+		case fieldFlag:
+			b, err := strconv.ParseBool(value)
+			if err != nil {
+				return nil, &rowError{fmt.Errorf("column %s: %w", name, err)}
+			}
+			flags[name] = b
+		}
+	}
+	if len(flags) > 0 {
+		obj[r.fields.FlagsKey] = flags
+	}
+	return json.Marshal(obj)
+}
+
+// ParseColumns parses a csv column mapping in the form "header:field,header:field".
+func ParseColumns(s string) (map[string]string, error) {
+	columns := map[string]string{}
+	if s == "" {
+		return columns, nil
+	}
+	for _, pair := range strings.Split(s, ",") {
+		from, to, ok := strings.Cut(pair, ":")
+		if !ok {
+			return nil, errors.New("invalid column mapping: " + pair)
+		}
+		columns[strings.TrimSpace(from)] = strings.TrimSpace(to)
+	}
+	return columns, nil
+}
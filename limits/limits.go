@@ -0,0 +1,270 @@
+// Package limits caps the amount debited from accounts over a sliding time window.
+//
+//	limits:
+//	  - name: daily
+//	    window: 24h
+//	    amount: 100000
+//	    ledger: 1
+//	  - name: monthly
+//	    window: 720h
+//	    amount: 1000000
+//	    ledger: 1
+//	    code: 10
+//	    accounts: ["1a"]
+//
+// Ledger and code restrict the transfers a limit applies to, 0 matches any.
+// A limit without accounts applies to every debit account.
+// Pending transfers count until they are voided, posted for less or expire, within the window.
+//
+// Callers hold Lock from Check until the transfers are submitted, so requests debiting the same
+// account are checked one after the other. This serializes a single process only, instances
+// sharing debit accounts can each let a transfer through.
+package limits
+
+import (
+	"bytes"
+	"fmt"
+	"math/big"
+	"os"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/lil5/tigerbeetle_api/config"
+	"github.com/lil5/tigerbeetle_api/metrics"
+	tigerbeetle_go "github.com/tigerbeetle/tigerbeetle-go"
+	"github.com/tigerbeetle/tigerbeetle-go/pkg/types"
+	"gopkg.in/yaml.v3"
+)
+
+type Limit struct {
+	Name     string        `yaml:"name"`
+	Window   time.Duration `yaml:"window"`
+	Amount   uint64        `yaml:"amount"`
+	Ledger   uint32        `yaml:"ledger"`
+	Code     uint16        `yaml:"code"`
+	Accounts []string      `yaml:"accounts"`
+
+	accounts []types.Uint128
+}
+
+type Limits struct {
+	Limits []Limit `yaml:"limits"`
+
+	mu    sync.Mutex
+	locks map[types.Uint128]*accountLock
+}
+
+type accountLock struct {
+	sync.Mutex
+	refs int
+}
+
+// Breach is a transfer that would take an account over a limit.
+type Breach struct {
+	Index int
+	Limit string
+}
+
+func Load(path string) (*Limits, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return Parse(b)
+}
+
+func Parse(b []byte) (*Limits, error) {
+	var l Limits
+	if err := yaml.Unmarshal(b, &l); err != nil {
+		return nil, fmt.Errorf("parsing limits: %w", err)
+	}
+	names := map[string]bool{}
+	for i := range l.Limits {
+		limit := &l.Limits[i]
+		if limit.Name == "" {
+			return nil, fmt.Errorf("limits[%d]: name is required", i)
+		}
+		if names[limit.Name] {
+			return nil, fmt.Errorf("limits: duplicate name %q", limit.Name)
+		}
+		names[limit.Name] = true
+		if limit.Window <= 0 {
+			return nil, fmt.Errorf("limits: %s: window must be positive", limit.Name)
+		}
+		for _, account := range limit.Accounts {
+			id, err := types.HexStringToUint128(account)
+			if err != nil {
+				return nil, fmt.Errorf("limits: %s: account %q: %w", limit.Name, account, err)
+			}
+			limit.accounts = append(limit.accounts, id)
+		}
+	}
+	return &l, nil
+}
+
+func (l *Limit) applies(t types.Transfer) bool {
+	return (l.Ledger == 0 || l.Ledger == t.Ledger) &&
+		(l.Code == 0 || l.Code == t.Code) &&
+		(len(l.accounts) == 0 || slices.Contains(l.accounts, t.DebitAccountID))
+}
+
+// Lock locks the debit accounts of the transfers a limit applies to, in order, and returns the function unlocking them.
+func (l *Limits) Lock(transfers []types.Transfer) (unlock func()) {
+	accounts := []types.Uint128{}
+	for _, t := range transfers {
+		flags := t.TransferFlags()
+		if flags.PostPendingTransfer || flags.VoidPendingTransfer {
+			continue
+		}
+		if slices.ContainsFunc(l.Limits, func(limit Limit) bool { return limit.applies(t) }) {
+			accounts = append(accounts, t.DebitAccountID)
+		}
+	}
+	slices.SortFunc(accounts, func(a, b types.Uint128) int {
+		return bytes.Compare(a[:], b[:])
+	})
+	accounts = slices.Compact(accounts)
+
+	locks := make([]*accountLock, len(accounts))
+	l.mu.Lock()
+	if l.locks == nil {
+		l.locks = map[types.Uint128]*accountLock{}
+	}
+	for i, account := range accounts {
+		lock, ok := l.locks[account]
+		if !ok {
+			lock = &accountLock{}
+			l.locks[account] = lock
+		}
+		lock.refs++
+		locks[i] = lock
+	}
+	l.mu.Unlock()
+	for _, lock := range locks {
+		lock.Lock()
+	}
+	return func() {
+		for _, lock := range locks {
+			lock.Unlock()
+		}
+		l.mu.Lock()
+		for i, account := range accounts {
+			if locks[i].refs--; locks[i].refs == 0 {
+				delete(l.locks, account)
+			}
+		}
+		l.mu.Unlock()
+	}
+}
+
+type usageKey struct {
+	limit   int
+	account types.Uint128
+}
+
+// Check returns the transfers that would take their debit account over a limit,
+// counting the transfers debited within the window before now and the earlier transfers of the batch.
+// Post and void transfers are not checked, their pending transfer was.
+func (l *Limits) Check(tb tigerbeetle_go.Client, transfers []types.Transfer, now time.Time) ([]Breach, error) {
+	breaches := []Breach{}
+	used := map[usageKey]*big.Int{}
+	for i, t := range transfers {
+		flags := t.TransferFlags()
+		if flags.PostPendingTransfer || flags.VoidPendingTransfer {
+			continue
+		}
+		amount := t.Amount.BigInt()
+		applied := []usageKey{}
+		var breach *Limit
+		for j := range l.Limits {
+			limit := &l.Limits[j]
+			if !limit.applies(t) {
+				continue
+			}
+			key := usageKey{j, t.DebitAccountID}
+			if _, ok := used[key]; !ok {
+				sum, err := limit.debited(tb, t.DebitAccountID, now)
+				if err != nil {
+					return nil, fmt.Errorf("limits: %s: %w", limit.Name, err)
+				}
+				used[key] = sum
+			}
+			metrics.TotalLimitChecks.WithLabelValues(limit.Name).Inc()
+			total := new(big.Int).Add(used[key], &amount)
+			if total.Cmp(new(big.Int).SetUint64(limit.Amount)) > 0 {
+				breach = limit
+				break
+			}
+			applied = append(applied, key)
+		}
+		if breach != nil {
+			metrics.TotalLimitBreaches.WithLabelValues(breach.Name).Inc()
+			breaches = append(breaches, Breach{Index: i, Limit: breach.Name})
+			continue
+		}
+		for _, key := range applied {
+			used[key].Add(used[key], &amount)
+		}
+	}
+	return breaches, nil
+}
+
+// debited sums the transfers of the limit debited from account within the window before now.
+// A void releases its pending transfer and a post the part of it that was not posted,
+// when the pending transfer is in the window. A pending transfer whose timeout passed before now is released as well.
+func (l *Limit) debited(tb tigerbeetle_go.Client, account types.Uint128, now time.Time) (*big.Int, error) {
+	filter := types.AccountFilter{
+		AccountID:    account,
+		Code:         l.Code,
+		TimestampMin: uint64(now.Add(-l.Window).UnixNano()),
+		Limit:        config.TB_MAX_BATCH_SIZE,
+		Flags:        types.AccountFilterFlags{Debits: true}.ToUint32(),
+	}
+	sum := new(big.Int)
+	pending := map[types.Uint128]types.Transfer{}
+	for {
+		metrics.TotalTbGetAccountTransfersCall.Inc()
+		res, err := tb.GetAccountTransfers(filter)
+		if err != nil {
+			return nil, err
+		}
+		for _, t := range res {
+			if l.Ledger != 0 && t.Ledger != l.Ledger {
+				continue
+			}
+			flags := t.TransferFlags()
+			amount := t.Amount.BigInt()
+			switch {
+			case flags.Pending:
+				pending[t.ID] = t
+				sum.Add(sum, &amount)
+			case flags.VoidPendingTransfer, flags.PostPendingTransfer:
+				p, ok := pending[t.PendingID]
+				if !ok {
+					continue
+				}
+				delete(pending, t.PendingID)
+				reserved := p.Amount.BigInt()
+				if flags.PostPendingTransfer {
+					reserved.Sub(&reserved, &amount)
+				}
+				sum.Sub(sum, &reserved)
+			default:
+				sum.Add(sum, &amount)
+			}
+		}
+		if len(res) < config.TB_MAX_BATCH_SIZE {
+			break
+		}
+		filter.TimestampMin = res[len(res)-1].Timestamp + 1
+	}
+	// An expired pending transfer leaves no transfer behind.
+	for _, p := range pending {
+		if p.Timeout != 0 && p.Timestamp+uint64(p.Timeout)*uint64(time.Second) <= uint64(now.UnixNano()) {
+			reserved := p.Amount.BigInt()
+			sum.Sub(sum, &reserved)
+		}
+	}
+	return sum, nil
+}
@@ -0,0 +1,133 @@
+package limits
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	tigerbeetle_go "github.com/tigerbeetle/tigerbeetle-go"
+	"github.com/tigerbeetle/tigerbeetle-go/pkg/types"
+)
+
+type fakeClient struct {
+	tigerbeetle_go.Client
+	transfers []types.Transfer
+}
+
+func (c *fakeClient) GetAccountTransfers(filter types.AccountFilter) ([]types.Transfer, error) {
+	res := []types.Transfer{}
+	for _, t := range c.transfers {
+		if t.DebitAccountID != filter.AccountID || t.Timestamp < filter.TimestampMin {
+			continue
+		}
+		if filter.Code != 0 && t.Code != filter.Code {
+			continue
+		}
+		res = append(res, t)
+	}
+	return res, nil
+}
+
+func TestCheck(t *testing.T) {
+	now := time.Date(2025, 1, 2, 12, 0, 0, 0, time.UTC)
+	at := func(d time.Duration) uint64 { return uint64(now.Add(-d).UnixNano()) }
+	a, b := types.ToUint128(0xa), types.ToUint128(0xb)
+	client := &fakeClient{transfers: []types.Transfer{
+		{ID: types.ToUint128(1), DebitAccountID: a, Amount: types.ToUint128(300), Ledger: 1, Timestamp: at(2 * time.Hour)},
+		// Outside the window
+		{ID: types.ToUint128(2), DebitAccountID: a, Amount: types.ToUint128(900), Ledger: 1, Timestamp: at(25 * time.Hour)},
+		// Other ledger
+		{ID: types.ToUint128(3), DebitAccountID: a, Amount: types.ToUint128(900), Ledger: 2, Timestamp: at(time.Hour)},
+		// Voided pending transfer
+		{ID: types.ToUint128(4), DebitAccountID: a, Amount: types.ToUint128(500), Ledger: 1, Timestamp: at(time.Hour), Flags: types.TransferFlags{Pending: true}.ToUint16()},
+		{ID: types.ToUint128(5), DebitAccountID: a, PendingID: types.ToUint128(4), Amount: types.ToUint128(500), Ledger: 1, Timestamp: at(time.Minute), Flags: types.TransferFlags{VoidPendingTransfer: true}.ToUint16()},
+		// Pending transfer posted for less
+		{ID: types.ToUint128(6), DebitAccountID: a, Amount: types.ToUint128(200), Ledger: 1, Timestamp: at(time.Hour), Flags: types.TransferFlags{Pending: true}.ToUint16()},
+		{ID: types.ToUint128(7), DebitAccountID: a, PendingID: types.ToUint128(6), Amount: types.ToUint128(150), Ledger: 1, Timestamp: at(time.Minute), Flags: types.TransferFlags{PostPendingTransfer: true}.ToUint16()},
+	}}
+	l, err := Parse([]byte(`
+limits:
+  - name: daily
+    window: 24h
+    amount: 1000
+    ledger: 1
+  - name: vip
+    window: 1h
+    amount: 10
+    accounts: ["b"]
+`))
+	require.NoError(t, err)
+
+	// a has 450 debited within the day
+	breaches, err := l.Check(client, []types.Transfer{
+		{DebitAccountID: a, Amount: types.ToUint128(500), Ledger: 1},
+		{DebitAccountID: a, Amount: types.ToUint128(100), Ledger: 1},
+		{DebitAccountID: a, Amount: types.ToUint128(50), Ledger: 1},
+		{DebitAccountID: a, Amount: types.ToUint128(5000), Ledger: 2},
+		{DebitAccountID: b, Amount: types.ToUint128(11), Ledger: 2},
+		{DebitAccountID: a, PendingID: types.ToUint128(6), Ledger: 1, Flags: types.TransferFlags{PostPendingTransfer: true}.ToUint16()},
+	}, now)
+	require.NoError(t, err)
+	assert.Equal(t, []Breach{{Index: 1, Limit: "daily"}, {Index: 4, Limit: "vip"}}, breaches)
+}
+
+func TestCheckExpiredPending(t *testing.T) {
+	now := time.Date(2025, 1, 2, 12, 0, 0, 0, time.UTC)
+	at := func(d time.Duration) uint64 { return uint64(now.Add(-d).UnixNano()) }
+	a := types.ToUint128(0xa)
+	pending := types.TransferFlags{Pending: true}.ToUint16()
+	client := &fakeClient{transfers: []types.Transfer{
+		// Expired an hour ago
+		{ID: types.ToUint128(1), DebitAccountID: a, Amount: types.ToUint128(600), Timeout: 60, Timestamp: at(time.Hour + time.Minute), Flags: pending},
+		// Expires in an hour
+		{ID: types.ToUint128(2), DebitAccountID: a, Amount: types.ToUint128(300), Timeout: 7200, Timestamp: at(time.Hour), Flags: pending},
+	}}
+	l, err := Parse([]byte("limits:\n  - {name: daily, window: 24h, amount: 1000}\n"))
+	require.NoError(t, err)
+
+	breaches, err := l.Check(client, []types.Transfer{
+		{DebitAccountID: a, Amount: types.ToUint128(700)},
+		{DebitAccountID: a, Amount: types.ToUint128(1)},
+	}, now)
+	require.NoError(t, err)
+	assert.Equal(t, []Breach{{Index: 1, Limit: "daily"}}, breaches)
+}
+
+func TestParseInvalid(t *testing.T) {
+	_, err := Parse([]byte("limits:\n  - name: a\n    amount: 1\n"))
+	assert.ErrorContains(t, err, "window")
+	_, err = Parse([]byte("limits:\n  - {name: a, window: 1h}\n  - {name: a, window: 1h}\n"))
+	assert.ErrorContains(t, err, "duplicate")
+	_, err = Parse([]byte("limits:\n  - {name: a, window: 1h, accounts: [x]}\n"))
+	assert.ErrorContains(t, err, "account")
+}
+
+func TestLoadExample(t *testing.T) {
+	_, err := Load("../limits.example.yaml")
+	assert.NoError(t, err)
+}
+
+func TestLock(t *testing.T) {
+	l, err := Parse([]byte("limits:\n  - {name: daily, window: 24h, amount: 100}\n"))
+	require.NoError(t, err)
+	a, b := types.ToUint128(0xa), types.ToUint128(0xb)
+
+	unlock := l.Lock([]types.Transfer{{DebitAccountID: b}, {DebitAccountID: a}, {DebitAccountID: b}})
+	// Other accounts are not held.
+	l.Lock([]types.Transfer{{DebitAccountID: types.ToUint128(0xc)}})()
+
+	locked := make(chan struct{})
+	go func() {
+		l.Lock([]types.Transfer{{DebitAccountID: a}})()
+		close(locked)
+	}()
+	select {
+	case <-locked:
+		t.Fatal("account locked twice")
+	case <-time.After(50 * time.Millisecond):
+	}
+	unlock()
+	<-locked
+	assert.Empty(t, l.locks)
+}
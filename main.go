@@ -4,6 +4,7 @@ import (
 	"os"
 
 	"github.com/joho/godotenv"
+	"github.com/lil5/tigerbeetle_api/cli"
 	"github.com/lil5/tigerbeetle_api/config"
 	"github.com/lil5/tigerbeetle_api/grpc"
 	"github.com/lil5/tigerbeetle_api/rest"
@@ -14,6 +15,9 @@ func main() {
 	if ok := config.NewConfig(); !ok {
 		os.Exit(1)
 	}
+	if len(os.Args) > 1 {
+		os.Exit(cli.Run(os.Args[1], os.Args[2:]))
+	}
 	// log add version
 	if config.Config.UseGrpc {
 		grpc.NewServer()
@@ -0,0 +1,119 @@
+package metrics
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/lil5/tigerbeetle_api/proto"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	tigerbeetle_go "github.com/tigerbeetle/tigerbeetle-go"
+	"github.com/tigerbeetle/tigerbeetle-go/pkg/types"
+)
+
+var (
+	TbCallDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "tigerbeetleapi_tb_call_duration_seconds",
+		Help:    "Latency of each tigerbeetle client call",
+		Buckets: prometheus.ExponentialBuckets(0.0005, 2, 14),
+	}, []string{"operation"})
+
+	TbBatchSize = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "tigerbeetleapi_tb_batch_size",
+		Help:    "Events sent in each tigerbeetle client create or lookup call",
+		Buckets: prometheus.ExponentialBuckets(1, 2, 14),
+	}, []string{"operation"})
+
+	BufferWait = promauto.NewHistogram(prometheus.HistogramOpts{
+		Name:    "tigerbeetleapi_buffer_wait_seconds",
+		Help:    "Time the transfers of a request wait in the buffer until it is flushed",
+		Buckets: prometheus.ExponentialBuckets(0.0005, 2, 14),
+	})
+
+	TotalCreateTransferResults = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "tigerbeetleapi_create_transfer_results_total",
+		Help: "Counter for each transfer of a create request by the result replied and ledger",
+	}, []string{"result", "ledger"})
+
+	TotalCreateAccountResults = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "tigerbeetleapi_create_account_results_total",
+		Help: "Counter for each account sent to tigerbeetle by result and ledger",
+	}, []string{"result", "ledger"})
+)
+
+// Instrument returns tb with the latency and batch size of each call observed,
+// and the results of created accounts counted.
+// Transfer results are counted per request with CountTransferResults, a request may send helper transfers.
+func Instrument(tb tigerbeetle_go.Client) tigerbeetle_go.Client {
+	return &client{Client: tb}
+}
+
+type client struct {
+	tigerbeetle_go.Client
+}
+
+// observe times f as operation, count is the batch size, negative for calls without one.
+func observe[T any](operation string, count int, f func() ([]T, error)) ([]T, error) {
+	if count >= 0 {
+		TbBatchSize.WithLabelValues(operation).Observe(float64(count))
+	}
+	start := time.Now()
+	res, err := f()
+	TbCallDuration.WithLabelValues(operation).Observe(time.Since(start).Seconds())
+	return res, err
+}
+
+func (c *client) CreateAccounts(accounts []types.Account) ([]types.AccountEventResult, error) {
+	results, err := observe("create_accounts", len(accounts), func() ([]types.AccountEventResult, error) { return c.Client.CreateAccounts(accounts) })
+	if err != nil {
+		return results, err
+	}
+	failed := make(map[uint32]types.CreateAccountResult, len(results))
+	for _, r := range results {
+		failed[r.Index] = r.Result
+	}
+	for i, a := range accounts {
+		TotalCreateAccountResults.WithLabelValues(proto.CreateAccountResult(failed[uint32(i)]).String(), strconv.FormatUint(uint64(a.Ledger), 10)).Inc()
+	}
+	return results, nil
+}
+
+func (c *client) CreateTransfers(transfers []types.Transfer) ([]types.TransferEventResult, error) {
+	return observe("create_transfers", len(transfers), func() ([]types.TransferEventResult, error) { return c.Client.CreateTransfers(transfers) })
+}
+
+// CountTransferResults counts the result of each transfer of a request,
+// results holds the failed transfers by index as they are replied.
+func CountTransferResults(transfers []types.Transfer, results []types.TransferEventResult) {
+	failed := make(map[uint32]types.CreateTransferResult, len(results))
+	for _, r := range results {
+		failed[r.Index] = r.Result
+	}
+	for i, t := range transfers {
+		TotalCreateTransferResults.WithLabelValues(proto.CreateTransferResult(failed[uint32(i)]).String(), strconv.FormatUint(uint64(t.Ledger), 10)).Inc()
+	}
+}
+
+func (c *client) LookupAccounts(ids []types.Uint128) ([]types.Account, error) {
+	return observe("lookup_accounts", len(ids), func() ([]types.Account, error) { return c.Client.LookupAccounts(ids) })
+}
+
+func (c *client) LookupTransfers(ids []types.Uint128) ([]types.Transfer, error) {
+	return observe("lookup_transfers", len(ids), func() ([]types.Transfer, error) { return c.Client.LookupTransfers(ids) })
+}
+
+func (c *client) GetAccountTransfers(filter types.AccountFilter) ([]types.Transfer, error) {
+	return observe("get_account_transfers", -1, func() ([]types.Transfer, error) { return c.Client.GetAccountTransfers(filter) })
+}
+
+func (c *client) GetAccountBalances(filter types.AccountFilter) ([]types.AccountBalance, error) {
+	return observe("get_account_balances", -1, func() ([]types.AccountBalance, error) { return c.Client.GetAccountBalances(filter) })
+}
+
+func (c *client) QueryAccounts(filter types.QueryFilter) ([]types.Account, error) {
+	return observe("query_accounts", -1, func() ([]types.Account, error) { return c.Client.QueryAccounts(filter) })
+}
+
+func (c *client) QueryTransfers(filter types.QueryFilter) ([]types.Transfer, error) {
+	return observe("query_transfers", -1, func() ([]types.Transfer, error) { return c.Client.QueryTransfers(filter) })
+}
@@ -0,0 +1,35 @@
+package metrics
+
+import (
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	tigerbeetle_go "github.com/tigerbeetle/tigerbeetle-go"
+	"github.com/tigerbeetle/tigerbeetle-go/pkg/types"
+)
+
+type fakeClient struct {
+	tigerbeetle_go.Client
+}
+
+func (fakeClient) CreateTransfers(transfers []types.Transfer) ([]types.TransferEventResult, error) {
+	return []types.TransferEventResult{{Index: 1, Result: types.TransferExceedsCredits}}, nil
+}
+
+func TestInstrument(t *testing.T) {
+	tb := Instrument(fakeClient{})
+	transfers := []types.Transfer{{Ledger: 700}, {Ledger: 700}, {Ledger: 701}}
+	results, err := tb.CreateTransfers(transfers)
+	require.NoError(t, err)
+	// Results are counted per request, not per call
+	assert.Equal(t, 0, testutil.CollectAndCount(TotalCreateTransferResults))
+	CountTransferResults(transfers, results)
+
+	assert.Equal(t, 1.0, testutil.ToFloat64(TotalCreateTransferResults.WithLabelValues("TransferExceedsCredits", "700")))
+	assert.Equal(t, 1.0, testutil.ToFloat64(TotalCreateTransferResults.WithLabelValues("TransferOK", "700")))
+	assert.Equal(t, 1.0, testutil.ToFloat64(TotalCreateTransferResults.WithLabelValues("TransferOK", "701")))
+	assert.Equal(t, 1, testutil.CollectAndCount(TbCallDuration, "tigerbeetleapi_tb_call_duration_seconds"))
+	assert.Equal(t, 1, testutil.CollectAndCount(TbBatchSize, "tigerbeetleapi_tb_batch_size"))
+}
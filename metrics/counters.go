@@ -24,6 +24,23 @@ var (
 		Help: "Counter for each time the buffer is flushed",
 	})
 
+	BufferPendingPayloads = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "tigerbeetleapi_buffer_pending_payloads",
+		Help: "Requests waiting in each buffer shard to be flushed",
+	}, []string{"shard"})
+	BufferPendingTransfers = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "tigerbeetleapi_buffer_pending_transfers",
+		Help: "Transfers waiting in each buffer shard to be flushed",
+	}, []string{"shard"})
+	TotalBufferFlushes = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "tigerbeetleapi_buffer_flushes_total",
+		Help: "Counter for each flush of a buffer shard, by a full buffer (size) or BUFFER_DELAY (timer)",
+	}, []string{"shard", "reason"})
+	BufferFillRatio = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "tigerbeetleapi_buffer_fill_ratio",
+		Help: "Transfers of the last batch sent by each buffer shard relative to the maximum batch size",
+	}, []string{"shard"})
+
 	TotalCreateTransferTx = promauto.NewCounter(prometheus.CounterOpts{
 		Name: "tigerbeetleapi_create_transfers_tx_total",
 		Help: "Counter for each tranfer created",
@@ -78,4 +95,24 @@ var (
 		Name: "tigerbeetleapi_tb_query_accounts_total",
 		Help: "Called when tigerbeetle client query_accounts is run",
 	})
+
+	TotalLimitChecks = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "tigerbeetleapi_limit_checks_total",
+		Help: "Counter for each transfer checked against a spending limit",
+	}, []string{"limit"})
+
+	TotalLimitBreaches = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "tigerbeetleapi_limit_breaches_total",
+		Help: "Counter for each transfer rejected by a spending limit",
+	}, []string{"limit"})
+
+	TotalQuotaRejections = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "tigerbeetleapi_quota_rejections_total",
+		Help: "Counter for each request rejected by a rate limit or in flight quota",
+	}, []string{"operation", "reason"})
+
+	TotalAuditErrors = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "tigerbeetleapi_audit_errors_total",
+		Help: "Counter for each write request that could not be written to the audit log",
+	})
 )
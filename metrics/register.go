@@ -2,6 +2,7 @@ package metrics
 
 import (
 	"context"
+	"crypto/tls"
 	"log/slog"
 	"net/http"
 
@@ -9,14 +10,19 @@ import (
 	"github.com/prometheus/client_golang/prometheus/promhttp"
 )
 
-func Register(addr string) func() {
+// Register serves /metrics on addr, over TLS when tlsConfig is set.
+func Register(addr string, tlsConfig *tls.Config) func() {
 	h := promhttp.HandlerFor(prometheus.DefaultGatherer, promhttp.HandlerOpts{
 		EnableOpenMetrics: true,
 	})
 	mux := http.NewServeMux()
 	mux.Handle("/metrics", h)
-	server := &http.Server{Addr: addr, Handler: mux}
-	slog.Info("Prometheus server listening at", "address", addr, "path", "/metrics")
-	go server.ListenAndServe()
+	server := &http.Server{Addr: addr, Handler: mux, TLSConfig: tlsConfig}
+	slog.Info("Prometheus server listening at", "address", addr, "path", "/metrics", "tls", tlsConfig != nil)
+	if tlsConfig != nil {
+		go server.ListenAndServeTLS("", "")
+	} else {
+		go server.ListenAndServe()
+	}
 	return func() { server.Shutdown(context.TODO()) }
 }
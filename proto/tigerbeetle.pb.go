@@ -21,6 +21,159 @@ const (
 	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
 )
 
+type AggregateBucket int32
+
+const (
+	AggregateBucket_AggregateBucketNone AggregateBucket = 0
+	AggregateBucket_AggregateBucketHour AggregateBucket = 1
+	AggregateBucket_AggregateBucketDay  AggregateBucket = 2
+)
+
+// Enum value maps for AggregateBucket.
+var (
+	AggregateBucket_name = map[int32]string{
+		0: "AggregateBucketNone",
+		1: "AggregateBucketHour",
+		2: "AggregateBucketDay",
+	}
+	AggregateBucket_value = map[string]int32{
+		"AggregateBucketNone": 0,
+		"AggregateBucketHour": 1,
+		"AggregateBucketDay":  2,
+	}
+)
+
+func (x AggregateBucket) Enum() *AggregateBucket {
+	p := new(AggregateBucket)
+	*p = x
+	return p
+}
+
+func (x AggregateBucket) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (AggregateBucket) Descriptor() protoreflect.EnumDescriptor {
+	return file_proto_tigerbeetle_proto_enumTypes[0].Descriptor()
+}
+
+func (AggregateBucket) Type() protoreflect.EnumType {
+	return &file_proto_tigerbeetle_proto_enumTypes[0]
+}
+
+func (x AggregateBucket) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use AggregateBucket.Descriptor instead.
+func (AggregateBucket) EnumDescriptor() ([]byte, []int) {
+	return file_proto_tigerbeetle_proto_rawDescGZIP(), []int{0}
+}
+
+type GroupBy int32
+
+const (
+	GroupBy_GroupByCode        GroupBy = 0
+	GroupBy_GroupByLedger      GroupBy = 1
+	GroupBy_GroupByUserData128 GroupBy = 2
+	GroupBy_GroupByUserData64  GroupBy = 3
+	GroupBy_GroupByUserData32  GroupBy = 4
+)
+
+// Enum value maps for GroupBy.
+var (
+	GroupBy_name = map[int32]string{
+		0: "GroupByCode",
+		1: "GroupByLedger",
+		2: "GroupByUserData128",
+		3: "GroupByUserData64",
+		4: "GroupByUserData32",
+	}
+	GroupBy_value = map[string]int32{
+		"GroupByCode":        0,
+		"GroupByLedger":      1,
+		"GroupByUserData128": 2,
+		"GroupByUserData64":  3,
+		"GroupByUserData32":  4,
+	}
+)
+
+func (x GroupBy) Enum() *GroupBy {
+	p := new(GroupBy)
+	*p = x
+	return p
+}
+
+func (x GroupBy) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (GroupBy) Descriptor() protoreflect.EnumDescriptor {
+	return file_proto_tigerbeetle_proto_enumTypes[1].Descriptor()
+}
+
+func (GroupBy) Type() protoreflect.EnumType {
+	return &file_proto_tigerbeetle_proto_enumTypes[1]
+}
+
+func (x GroupBy) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use GroupBy.Descriptor instead.
+func (GroupBy) EnumDescriptor() ([]byte, []int) {
+	return file_proto_tigerbeetle_proto_rawDescGZIP(), []int{1}
+}
+
+type BalanceSource int32
+
+const (
+	BalanceSource_BalanceSourceCurrent   BalanceSource = 0
+	BalanceSource_BalanceSourceHistory   BalanceSource = 1
+	BalanceSource_BalanceSourceTransfers BalanceSource = 2
+)
+
+// Enum value maps for BalanceSource.
+var (
+	BalanceSource_name = map[int32]string{
+		0: "BalanceSourceCurrent",
+		1: "BalanceSourceHistory",
+		2: "BalanceSourceTransfers",
+	}
+	BalanceSource_value = map[string]int32{
+		"BalanceSourceCurrent":   0,
+		"BalanceSourceHistory":   1,
+		"BalanceSourceTransfers": 2,
+	}
+)
+
+func (x BalanceSource) Enum() *BalanceSource {
+	p := new(BalanceSource)
+	*p = x
+	return p
+}
+
+func (x BalanceSource) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (BalanceSource) Descriptor() protoreflect.EnumDescriptor {
+	return file_proto_tigerbeetle_proto_enumTypes[2].Descriptor()
+}
+
+func (BalanceSource) Type() protoreflect.EnumType {
+	return &file_proto_tigerbeetle_proto_enumTypes[2]
+}
+
+func (x BalanceSource) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use BalanceSource.Descriptor instead.
+func (BalanceSource) EnumDescriptor() ([]byte, []int) {
+	return file_proto_tigerbeetle_proto_rawDescGZIP(), []int{2}
+}
+
 type CreateAccountResult int32
 
 const (
@@ -126,11 +279,11 @@ func (x CreateAccountResult) String() string {
 }
 
 func (CreateAccountResult) Descriptor() protoreflect.EnumDescriptor {
-	return file_proto_tigerbeetle_proto_enumTypes[0].Descriptor()
+	return file_proto_tigerbeetle_proto_enumTypes[3].Descriptor()
 }
 
 func (CreateAccountResult) Type() protoreflect.EnumType {
-	return &file_proto_tigerbeetle_proto_enumTypes[0]
+	return &file_proto_tigerbeetle_proto_enumTypes[3]
 }
 
 func (x CreateAccountResult) Number() protoreflect.EnumNumber {
@@ -139,7 +292,7 @@ func (x CreateAccountResult) Number() protoreflect.EnumNumber {
 
 // Deprecated: Use CreateAccountResult.Descriptor instead.
 func (CreateAccountResult) EnumDescriptor() ([]byte, []int) {
-	return file_proto_tigerbeetle_proto_rawDescGZIP(), []int{0}
+	return file_proto_tigerbeetle_proto_rawDescGZIP(), []int{3}
 }
 
 type CreateTransferResult int32
@@ -214,80 +367,87 @@ const (
 	CreateTransferResult_TransferOverflowsTimeout                                CreateTransferResult = 53
 	CreateTransferResult_TransferExceedsCredits                                  CreateTransferResult = 54
 	CreateTransferResult_TransferExceedsDebits                                   CreateTransferResult = 55
+	// Returned by this api for the conditions of a transfer
+	CreateTransferResult_TransferMinBalanceNotMet      CreateTransferResult = 1000
+	CreateTransferResult_TransferMaxBalanceExceeded    CreateTransferResult = 1001
+	CreateTransferResult_TransferSpendingLimitExceeded CreateTransferResult = 1002
 )
 
 // Enum value maps for CreateTransferResult.
 var (
 	CreateTransferResult_name = map[int32]string{
-		0:  "TransferOK",
-		1:  "TransferLinkedEventFailed",
-		2:  "TransferLinkedEventChainOpen",
-		56: "TransferImportedEventExpected",
-		57: "TransferImportedEventNotExpected",
-		3:  "TransferTimestampMustBeZero",
-		58: "TransferImportedEventTimestampOutOfRange",
-		59: "TransferImportedEventTimestampMustNotAdvance",
-		4:  "TransferReservedFlag",
-		5:  "TransferIDMustNotBeZero",
-		6:  "TransferIDMustNotBeIntMax",
-		36: "TransferExistsWithDifferentFlags",
-		40: "TransferExistsWithDifferentPendingID",
-		44: "TransferExistsWithDifferentTimeout",
-		37: "TransferExistsWithDifferentDebitAccountID",
-		38: "TransferExistsWithDifferentCreditAccountID",
-		39: "TransferExistsWithDifferentAmount",
-		41: "TransferExistsWithDifferentUserData128",
-		42: "TransferExistsWithDifferentUserData64",
-		43: "TransferExistsWithDifferentUserData32",
-		67: "TransferExistsWithDifferentLedger",
-		45: "TransferExistsWithDifferentCode",
-		46: "TransferExists",
-		68: "TransferIDAlreadyFailed",
-		7:  "TransferFlagsAreMutuallyExclusive",
-		8:  "TransferDebitAccountIDMustNotBeZero",
-		9:  "TransferDebitAccountIDMustNotBeIntMax",
-		10: "TransferCreditAccountIDMustNotBeZero",
-		11: "TransferCreditAccountIDMustNotBeIntMax",
-		12: "TransferAccountsMustBeDifferent",
-		13: "TransferPendingIDMustBeZero",
-		14: "TransferPendingIDMustNotBeZero",
-		15: "TransferPendingIDMustNotBeIntMax",
-		16: "TransferPendingIDMustBeDifferent",
-		17: "TransferTimeoutReservedForPendingTransfer",
-		64: "TransferClosingTransferMustBePending",
-		18: "TransferAmountMustNotBeZero",
-		19: "TransferLedgerMustNotBeZero",
-		20: "TransferCodeMustNotBeZero",
-		21: "TransferDebitAccountNotFound",
-		22: "TransferCreditAccountNotFound",
-		23: "TransferAccountsMustHaveTheSameLedger",
-		24: "TransferTransferMustHaveTheSameLedgerAsAccounts",
-		25: "TransferPendingTransferNotFound",
-		26: "TransferPendingTransferNotPending",
-		27: "TransferPendingTransferHasDifferentDebitAccountID",
-		28: "TransferPendingTransferHasDifferentCreditAccountID",
-		29: "TransferPendingTransferHasDifferentLedger",
-		30: "TransferPendingTransferHasDifferentCode",
-		31: "TransferExceedsPendingTransferAmount",
-		32: "TransferPendingTransferHasDifferentAmount",
-		33: "TransferPendingTransferAlreadyPosted",
-		34: "TransferPendingTransferAlreadyVoided",
-		35: "TransferPendingTransferExpired",
-		60: "TransferImportedEventTimestampMustNotRegress",
-		61: "TransferImportedEventTimestampMustPostdateDebitAccount",
-		62: "TransferImportedEventTimestampMustPostdateCreditAccount",
-		63: "TransferImportedEventTimeoutMustBeZero",
-		65: "TransferDebitAccountAlreadyClosed",
-		66: "TransferCreditAccountAlreadyClosed",
-		47: "TransferOverflowsDebitsPending",
-		48: "TransferOverflowsCreditsPending",
-		49: "TransferOverflowsDebitsPosted",
-		50: "TransferOverflowsCreditsPosted",
-		51: "TransferOverflowsDebits",
-		52: "TransferOverflowsCredits",
-		53: "TransferOverflowsTimeout",
-		54: "TransferExceedsCredits",
-		55: "TransferExceedsDebits",
+		0:    "TransferOK",
+		1:    "TransferLinkedEventFailed",
+		2:    "TransferLinkedEventChainOpen",
+		56:   "TransferImportedEventExpected",
+		57:   "TransferImportedEventNotExpected",
+		3:    "TransferTimestampMustBeZero",
+		58:   "TransferImportedEventTimestampOutOfRange",
+		59:   "TransferImportedEventTimestampMustNotAdvance",
+		4:    "TransferReservedFlag",
+		5:    "TransferIDMustNotBeZero",
+		6:    "TransferIDMustNotBeIntMax",
+		36:   "TransferExistsWithDifferentFlags",
+		40:   "TransferExistsWithDifferentPendingID",
+		44:   "TransferExistsWithDifferentTimeout",
+		37:   "TransferExistsWithDifferentDebitAccountID",
+		38:   "TransferExistsWithDifferentCreditAccountID",
+		39:   "TransferExistsWithDifferentAmount",
+		41:   "TransferExistsWithDifferentUserData128",
+		42:   "TransferExistsWithDifferentUserData64",
+		43:   "TransferExistsWithDifferentUserData32",
+		67:   "TransferExistsWithDifferentLedger",
+		45:   "TransferExistsWithDifferentCode",
+		46:   "TransferExists",
+		68:   "TransferIDAlreadyFailed",
+		7:    "TransferFlagsAreMutuallyExclusive",
+		8:    "TransferDebitAccountIDMustNotBeZero",
+		9:    "TransferDebitAccountIDMustNotBeIntMax",
+		10:   "TransferCreditAccountIDMustNotBeZero",
+		11:   "TransferCreditAccountIDMustNotBeIntMax",
+		12:   "TransferAccountsMustBeDifferent",
+		13:   "TransferPendingIDMustBeZero",
+		14:   "TransferPendingIDMustNotBeZero",
+		15:   "TransferPendingIDMustNotBeIntMax",
+		16:   "TransferPendingIDMustBeDifferent",
+		17:   "TransferTimeoutReservedForPendingTransfer",
+		64:   "TransferClosingTransferMustBePending",
+		18:   "TransferAmountMustNotBeZero",
+		19:   "TransferLedgerMustNotBeZero",
+		20:   "TransferCodeMustNotBeZero",
+		21:   "TransferDebitAccountNotFound",
+		22:   "TransferCreditAccountNotFound",
+		23:   "TransferAccountsMustHaveTheSameLedger",
+		24:   "TransferTransferMustHaveTheSameLedgerAsAccounts",
+		25:   "TransferPendingTransferNotFound",
+		26:   "TransferPendingTransferNotPending",
+		27:   "TransferPendingTransferHasDifferentDebitAccountID",
+		28:   "TransferPendingTransferHasDifferentCreditAccountID",
+		29:   "TransferPendingTransferHasDifferentLedger",
+		30:   "TransferPendingTransferHasDifferentCode",
+		31:   "TransferExceedsPendingTransferAmount",
+		32:   "TransferPendingTransferHasDifferentAmount",
+		33:   "TransferPendingTransferAlreadyPosted",
+		34:   "TransferPendingTransferAlreadyVoided",
+		35:   "TransferPendingTransferExpired",
+		60:   "TransferImportedEventTimestampMustNotRegress",
+		61:   "TransferImportedEventTimestampMustPostdateDebitAccount",
+		62:   "TransferImportedEventTimestampMustPostdateCreditAccount",
+		63:   "TransferImportedEventTimeoutMustBeZero",
+		65:   "TransferDebitAccountAlreadyClosed",
+		66:   "TransferCreditAccountAlreadyClosed",
+		47:   "TransferOverflowsDebitsPending",
+		48:   "TransferOverflowsCreditsPending",
+		49:   "TransferOverflowsDebitsPosted",
+		50:   "TransferOverflowsCreditsPosted",
+		51:   "TransferOverflowsDebits",
+		52:   "TransferOverflowsCredits",
+		53:   "TransferOverflowsTimeout",
+		54:   "TransferExceedsCredits",
+		55:   "TransferExceedsDebits",
+		1000: "TransferMinBalanceNotMet",
+		1001: "TransferMaxBalanceExceeded",
+		1002: "TransferSpendingLimitExceeded",
 	}
 	CreateTransferResult_value = map[string]int32{
 		"TransferOK":                                              0,
@@ -359,6 +519,9 @@ var (
 		"TransferOverflowsTimeout":                                53,
 		"TransferExceedsCredits":                                  54,
 		"TransferExceedsDebits":                                   55,
+		"TransferMinBalanceNotMet":                                1000,
+		"TransferMaxBalanceExceeded":                              1001,
+		"TransferSpendingLimitExceeded":                           1002,
 	}
 )
 
@@ -373,11 +536,11 @@ func (x CreateTransferResult) String() string {
 }
 
 func (CreateTransferResult) Descriptor() protoreflect.EnumDescriptor {
-	return file_proto_tigerbeetle_proto_enumTypes[1].Descriptor()
+	return file_proto_tigerbeetle_proto_enumTypes[4].Descriptor()
 }
 
 func (CreateTransferResult) Type() protoreflect.EnumType {
-	return &file_proto_tigerbeetle_proto_enumTypes[1]
+	return &file_proto_tigerbeetle_proto_enumTypes[4]
 }
 
 func (x CreateTransferResult) Number() protoreflect.EnumNumber {
@@ -386,13 +549,15 @@ func (x CreateTransferResult) Number() protoreflect.EnumNumber {
 
 // Deprecated: Use CreateTransferResult.Descriptor instead.
 func (CreateTransferResult) EnumDescriptor() ([]byte, []int) {
-	return file_proto_tigerbeetle_proto_rawDescGZIP(), []int{1}
+	return file_proto_tigerbeetle_proto_rawDescGZIP(), []int{4}
 }
 
 type GetIDRequest struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Returns the id derived from this idempotency key instead of a new id
+	IdempotencyKey *string `protobuf:"bytes,1,opt,name=idempotency_key,json=idempotencyKey,proto3,oneof" json:"idempotency_key,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
 }
 
 func (x *GetIDRequest) Reset() {
@@ -425,6 +590,13 @@ func (*GetIDRequest) Descriptor() ([]byte, []int) {
 	return file_proto_tigerbeetle_proto_rawDescGZIP(), []int{0}
 }
 
+func (x *GetIDRequest) GetIdempotencyKey() string {
+	if x != nil && x.IdempotencyKey != nil {
+		return *x.IdempotencyKey
+	}
+	return ""
+}
+
 type GetIDReply struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
@@ -1285,40 +1457,28 @@ func (x *QueryAccountsReply) GetAccounts() []*Account {
 	return nil
 }
 
-// Types
-// ----------------------------------------------------------------
-type Account struct {
-	state          protoimpl.MessageState `protogen:"open.v1"`
-	Id             string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
-	DebitsPending  uint64                 `protobuf:"varint,2,opt,name=debits_pending,json=debitsPending,proto3" json:"debits_pending,omitempty"`
-	DebitsPosted   uint64                 `protobuf:"varint,3,opt,name=debits_posted,json=debitsPosted,proto3" json:"debits_posted,omitempty"`
-	CreditsPending uint64                 `protobuf:"varint,4,opt,name=credits_pending,json=creditsPending,proto3" json:"credits_pending,omitempty"`
-	CreditsPosted  uint64                 `protobuf:"varint,5,opt,name=credits_posted,json=creditsPosted,proto3" json:"credits_posted,omitempty"`
-	UserData128    string                 `protobuf:"bytes,6,opt,name=user_data128,json=userData128,proto3" json:"user_data128,omitempty"`
-	UserData64     uint64                 `protobuf:"varint,7,opt,name=user_data64,json=userData64,proto3" json:"user_data64,omitempty"`
-	UserData32     uint32                 `protobuf:"varint,8,opt,name=user_data32,json=userData32,proto3" json:"user_data32,omitempty"`
-	Ledger         uint32                 `protobuf:"varint,9,opt,name=ledger,proto3" json:"ledger,omitempty"`
-	Code           uint32                 `protobuf:"varint,10,opt,name=code,proto3" json:"code,omitempty"`
-	Flags          *AccountFlags          `protobuf:"bytes,11,opt,name=flags,proto3" json:"flags,omitempty"`
-	Timestamp      uint64                 `protobuf:"varint,12,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
-	unknownFields  protoimpl.UnknownFields
-	sizeCache      protoimpl.SizeCache
+type GetBalancesAtRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	AccountIds    []string               `protobuf:"bytes,1,rep,name=account_ids,json=accountIds,proto3" json:"account_ids,omitempty"`
+	Timestamp     uint64                 `protobuf:"varint,2,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
 }
 
-func (x *Account) Reset() {
-	*x = Account{}
+func (x *GetBalancesAtRequest) Reset() {
+	*x = GetBalancesAtRequest{}
 	mi := &file_proto_tigerbeetle_proto_msgTypes[20]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *Account) String() string {
+func (x *GetBalancesAtRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*Account) ProtoMessage() {}
+func (*GetBalancesAtRequest) ProtoMessage() {}
 
-func (x *Account) ProtoReflect() protoreflect.Message {
+func (x *GetBalancesAtRequest) ProtoReflect() protoreflect.Message {
 	mi := &file_proto_tigerbeetle_proto_msgTypes[20]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -1330,120 +1490,146 @@ func (x *Account) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use Account.ProtoReflect.Descriptor instead.
-func (*Account) Descriptor() ([]byte, []int) {
+// Deprecated: Use GetBalancesAtRequest.ProtoReflect.Descriptor instead.
+func (*GetBalancesAtRequest) Descriptor() ([]byte, []int) {
 	return file_proto_tigerbeetle_proto_rawDescGZIP(), []int{20}
 }
 
-func (x *Account) GetId() string {
+func (x *GetBalancesAtRequest) GetAccountIds() []string {
 	if x != nil {
-		return x.Id
+		return x.AccountIds
 	}
-	return ""
+	return nil
 }
 
-func (x *Account) GetDebitsPending() uint64 {
+func (x *GetBalancesAtRequest) GetTimestamp() uint64 {
 	if x != nil {
-		return x.DebitsPending
+		return x.Timestamp
 	}
 	return 0
 }
 
-func (x *Account) GetDebitsPosted() uint64 {
-	if x != nil {
-		return x.DebitsPosted
-	}
-	return 0
+type GetBalancesAtReply struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Balances      []*BalanceAt           `protobuf:"bytes,1,rep,name=balances,proto3" json:"balances,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
 }
 
-func (x *Account) GetCreditsPending() uint64 {
-	if x != nil {
-		return x.CreditsPending
-	}
-	return 0
+func (x *GetBalancesAtReply) Reset() {
+	*x = GetBalancesAtReply{}
+	mi := &file_proto_tigerbeetle_proto_msgTypes[21]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
 }
 
-func (x *Account) GetCreditsPosted() uint64 {
-	if x != nil {
-		return x.CreditsPosted
-	}
-	return 0
+func (x *GetBalancesAtReply) String() string {
+	return protoimpl.X.MessageStringOf(x)
 }
 
-func (x *Account) GetUserData128() string {
+func (*GetBalancesAtReply) ProtoMessage() {}
+
+func (x *GetBalancesAtReply) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_tigerbeetle_proto_msgTypes[21]
 	if x != nil {
-		return x.UserData128
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
 	}
-	return ""
+	return mi.MessageOf(x)
 }
 
-func (x *Account) GetUserData64() uint64 {
-	if x != nil {
-		return x.UserData64
-	}
-	return 0
+// Deprecated: Use GetBalancesAtReply.ProtoReflect.Descriptor instead.
+func (*GetBalancesAtReply) Descriptor() ([]byte, []int) {
+	return file_proto_tigerbeetle_proto_rawDescGZIP(), []int{21}
 }
 
-func (x *Account) GetUserData32() uint32 {
+func (x *GetBalancesAtReply) GetBalances() []*BalanceAt {
 	if x != nil {
-		return x.UserData32
+		return x.Balances
 	}
-	return 0
+	return nil
 }
 
-func (x *Account) GetLedger() uint32 {
-	if x != nil {
-		return x.Ledger
-	}
-	return 0
+type LedgerSummaryRequest struct {
+	state  protoimpl.MessageState `protogen:"open.v1"`
+	Ledger uint32                 `protobuf:"varint,1,opt,name=ledger,proto3" json:"ledger,omitempty"`
+	// Rows are always grouped by code, group_by adds user_data fields, code and ledger are rejected
+	GroupBy       []GroupBy `protobuf:"varint,2,rep,packed,name=group_by,json=groupBy,proto3,enum=proto.GroupBy" json:"group_by,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
 }
 
-func (x *Account) GetCode() uint32 {
+func (x *LedgerSummaryRequest) Reset() {
+	*x = LedgerSummaryRequest{}
+	mi := &file_proto_tigerbeetle_proto_msgTypes[22]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *LedgerSummaryRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LedgerSummaryRequest) ProtoMessage() {}
+
+func (x *LedgerSummaryRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_tigerbeetle_proto_msgTypes[22]
 	if x != nil {
-		return x.Code
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
 	}
-	return 0
+	return mi.MessageOf(x)
 }
 
-func (x *Account) GetFlags() *AccountFlags {
+// Deprecated: Use LedgerSummaryRequest.ProtoReflect.Descriptor instead.
+func (*LedgerSummaryRequest) Descriptor() ([]byte, []int) {
+	return file_proto_tigerbeetle_proto_rawDescGZIP(), []int{22}
+}
+
+func (x *LedgerSummaryRequest) GetLedger() uint32 {
 	if x != nil {
-		return x.Flags
+		return x.Ledger
 	}
-	return nil
+	return 0
 }
 
-func (x *Account) GetTimestamp() uint64 {
+func (x *LedgerSummaryRequest) GetGroupBy() []GroupBy {
 	if x != nil {
-		return x.Timestamp
+		return x.GroupBy
 	}
-	return 0
+	return nil
 }
 
-type AccountFlags struct {
-	state                      protoimpl.MessageState `protogen:"open.v1"`
-	Linked                     *bool                  `protobuf:"varint,1,opt,name=linked,proto3,oneof" json:"linked,omitempty"`
-	DebitsMustNotExceedCredits *bool                  `protobuf:"varint,2,opt,name=debits_must_not_exceed_credits,json=debitsMustNotExceedCredits,proto3,oneof" json:"debits_must_not_exceed_credits,omitempty"`
-	CreditsMustNotExceedDebits *bool                  `protobuf:"varint,3,opt,name=credits_must_not_exceed_debits,json=creditsMustNotExceedDebits,proto3,oneof" json:"credits_must_not_exceed_debits,omitempty"`
-	History                    *bool                  `protobuf:"varint,4,opt,name=history,proto3,oneof" json:"history,omitempty"`
-	unknownFields              protoimpl.UnknownFields
-	sizeCache                  protoimpl.SizeCache
+type GetAccountStatementRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	AccountId     string                 `protobuf:"bytes,1,opt,name=account_id,json=accountId,proto3" json:"account_id,omitempty"`
+	TimestampMin  uint64                 `protobuf:"varint,2,opt,name=timestamp_min,json=timestampMin,proto3" json:"timestamp_min,omitempty"`
+	TimestampMax  uint64                 `protobuf:"varint,3,opt,name=timestamp_max,json=timestampMax,proto3" json:"timestamp_max,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
 }
 
-func (x *AccountFlags) Reset() {
-	*x = AccountFlags{}
-	mi := &file_proto_tigerbeetle_proto_msgTypes[21]
+func (x *GetAccountStatementRequest) Reset() {
+	*x = GetAccountStatementRequest{}
+	mi := &file_proto_tigerbeetle_proto_msgTypes[23]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *AccountFlags) String() string {
+func (x *GetAccountStatementRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*AccountFlags) ProtoMessage() {}
+func (*GetAccountStatementRequest) ProtoMessage() {}
 
-func (x *AccountFlags) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_tigerbeetle_proto_msgTypes[21]
+func (x *GetAccountStatementRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_tigerbeetle_proto_msgTypes[23]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1454,72 +1640,2352 @@ func (x *AccountFlags) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use AccountFlags.ProtoReflect.Descriptor instead.
-func (*AccountFlags) Descriptor() ([]byte, []int) {
-	return file_proto_tigerbeetle_proto_rawDescGZIP(), []int{21}
+// Deprecated: Use GetAccountStatementRequest.ProtoReflect.Descriptor instead.
+func (*GetAccountStatementRequest) Descriptor() ([]byte, []int) {
+	return file_proto_tigerbeetle_proto_rawDescGZIP(), []int{23}
 }
 
-func (x *AccountFlags) GetLinked() bool {
-	if x != nil && x.Linked != nil {
-		return *x.Linked
+func (x *GetAccountStatementRequest) GetAccountId() string {
+	if x != nil {
+		return x.AccountId
 	}
-	return false
+	return ""
 }
 
-func (x *AccountFlags) GetDebitsMustNotExceedCredits() bool {
-	if x != nil && x.DebitsMustNotExceedCredits != nil {
-		return *x.DebitsMustNotExceedCredits
+func (x *GetAccountStatementRequest) GetTimestampMin() uint64 {
+	if x != nil {
+		return x.TimestampMin
 	}
-	return false
+	return 0
 }
 
-func (x *AccountFlags) GetCreditsMustNotExceedDebits() bool {
+func (x *GetAccountStatementRequest) GetTimestampMax() uint64 {
+	if x != nil {
+		return x.TimestampMax
+	}
+	return 0
+}
+
+type GetAccountStatementReply struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	Account        *Account               `protobuf:"bytes,1,opt,name=account,proto3" json:"account,omitempty"`
+	TimestampMin   uint64                 `protobuf:"varint,2,opt,name=timestamp_min,json=timestampMin,proto3" json:"timestamp_min,omitempty"`
+	TimestampMax   uint64                 `protobuf:"varint,3,opt,name=timestamp_max,json=timestampMax,proto3" json:"timestamp_max,omitempty"`
+	OpeningBalance *BalanceAt             `protobuf:"bytes,4,opt,name=opening_balance,json=openingBalance,proto3" json:"opening_balance,omitempty"`
+	Lines          []*StatementLine       `protobuf:"bytes,5,rep,name=lines,proto3" json:"lines,omitempty"`
+	ClosingBalance *BalanceAt             `protobuf:"bytes,6,opt,name=closing_balance,json=closingBalance,proto3" json:"closing_balance,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *GetAccountStatementReply) Reset() {
+	*x = GetAccountStatementReply{}
+	mi := &file_proto_tigerbeetle_proto_msgTypes[24]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetAccountStatementReply) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetAccountStatementReply) ProtoMessage() {}
+
+func (x *GetAccountStatementReply) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_tigerbeetle_proto_msgTypes[24]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetAccountStatementReply.ProtoReflect.Descriptor instead.
+func (*GetAccountStatementReply) Descriptor() ([]byte, []int) {
+	return file_proto_tigerbeetle_proto_rawDescGZIP(), []int{24}
+}
+
+func (x *GetAccountStatementReply) GetAccount() *Account {
+	if x != nil {
+		return x.Account
+	}
+	return nil
+}
+
+func (x *GetAccountStatementReply) GetTimestampMin() uint64 {
+	if x != nil {
+		return x.TimestampMin
+	}
+	return 0
+}
+
+func (x *GetAccountStatementReply) GetTimestampMax() uint64 {
+	if x != nil {
+		return x.TimestampMax
+	}
+	return 0
+}
+
+func (x *GetAccountStatementReply) GetOpeningBalance() *BalanceAt {
+	if x != nil {
+		return x.OpeningBalance
+	}
+	return nil
+}
+
+func (x *GetAccountStatementReply) GetLines() []*StatementLine {
+	if x != nil {
+		return x.Lines
+	}
+	return nil
+}
+
+func (x *GetAccountStatementReply) GetClosingBalance() *BalanceAt {
+	if x != nil {
+		return x.ClosingBalance
+	}
+	return nil
+}
+
+type AggregateTransfersRequest struct {
+	state   protoimpl.MessageState `protogen:"open.v1"`
+	Filter  *QueryFilter           `protobuf:"bytes,1,opt,name=filter,proto3" json:"filter,omitempty"`
+	GroupBy []GroupBy              `protobuf:"varint,2,rep,packed,name=group_by,json=groupBy,proto3,enum=proto.GroupBy" json:"group_by,omitempty"`
+	Bucket  AggregateBucket        `protobuf:"varint,3,opt,name=bucket,proto3,enum=proto.AggregateBucket" json:"bucket,omitempty"`
+	// Maximum amount of groups returned, 0 is the server maximum
+	Limit uint32 `protobuf:"varint,4,opt,name=limit,proto3" json:"limit,omitempty"`
+	// Maximum amount of transfers scanned, 0 is the server maximum
+	MaxScan       uint64 `protobuf:"varint,5,opt,name=max_scan,json=maxScan,proto3" json:"max_scan,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AggregateTransfersRequest) Reset() {
+	*x = AggregateTransfersRequest{}
+	mi := &file_proto_tigerbeetle_proto_msgTypes[25]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AggregateTransfersRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AggregateTransfersRequest) ProtoMessage() {}
+
+func (x *AggregateTransfersRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_tigerbeetle_proto_msgTypes[25]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AggregateTransfersRequest.ProtoReflect.Descriptor instead.
+func (*AggregateTransfersRequest) Descriptor() ([]byte, []int) {
+	return file_proto_tigerbeetle_proto_rawDescGZIP(), []int{25}
+}
+
+func (x *AggregateTransfersRequest) GetFilter() *QueryFilter {
+	if x != nil {
+		return x.Filter
+	}
+	return nil
+}
+
+func (x *AggregateTransfersRequest) GetGroupBy() []GroupBy {
+	if x != nil {
+		return x.GroupBy
+	}
+	return nil
+}
+
+func (x *AggregateTransfersRequest) GetBucket() AggregateBucket {
+	if x != nil {
+		return x.Bucket
+	}
+	return AggregateBucket_AggregateBucketNone
+}
+
+func (x *AggregateTransfersRequest) GetLimit() uint32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+func (x *AggregateTransfersRequest) GetMaxScan() uint64 {
+	if x != nil {
+		return x.MaxScan
+	}
+	return 0
+}
+
+type AggregateTransfersReply struct {
+	state   protoimpl.MessageState     `protogen:"open.v1"`
+	Groups  []*AggregateTransfersGroup `protobuf:"bytes,1,rep,name=groups,proto3" json:"groups,omitempty"`
+	Scanned uint64                     `protobuf:"varint,2,opt,name=scanned,proto3" json:"scanned,omitempty"`
+	// The scan stopped at max_scan or limit before the filter was exhausted
+	Truncated bool `protobuf:"varint,3,opt,name=truncated,proto3" json:"truncated,omitempty"`
+	// Timestamp of the last transfer scanned, resume with timestamp_min = last_timestamp + 1
+	LastTimestamp uint64 `protobuf:"varint,4,opt,name=last_timestamp,json=lastTimestamp,proto3" json:"last_timestamp,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AggregateTransfersReply) Reset() {
+	*x = AggregateTransfersReply{}
+	mi := &file_proto_tigerbeetle_proto_msgTypes[26]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AggregateTransfersReply) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AggregateTransfersReply) ProtoMessage() {}
+
+func (x *AggregateTransfersReply) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_tigerbeetle_proto_msgTypes[26]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AggregateTransfersReply.ProtoReflect.Descriptor instead.
+func (*AggregateTransfersReply) Descriptor() ([]byte, []int) {
+	return file_proto_tigerbeetle_proto_rawDescGZIP(), []int{26}
+}
+
+func (x *AggregateTransfersReply) GetGroups() []*AggregateTransfersGroup {
+	if x != nil {
+		return x.Groups
+	}
+	return nil
+}
+
+func (x *AggregateTransfersReply) GetScanned() uint64 {
+	if x != nil {
+		return x.Scanned
+	}
+	return 0
+}
+
+func (x *AggregateTransfersReply) GetTruncated() bool {
+	if x != nil {
+		return x.Truncated
+	}
+	return false
+}
+
+func (x *AggregateTransfersReply) GetLastTimestamp() uint64 {
+	if x != nil {
+		return x.LastTimestamp
+	}
+	return 0
+}
+
+type LedgerSummaryReply struct {
+	state  protoimpl.MessageState `protogen:"open.v1"`
+	Ledger uint32                 `protobuf:"varint,1,opt,name=ledger,proto3" json:"ledger,omitempty"`
+	Rows   []*LedgerSummaryRow    `protobuf:"bytes,2,rep,name=rows,proto3" json:"rows,omitempty"`
+	Total  *LedgerSummaryRow      `protobuf:"bytes,3,opt,name=total,proto3" json:"total,omitempty"`
+	// Total debits equal total credits, both pending and posted
+	Balanced      bool `protobuf:"varint,4,opt,name=balanced,proto3" json:"balanced,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *LedgerSummaryReply) Reset() {
+	*x = LedgerSummaryReply{}
+	mi := &file_proto_tigerbeetle_proto_msgTypes[27]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *LedgerSummaryReply) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LedgerSummaryReply) ProtoMessage() {}
+
+func (x *LedgerSummaryReply) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_tigerbeetle_proto_msgTypes[27]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LedgerSummaryReply.ProtoReflect.Descriptor instead.
+func (*LedgerSummaryReply) Descriptor() ([]byte, []int) {
+	return file_proto_tigerbeetle_proto_rawDescGZIP(), []int{27}
+}
+
+func (x *LedgerSummaryReply) GetLedger() uint32 {
+	if x != nil {
+		return x.Ledger
+	}
+	return 0
+}
+
+func (x *LedgerSummaryReply) GetRows() []*LedgerSummaryRow {
+	if x != nil {
+		return x.Rows
+	}
+	return nil
+}
+
+func (x *LedgerSummaryReply) GetTotal() *LedgerSummaryRow {
+	if x != nil {
+		return x.Total
+	}
+	return nil
+}
+
+func (x *LedgerSummaryReply) GetBalanced() bool {
+	if x != nil {
+		return x.Balanced
+	}
+	return false
+}
+
+// Name of a backup directory in BACKUP_DIR
+type ExportLedgerRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ExportLedgerRequest) Reset() {
+	*x = ExportLedgerRequest{}
+	mi := &file_proto_tigerbeetle_proto_msgTypes[28]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ExportLedgerRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ExportLedgerRequest) ProtoMessage() {}
+
+func (x *ExportLedgerRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_tigerbeetle_proto_msgTypes[28]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ExportLedgerRequest.ProtoReflect.Descriptor instead.
+func (*ExportLedgerRequest) Descriptor() ([]byte, []int) {
+	return file_proto_tigerbeetle_proto_rawDescGZIP(), []int{28}
+}
+
+func (x *ExportLedgerRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+type ExportLedgerReply struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Accounts      uint64                 `protobuf:"varint,2,opt,name=accounts,proto3" json:"accounts,omitempty"`
+	Transfers     uint64                 `protobuf:"varint,3,opt,name=transfers,proto3" json:"transfers,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ExportLedgerReply) Reset() {
+	*x = ExportLedgerReply{}
+	mi := &file_proto_tigerbeetle_proto_msgTypes[29]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ExportLedgerReply) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ExportLedgerReply) ProtoMessage() {}
+
+func (x *ExportLedgerReply) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_tigerbeetle_proto_msgTypes[29]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ExportLedgerReply.ProtoReflect.Descriptor instead.
+func (*ExportLedgerReply) Descriptor() ([]byte, []int) {
+	return file_proto_tigerbeetle_proto_rawDescGZIP(), []int{29}
+}
+
+func (x *ExportLedgerReply) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *ExportLedgerReply) GetAccounts() uint64 {
+	if x != nil {
+		return x.Accounts
+	}
+	return 0
+}
+
+func (x *ExportLedgerReply) GetTransfers() uint64 {
+	if x != nil {
+		return x.Transfers
+	}
+	return 0
+}
+
+type RestoreLedgerRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RestoreLedgerRequest) Reset() {
+	*x = RestoreLedgerRequest{}
+	mi := &file_proto_tigerbeetle_proto_msgTypes[30]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RestoreLedgerRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RestoreLedgerRequest) ProtoMessage() {}
+
+func (x *RestoreLedgerRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_tigerbeetle_proto_msgTypes[30]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RestoreLedgerRequest.ProtoReflect.Descriptor instead.
+func (*RestoreLedgerRequest) Descriptor() ([]byte, []int) {
+	return file_proto_tigerbeetle_proto_rawDescGZIP(), []int{30}
+}
+
+func (x *RestoreLedgerRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+type RestoreLedgerReply struct {
+	state         protoimpl.MessageState    `protogen:"open.v1"`
+	Accounts      uint64                    `protobuf:"varint,1,opt,name=accounts,proto3" json:"accounts,omitempty"`
+	Transfers     uint64                    `protobuf:"varint,2,opt,name=transfers,proto3" json:"transfers,omitempty"`
+	Rejected      []*RestoreLedgerRejection `protobuf:"bytes,3,rep,name=rejected,proto3" json:"rejected,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RestoreLedgerReply) Reset() {
+	*x = RestoreLedgerReply{}
+	mi := &file_proto_tigerbeetle_proto_msgTypes[31]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RestoreLedgerReply) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RestoreLedgerReply) ProtoMessage() {}
+
+func (x *RestoreLedgerReply) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_tigerbeetle_proto_msgTypes[31]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RestoreLedgerReply.ProtoReflect.Descriptor instead.
+func (*RestoreLedgerReply) Descriptor() ([]byte, []int) {
+	return file_proto_tigerbeetle_proto_rawDescGZIP(), []int{31}
+}
+
+func (x *RestoreLedgerReply) GetAccounts() uint64 {
+	if x != nil {
+		return x.Accounts
+	}
+	return 0
+}
+
+func (x *RestoreLedgerReply) GetTransfers() uint64 {
+	if x != nil {
+		return x.Transfers
+	}
+	return 0
+}
+
+func (x *RestoreLedgerReply) GetRejected() []*RestoreLedgerRejection {
+	if x != nil {
+		return x.Rejected
+	}
+	return nil
+}
+
+type RegisterAliasRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Alias string                 `protobuf:"bytes,1,opt,name=alias,proto3" json:"alias,omitempty"`
+	// Generated in store mode when empty, must be empty or the derived id in hash mode
+	Id            *string `protobuf:"bytes,2,opt,name=id,proto3,oneof" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RegisterAliasRequest) Reset() {
+	*x = RegisterAliasRequest{}
+	mi := &file_proto_tigerbeetle_proto_msgTypes[32]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RegisterAliasRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RegisterAliasRequest) ProtoMessage() {}
+
+func (x *RegisterAliasRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_tigerbeetle_proto_msgTypes[32]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RegisterAliasRequest.ProtoReflect.Descriptor instead.
+func (*RegisterAliasRequest) Descriptor() ([]byte, []int) {
+	return file_proto_tigerbeetle_proto_rawDescGZIP(), []int{32}
+}
+
+func (x *RegisterAliasRequest) GetAlias() string {
+	if x != nil {
+		return x.Alias
+	}
+	return ""
+}
+
+func (x *RegisterAliasRequest) GetId() string {
+	if x != nil && x.Id != nil {
+		return *x.Id
+	}
+	return ""
+}
+
+type RegisterAliasReply struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Alias         *Alias                 `protobuf:"bytes,1,opt,name=alias,proto3" json:"alias,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RegisterAliasReply) Reset() {
+	*x = RegisterAliasReply{}
+	mi := &file_proto_tigerbeetle_proto_msgTypes[33]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RegisterAliasReply) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RegisterAliasReply) ProtoMessage() {}
+
+func (x *RegisterAliasReply) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_tigerbeetle_proto_msgTypes[33]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RegisterAliasReply.ProtoReflect.Descriptor instead.
+func (*RegisterAliasReply) Descriptor() ([]byte, []int) {
+	return file_proto_tigerbeetle_proto_rawDescGZIP(), []int{33}
+}
+
+func (x *RegisterAliasReply) GetAlias() *Alias {
+	if x != nil {
+		return x.Alias
+	}
+	return nil
+}
+
+type ListLedgersRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListLedgersRequest) Reset() {
+	*x = ListLedgersRequest{}
+	mi := &file_proto_tigerbeetle_proto_msgTypes[34]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListLedgersRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListLedgersRequest) ProtoMessage() {}
+
+func (x *ListLedgersRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_tigerbeetle_proto_msgTypes[34]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListLedgersRequest.ProtoReflect.Descriptor instead.
+func (*ListLedgersRequest) Descriptor() ([]byte, []int) {
+	return file_proto_tigerbeetle_proto_rawDescGZIP(), []int{34}
+}
+
+type ListLedgersReply struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Ledgers       []*LedgerInfo          `protobuf:"bytes,1,rep,name=ledgers,proto3" json:"ledgers,omitempty"`
+	Codes         []*CodeInfo            `protobuf:"bytes,2,rep,name=codes,proto3" json:"codes,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListLedgersReply) Reset() {
+	*x = ListLedgersReply{}
+	mi := &file_proto_tigerbeetle_proto_msgTypes[35]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListLedgersReply) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListLedgersReply) ProtoMessage() {}
+
+func (x *ListLedgersReply) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_tigerbeetle_proto_msgTypes[35]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListLedgersReply.ProtoReflect.Descriptor instead.
+func (*ListLedgersReply) Descriptor() ([]byte, []int) {
+	return file_proto_tigerbeetle_proto_rawDescGZIP(), []int{35}
+}
+
+func (x *ListLedgersReply) GetLedgers() []*LedgerInfo {
+	if x != nil {
+		return x.Ledgers
+	}
+	return nil
+}
+
+func (x *ListLedgersReply) GetCodes() []*CodeInfo {
+	if x != nil {
+		return x.Codes
+	}
+	return nil
+}
+
+type LookupAliasesRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Aliases       []string               `protobuf:"bytes,1,rep,name=aliases,proto3" json:"aliases,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *LookupAliasesRequest) Reset() {
+	*x = LookupAliasesRequest{}
+	mi := &file_proto_tigerbeetle_proto_msgTypes[36]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *LookupAliasesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LookupAliasesRequest) ProtoMessage() {}
+
+func (x *LookupAliasesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_tigerbeetle_proto_msgTypes[36]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LookupAliasesRequest.ProtoReflect.Descriptor instead.
+func (*LookupAliasesRequest) Descriptor() ([]byte, []int) {
+	return file_proto_tigerbeetle_proto_rawDescGZIP(), []int{36}
+}
+
+func (x *LookupAliasesRequest) GetAliases() []string {
+	if x != nil {
+		return x.Aliases
+	}
+	return nil
+}
+
+type LookupAliasesReply struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Unknown aliases are left out
+	Aliases       []*Alias `protobuf:"bytes,1,rep,name=aliases,proto3" json:"aliases,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *LookupAliasesReply) Reset() {
+	*x = LookupAliasesReply{}
+	mi := &file_proto_tigerbeetle_proto_msgTypes[37]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *LookupAliasesReply) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LookupAliasesReply) ProtoMessage() {}
+
+func (x *LookupAliasesReply) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_tigerbeetle_proto_msgTypes[37]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LookupAliasesReply.ProtoReflect.Descriptor instead.
+func (*LookupAliasesReply) Descriptor() ([]byte, []int) {
+	return file_proto_tigerbeetle_proto_rawDescGZIP(), []int{37}
+}
+
+func (x *LookupAliasesReply) GetAliases() []*Alias {
+	if x != nil {
+		return x.Aliases
+	}
+	return nil
+}
+
+type CreateCompoundTransferRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Linked in order, ids are generated when empty
+	Legs []*Transfer `protobuf:"bytes,1,rep,name=legs,proto3" json:"legs,omitempty"`
+	// Shared metadata, used by legs that leave these fields zero
+	Ledger      uint32 `protobuf:"varint,2,opt,name=ledger,proto3" json:"ledger,omitempty"`
+	Code        uint32 `protobuf:"varint,3,opt,name=code,proto3" json:"code,omitempty"`
+	UserData128 string `protobuf:"bytes,4,opt,name=user_data128,json=userData128,proto3" json:"user_data128,omitempty"`
+	UserData64  uint64 `protobuf:"varint,5,opt,name=user_data64,json=userData64,proto3" json:"user_data64,omitempty"`
+	UserData32  uint32 `protobuf:"varint,6,opt,name=user_data32,json=userData32,proto3" json:"user_data32,omitempty"`
+	// Derives the id of each leg from the key and its index
+	IdempotencyKey *string `protobuf:"bytes,7,opt,name=idempotency_key,json=idempotencyKey,proto3,oneof" json:"idempotency_key,omitempty"`
+	// Amount expected to move on each ledger, the legs of a ledger must add up to it, required
+	Totals        []*LedgerTotal `protobuf:"bytes,8,rep,name=totals,proto3" json:"totals,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateCompoundTransferRequest) Reset() {
+	*x = CreateCompoundTransferRequest{}
+	mi := &file_proto_tigerbeetle_proto_msgTypes[38]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateCompoundTransferRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateCompoundTransferRequest) ProtoMessage() {}
+
+func (x *CreateCompoundTransferRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_tigerbeetle_proto_msgTypes[38]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateCompoundTransferRequest.ProtoReflect.Descriptor instead.
+func (*CreateCompoundTransferRequest) Descriptor() ([]byte, []int) {
+	return file_proto_tigerbeetle_proto_rawDescGZIP(), []int{38}
+}
+
+func (x *CreateCompoundTransferRequest) GetLegs() []*Transfer {
+	if x != nil {
+		return x.Legs
+	}
+	return nil
+}
+
+func (x *CreateCompoundTransferRequest) GetLedger() uint32 {
+	if x != nil {
+		return x.Ledger
+	}
+	return 0
+}
+
+func (x *CreateCompoundTransferRequest) GetCode() uint32 {
+	if x != nil {
+		return x.Code
+	}
+	return 0
+}
+
+func (x *CreateCompoundTransferRequest) GetUserData128() string {
+	if x != nil {
+		return x.UserData128
+	}
+	return ""
+}
+
+func (x *CreateCompoundTransferRequest) GetUserData64() uint64 {
+	if x != nil {
+		return x.UserData64
+	}
+	return 0
+}
+
+func (x *CreateCompoundTransferRequest) GetUserData32() uint32 {
+	if x != nil {
+		return x.UserData32
+	}
+	return 0
+}
+
+func (x *CreateCompoundTransferRequest) GetIdempotencyKey() string {
+	if x != nil && x.IdempotencyKey != nil {
+		return *x.IdempotencyKey
+	}
+	return ""
+}
+
+func (x *CreateCompoundTransferRequest) GetTotals() []*LedgerTotal {
+	if x != nil {
+		return x.Totals
+	}
+	return nil
+}
+
+type CreateCompoundTransferReply struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Ok    bool                   `protobuf:"varint,1,opt,name=ok,proto3" json:"ok,omitempty"`
+	Ids   []string               `protobuf:"bytes,2,rep,name=ids,proto3" json:"ids,omitempty"`
+	// The leg that failed the chain, not set when ok
+	FailedLegIndex *int32               `protobuf:"varint,3,opt,name=failed_leg_index,json=failedLegIndex,proto3,oneof" json:"failed_leg_index,omitempty"`
+	Result         CreateTransferResult `protobuf:"varint,4,opt,name=result,proto3,enum=proto.CreateTransferResult" json:"result,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *CreateCompoundTransferReply) Reset() {
+	*x = CreateCompoundTransferReply{}
+	mi := &file_proto_tigerbeetle_proto_msgTypes[39]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateCompoundTransferReply) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateCompoundTransferReply) ProtoMessage() {}
+
+func (x *CreateCompoundTransferReply) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_tigerbeetle_proto_msgTypes[39]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateCompoundTransferReply.ProtoReflect.Descriptor instead.
+func (*CreateCompoundTransferReply) Descriptor() ([]byte, []int) {
+	return file_proto_tigerbeetle_proto_rawDescGZIP(), []int{39}
+}
+
+func (x *CreateCompoundTransferReply) GetOk() bool {
+	if x != nil {
+		return x.Ok
+	}
+	return false
+}
+
+func (x *CreateCompoundTransferReply) GetIds() []string {
+	if x != nil {
+		return x.Ids
+	}
+	return nil
+}
+
+func (x *CreateCompoundTransferReply) GetFailedLegIndex() int32 {
+	if x != nil && x.FailedLegIndex != nil {
+		return *x.FailedLegIndex
+	}
+	return 0
+}
+
+func (x *CreateCompoundTransferReply) GetResult() CreateTransferResult {
+	if x != nil {
+		return x.Result
+	}
+	return CreateTransferResult_TransferOK
+}
+
+type ExchangeRequest struct {
+	state                protoimpl.MessageState `protogen:"open.v1"`
+	SourceAccountId      string                 `protobuf:"bytes,1,opt,name=source_account_id,json=sourceAccountId,proto3" json:"source_account_id,omitempty"`
+	DestinationAccountId string                 `protobuf:"bytes,2,opt,name=destination_account_id,json=destinationAccountId,proto3" json:"destination_account_id,omitempty"`
+	SourceAmount         uint64                 `protobuf:"varint,3,opt,name=source_amount,json=sourceAmount,proto3" json:"source_amount,omitempty"`
+	// Replaces source_amount, with the asset scale of the source ledger
+	SourceAmountDecimal *string `protobuf:"bytes,4,opt,name=source_amount_decimal,json=sourceAmountDecimal,proto3,oneof" json:"source_amount_decimal,omitempty"`
+	// Destination currency per unit of source currency, such as "1.0850"
+	Rate string `protobuf:"bytes,5,opt,name=rate,proto3" json:"rate,omitempty"`
+	// Defaults to the code of the exchange in the registry
+	Code        uint32 `protobuf:"varint,6,opt,name=code,proto3" json:"code,omitempty"`
+	UserData128 string `protobuf:"bytes,7,opt,name=user_data128,json=userData128,proto3" json:"user_data128,omitempty"`
+	UserData64  uint64 `protobuf:"varint,8,opt,name=user_data64,json=userData64,proto3" json:"user_data64,omitempty"`
+	UserData32  uint32 `protobuf:"varint,9,opt,name=user_data32,json=userData32,proto3" json:"user_data32,omitempty"`
+	// Derives the id of each leg from the key and its index
+	IdempotencyKey *string `protobuf:"bytes,10,opt,name=idempotency_key,json=idempotencyKey,proto3,oneof" json:"idempotency_key,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *ExchangeRequest) Reset() {
+	*x = ExchangeRequest{}
+	mi := &file_proto_tigerbeetle_proto_msgTypes[40]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ExchangeRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ExchangeRequest) ProtoMessage() {}
+
+func (x *ExchangeRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_tigerbeetle_proto_msgTypes[40]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ExchangeRequest.ProtoReflect.Descriptor instead.
+func (*ExchangeRequest) Descriptor() ([]byte, []int) {
+	return file_proto_tigerbeetle_proto_rawDescGZIP(), []int{40}
+}
+
+func (x *ExchangeRequest) GetSourceAccountId() string {
+	if x != nil {
+		return x.SourceAccountId
+	}
+	return ""
+}
+
+func (x *ExchangeRequest) GetDestinationAccountId() string {
+	if x != nil {
+		return x.DestinationAccountId
+	}
+	return ""
+}
+
+func (x *ExchangeRequest) GetSourceAmount() uint64 {
+	if x != nil {
+		return x.SourceAmount
+	}
+	return 0
+}
+
+func (x *ExchangeRequest) GetSourceAmountDecimal() string {
+	if x != nil && x.SourceAmountDecimal != nil {
+		return *x.SourceAmountDecimal
+	}
+	return ""
+}
+
+func (x *ExchangeRequest) GetRate() string {
+	if x != nil {
+		return x.Rate
+	}
+	return ""
+}
+
+func (x *ExchangeRequest) GetCode() uint32 {
+	if x != nil {
+		return x.Code
+	}
+	return 0
+}
+
+func (x *ExchangeRequest) GetUserData128() string {
+	if x != nil {
+		return x.UserData128
+	}
+	return ""
+}
+
+func (x *ExchangeRequest) GetUserData64() uint64 {
+	if x != nil {
+		return x.UserData64
+	}
+	return 0
+}
+
+func (x *ExchangeRequest) GetUserData32() uint32 {
+	if x != nil {
+		return x.UserData32
+	}
+	return 0
+}
+
+func (x *ExchangeRequest) GetIdempotencyKey() string {
+	if x != nil && x.IdempotencyKey != nil {
+		return *x.IdempotencyKey
+	}
+	return ""
+}
+
+type ExchangeReply struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Ok    bool                   `protobuf:"varint,1,opt,name=ok,proto3" json:"ok,omitempty"`
+	Ids   []string               `protobuf:"bytes,2,rep,name=ids,proto3" json:"ids,omitempty"`
+	// The leg that failed the chain, not set when ok
+	FailedLegIndex           *int32               `protobuf:"varint,3,opt,name=failed_leg_index,json=failedLegIndex,proto3,oneof" json:"failed_leg_index,omitempty"`
+	Result                   CreateTransferResult `protobuf:"varint,4,opt,name=result,proto3,enum=proto.CreateTransferResult" json:"result,omitempty"`
+	SourceLedger             uint32               `protobuf:"varint,5,opt,name=source_ledger,json=sourceLedger,proto3" json:"source_ledger,omitempty"`
+	DestinationLedger        uint32               `protobuf:"varint,6,opt,name=destination_ledger,json=destinationLedger,proto3" json:"destination_ledger,omitempty"`
+	SourceAmount             uint64               `protobuf:"varint,7,opt,name=source_amount,json=sourceAmount,proto3" json:"source_amount,omitempty"`
+	DestinationAmount        uint64               `protobuf:"varint,8,opt,name=destination_amount,json=destinationAmount,proto3" json:"destination_amount,omitempty"`
+	SourceAmountDecimal      string               `protobuf:"bytes,9,opt,name=source_amount_decimal,json=sourceAmountDecimal,proto3" json:"source_amount_decimal,omitempty"`
+	DestinationAmountDecimal string               `protobuf:"bytes,10,opt,name=destination_amount_decimal,json=destinationAmountDecimal,proto3" json:"destination_amount_decimal,omitempty"`
+	unknownFields            protoimpl.UnknownFields
+	sizeCache                protoimpl.SizeCache
+}
+
+func (x *ExchangeReply) Reset() {
+	*x = ExchangeReply{}
+	mi := &file_proto_tigerbeetle_proto_msgTypes[41]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ExchangeReply) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ExchangeReply) ProtoMessage() {}
+
+func (x *ExchangeReply) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_tigerbeetle_proto_msgTypes[41]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ExchangeReply.ProtoReflect.Descriptor instead.
+func (*ExchangeReply) Descriptor() ([]byte, []int) {
+	return file_proto_tigerbeetle_proto_rawDescGZIP(), []int{41}
+}
+
+func (x *ExchangeReply) GetOk() bool {
+	if x != nil {
+		return x.Ok
+	}
+	return false
+}
+
+func (x *ExchangeReply) GetIds() []string {
+	if x != nil {
+		return x.Ids
+	}
+	return nil
+}
+
+func (x *ExchangeReply) GetFailedLegIndex() int32 {
+	if x != nil && x.FailedLegIndex != nil {
+		return *x.FailedLegIndex
+	}
+	return 0
+}
+
+func (x *ExchangeReply) GetResult() CreateTransferResult {
+	if x != nil {
+		return x.Result
+	}
+	return CreateTransferResult_TransferOK
+}
+
+func (x *ExchangeReply) GetSourceLedger() uint32 {
+	if x != nil {
+		return x.SourceLedger
+	}
+	return 0
+}
+
+func (x *ExchangeReply) GetDestinationLedger() uint32 {
+	if x != nil {
+		return x.DestinationLedger
+	}
+	return 0
+}
+
+func (x *ExchangeReply) GetSourceAmount() uint64 {
+	if x != nil {
+		return x.SourceAmount
+	}
+	return 0
+}
+
+func (x *ExchangeReply) GetDestinationAmount() uint64 {
+	if x != nil {
+		return x.DestinationAmount
+	}
+	return 0
+}
+
+func (x *ExchangeReply) GetSourceAmountDecimal() string {
+	if x != nil {
+		return x.SourceAmountDecimal
+	}
+	return ""
+}
+
+func (x *ExchangeReply) GetDestinationAmountDecimal() string {
+	if x != nil {
+		return x.DestinationAmountDecimal
+	}
+	return ""
+}
+
+type CloseAccountRequest struct {
+	state     protoimpl.MessageState `protogen:"open.v1"`
+	AccountId string                 `protobuf:"bytes,1,opt,name=account_id,json=accountId,proto3" json:"account_id,omitempty"`
+	// Counterparty of the closing transfer, on the ledger of the account
+	SweepAccountId string `protobuf:"bytes,2,opt,name=sweep_account_id,json=sweepAccountId,proto3" json:"sweep_account_id,omitempty"`
+	// Moves the remaining balance to or from sweep_account_id before closing
+	Sweep bool `protobuf:"varint,3,opt,name=sweep,proto3" json:"sweep,omitempty"`
+	// Defaults to the code of the account
+	Code uint32 `protobuf:"varint,4,opt,name=code,proto3" json:"code,omitempty"`
+	// Derives the ids of the sweep and closing transfers from the key
+	IdempotencyKey *string `protobuf:"bytes,5,opt,name=idempotency_key,json=idempotencyKey,proto3,oneof" json:"idempotency_key,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *CloseAccountRequest) Reset() {
+	*x = CloseAccountRequest{}
+	mi := &file_proto_tigerbeetle_proto_msgTypes[42]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CloseAccountRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CloseAccountRequest) ProtoMessage() {}
+
+func (x *CloseAccountRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_tigerbeetle_proto_msgTypes[42]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CloseAccountRequest.ProtoReflect.Descriptor instead.
+func (*CloseAccountRequest) Descriptor() ([]byte, []int) {
+	return file_proto_tigerbeetle_proto_rawDescGZIP(), []int{42}
+}
+
+func (x *CloseAccountRequest) GetAccountId() string {
+	if x != nil {
+		return x.AccountId
+	}
+	return ""
+}
+
+func (x *CloseAccountRequest) GetSweepAccountId() string {
+	if x != nil {
+		return x.SweepAccountId
+	}
+	return ""
+}
+
+func (x *CloseAccountRequest) GetSweep() bool {
+	if x != nil {
+		return x.Sweep
+	}
+	return false
+}
+
+func (x *CloseAccountRequest) GetCode() uint32 {
+	if x != nil {
+		return x.Code
+	}
+	return 0
+}
+
+func (x *CloseAccountRequest) GetIdempotencyKey() string {
+	if x != nil && x.IdempotencyKey != nil {
+		return *x.IdempotencyKey
+	}
+	return ""
+}
+
+type CloseAccountReply struct {
+	state             protoimpl.MessageState `protogen:"open.v1"`
+	Ok                bool                   `protobuf:"varint,1,opt,name=ok,proto3" json:"ok,omitempty"`
+	ClosingTransferId string                 `protobuf:"bytes,2,opt,name=closing_transfer_id,json=closingTransferId,proto3" json:"closing_transfer_id,omitempty"`
+	// Not set when there was no balance to sweep
+	SweepTransferId *string `protobuf:"bytes,3,opt,name=sweep_transfer_id,json=sweepTransferId,proto3,oneof" json:"sweep_transfer_id,omitempty"`
+	// The leg that failed the chain, not set when ok
+	FailedLegIndex *int32               `protobuf:"varint,4,opt,name=failed_leg_index,json=failedLegIndex,proto3,oneof" json:"failed_leg_index,omitempty"`
+	Result         CreateTransferResult `protobuf:"varint,5,opt,name=result,proto3,enum=proto.CreateTransferResult" json:"result,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *CloseAccountReply) Reset() {
+	*x = CloseAccountReply{}
+	mi := &file_proto_tigerbeetle_proto_msgTypes[43]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CloseAccountReply) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CloseAccountReply) ProtoMessage() {}
+
+func (x *CloseAccountReply) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_tigerbeetle_proto_msgTypes[43]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CloseAccountReply.ProtoReflect.Descriptor instead.
+func (*CloseAccountReply) Descriptor() ([]byte, []int) {
+	return file_proto_tigerbeetle_proto_rawDescGZIP(), []int{43}
+}
+
+func (x *CloseAccountReply) GetOk() bool {
+	if x != nil {
+		return x.Ok
+	}
+	return false
+}
+
+func (x *CloseAccountReply) GetClosingTransferId() string {
+	if x != nil {
+		return x.ClosingTransferId
+	}
+	return ""
+}
+
+func (x *CloseAccountReply) GetSweepTransferId() string {
+	if x != nil && x.SweepTransferId != nil {
+		return *x.SweepTransferId
+	}
+	return ""
+}
+
+func (x *CloseAccountReply) GetFailedLegIndex() int32 {
+	if x != nil && x.FailedLegIndex != nil {
+		return *x.FailedLegIndex
+	}
+	return 0
+}
+
+func (x *CloseAccountReply) GetResult() CreateTransferResult {
+	if x != nil {
+		return x.Result
+	}
+	return CreateTransferResult_TransferOK
+}
+
+type ReopenAccountRequest struct {
+	state             protoimpl.MessageState `protogen:"open.v1"`
+	ClosingTransferId string                 `protobuf:"bytes,1,opt,name=closing_transfer_id,json=closingTransferId,proto3" json:"closing_transfer_id,omitempty"`
+	// Derives the id of the voiding transfer
+	IdempotencyKey *string `protobuf:"bytes,2,opt,name=idempotency_key,json=idempotencyKey,proto3,oneof" json:"idempotency_key,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *ReopenAccountRequest) Reset() {
+	*x = ReopenAccountRequest{}
+	mi := &file_proto_tigerbeetle_proto_msgTypes[44]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReopenAccountRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReopenAccountRequest) ProtoMessage() {}
+
+func (x *ReopenAccountRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_tigerbeetle_proto_msgTypes[44]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReopenAccountRequest.ProtoReflect.Descriptor instead.
+func (*ReopenAccountRequest) Descriptor() ([]byte, []int) {
+	return file_proto_tigerbeetle_proto_rawDescGZIP(), []int{44}
+}
+
+func (x *ReopenAccountRequest) GetClosingTransferId() string {
+	if x != nil {
+		return x.ClosingTransferId
+	}
+	return ""
+}
+
+func (x *ReopenAccountRequest) GetIdempotencyKey() string {
+	if x != nil && x.IdempotencyKey != nil {
+		return *x.IdempotencyKey
+	}
+	return ""
+}
+
+type ReopenAccountReply struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	Ok             bool                   `protobuf:"varint,1,opt,name=ok,proto3" json:"ok,omitempty"`
+	VoidTransferId string                 `protobuf:"bytes,2,opt,name=void_transfer_id,json=voidTransferId,proto3" json:"void_transfer_id,omitempty"`
+	Result         CreateTransferResult   `protobuf:"varint,3,opt,name=result,proto3,enum=proto.CreateTransferResult" json:"result,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *ReopenAccountReply) Reset() {
+	*x = ReopenAccountReply{}
+	mi := &file_proto_tigerbeetle_proto_msgTypes[45]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReopenAccountReply) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReopenAccountReply) ProtoMessage() {}
+
+func (x *ReopenAccountReply) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_tigerbeetle_proto_msgTypes[45]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReopenAccountReply.ProtoReflect.Descriptor instead.
+func (*ReopenAccountReply) Descriptor() ([]byte, []int) {
+	return file_proto_tigerbeetle_proto_rawDescGZIP(), []int{45}
+}
+
+func (x *ReopenAccountReply) GetOk() bool {
+	if x != nil {
+		return x.Ok
+	}
+	return false
+}
+
+func (x *ReopenAccountReply) GetVoidTransferId() string {
+	if x != nil {
+		return x.VoidTransferId
+	}
+	return ""
+}
+
+func (x *ReopenAccountReply) GetResult() CreateTransferResult {
+	if x != nil {
+		return x.Result
+	}
+	return CreateTransferResult_TransferOK
+}
+
+// Types
+// ----------------------------------------------------------------
+type Account struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	Id             string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	DebitsPending  uint64                 `protobuf:"varint,2,opt,name=debits_pending,json=debitsPending,proto3" json:"debits_pending,omitempty"`
+	DebitsPosted   uint64                 `protobuf:"varint,3,opt,name=debits_posted,json=debitsPosted,proto3" json:"debits_posted,omitempty"`
+	CreditsPending uint64                 `protobuf:"varint,4,opt,name=credits_pending,json=creditsPending,proto3" json:"credits_pending,omitempty"`
+	CreditsPosted  uint64                 `protobuf:"varint,5,opt,name=credits_posted,json=creditsPosted,proto3" json:"credits_posted,omitempty"`
+	UserData128    string                 `protobuf:"bytes,6,opt,name=user_data128,json=userData128,proto3" json:"user_data128,omitempty"`
+	UserData64     uint64                 `protobuf:"varint,7,opt,name=user_data64,json=userData64,proto3" json:"user_data64,omitempty"`
+	UserData32     uint32                 `protobuf:"varint,8,opt,name=user_data32,json=userData32,proto3" json:"user_data32,omitempty"`
+	Ledger         uint32                 `protobuf:"varint,9,opt,name=ledger,proto3" json:"ledger,omitempty"`
+	Code           uint32                 `protobuf:"varint,10,opt,name=code,proto3" json:"code,omitempty"`
+	Flags          *AccountFlags          `protobuf:"bytes,11,opt,name=flags,proto3" json:"flags,omitempty"`
+	Timestamp      uint64                 `protobuf:"varint,12,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	// Derives the id from this key, creating the account again is reported as success
+	IdempotencyKey *string `protobuf:"bytes,13,opt,name=idempotency_key,json=idempotencyKey,proto3,oneof" json:"idempotency_key,omitempty"`
+	// Names from the registry, ignored on create
+	LedgerName *string `protobuf:"bytes,14,opt,name=ledger_name,json=ledgerName,proto3,oneof" json:"ledger_name,omitempty"`
+	CodeName   *string `protobuf:"bytes,15,opt,name=code_name,json=codeName,proto3,oneof" json:"code_name,omitempty"`
+	// Balances as decimals with the asset scale of the ledger in the registry
+	DebitsPendingDecimal  *string `protobuf:"bytes,16,opt,name=debits_pending_decimal,json=debitsPendingDecimal,proto3,oneof" json:"debits_pending_decimal,omitempty"`
+	DebitsPostedDecimal   *string `protobuf:"bytes,17,opt,name=debits_posted_decimal,json=debitsPostedDecimal,proto3,oneof" json:"debits_posted_decimal,omitempty"`
+	CreditsPendingDecimal *string `protobuf:"bytes,18,opt,name=credits_pending_decimal,json=creditsPendingDecimal,proto3,oneof" json:"credits_pending_decimal,omitempty"`
+	CreditsPostedDecimal  *string `protobuf:"bytes,19,opt,name=credits_posted_decimal,json=creditsPostedDecimal,proto3,oneof" json:"credits_posted_decimal,omitempty"`
+	unknownFields         protoimpl.UnknownFields
+	sizeCache             protoimpl.SizeCache
+}
+
+func (x *Account) Reset() {
+	*x = Account{}
+	mi := &file_proto_tigerbeetle_proto_msgTypes[46]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Account) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Account) ProtoMessage() {}
+
+func (x *Account) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_tigerbeetle_proto_msgTypes[46]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Account.ProtoReflect.Descriptor instead.
+func (*Account) Descriptor() ([]byte, []int) {
+	return file_proto_tigerbeetle_proto_rawDescGZIP(), []int{46}
+}
+
+func (x *Account) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Account) GetDebitsPending() uint64 {
+	if x != nil {
+		return x.DebitsPending
+	}
+	return 0
+}
+
+func (x *Account) GetDebitsPosted() uint64 {
+	if x != nil {
+		return x.DebitsPosted
+	}
+	return 0
+}
+
+func (x *Account) GetCreditsPending() uint64 {
+	if x != nil {
+		return x.CreditsPending
+	}
+	return 0
+}
+
+func (x *Account) GetCreditsPosted() uint64 {
+	if x != nil {
+		return x.CreditsPosted
+	}
+	return 0
+}
+
+func (x *Account) GetUserData128() string {
+	if x != nil {
+		return x.UserData128
+	}
+	return ""
+}
+
+func (x *Account) GetUserData64() uint64 {
+	if x != nil {
+		return x.UserData64
+	}
+	return 0
+}
+
+func (x *Account) GetUserData32() uint32 {
+	if x != nil {
+		return x.UserData32
+	}
+	return 0
+}
+
+func (x *Account) GetLedger() uint32 {
+	if x != nil {
+		return x.Ledger
+	}
+	return 0
+}
+
+func (x *Account) GetCode() uint32 {
+	if x != nil {
+		return x.Code
+	}
+	return 0
+}
+
+func (x *Account) GetFlags() *AccountFlags {
+	if x != nil {
+		return x.Flags
+	}
+	return nil
+}
+
+func (x *Account) GetTimestamp() uint64 {
+	if x != nil {
+		return x.Timestamp
+	}
+	return 0
+}
+
+func (x *Account) GetIdempotencyKey() string {
+	if x != nil && x.IdempotencyKey != nil {
+		return *x.IdempotencyKey
+	}
+	return ""
+}
+
+func (x *Account) GetLedgerName() string {
+	if x != nil && x.LedgerName != nil {
+		return *x.LedgerName
+	}
+	return ""
+}
+
+func (x *Account) GetCodeName() string {
+	if x != nil && x.CodeName != nil {
+		return *x.CodeName
+	}
+	return ""
+}
+
+func (x *Account) GetDebitsPendingDecimal() string {
+	if x != nil && x.DebitsPendingDecimal != nil {
+		return *x.DebitsPendingDecimal
+	}
+	return ""
+}
+
+func (x *Account) GetDebitsPostedDecimal() string {
+	if x != nil && x.DebitsPostedDecimal != nil {
+		return *x.DebitsPostedDecimal
+	}
+	return ""
+}
+
+func (x *Account) GetCreditsPendingDecimal() string {
+	if x != nil && x.CreditsPendingDecimal != nil {
+		return *x.CreditsPendingDecimal
+	}
+	return ""
+}
+
+func (x *Account) GetCreditsPostedDecimal() string {
+	if x != nil && x.CreditsPostedDecimal != nil {
+		return *x.CreditsPostedDecimal
+	}
+	return ""
+}
+
+type AccountFlags struct {
+	state                      protoimpl.MessageState `protogen:"open.v1"`
+	Linked                     *bool                  `protobuf:"varint,1,opt,name=linked,proto3,oneof" json:"linked,omitempty"`
+	DebitsMustNotExceedCredits *bool                  `protobuf:"varint,2,opt,name=debits_must_not_exceed_credits,json=debitsMustNotExceedCredits,proto3,oneof" json:"debits_must_not_exceed_credits,omitempty"`
+	CreditsMustNotExceedDebits *bool                  `protobuf:"varint,3,opt,name=credits_must_not_exceed_debits,json=creditsMustNotExceedDebits,proto3,oneof" json:"credits_must_not_exceed_debits,omitempty"`
+	History                    *bool                  `protobuf:"varint,4,opt,name=history,proto3,oneof" json:"history,omitempty"`
+	Imported                   *bool                  `protobuf:"varint,5,opt,name=imported,proto3,oneof" json:"imported,omitempty"`
+	Closed                     *bool                  `protobuf:"varint,6,opt,name=closed,proto3,oneof" json:"closed,omitempty"`
+	unknownFields              protoimpl.UnknownFields
+	sizeCache                  protoimpl.SizeCache
+}
+
+func (x *AccountFlags) Reset() {
+	*x = AccountFlags{}
+	mi := &file_proto_tigerbeetle_proto_msgTypes[47]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AccountFlags) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AccountFlags) ProtoMessage() {}
+
+func (x *AccountFlags) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_tigerbeetle_proto_msgTypes[47]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AccountFlags.ProtoReflect.Descriptor instead.
+func (*AccountFlags) Descriptor() ([]byte, []int) {
+	return file_proto_tigerbeetle_proto_rawDescGZIP(), []int{47}
+}
+
+func (x *AccountFlags) GetLinked() bool {
+	if x != nil && x.Linked != nil {
+		return *x.Linked
+	}
+	return false
+}
+
+func (x *AccountFlags) GetDebitsMustNotExceedCredits() bool {
+	if x != nil && x.DebitsMustNotExceedCredits != nil {
+		return *x.DebitsMustNotExceedCredits
+	}
+	return false
+}
+
+func (x *AccountFlags) GetCreditsMustNotExceedDebits() bool {
 	if x != nil && x.CreditsMustNotExceedDebits != nil {
 		return *x.CreditsMustNotExceedDebits
 	}
-	return false
+	return false
+}
+
+func (x *AccountFlags) GetHistory() bool {
+	if x != nil && x.History != nil {
+		return *x.History
+	}
+	return false
+}
+
+func (x *AccountFlags) GetImported() bool {
+	if x != nil && x.Imported != nil {
+		return *x.Imported
+	}
+	return false
+}
+
+func (x *AccountFlags) GetClosed() bool {
+	if x != nil && x.Closed != nil {
+		return *x.Closed
+	}
+	return false
+}
+
+type Transfer struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	Id              string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	DebitAccountId  string                 `protobuf:"bytes,2,opt,name=debit_account_id,json=debitAccountId,proto3" json:"debit_account_id,omitempty"`
+	CreditAccountId string                 `protobuf:"bytes,3,opt,name=credit_account_id,json=creditAccountId,proto3" json:"credit_account_id,omitempty"`
+	Amount          int64                  `protobuf:"varint,4,opt,name=amount,proto3" json:"amount,omitempty"`
+	PendingId       *string                `protobuf:"bytes,5,opt,name=pending_id,json=pendingId,proto3,oneof" json:"pending_id,omitempty"`
+	UserData128     string                 `protobuf:"bytes,6,opt,name=user_data128,json=userData128,proto3" json:"user_data128,omitempty"`
+	UserData64      uint64                 `protobuf:"varint,7,opt,name=user_data64,json=userData64,proto3" json:"user_data64,omitempty"`
+	UserData32      uint32                 `protobuf:"varint,8,opt,name=user_data32,json=userData32,proto3" json:"user_data32,omitempty"`
+	Ledger          uint32                 `protobuf:"varint,9,opt,name=ledger,proto3" json:"ledger,omitempty"`
+	Code            uint32                 `protobuf:"varint,10,opt,name=code,proto3" json:"code,omitempty"`
+	TransferFlags   *TransferFlags         `protobuf:"bytes,11,opt,name=transfer_flags,json=transferFlags,proto3" json:"transfer_flags,omitempty"`
+	Timestamp       *uint64                `protobuf:"varint,13,opt,name=timestamp,proto3,oneof" json:"timestamp,omitempty"`
+	// Derives the id from this key, creating the transfer again is reported as success
+	IdempotencyKey *string `protobuf:"bytes,14,opt,name=idempotency_key,json=idempotencyKey,proto3,oneof" json:"idempotency_key,omitempty"`
+	// Names from the registry, ignored on create
+	LedgerName *string `protobuf:"bytes,15,opt,name=ledger_name,json=ledgerName,proto3,oneof" json:"ledger_name,omitempty"`
+	CodeName   *string `protobuf:"bytes,16,opt,name=code_name,json=codeName,proto3,oneof" json:"code_name,omitempty"`
+	// Amount as a decimal with the asset scale of the ledger in the registry,
+	// replaces amount on create and may exceed 64 bits
+	AmountDecimal *string `protobuf:"bytes,17,opt,name=amount_decimal,json=amountDecimal,proto3,oneof" json:"amount_decimal,omitempty"`
+	// Conditions checked in the same linked chain with the control accounts of the ledger in the registry.
+	// Fails with TransferMinBalanceNotMet unless the debit account has at least this balance,
+	// the debit account must have debits_must_not_exceed_credits
+	RequireMinBalance *uint64 `protobuf:"varint,18,opt,name=require_min_balance,json=requireMinBalance,proto3,oneof" json:"require_min_balance,omitempty"`
+	// Fails with TransferMaxBalanceExceeded when the credit balance of the credit account ends above this
+	MaxBalance    *uint64 `protobuf:"varint,19,opt,name=max_balance,json=maxBalance,proto3,oneof" json:"max_balance,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Transfer) Reset() {
+	*x = Transfer{}
+	mi := &file_proto_tigerbeetle_proto_msgTypes[48]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Transfer) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Transfer) ProtoMessage() {}
+
+func (x *Transfer) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_tigerbeetle_proto_msgTypes[48]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Transfer.ProtoReflect.Descriptor instead.
+func (*Transfer) Descriptor() ([]byte, []int) {
+	return file_proto_tigerbeetle_proto_rawDescGZIP(), []int{48}
+}
+
+func (x *Transfer) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Transfer) GetDebitAccountId() string {
+	if x != nil {
+		return x.DebitAccountId
+	}
+	return ""
+}
+
+func (x *Transfer) GetCreditAccountId() string {
+	if x != nil {
+		return x.CreditAccountId
+	}
+	return ""
+}
+
+func (x *Transfer) GetAmount() int64 {
+	if x != nil {
+		return x.Amount
+	}
+	return 0
+}
+
+func (x *Transfer) GetPendingId() string {
+	if x != nil && x.PendingId != nil {
+		return *x.PendingId
+	}
+	return ""
+}
+
+func (x *Transfer) GetUserData128() string {
+	if x != nil {
+		return x.UserData128
+	}
+	return ""
+}
+
+func (x *Transfer) GetUserData64() uint64 {
+	if x != nil {
+		return x.UserData64
+	}
+	return 0
+}
+
+func (x *Transfer) GetUserData32() uint32 {
+	if x != nil {
+		return x.UserData32
+	}
+	return 0
+}
+
+func (x *Transfer) GetLedger() uint32 {
+	if x != nil {
+		return x.Ledger
+	}
+	return 0
+}
+
+func (x *Transfer) GetCode() uint32 {
+	if x != nil {
+		return x.Code
+	}
+	return 0
+}
+
+func (x *Transfer) GetTransferFlags() *TransferFlags {
+	if x != nil {
+		return x.TransferFlags
+	}
+	return nil
+}
+
+func (x *Transfer) GetTimestamp() uint64 {
+	if x != nil && x.Timestamp != nil {
+		return *x.Timestamp
+	}
+	return 0
+}
+
+func (x *Transfer) GetIdempotencyKey() string {
+	if x != nil && x.IdempotencyKey != nil {
+		return *x.IdempotencyKey
+	}
+	return ""
+}
+
+func (x *Transfer) GetLedgerName() string {
+	if x != nil && x.LedgerName != nil {
+		return *x.LedgerName
+	}
+	return ""
+}
+
+func (x *Transfer) GetCodeName() string {
+	if x != nil && x.CodeName != nil {
+		return *x.CodeName
+	}
+	return ""
+}
+
+func (x *Transfer) GetAmountDecimal() string {
+	if x != nil && x.AmountDecimal != nil {
+		return *x.AmountDecimal
+	}
+	return ""
+}
+
+func (x *Transfer) GetRequireMinBalance() uint64 {
+	if x != nil && x.RequireMinBalance != nil {
+		return *x.RequireMinBalance
+	}
+	return 0
+}
+
+func (x *Transfer) GetMaxBalance() uint64 {
+	if x != nil && x.MaxBalance != nil {
+		return *x.MaxBalance
+	}
+	return 0
+}
+
+type TransferFlags struct {
+	state               protoimpl.MessageState `protogen:"open.v1"`
+	Linked              *bool                  `protobuf:"varint,1,opt,name=linked,proto3,oneof" json:"linked,omitempty"`
+	Pending             *bool                  `protobuf:"varint,2,opt,name=pending,proto3,oneof" json:"pending,omitempty"`
+	PostPendingTransfer *bool                  `protobuf:"varint,3,opt,name=post_pending_transfer,json=postPendingTransfer,proto3,oneof" json:"post_pending_transfer,omitempty"`
+	VoidPendingTransfer *bool                  `protobuf:"varint,4,opt,name=void_pending_transfer,json=voidPendingTransfer,proto3,oneof" json:"void_pending_transfer,omitempty"`
+	BalancingDebit      *bool                  `protobuf:"varint,5,opt,name=balancing_debit,json=balancingDebit,proto3,oneof" json:"balancing_debit,omitempty"`
+	BalancingCredit     *bool                  `protobuf:"varint,6,opt,name=balancing_credit,json=balancingCredit,proto3,oneof" json:"balancing_credit,omitempty"`
+	Imported            *bool                  `protobuf:"varint,7,opt,name=imported,proto3,oneof" json:"imported,omitempty"`
+	ClosingDebit        *bool                  `protobuf:"varint,8,opt,name=closing_debit,json=closingDebit,proto3,oneof" json:"closing_debit,omitempty"`
+	ClosingCredit       *bool                  `protobuf:"varint,9,opt,name=closing_credit,json=closingCredit,proto3,oneof" json:"closing_credit,omitempty"`
+	unknownFields       protoimpl.UnknownFields
+	sizeCache           protoimpl.SizeCache
+}
+
+func (x *TransferFlags) Reset() {
+	*x = TransferFlags{}
+	mi := &file_proto_tigerbeetle_proto_msgTypes[49]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TransferFlags) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TransferFlags) ProtoMessage() {}
+
+func (x *TransferFlags) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_tigerbeetle_proto_msgTypes[49]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TransferFlags.ProtoReflect.Descriptor instead.
+func (*TransferFlags) Descriptor() ([]byte, []int) {
+	return file_proto_tigerbeetle_proto_rawDescGZIP(), []int{49}
+}
+
+func (x *TransferFlags) GetLinked() bool {
+	if x != nil && x.Linked != nil {
+		return *x.Linked
+	}
+	return false
+}
+
+func (x *TransferFlags) GetPending() bool {
+	if x != nil && x.Pending != nil {
+		return *x.Pending
+	}
+	return false
+}
+
+func (x *TransferFlags) GetPostPendingTransfer() bool {
+	if x != nil && x.PostPendingTransfer != nil {
+		return *x.PostPendingTransfer
+	}
+	return false
+}
+
+func (x *TransferFlags) GetVoidPendingTransfer() bool {
+	if x != nil && x.VoidPendingTransfer != nil {
+		return *x.VoidPendingTransfer
+	}
+	return false
+}
+
+func (x *TransferFlags) GetBalancingDebit() bool {
+	if x != nil && x.BalancingDebit != nil {
+		return *x.BalancingDebit
+	}
+	return false
+}
+
+func (x *TransferFlags) GetBalancingCredit() bool {
+	if x != nil && x.BalancingCredit != nil {
+		return *x.BalancingCredit
+	}
+	return false
+}
+
+func (x *TransferFlags) GetImported() bool {
+	if x != nil && x.Imported != nil {
+		return *x.Imported
+	}
+	return false
+}
+
+func (x *TransferFlags) GetClosingDebit() bool {
+	if x != nil && x.ClosingDebit != nil {
+		return *x.ClosingDebit
+	}
+	return false
+}
+
+func (x *TransferFlags) GetClosingCredit() bool {
+	if x != nil && x.ClosingCredit != nil {
+		return *x.ClosingCredit
+	}
+	return false
+}
+
+type AccountFilter struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	AccountId     string                 `protobuf:"bytes,1,opt,name=account_id,json=accountId,proto3" json:"account_id,omitempty"`
+	TimestampMin  *uint64                `protobuf:"varint,2,opt,name=timestamp_min,json=timestampMin,proto3,oneof" json:"timestamp_min,omitempty"`
+	TimestampMax  *uint64                `protobuf:"varint,3,opt,name=timestamp_max,json=timestampMax,proto3,oneof" json:"timestamp_max,omitempty"`
+	Limit         uint32                 `protobuf:"varint,4,opt,name=limit,proto3" json:"limit,omitempty"`
+	Flags         *AccountFilterFlags    `protobuf:"bytes,5,opt,name=flags,proto3,oneof" json:"flags,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AccountFilter) Reset() {
+	*x = AccountFilter{}
+	mi := &file_proto_tigerbeetle_proto_msgTypes[50]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AccountFilter) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AccountFilter) ProtoMessage() {}
+
+func (x *AccountFilter) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_tigerbeetle_proto_msgTypes[50]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AccountFilter.ProtoReflect.Descriptor instead.
+func (*AccountFilter) Descriptor() ([]byte, []int) {
+	return file_proto_tigerbeetle_proto_rawDescGZIP(), []int{50}
+}
+
+func (x *AccountFilter) GetAccountId() string {
+	if x != nil {
+		return x.AccountId
+	}
+	return ""
+}
+
+func (x *AccountFilter) GetTimestampMin() uint64 {
+	if x != nil && x.TimestampMin != nil {
+		return *x.TimestampMin
+	}
+	return 0
+}
+
+func (x *AccountFilter) GetTimestampMax() uint64 {
+	if x != nil && x.TimestampMax != nil {
+		return *x.TimestampMax
+	}
+	return 0
+}
+
+func (x *AccountFilter) GetLimit() uint32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+func (x *AccountFilter) GetFlags() *AccountFilterFlags {
+	if x != nil {
+		return x.Flags
+	}
+	return nil
+}
+
+type AccountFilterFlags struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Debits        *bool                  `protobuf:"varint,1,opt,name=debits,proto3,oneof" json:"debits,omitempty"`
+	Credits       *bool                  `protobuf:"varint,2,opt,name=credits,proto3,oneof" json:"credits,omitempty"`
+	Reversed      *bool                  `protobuf:"varint,3,opt,name=reversed,proto3,oneof" json:"reversed,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AccountFilterFlags) Reset() {
+	*x = AccountFilterFlags{}
+	mi := &file_proto_tigerbeetle_proto_msgTypes[51]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AccountFilterFlags) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AccountFilterFlags) ProtoMessage() {}
+
+func (x *AccountFilterFlags) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_tigerbeetle_proto_msgTypes[51]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AccountFilterFlags.ProtoReflect.Descriptor instead.
+func (*AccountFilterFlags) Descriptor() ([]byte, []int) {
+	return file_proto_tigerbeetle_proto_rawDescGZIP(), []int{51}
+}
+
+func (x *AccountFilterFlags) GetDebits() bool {
+	if x != nil && x.Debits != nil {
+		return *x.Debits
+	}
+	return false
+}
+
+func (x *AccountFilterFlags) GetCredits() bool {
+	if x != nil && x.Credits != nil {
+		return *x.Credits
+	}
+	return false
+}
+
+func (x *AccountFilterFlags) GetReversed() bool {
+	if x != nil && x.Reversed != nil {
+		return *x.Reversed
+	}
+	return false
+}
+
+type AccountBalance struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	DebitsPending  uint64                 `protobuf:"varint,1,opt,name=debits_pending,json=debitsPending,proto3" json:"debits_pending,omitempty"`
+	DebitsPosted   uint64                 `protobuf:"varint,2,opt,name=debits_posted,json=debitsPosted,proto3" json:"debits_posted,omitempty"`
+	CreditsPending uint64                 `protobuf:"varint,3,opt,name=credits_pending,json=creditsPending,proto3" json:"credits_pending,omitempty"`
+	CreditsPosted  uint64                 `protobuf:"varint,4,opt,name=credits_posted,json=creditsPosted,proto3" json:"credits_posted,omitempty"`
+	Timestamp      uint64                 `protobuf:"varint,5,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	// Balances as decimals with the asset scale of the ledger in the registry
+	DebitsPendingDecimal  *string `protobuf:"bytes,6,opt,name=debits_pending_decimal,json=debitsPendingDecimal,proto3,oneof" json:"debits_pending_decimal,omitempty"`
+	DebitsPostedDecimal   *string `protobuf:"bytes,7,opt,name=debits_posted_decimal,json=debitsPostedDecimal,proto3,oneof" json:"debits_posted_decimal,omitempty"`
+	CreditsPendingDecimal *string `protobuf:"bytes,8,opt,name=credits_pending_decimal,json=creditsPendingDecimal,proto3,oneof" json:"credits_pending_decimal,omitempty"`
+	CreditsPostedDecimal  *string `protobuf:"bytes,9,opt,name=credits_posted_decimal,json=creditsPostedDecimal,proto3,oneof" json:"credits_posted_decimal,omitempty"`
+	unknownFields         protoimpl.UnknownFields
+	sizeCache             protoimpl.SizeCache
+}
+
+func (x *AccountBalance) Reset() {
+	*x = AccountBalance{}
+	mi := &file_proto_tigerbeetle_proto_msgTypes[52]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AccountBalance) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AccountBalance) ProtoMessage() {}
+
+func (x *AccountBalance) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_tigerbeetle_proto_msgTypes[52]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AccountBalance.ProtoReflect.Descriptor instead.
+func (*AccountBalance) Descriptor() ([]byte, []int) {
+	return file_proto_tigerbeetle_proto_rawDescGZIP(), []int{52}
+}
+
+func (x *AccountBalance) GetDebitsPending() uint64 {
+	if x != nil {
+		return x.DebitsPending
+	}
+	return 0
+}
+
+func (x *AccountBalance) GetDebitsPosted() uint64 {
+	if x != nil {
+		return x.DebitsPosted
+	}
+	return 0
+}
+
+func (x *AccountBalance) GetCreditsPending() uint64 {
+	if x != nil {
+		return x.CreditsPending
+	}
+	return 0
+}
+
+func (x *AccountBalance) GetCreditsPosted() uint64 {
+	if x != nil {
+		return x.CreditsPosted
+	}
+	return 0
+}
+
+func (x *AccountBalance) GetTimestamp() uint64 {
+	if x != nil {
+		return x.Timestamp
+	}
+	return 0
 }
 
-func (x *AccountFlags) GetHistory() bool {
-	if x != nil && x.History != nil {
-		return *x.History
+func (x *AccountBalance) GetDebitsPendingDecimal() string {
+	if x != nil && x.DebitsPendingDecimal != nil {
+		return *x.DebitsPendingDecimal
 	}
-	return false
+	return ""
 }
 
-type Transfer struct {
-	state           protoimpl.MessageState `protogen:"open.v1"`
-	Id              string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
-	DebitAccountId  string                 `protobuf:"bytes,2,opt,name=debit_account_id,json=debitAccountId,proto3" json:"debit_account_id,omitempty"`
-	CreditAccountId string                 `protobuf:"bytes,3,opt,name=credit_account_id,json=creditAccountId,proto3" json:"credit_account_id,omitempty"`
-	Amount          int64                  `protobuf:"varint,4,opt,name=amount,proto3" json:"amount,omitempty"`
-	PendingId       *string                `protobuf:"bytes,5,opt,name=pending_id,json=pendingId,proto3,oneof" json:"pending_id,omitempty"`
-	UserData128     string                 `protobuf:"bytes,6,opt,name=user_data128,json=userData128,proto3" json:"user_data128,omitempty"`
-	UserData64      uint64                 `protobuf:"varint,7,opt,name=user_data64,json=userData64,proto3" json:"user_data64,omitempty"`
-	UserData32      uint32                 `protobuf:"varint,8,opt,name=user_data32,json=userData32,proto3" json:"user_data32,omitempty"`
-	Ledger          uint32                 `protobuf:"varint,9,opt,name=ledger,proto3" json:"ledger,omitempty"`
-	Code            uint32                 `protobuf:"varint,10,opt,name=code,proto3" json:"code,omitempty"`
-	TransferFlags   *TransferFlags         `protobuf:"bytes,11,opt,name=transfer_flags,json=transferFlags,proto3" json:"transfer_flags,omitempty"`
-	Timestamp       *uint64                `protobuf:"varint,13,opt,name=timestamp,proto3,oneof" json:"timestamp,omitempty"`
-	unknownFields   protoimpl.UnknownFields
-	sizeCache       protoimpl.SizeCache
+func (x *AccountBalance) GetDebitsPostedDecimal() string {
+	if x != nil && x.DebitsPostedDecimal != nil {
+		return *x.DebitsPostedDecimal
+	}
+	return ""
 }
 
-func (x *Transfer) Reset() {
-	*x = Transfer{}
-	mi := &file_proto_tigerbeetle_proto_msgTypes[22]
+func (x *AccountBalance) GetCreditsPendingDecimal() string {
+	if x != nil && x.CreditsPendingDecimal != nil {
+		return *x.CreditsPendingDecimal
+	}
+	return ""
+}
+
+func (x *AccountBalance) GetCreditsPostedDecimal() string {
+	if x != nil && x.CreditsPostedDecimal != nil {
+		return *x.CreditsPostedDecimal
+	}
+	return ""
+}
+
+type BalanceAt struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	AccountId      string                 `protobuf:"bytes,1,opt,name=account_id,json=accountId,proto3" json:"account_id,omitempty"`
+	DebitsPending  uint64                 `protobuf:"varint,2,opt,name=debits_pending,json=debitsPending,proto3" json:"debits_pending,omitempty"`
+	DebitsPosted   uint64                 `protobuf:"varint,3,opt,name=debits_posted,json=debitsPosted,proto3" json:"debits_posted,omitempty"`
+	CreditsPending uint64                 `protobuf:"varint,4,opt,name=credits_pending,json=creditsPending,proto3" json:"credits_pending,omitempty"`
+	CreditsPosted  uint64                 `protobuf:"varint,5,opt,name=credits_posted,json=creditsPosted,proto3" json:"credits_posted,omitempty"`
+	// credits_posted - debits_posted
+	Balance int64 `protobuf:"varint,6,opt,name=balance,proto3" json:"balance,omitempty"`
+	// Timestamp of the snapshot or last transfer the balance was derived from
+	Timestamp uint64        `protobuf:"varint,7,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	Source    BalanceSource `protobuf:"varint,8,opt,name=source,proto3,enum=proto.BalanceSource" json:"source,omitempty"`
+	// Balances as decimals with the asset scale of the ledger in the registry
+	DebitsPendingDecimal  *string `protobuf:"bytes,9,opt,name=debits_pending_decimal,json=debitsPendingDecimal,proto3,oneof" json:"debits_pending_decimal,omitempty"`
+	DebitsPostedDecimal   *string `protobuf:"bytes,10,opt,name=debits_posted_decimal,json=debitsPostedDecimal,proto3,oneof" json:"debits_posted_decimal,omitempty"`
+	CreditsPendingDecimal *string `protobuf:"bytes,11,opt,name=credits_pending_decimal,json=creditsPendingDecimal,proto3,oneof" json:"credits_pending_decimal,omitempty"`
+	CreditsPostedDecimal  *string `protobuf:"bytes,12,opt,name=credits_posted_decimal,json=creditsPostedDecimal,proto3,oneof" json:"credits_posted_decimal,omitempty"`
+	BalanceDecimal        *string `protobuf:"bytes,13,opt,name=balance_decimal,json=balanceDecimal,proto3,oneof" json:"balance_decimal,omitempty"`
+	unknownFields         protoimpl.UnknownFields
+	sizeCache             protoimpl.SizeCache
+}
+
+func (x *BalanceAt) Reset() {
+	*x = BalanceAt{}
+	mi := &file_proto_tigerbeetle_proto_msgTypes[53]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *Transfer) String() string {
+func (x *BalanceAt) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*Transfer) ProtoMessage() {}
+func (*BalanceAt) ProtoMessage() {}
 
-func (x *Transfer) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_tigerbeetle_proto_msgTypes[22]
+func (x *BalanceAt) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_tigerbeetle_proto_msgTypes[53]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1530,122 +3996,402 @@ func (x *Transfer) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use Transfer.ProtoReflect.Descriptor instead.
-func (*Transfer) Descriptor() ([]byte, []int) {
-	return file_proto_tigerbeetle_proto_rawDescGZIP(), []int{22}
+// Deprecated: Use BalanceAt.ProtoReflect.Descriptor instead.
+func (*BalanceAt) Descriptor() ([]byte, []int) {
+	return file_proto_tigerbeetle_proto_rawDescGZIP(), []int{53}
 }
 
-func (x *Transfer) GetId() string {
+func (x *BalanceAt) GetAccountId() string {
 	if x != nil {
-		return x.Id
+		return x.AccountId
 	}
 	return ""
 }
 
-func (x *Transfer) GetDebitAccountId() string {
+func (x *BalanceAt) GetDebitsPending() uint64 {
 	if x != nil {
-		return x.DebitAccountId
+		return x.DebitsPending
+	}
+	return 0
+}
+
+func (x *BalanceAt) GetDebitsPosted() uint64 {
+	if x != nil {
+		return x.DebitsPosted
+	}
+	return 0
+}
+
+func (x *BalanceAt) GetCreditsPending() uint64 {
+	if x != nil {
+		return x.CreditsPending
+	}
+	return 0
+}
+
+func (x *BalanceAt) GetCreditsPosted() uint64 {
+	if x != nil {
+		return x.CreditsPosted
+	}
+	return 0
+}
+
+func (x *BalanceAt) GetBalance() int64 {
+	if x != nil {
+		return x.Balance
+	}
+	return 0
+}
+
+func (x *BalanceAt) GetTimestamp() uint64 {
+	if x != nil {
+		return x.Timestamp
+	}
+	return 0
+}
+
+func (x *BalanceAt) GetSource() BalanceSource {
+	if x != nil {
+		return x.Source
+	}
+	return BalanceSource_BalanceSourceCurrent
+}
+
+func (x *BalanceAt) GetDebitsPendingDecimal() string {
+	if x != nil && x.DebitsPendingDecimal != nil {
+		return *x.DebitsPendingDecimal
 	}
 	return ""
 }
 
-func (x *Transfer) GetCreditAccountId() string {
+func (x *BalanceAt) GetDebitsPostedDecimal() string {
+	if x != nil && x.DebitsPostedDecimal != nil {
+		return *x.DebitsPostedDecimal
+	}
+	return ""
+}
+
+func (x *BalanceAt) GetCreditsPendingDecimal() string {
+	if x != nil && x.CreditsPendingDecimal != nil {
+		return *x.CreditsPendingDecimal
+	}
+	return ""
+}
+
+func (x *BalanceAt) GetCreditsPostedDecimal() string {
+	if x != nil && x.CreditsPostedDecimal != nil {
+		return *x.CreditsPostedDecimal
+	}
+	return ""
+}
+
+func (x *BalanceAt) GetBalanceDecimal() string {
+	if x != nil && x.BalanceDecimal != nil {
+		return *x.BalanceDecimal
+	}
+	return ""
+}
+
+type StatementLine struct {
+	state    protoimpl.MessageState `protogen:"open.v1"`
+	Transfer *Transfer              `protobuf:"bytes,1,opt,name=transfer,proto3" json:"transfer,omitempty"`
+	// Change of credits_posted - debits_posted
+	PostedAmount int64 `protobuf:"varint,2,opt,name=posted_amount,json=postedAmount,proto3" json:"posted_amount,omitempty"`
+	// Change of credits_pending - debits_pending
+	PendingAmount int64 `protobuf:"varint,3,opt,name=pending_amount,json=pendingAmount,proto3" json:"pending_amount,omitempty"`
+	// Running credits_posted - debits_posted
+	Balance int64 `protobuf:"varint,4,opt,name=balance,proto3" json:"balance,omitempty"`
+	// Running credits_pending - debits_pending
+	PendingBalance int64 `protobuf:"varint,5,opt,name=pending_balance,json=pendingBalance,proto3" json:"pending_balance,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *StatementLine) Reset() {
+	*x = StatementLine{}
+	mi := &file_proto_tigerbeetle_proto_msgTypes[54]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StatementLine) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StatementLine) ProtoMessage() {}
+
+func (x *StatementLine) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_tigerbeetle_proto_msgTypes[54]
 	if x != nil {
-		return x.CreditAccountId
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StatementLine.ProtoReflect.Descriptor instead.
+func (*StatementLine) Descriptor() ([]byte, []int) {
+	return file_proto_tigerbeetle_proto_rawDescGZIP(), []int{54}
+}
+
+func (x *StatementLine) GetTransfer() *Transfer {
+	if x != nil {
+		return x.Transfer
+	}
+	return nil
+}
+
+func (x *StatementLine) GetPostedAmount() int64 {
+	if x != nil {
+		return x.PostedAmount
+	}
+	return 0
+}
+
+func (x *StatementLine) GetPendingAmount() int64 {
+	if x != nil {
+		return x.PendingAmount
+	}
+	return 0
+}
+
+func (x *StatementLine) GetBalance() int64 {
+	if x != nil {
+		return x.Balance
+	}
+	return 0
+}
+
+func (x *StatementLine) GetPendingBalance() int64 {
+	if x != nil {
+		return x.PendingBalance
+	}
+	return 0
+}
+
+// Amounts are decimal strings as their sum may not fit in 64 bits
+type LedgerSummaryRow struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	Code           *uint32                `protobuf:"varint,1,opt,name=code,proto3,oneof" json:"code,omitempty"`
+	UserData128    *string                `protobuf:"bytes,2,opt,name=user_data128,json=userData128,proto3,oneof" json:"user_data128,omitempty"`
+	UserData64     *uint64                `protobuf:"varint,3,opt,name=user_data64,json=userData64,proto3,oneof" json:"user_data64,omitempty"`
+	UserData32     *uint32                `protobuf:"varint,4,opt,name=user_data32,json=userData32,proto3,oneof" json:"user_data32,omitempty"`
+	Accounts       uint64                 `protobuf:"varint,5,opt,name=accounts,proto3" json:"accounts,omitempty"`
+	DebitsPending  string                 `protobuf:"bytes,6,opt,name=debits_pending,json=debitsPending,proto3" json:"debits_pending,omitempty"`
+	DebitsPosted   string                 `protobuf:"bytes,7,opt,name=debits_posted,json=debitsPosted,proto3" json:"debits_posted,omitempty"`
+	CreditsPending string                 `protobuf:"bytes,8,opt,name=credits_pending,json=creditsPending,proto3" json:"credits_pending,omitempty"`
+	CreditsPosted  string                 `protobuf:"bytes,9,opt,name=credits_posted,json=creditsPosted,proto3" json:"credits_posted,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *LedgerSummaryRow) Reset() {
+	*x = LedgerSummaryRow{}
+	mi := &file_proto_tigerbeetle_proto_msgTypes[55]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *LedgerSummaryRow) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LedgerSummaryRow) ProtoMessage() {}
+
+func (x *LedgerSummaryRow) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_tigerbeetle_proto_msgTypes[55]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LedgerSummaryRow.ProtoReflect.Descriptor instead.
+func (*LedgerSummaryRow) Descriptor() ([]byte, []int) {
+	return file_proto_tigerbeetle_proto_rawDescGZIP(), []int{55}
+}
+
+func (x *LedgerSummaryRow) GetCode() uint32 {
+	if x != nil && x.Code != nil {
+		return *x.Code
+	}
+	return 0
+}
+
+func (x *LedgerSummaryRow) GetUserData128() string {
+	if x != nil && x.UserData128 != nil {
+		return *x.UserData128
 	}
 	return ""
 }
 
-func (x *Transfer) GetAmount() int64 {
+func (x *LedgerSummaryRow) GetUserData64() uint64 {
+	if x != nil && x.UserData64 != nil {
+		return *x.UserData64
+	}
+	return 0
+}
+
+func (x *LedgerSummaryRow) GetUserData32() uint32 {
+	if x != nil && x.UserData32 != nil {
+		return *x.UserData32
+	}
+	return 0
+}
+
+func (x *LedgerSummaryRow) GetAccounts() uint64 {
 	if x != nil {
-		return x.Amount
+		return x.Accounts
 	}
 	return 0
 }
 
-func (x *Transfer) GetPendingId() string {
-	if x != nil && x.PendingId != nil {
-		return *x.PendingId
+func (x *LedgerSummaryRow) GetDebitsPending() string {
+	if x != nil {
+		return x.DebitsPending
 	}
 	return ""
 }
 
-func (x *Transfer) GetUserData128() string {
+func (x *LedgerSummaryRow) GetDebitsPosted() string {
 	if x != nil {
-		return x.UserData128
+		return x.DebitsPosted
 	}
 	return ""
 }
 
-func (x *Transfer) GetUserData64() uint64 {
+func (x *LedgerSummaryRow) GetCreditsPending() string {
 	if x != nil {
-		return x.UserData64
+		return x.CreditsPending
+	}
+	return ""
+}
+
+func (x *LedgerSummaryRow) GetCreditsPosted() string {
+	if x != nil {
+		return x.CreditsPosted
+	}
+	return ""
+}
+
+type AggregateTransfersGroup struct {
+	state      protoimpl.MessageState `protogen:"open.v1"`
+	Code       *uint32                `protobuf:"varint,1,opt,name=code,proto3,oneof" json:"code,omitempty"`
+	Ledger     *uint32                `protobuf:"varint,2,opt,name=ledger,proto3,oneof" json:"ledger,omitempty"`
+	UserData32 *uint32                `protobuf:"varint,3,opt,name=user_data32,json=userData32,proto3,oneof" json:"user_data32,omitempty"`
+	// Start of the bucket in nanoseconds
+	Bucket *uint64 `protobuf:"varint,4,opt,name=bucket,proto3,oneof" json:"bucket,omitempty"`
+	Count  uint64  `protobuf:"varint,5,opt,name=count,proto3" json:"count,omitempty"`
+	// Decimal string as the sum may not fit in 64 bits
+	Sum           string `protobuf:"bytes,6,opt,name=sum,proto3" json:"sum,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AggregateTransfersGroup) Reset() {
+	*x = AggregateTransfersGroup{}
+	mi := &file_proto_tigerbeetle_proto_msgTypes[56]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AggregateTransfersGroup) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AggregateTransfersGroup) ProtoMessage() {}
+
+func (x *AggregateTransfersGroup) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_tigerbeetle_proto_msgTypes[56]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AggregateTransfersGroup.ProtoReflect.Descriptor instead.
+func (*AggregateTransfersGroup) Descriptor() ([]byte, []int) {
+	return file_proto_tigerbeetle_proto_rawDescGZIP(), []int{56}
+}
+
+func (x *AggregateTransfersGroup) GetCode() uint32 {
+	if x != nil && x.Code != nil {
+		return *x.Code
 	}
 	return 0
 }
 
-func (x *Transfer) GetUserData32() uint32 {
-	if x != nil {
-		return x.UserData32
+func (x *AggregateTransfersGroup) GetLedger() uint32 {
+	if x != nil && x.Ledger != nil {
+		return *x.Ledger
 	}
 	return 0
 }
 
-func (x *Transfer) GetLedger() uint32 {
-	if x != nil {
-		return x.Ledger
+func (x *AggregateTransfersGroup) GetUserData32() uint32 {
+	if x != nil && x.UserData32 != nil {
+		return *x.UserData32
 	}
 	return 0
 }
 
-func (x *Transfer) GetCode() uint32 {
-	if x != nil {
-		return x.Code
+func (x *AggregateTransfersGroup) GetBucket() uint64 {
+	if x != nil && x.Bucket != nil {
+		return *x.Bucket
 	}
 	return 0
 }
 
-func (x *Transfer) GetTransferFlags() *TransferFlags {
+func (x *AggregateTransfersGroup) GetCount() uint64 {
 	if x != nil {
-		return x.TransferFlags
+		return x.Count
 	}
-	return nil
+	return 0
 }
 
-func (x *Transfer) GetTimestamp() uint64 {
-	if x != nil && x.Timestamp != nil {
-		return *x.Timestamp
+func (x *AggregateTransfersGroup) GetSum() string {
+	if x != nil {
+		return x.Sum
 	}
-	return 0
+	return ""
 }
 
-type TransferFlags struct {
-	state               protoimpl.MessageState `protogen:"open.v1"`
-	Linked              *bool                  `protobuf:"varint,1,opt,name=linked,proto3,oneof" json:"linked,omitempty"`
-	Pending             *bool                  `protobuf:"varint,2,opt,name=pending,proto3,oneof" json:"pending,omitempty"`
-	PostPendingTransfer *bool                  `protobuf:"varint,3,opt,name=post_pending_transfer,json=postPendingTransfer,proto3,oneof" json:"post_pending_transfer,omitempty"`
-	VoidPendingTransfer *bool                  `protobuf:"varint,4,opt,name=void_pending_transfer,json=voidPendingTransfer,proto3,oneof" json:"void_pending_transfer,omitempty"`
-	BalancingDebit      *bool                  `protobuf:"varint,5,opt,name=balancing_debit,json=balancingDebit,proto3,oneof" json:"balancing_debit,omitempty"`
-	BalancingCredit     *bool                  `protobuf:"varint,6,opt,name=balancing_credit,json=balancingCredit,proto3,oneof" json:"balancing_credit,omitempty"`
-	unknownFields       protoimpl.UnknownFields
-	sizeCache           protoimpl.SizeCache
+type LedgerInfo struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            uint32                 `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Name          string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Currency      string                 `protobuf:"bytes,3,opt,name=currency,proto3" json:"currency,omitempty"`
+	AssetScale    uint32                 `protobuf:"varint,4,opt,name=asset_scale,json=assetScale,proto3" json:"asset_scale,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
 }
 
-func (x *TransferFlags) Reset() {
-	*x = TransferFlags{}
-	mi := &file_proto_tigerbeetle_proto_msgTypes[23]
+func (x *LedgerInfo) Reset() {
+	*x = LedgerInfo{}
+	mi := &file_proto_tigerbeetle_proto_msgTypes[57]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *TransferFlags) String() string {
+func (x *LedgerInfo) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*TransferFlags) ProtoMessage() {}
+func (*LedgerInfo) ProtoMessage() {}
 
-func (x *TransferFlags) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_tigerbeetle_proto_msgTypes[23]
+func (x *LedgerInfo) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_tigerbeetle_proto_msgTypes[57]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1656,79 +4402,65 @@ func (x *TransferFlags) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use TransferFlags.ProtoReflect.Descriptor instead.
-func (*TransferFlags) Descriptor() ([]byte, []int) {
-	return file_proto_tigerbeetle_proto_rawDescGZIP(), []int{23}
-}
-
-func (x *TransferFlags) GetLinked() bool {
-	if x != nil && x.Linked != nil {
-		return *x.Linked
-	}
-	return false
-}
-
-func (x *TransferFlags) GetPending() bool {
-	if x != nil && x.Pending != nil {
-		return *x.Pending
-	}
-	return false
+// Deprecated: Use LedgerInfo.ProtoReflect.Descriptor instead.
+func (*LedgerInfo) Descriptor() ([]byte, []int) {
+	return file_proto_tigerbeetle_proto_rawDescGZIP(), []int{57}
 }
 
-func (x *TransferFlags) GetPostPendingTransfer() bool {
-	if x != nil && x.PostPendingTransfer != nil {
-		return *x.PostPendingTransfer
+func (x *LedgerInfo) GetId() uint32 {
+	if x != nil {
+		return x.Id
 	}
-	return false
+	return 0
 }
 
-func (x *TransferFlags) GetVoidPendingTransfer() bool {
-	if x != nil && x.VoidPendingTransfer != nil {
-		return *x.VoidPendingTransfer
+func (x *LedgerInfo) GetName() string {
+	if x != nil {
+		return x.Name
 	}
-	return false
+	return ""
 }
 
-func (x *TransferFlags) GetBalancingDebit() bool {
-	if x != nil && x.BalancingDebit != nil {
-		return *x.BalancingDebit
+func (x *LedgerInfo) GetCurrency() string {
+	if x != nil {
+		return x.Currency
 	}
-	return false
+	return ""
 }
 
-func (x *TransferFlags) GetBalancingCredit() bool {
-	if x != nil && x.BalancingCredit != nil {
-		return *x.BalancingCredit
+func (x *LedgerInfo) GetAssetScale() uint32 {
+	if x != nil {
+		return x.AssetScale
 	}
-	return false
+	return 0
 }
 
-type AccountFilter struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	AccountId     string                 `protobuf:"bytes,1,opt,name=account_id,json=accountId,proto3" json:"account_id,omitempty"`
-	TimestampMin  *uint64                `protobuf:"varint,2,opt,name=timestamp_min,json=timestampMin,proto3,oneof" json:"timestamp_min,omitempty"`
-	TimestampMax  *uint64                `protobuf:"varint,3,opt,name=timestamp_max,json=timestampMax,proto3,oneof" json:"timestamp_max,omitempty"`
-	Limit         uint32                 `protobuf:"varint,4,opt,name=limit,proto3" json:"limit,omitempty"`
-	Flags         *AccountFilterFlags    `protobuf:"bytes,5,opt,name=flags,proto3,oneof" json:"flags,omitempty"`
+type CodeInfo struct {
+	state       protoimpl.MessageState `protogen:"open.v1"`
+	Id          uint32                 `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Name        string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Description string                 `protobuf:"bytes,3,opt,name=description,proto3" json:"description,omitempty"`
+	// Empty when the code is allowed on every ledger
+	Ledgers       []uint32 `protobuf:"varint,4,rep,packed,name=ledgers,proto3" json:"ledgers,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *AccountFilter) Reset() {
-	*x = AccountFilter{}
-	mi := &file_proto_tigerbeetle_proto_msgTypes[24]
+func (x *CodeInfo) Reset() {
+	*x = CodeInfo{}
+	mi := &file_proto_tigerbeetle_proto_msgTypes[58]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *AccountFilter) String() string {
+func (x *CodeInfo) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*AccountFilter) ProtoMessage() {}
+func (*CodeInfo) ProtoMessage() {}
 
-func (x *AccountFilter) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_tigerbeetle_proto_msgTypes[24]
+func (x *CodeInfo) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_tigerbeetle_proto_msgTypes[58]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1739,70 +4471,62 @@ func (x *AccountFilter) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use AccountFilter.ProtoReflect.Descriptor instead.
-func (*AccountFilter) Descriptor() ([]byte, []int) {
-	return file_proto_tigerbeetle_proto_rawDescGZIP(), []int{24}
+// Deprecated: Use CodeInfo.ProtoReflect.Descriptor instead.
+func (*CodeInfo) Descriptor() ([]byte, []int) {
+	return file_proto_tigerbeetle_proto_rawDescGZIP(), []int{58}
 }
 
-func (x *AccountFilter) GetAccountId() string {
+func (x *CodeInfo) GetId() uint32 {
 	if x != nil {
-		return x.AccountId
-	}
-	return ""
-}
-
-func (x *AccountFilter) GetTimestampMin() uint64 {
-	if x != nil && x.TimestampMin != nil {
-		return *x.TimestampMin
+		return x.Id
 	}
 	return 0
 }
 
-func (x *AccountFilter) GetTimestampMax() uint64 {
-	if x != nil && x.TimestampMax != nil {
-		return *x.TimestampMax
+func (x *CodeInfo) GetName() string {
+	if x != nil {
+		return x.Name
 	}
-	return 0
+	return ""
 }
 
-func (x *AccountFilter) GetLimit() uint32 {
+func (x *CodeInfo) GetDescription() string {
 	if x != nil {
-		return x.Limit
+		return x.Description
 	}
-	return 0
+	return ""
 }
 
-func (x *AccountFilter) GetFlags() *AccountFilterFlags {
+func (x *CodeInfo) GetLedgers() []uint32 {
 	if x != nil {
-		return x.Flags
+		return x.Ledgers
 	}
 	return nil
 }
 
-type AccountFilterFlags struct {
+type Alias struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Debits        *bool                  `protobuf:"varint,1,opt,name=debits,proto3,oneof" json:"debits,omitempty"`
-	Credits       *bool                  `protobuf:"varint,2,opt,name=credits,proto3,oneof" json:"credits,omitempty"`
-	Reversed      *bool                  `protobuf:"varint,3,opt,name=reversed,proto3,oneof" json:"reversed,omitempty"`
+	Alias         string                 `protobuf:"bytes,1,opt,name=alias,proto3" json:"alias,omitempty"`
+	Id            string                 `protobuf:"bytes,2,opt,name=id,proto3" json:"id,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *AccountFilterFlags) Reset() {
-	*x = AccountFilterFlags{}
-	mi := &file_proto_tigerbeetle_proto_msgTypes[25]
+func (x *Alias) Reset() {
+	*x = Alias{}
+	mi := &file_proto_tigerbeetle_proto_msgTypes[59]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *AccountFilterFlags) String() string {
+func (x *Alias) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*AccountFilterFlags) ProtoMessage() {}
+func (*Alias) ProtoMessage() {}
 
-func (x *AccountFilterFlags) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_tigerbeetle_proto_msgTypes[25]
+func (x *Alias) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_tigerbeetle_proto_msgTypes[59]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1813,58 +4537,50 @@ func (x *AccountFilterFlags) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use AccountFilterFlags.ProtoReflect.Descriptor instead.
-func (*AccountFilterFlags) Descriptor() ([]byte, []int) {
-	return file_proto_tigerbeetle_proto_rawDescGZIP(), []int{25}
-}
-
-func (x *AccountFilterFlags) GetDebits() bool {
-	if x != nil && x.Debits != nil {
-		return *x.Debits
-	}
-	return false
+// Deprecated: Use Alias.ProtoReflect.Descriptor instead.
+func (*Alias) Descriptor() ([]byte, []int) {
+	return file_proto_tigerbeetle_proto_rawDescGZIP(), []int{59}
 }
 
-func (x *AccountFilterFlags) GetCredits() bool {
-	if x != nil && x.Credits != nil {
-		return *x.Credits
+func (x *Alias) GetAlias() string {
+	if x != nil {
+		return x.Alias
 	}
-	return false
+	return ""
 }
 
-func (x *AccountFilterFlags) GetReversed() bool {
-	if x != nil && x.Reversed != nil {
-		return *x.Reversed
+func (x *Alias) GetId() string {
+	if x != nil {
+		return x.Id
 	}
-	return false
+	return ""
 }
 
-type AccountBalance struct {
-	state          protoimpl.MessageState `protogen:"open.v1"`
-	DebitsPending  uint64                 `protobuf:"varint,1,opt,name=debits_pending,json=debitsPending,proto3" json:"debits_pending,omitempty"`
-	DebitsPosted   uint64                 `protobuf:"varint,2,opt,name=debits_posted,json=debitsPosted,proto3" json:"debits_posted,omitempty"`
-	CreditsPending uint64                 `protobuf:"varint,3,opt,name=credits_pending,json=creditsPending,proto3" json:"credits_pending,omitempty"`
-	CreditsPosted  uint64                 `protobuf:"varint,4,opt,name=credits_posted,json=creditsPosted,proto3" json:"credits_posted,omitempty"`
-	Timestamp      uint64                 `protobuf:"varint,5,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
-	unknownFields  protoimpl.UnknownFields
-	sizeCache      protoimpl.SizeCache
+type LedgerTotal struct {
+	state  protoimpl.MessageState `protogen:"open.v1"`
+	Ledger uint32                 `protobuf:"varint,1,opt,name=ledger,proto3" json:"ledger,omitempty"`
+	Amount uint64                 `protobuf:"varint,2,opt,name=amount,proto3" json:"amount,omitempty"`
+	// Replaces amount, with the asset scale of the ledger in the registry
+	AmountDecimal *string `protobuf:"bytes,3,opt,name=amount_decimal,json=amountDecimal,proto3,oneof" json:"amount_decimal,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
 }
 
-func (x *AccountBalance) Reset() {
-	*x = AccountBalance{}
-	mi := &file_proto_tigerbeetle_proto_msgTypes[26]
+func (x *LedgerTotal) Reset() {
+	*x = LedgerTotal{}
+	mi := &file_proto_tigerbeetle_proto_msgTypes[60]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *AccountBalance) String() string {
+func (x *LedgerTotal) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*AccountBalance) ProtoMessage() {}
+func (*LedgerTotal) ProtoMessage() {}
 
-func (x *AccountBalance) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_tigerbeetle_proto_msgTypes[26]
+func (x *LedgerTotal) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_tigerbeetle_proto_msgTypes[60]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1875,46 +4591,101 @@ func (x *AccountBalance) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use AccountBalance.ProtoReflect.Descriptor instead.
-func (*AccountBalance) Descriptor() ([]byte, []int) {
-	return file_proto_tigerbeetle_proto_rawDescGZIP(), []int{26}
+// Deprecated: Use LedgerTotal.ProtoReflect.Descriptor instead.
+func (*LedgerTotal) Descriptor() ([]byte, []int) {
+	return file_proto_tigerbeetle_proto_rawDescGZIP(), []int{60}
 }
 
-func (x *AccountBalance) GetDebitsPending() uint64 {
+func (x *LedgerTotal) GetLedger() uint32 {
 	if x != nil {
-		return x.DebitsPending
+		return x.Ledger
 	}
 	return 0
 }
 
-func (x *AccountBalance) GetDebitsPosted() uint64 {
+func (x *LedgerTotal) GetAmount() uint64 {
 	if x != nil {
-		return x.DebitsPosted
+		return x.Amount
 	}
 	return 0
 }
 
-func (x *AccountBalance) GetCreditsPending() uint64 {
+func (x *LedgerTotal) GetAmountDecimal() string {
+	if x != nil && x.AmountDecimal != nil {
+		return *x.AmountDecimal
+	}
+	return ""
+}
+
+type RestoreLedgerRejection struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// account or transfer
+	Kind          string `protobuf:"bytes,1,opt,name=kind,proto3" json:"kind,omitempty"`
+	Id            string `protobuf:"bytes,2,opt,name=id,proto3" json:"id,omitempty"`
+	Code          uint32 `protobuf:"varint,3,opt,name=code,proto3" json:"code,omitempty"`
+	Result        string `protobuf:"bytes,4,opt,name=result,proto3" json:"result,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RestoreLedgerRejection) Reset() {
+	*x = RestoreLedgerRejection{}
+	mi := &file_proto_tigerbeetle_proto_msgTypes[61]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RestoreLedgerRejection) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RestoreLedgerRejection) ProtoMessage() {}
+
+func (x *RestoreLedgerRejection) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_tigerbeetle_proto_msgTypes[61]
 	if x != nil {
-		return x.CreditsPending
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
 	}
-	return 0
+	return mi.MessageOf(x)
 }
 
-func (x *AccountBalance) GetCreditsPosted() uint64 {
+// Deprecated: Use RestoreLedgerRejection.ProtoReflect.Descriptor instead.
+func (*RestoreLedgerRejection) Descriptor() ([]byte, []int) {
+	return file_proto_tigerbeetle_proto_rawDescGZIP(), []int{61}
+}
+
+func (x *RestoreLedgerRejection) GetKind() string {
 	if x != nil {
-		return x.CreditsPosted
+		return x.Kind
 	}
-	return 0
+	return ""
 }
 
-func (x *AccountBalance) GetTimestamp() uint64 {
+func (x *RestoreLedgerRejection) GetId() string {
 	if x != nil {
-		return x.Timestamp
+		return x.Id
+	}
+	return ""
+}
+
+func (x *RestoreLedgerRejection) GetCode() uint32 {
+	if x != nil {
+		return x.Code
 	}
 	return 0
 }
 
+func (x *RestoreLedgerRejection) GetResult() string {
+	if x != nil {
+		return x.Result
+	}
+	return ""
+}
+
 type QueryFilter struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	UserData128   *string                `protobuf:"bytes,1,opt,name=user_data128,json=userData128,proto3,oneof" json:"user_data128,omitempty"`
@@ -1932,7 +4703,7 @@ type QueryFilter struct {
 
 func (x *QueryFilter) Reset() {
 	*x = QueryFilter{}
-	mi := &file_proto_tigerbeetle_proto_msgTypes[27]
+	mi := &file_proto_tigerbeetle_proto_msgTypes[62]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1944,7 +4715,7 @@ func (x *QueryFilter) String() string {
 func (*QueryFilter) ProtoMessage() {}
 
 func (x *QueryFilter) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_tigerbeetle_proto_msgTypes[27]
+	mi := &file_proto_tigerbeetle_proto_msgTypes[62]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1957,7 +4728,7 @@ func (x *QueryFilter) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use QueryFilter.ProtoReflect.Descriptor instead.
 func (*QueryFilter) Descriptor() ([]byte, []int) {
-	return file_proto_tigerbeetle_proto_rawDescGZIP(), []int{27}
+	return file_proto_tigerbeetle_proto_rawDescGZIP(), []int{62}
 }
 
 func (x *QueryFilter) GetUserData128() string {
@@ -2032,7 +4803,7 @@ type QueryFilterFlags struct {
 
 func (x *QueryFilterFlags) Reset() {
 	*x = QueryFilterFlags{}
-	mi := &file_proto_tigerbeetle_proto_msgTypes[28]
+	mi := &file_proto_tigerbeetle_proto_msgTypes[63]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -2044,7 +4815,7 @@ func (x *QueryFilterFlags) String() string {
 func (*QueryFilterFlags) ProtoMessage() {}
 
 func (x *QueryFilterFlags) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_tigerbeetle_proto_msgTypes[28]
+	mi := &file_proto_tigerbeetle_proto_msgTypes[63]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2057,7 +4828,7 @@ func (x *QueryFilterFlags) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use QueryFilterFlags.ProtoReflect.Descriptor instead.
 func (*QueryFilterFlags) Descriptor() ([]byte, []int) {
-	return file_proto_tigerbeetle_proto_rawDescGZIP(), []int{28}
+	return file_proto_tigerbeetle_proto_rawDescGZIP(), []int{63}
 }
 
 func (x *QueryFilterFlags) GetReversed() bool {
@@ -2071,8 +4842,10 @@ var File_proto_tigerbeetle_proto protoreflect.FileDescriptor
 
 const file_proto_tigerbeetle_proto_rawDesc = "" +
 	"\n" +
-	"\x17proto/tigerbeetle.proto\x12\x05proto\"\x0e\n" +
-	"\fGetIDRequest\"\x1c\n" +
+	"\x17proto/tigerbeetle.proto\x12\x05proto\"P\n" +
+	"\fGetIDRequest\x12,\n" +
+	"\x0fidempotency_key\x18\x01 \x01(\tH\x00R\x0eidempotencyKey\x88\x01\x01B\x12\n" +
+	"\x10_idempotency_key\"\x1c\n" +
 	"\n" +
 	"GetIDReply\x12\x0e\n" +
 	"\x02id\x18\x01 \x01(\tR\x02id\"C\n" +
@@ -2115,7 +4888,141 @@ const file_proto_tigerbeetle_proto_rawDesc = "" +
 	"\x14QueryAccountsRequest\x12*\n" +
 	"\x06filter\x18\x01 \x01(\v2\x12.proto.QueryFilterR\x06filter\"@\n" +
 	"\x12QueryAccountsReply\x12*\n" +
-	"\baccounts\x18\x01 \x03(\v2\x0e.proto.AccountR\baccounts\"\x8f\x03\n" +
+	"\baccounts\x18\x01 \x03(\v2\x0e.proto.AccountR\baccounts\"U\n" +
+	"\x14GetBalancesAtRequest\x12\x1f\n" +
+	"\vaccount_ids\x18\x01 \x03(\tR\n" +
+	"accountIds\x12\x1c\n" +
+	"\ttimestamp\x18\x02 \x01(\x04R\ttimestamp\"B\n" +
+	"\x12GetBalancesAtReply\x12,\n" +
+	"\bbalances\x18\x01 \x03(\v2\x10.proto.BalanceAtR\bbalances\"Y\n" +
+	"\x14LedgerSummaryRequest\x12\x16\n" +
+	"\x06ledger\x18\x01 \x01(\rR\x06ledger\x12)\n" +
+	"\bgroup_by\x18\x02 \x03(\x0e2\x0e.proto.GroupByR\agroupBy\"\x85\x01\n" +
+	"\x1aGetAccountStatementRequest\x12\x1d\n" +
+	"\n" +
+	"account_id\x18\x01 \x01(\tR\taccountId\x12#\n" +
+	"\rtimestamp_min\x18\x02 \x01(\x04R\ftimestampMin\x12#\n" +
+	"\rtimestamp_max\x18\x03 \x01(\x04R\ftimestampMax\"\xb0\x02\n" +
+	"\x18GetAccountStatementReply\x12(\n" +
+	"\aaccount\x18\x01 \x01(\v2\x0e.proto.AccountR\aaccount\x12#\n" +
+	"\rtimestamp_min\x18\x02 \x01(\x04R\ftimestampMin\x12#\n" +
+	"\rtimestamp_max\x18\x03 \x01(\x04R\ftimestampMax\x129\n" +
+	"\x0fopening_balance\x18\x04 \x01(\v2\x10.proto.BalanceAtR\x0eopeningBalance\x12*\n" +
+	"\x05lines\x18\x05 \x03(\v2\x14.proto.StatementLineR\x05lines\x129\n" +
+	"\x0fclosing_balance\x18\x06 \x01(\v2\x10.proto.BalanceAtR\x0eclosingBalance\"\xd3\x01\n" +
+	"\x19AggregateTransfersRequest\x12*\n" +
+	"\x06filter\x18\x01 \x01(\v2\x12.proto.QueryFilterR\x06filter\x12)\n" +
+	"\bgroup_by\x18\x02 \x03(\x0e2\x0e.proto.GroupByR\agroupBy\x12.\n" +
+	"\x06bucket\x18\x03 \x01(\x0e2\x16.proto.AggregateBucketR\x06bucket\x12\x14\n" +
+	"\x05limit\x18\x04 \x01(\rR\x05limit\x12\x19\n" +
+	"\bmax_scan\x18\x05 \x01(\x04R\amaxScan\"\xb0\x01\n" +
+	"\x17AggregateTransfersReply\x126\n" +
+	"\x06groups\x18\x01 \x03(\v2\x1e.proto.AggregateTransfersGroupR\x06groups\x12\x18\n" +
+	"\ascanned\x18\x02 \x01(\x04R\ascanned\x12\x1c\n" +
+	"\ttruncated\x18\x03 \x01(\bR\ttruncated\x12%\n" +
+	"\x0elast_timestamp\x18\x04 \x01(\x04R\rlastTimestamp\"\xa4\x01\n" +
+	"\x12LedgerSummaryReply\x12\x16\n" +
+	"\x06ledger\x18\x01 \x01(\rR\x06ledger\x12+\n" +
+	"\x04rows\x18\x02 \x03(\v2\x17.proto.LedgerSummaryRowR\x04rows\x12-\n" +
+	"\x05total\x18\x03 \x01(\v2\x17.proto.LedgerSummaryRowR\x05total\x12\x1a\n" +
+	"\bbalanced\x18\x04 \x01(\bR\bbalanced\")\n" +
+	"\x13ExportLedgerRequest\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\"a\n" +
+	"\x11ExportLedgerReply\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x1a\n" +
+	"\baccounts\x18\x02 \x01(\x04R\baccounts\x12\x1c\n" +
+	"\ttransfers\x18\x03 \x01(\x04R\ttransfers\"*\n" +
+	"\x14RestoreLedgerRequest\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\"\x89\x01\n" +
+	"\x12RestoreLedgerReply\x12\x1a\n" +
+	"\baccounts\x18\x01 \x01(\x04R\baccounts\x12\x1c\n" +
+	"\ttransfers\x18\x02 \x01(\x04R\ttransfers\x129\n" +
+	"\brejected\x18\x03 \x03(\v2\x1d.proto.RestoreLedgerRejectionR\brejected\"H\n" +
+	"\x14RegisterAliasRequest\x12\x14\n" +
+	"\x05alias\x18\x01 \x01(\tR\x05alias\x12\x13\n" +
+	"\x02id\x18\x02 \x01(\tH\x00R\x02id\x88\x01\x01B\x05\n" +
+	"\x03_id\"8\n" +
+	"\x12RegisterAliasReply\x12\"\n" +
+	"\x05alias\x18\x01 \x01(\v2\f.proto.AliasR\x05alias\"\x14\n" +
+	"\x12ListLedgersRequest\"f\n" +
+	"\x10ListLedgersReply\x12+\n" +
+	"\aledgers\x18\x01 \x03(\v2\x11.proto.LedgerInfoR\aledgers\x12%\n" +
+	"\x05codes\x18\x02 \x03(\v2\x0f.proto.CodeInfoR\x05codes\"0\n" +
+	"\x14LookupAliasesRequest\x12\x18\n" +
+	"\aaliases\x18\x01 \x03(\tR\aaliases\"<\n" +
+	"\x12LookupAliasesReply\x12&\n" +
+	"\aaliases\x18\x01 \x03(\v2\f.proto.AliasR\aaliases\"\xc3\x02\n" +
+	"\x1dCreateCompoundTransferRequest\x12#\n" +
+	"\x04legs\x18\x01 \x03(\v2\x0f.proto.TransferR\x04legs\x12\x16\n" +
+	"\x06ledger\x18\x02 \x01(\rR\x06ledger\x12\x12\n" +
+	"\x04code\x18\x03 \x01(\rR\x04code\x12!\n" +
+	"\fuser_data128\x18\x04 \x01(\tR\vuserData128\x12\x1f\n" +
+	"\vuser_data64\x18\x05 \x01(\x04R\n" +
+	"userData64\x12\x1f\n" +
+	"\vuser_data32\x18\x06 \x01(\rR\n" +
+	"userData32\x12,\n" +
+	"\x0fidempotency_key\x18\a \x01(\tH\x00R\x0eidempotencyKey\x88\x01\x01\x12*\n" +
+	"\x06totals\x18\b \x03(\v2\x12.proto.LedgerTotalR\x06totalsB\x12\n" +
+	"\x10_idempotency_key\"\xb8\x01\n" +
+	"\x1bCreateCompoundTransferReply\x12\x0e\n" +
+	"\x02ok\x18\x01 \x01(\bR\x02ok\x12\x10\n" +
+	"\x03ids\x18\x02 \x03(\tR\x03ids\x12-\n" +
+	"\x10failed_leg_index\x18\x03 \x01(\x05H\x00R\x0efailedLegIndex\x88\x01\x01\x123\n" +
+	"\x06result\x18\x04 \x01(\x0e2\x1b.proto.CreateTransferResultR\x06resultB\x13\n" +
+	"\x11_failed_leg_index\"\xba\x03\n" +
+	"\x0fExchangeRequest\x12*\n" +
+	"\x11source_account_id\x18\x01 \x01(\tR\x0fsourceAccountId\x124\n" +
+	"\x16destination_account_id\x18\x02 \x01(\tR\x14destinationAccountId\x12#\n" +
+	"\rsource_amount\x18\x03 \x01(\x04R\fsourceAmount\x127\n" +
+	"\x15source_amount_decimal\x18\x04 \x01(\tH\x00R\x13sourceAmountDecimal\x88\x01\x01\x12\x12\n" +
+	"\x04rate\x18\x05 \x01(\tR\x04rate\x12\x12\n" +
+	"\x04code\x18\x06 \x01(\rR\x04code\x12!\n" +
+	"\fuser_data128\x18\a \x01(\tR\vuserData128\x12\x1f\n" +
+	"\vuser_data64\x18\b \x01(\x04R\n" +
+	"userData64\x12\x1f\n" +
+	"\vuser_data32\x18\t \x01(\rR\n" +
+	"userData32\x12,\n" +
+	"\x0fidempotency_key\x18\n" +
+	" \x01(\tH\x01R\x0eidempotencyKey\x88\x01\x01B\x18\n" +
+	"\x16_source_amount_decimalB\x12\n" +
+	"\x10_idempotency_key\"\xc4\x03\n" +
+	"\rExchangeReply\x12\x0e\n" +
+	"\x02ok\x18\x01 \x01(\bR\x02ok\x12\x10\n" +
+	"\x03ids\x18\x02 \x03(\tR\x03ids\x12-\n" +
+	"\x10failed_leg_index\x18\x03 \x01(\x05H\x00R\x0efailedLegIndex\x88\x01\x01\x123\n" +
+	"\x06result\x18\x04 \x01(\x0e2\x1b.proto.CreateTransferResultR\x06result\x12#\n" +
+	"\rsource_ledger\x18\x05 \x01(\rR\fsourceLedger\x12-\n" +
+	"\x12destination_ledger\x18\x06 \x01(\rR\x11destinationLedger\x12#\n" +
+	"\rsource_amount\x18\a \x01(\x04R\fsourceAmount\x12-\n" +
+	"\x12destination_amount\x18\b \x01(\x04R\x11destinationAmount\x122\n" +
+	"\x15source_amount_decimal\x18\t \x01(\tR\x13sourceAmountDecimal\x12<\n" +
+	"\x1adestination_amount_decimal\x18\n" +
+	" \x01(\tR\x18destinationAmountDecimalB\x13\n" +
+	"\x11_failed_leg_index\"\xca\x01\n" +
+	"\x13CloseAccountRequest\x12\x1d\n" +
+	"\n" +
+	"account_id\x18\x01 \x01(\tR\taccountId\x12(\n" +
+	"\x10sweep_account_id\x18\x02 \x01(\tR\x0esweepAccountId\x12\x14\n" +
+	"\x05sweep\x18\x03 \x01(\bR\x05sweep\x12\x12\n" +
+	"\x04code\x18\x04 \x01(\rR\x04code\x12,\n" +
+	"\x0fidempotency_key\x18\x05 \x01(\tH\x00R\x0eidempotencyKey\x88\x01\x01B\x12\n" +
+	"\x10_idempotency_key\"\x93\x02\n" +
+	"\x11CloseAccountReply\x12\x0e\n" +
+	"\x02ok\x18\x01 \x01(\bR\x02ok\x12.\n" +
+	"\x13closing_transfer_id\x18\x02 \x01(\tR\x11closingTransferId\x12/\n" +
+	"\x11sweep_transfer_id\x18\x03 \x01(\tH\x00R\x0fsweepTransferId\x88\x01\x01\x12-\n" +
+	"\x10failed_leg_index\x18\x04 \x01(\x05H\x01R\x0efailedLegIndex\x88\x01\x01\x123\n" +
+	"\x06result\x18\x05 \x01(\x0e2\x1b.proto.CreateTransferResultR\x06resultB\x14\n" +
+	"\x12_sweep_transfer_idB\x13\n" +
+	"\x11_failed_leg_index\"\x88\x01\n" +
+	"\x14ReopenAccountRequest\x12.\n" +
+	"\x13closing_transfer_id\x18\x01 \x01(\tR\x11closingTransferId\x12,\n" +
+	"\x0fidempotency_key\x18\x02 \x01(\tH\x00R\x0eidempotencyKey\x88\x01\x01B\x12\n" +
+	"\x10_idempotency_key\"\x83\x01\n" +
+	"\x12ReopenAccountReply\x12\x0e\n" +
+	"\x02ok\x18\x01 \x01(\bR\x02ok\x12(\n" +
+	"\x10void_transfer_id\x18\x02 \x01(\tR\x0evoidTransferId\x123\n" +
+	"\x06result\x18\x03 \x01(\x0e2\x1b.proto.CreateTransferResultR\x06result\"\x8f\a\n" +
 	"\aAccount\x12\x0e\n" +
 	"\x02id\x18\x01 \x01(\tR\x02id\x12%\n" +
 	"\x0edebits_pending\x18\x02 \x01(\x04R\rdebitsPending\x12#\n" +
@@ -2131,17 +5038,37 @@ const file_proto_tigerbeetle_proto_rawDesc = "" +
 	"\x04code\x18\n" +
 	" \x01(\rR\x04code\x12)\n" +
 	"\x05flags\x18\v \x01(\v2\x13.proto.AccountFlagsR\x05flags\x12\x1c\n" +
-	"\ttimestamp\x18\f \x01(\x04R\ttimestamp\"\xb9\x02\n" +
+	"\ttimestamp\x18\f \x01(\x04R\ttimestamp\x12,\n" +
+	"\x0fidempotency_key\x18\r \x01(\tH\x00R\x0eidempotencyKey\x88\x01\x01\x12$\n" +
+	"\vledger_name\x18\x0e \x01(\tH\x01R\n" +
+	"ledgerName\x88\x01\x01\x12 \n" +
+	"\tcode_name\x18\x0f \x01(\tH\x02R\bcodeName\x88\x01\x01\x129\n" +
+	"\x16debits_pending_decimal\x18\x10 \x01(\tH\x03R\x14debitsPendingDecimal\x88\x01\x01\x127\n" +
+	"\x15debits_posted_decimal\x18\x11 \x01(\tH\x04R\x13debitsPostedDecimal\x88\x01\x01\x12;\n" +
+	"\x17credits_pending_decimal\x18\x12 \x01(\tH\x05R\x15creditsPendingDecimal\x88\x01\x01\x129\n" +
+	"\x16credits_posted_decimal\x18\x13 \x01(\tH\x06R\x14creditsPostedDecimal\x88\x01\x01B\x12\n" +
+	"\x10_idempotency_keyB\x0e\n" +
+	"\f_ledger_nameB\f\n" +
+	"\n" +
+	"_code_nameB\x19\n" +
+	"\x17_debits_pending_decimalB\x18\n" +
+	"\x16_debits_posted_decimalB\x1a\n" +
+	"\x18_credits_pending_decimalB\x19\n" +
+	"\x17_credits_posted_decimal\"\x8f\x03\n" +
 	"\fAccountFlags\x12\x1b\n" +
 	"\x06linked\x18\x01 \x01(\bH\x00R\x06linked\x88\x01\x01\x12G\n" +
 	"\x1edebits_must_not_exceed_credits\x18\x02 \x01(\bH\x01R\x1adebitsMustNotExceedCredits\x88\x01\x01\x12G\n" +
 	"\x1ecredits_must_not_exceed_debits\x18\x03 \x01(\bH\x02R\x1acreditsMustNotExceedDebits\x88\x01\x01\x12\x1d\n" +
-	"\ahistory\x18\x04 \x01(\bH\x03R\ahistory\x88\x01\x01B\t\n" +
+	"\ahistory\x18\x04 \x01(\bH\x03R\ahistory\x88\x01\x01\x12\x1f\n" +
+	"\bimported\x18\x05 \x01(\bH\x04R\bimported\x88\x01\x01\x12\x1b\n" +
+	"\x06closed\x18\x06 \x01(\bH\x05R\x06closed\x88\x01\x01B\t\n" +
 	"\a_linkedB!\n" +
 	"\x1f_debits_must_not_exceed_creditsB!\n" +
 	"\x1f_credits_must_not_exceed_debitsB\n" +
 	"\n" +
-	"\b_history\"\xba\x03\n" +
+	"\b_historyB\v\n" +
+	"\t_importedB\t\n" +
+	"\a_closed\"\xa4\x06\n" +
 	"\bTransfer\x12\x0e\n" +
 	"\x02id\x18\x01 \x01(\tR\x02id\x12(\n" +
 	"\x10debit_account_id\x18\x02 \x01(\tR\x0edebitAccountId\x12*\n" +
@@ -2158,24 +5085,45 @@ const file_proto_tigerbeetle_proto_rawDesc = "" +
 	"\x04code\x18\n" +
 	" \x01(\rR\x04code\x12;\n" +
 	"\x0etransfer_flags\x18\v \x01(\v2\x14.proto.TransferFlagsR\rtransferFlags\x12!\n" +
-	"\ttimestamp\x18\r \x01(\x04H\x01R\ttimestamp\x88\x01\x01B\r\n" +
+	"\ttimestamp\x18\r \x01(\x04H\x01R\ttimestamp\x88\x01\x01\x12,\n" +
+	"\x0fidempotency_key\x18\x0e \x01(\tH\x02R\x0eidempotencyKey\x88\x01\x01\x12$\n" +
+	"\vledger_name\x18\x0f \x01(\tH\x03R\n" +
+	"ledgerName\x88\x01\x01\x12 \n" +
+	"\tcode_name\x18\x10 \x01(\tH\x04R\bcodeName\x88\x01\x01\x12*\n" +
+	"\x0eamount_decimal\x18\x11 \x01(\tH\x05R\ramountDecimal\x88\x01\x01\x123\n" +
+	"\x13require_min_balance\x18\x12 \x01(\x04H\x06R\x11requireMinBalance\x88\x01\x01\x12$\n" +
+	"\vmax_balance\x18\x13 \x01(\x04H\aR\n" +
+	"maxBalance\x88\x01\x01B\r\n" +
 	"\v_pending_idB\f\n" +
 	"\n" +
-	"_timestamp\"\x8f\x03\n" +
+	"_timestampB\x12\n" +
+	"\x10_idempotency_keyB\x0e\n" +
+	"\f_ledger_nameB\f\n" +
+	"\n" +
+	"_code_nameB\x11\n" +
+	"\x0f_amount_decimalB\x16\n" +
+	"\x14_require_min_balanceB\x0e\n" +
+	"\f_max_balance\"\xb8\x04\n" +
 	"\rTransferFlags\x12\x1b\n" +
 	"\x06linked\x18\x01 \x01(\bH\x00R\x06linked\x88\x01\x01\x12\x1d\n" +
 	"\apending\x18\x02 \x01(\bH\x01R\apending\x88\x01\x01\x127\n" +
 	"\x15post_pending_transfer\x18\x03 \x01(\bH\x02R\x13postPendingTransfer\x88\x01\x01\x127\n" +
 	"\x15void_pending_transfer\x18\x04 \x01(\bH\x03R\x13voidPendingTransfer\x88\x01\x01\x12,\n" +
 	"\x0fbalancing_debit\x18\x05 \x01(\bH\x04R\x0ebalancingDebit\x88\x01\x01\x12.\n" +
-	"\x10balancing_credit\x18\x06 \x01(\bH\x05R\x0fbalancingCredit\x88\x01\x01B\t\n" +
+	"\x10balancing_credit\x18\x06 \x01(\bH\x05R\x0fbalancingCredit\x88\x01\x01\x12\x1f\n" +
+	"\bimported\x18\a \x01(\bH\x06R\bimported\x88\x01\x01\x12(\n" +
+	"\rclosing_debit\x18\b \x01(\bH\aR\fclosingDebit\x88\x01\x01\x12*\n" +
+	"\x0eclosing_credit\x18\t \x01(\bH\bR\rclosingCredit\x88\x01\x01B\t\n" +
 	"\a_linkedB\n" +
 	"\n" +
 	"\b_pendingB\x18\n" +
 	"\x16_post_pending_transferB\x18\n" +
 	"\x16_void_pending_transferB\x12\n" +
 	"\x10_balancing_debitB\x13\n" +
-	"\x11_balancing_credit\"\xfc\x01\n" +
+	"\x11_balancing_creditB\v\n" +
+	"\t_importedB\x10\n" +
+	"\x0e_closing_debitB\x11\n" +
+	"\x0f_closing_credit\"\xfc\x01\n" +
 	"\rAccountFilter\x12\x1d\n" +
 	"\n" +
 	"account_id\x18\x01 \x01(\tR\taccountId\x12(\n" +
@@ -2193,13 +5141,101 @@ const file_proto_tigerbeetle_proto_rawDesc = "" +
 	"\a_debitsB\n" +
 	"\n" +
 	"\b_creditsB\v\n" +
-	"\t_reversed\"\xca\x01\n" +
+	"\t_reversed\"\xa2\x04\n" +
 	"\x0eAccountBalance\x12%\n" +
 	"\x0edebits_pending\x18\x01 \x01(\x04R\rdebitsPending\x12#\n" +
 	"\rdebits_posted\x18\x02 \x01(\x04R\fdebitsPosted\x12'\n" +
 	"\x0fcredits_pending\x18\x03 \x01(\x04R\x0ecreditsPending\x12%\n" +
 	"\x0ecredits_posted\x18\x04 \x01(\x04R\rcreditsPosted\x12\x1c\n" +
-	"\ttimestamp\x18\x05 \x01(\x04R\ttimestamp\"\xc8\x03\n" +
+	"\ttimestamp\x18\x05 \x01(\x04R\ttimestamp\x129\n" +
+	"\x16debits_pending_decimal\x18\x06 \x01(\tH\x00R\x14debitsPendingDecimal\x88\x01\x01\x127\n" +
+	"\x15debits_posted_decimal\x18\a \x01(\tH\x01R\x13debitsPostedDecimal\x88\x01\x01\x12;\n" +
+	"\x17credits_pending_decimal\x18\b \x01(\tH\x02R\x15creditsPendingDecimal\x88\x01\x01\x129\n" +
+	"\x16credits_posted_decimal\x18\t \x01(\tH\x03R\x14creditsPostedDecimal\x88\x01\x01B\x19\n" +
+	"\x17_debits_pending_decimalB\x18\n" +
+	"\x16_debits_posted_decimalB\x1a\n" +
+	"\x18_credits_pending_decimalB\x19\n" +
+	"\x17_credits_posted_decimal\"\xc6\x05\n" +
+	"\tBalanceAt\x12\x1d\n" +
+	"\n" +
+	"account_id\x18\x01 \x01(\tR\taccountId\x12%\n" +
+	"\x0edebits_pending\x18\x02 \x01(\x04R\rdebitsPending\x12#\n" +
+	"\rdebits_posted\x18\x03 \x01(\x04R\fdebitsPosted\x12'\n" +
+	"\x0fcredits_pending\x18\x04 \x01(\x04R\x0ecreditsPending\x12%\n" +
+	"\x0ecredits_posted\x18\x05 \x01(\x04R\rcreditsPosted\x12\x18\n" +
+	"\abalance\x18\x06 \x01(\x03R\abalance\x12\x1c\n" +
+	"\ttimestamp\x18\a \x01(\x04R\ttimestamp\x12,\n" +
+	"\x06source\x18\b \x01(\x0e2\x14.proto.BalanceSourceR\x06source\x129\n" +
+	"\x16debits_pending_decimal\x18\t \x01(\tH\x00R\x14debitsPendingDecimal\x88\x01\x01\x127\n" +
+	"\x15debits_posted_decimal\x18\n" +
+	" \x01(\tH\x01R\x13debitsPostedDecimal\x88\x01\x01\x12;\n" +
+	"\x17credits_pending_decimal\x18\v \x01(\tH\x02R\x15creditsPendingDecimal\x88\x01\x01\x129\n" +
+	"\x16credits_posted_decimal\x18\f \x01(\tH\x03R\x14creditsPostedDecimal\x88\x01\x01\x12,\n" +
+	"\x0fbalance_decimal\x18\r \x01(\tH\x04R\x0ebalanceDecimal\x88\x01\x01B\x19\n" +
+	"\x17_debits_pending_decimalB\x18\n" +
+	"\x16_debits_posted_decimalB\x1a\n" +
+	"\x18_credits_pending_decimalB\x19\n" +
+	"\x17_credits_posted_decimalB\x12\n" +
+	"\x10_balance_decimal\"\xcb\x01\n" +
+	"\rStatementLine\x12+\n" +
+	"\btransfer\x18\x01 \x01(\v2\x0f.proto.TransferR\btransfer\x12#\n" +
+	"\rposted_amount\x18\x02 \x01(\x03R\fpostedAmount\x12%\n" +
+	"\x0epending_amount\x18\x03 \x01(\x03R\rpendingAmount\x12\x18\n" +
+	"\abalance\x18\x04 \x01(\x03R\abalance\x12'\n" +
+	"\x0fpending_balance\x18\x05 \x01(\x03R\x0ependingBalance\"\x91\x03\n" +
+	"\x10LedgerSummaryRow\x12\x17\n" +
+	"\x04code\x18\x01 \x01(\rH\x00R\x04code\x88\x01\x01\x12&\n" +
+	"\fuser_data128\x18\x02 \x01(\tH\x01R\vuserData128\x88\x01\x01\x12$\n" +
+	"\vuser_data64\x18\x03 \x01(\x04H\x02R\n" +
+	"userData64\x88\x01\x01\x12$\n" +
+	"\vuser_data32\x18\x04 \x01(\rH\x03R\n" +
+	"userData32\x88\x01\x01\x12\x1a\n" +
+	"\baccounts\x18\x05 \x01(\x04R\baccounts\x12%\n" +
+	"\x0edebits_pending\x18\x06 \x01(\tR\rdebitsPending\x12#\n" +
+	"\rdebits_posted\x18\a \x01(\tR\fdebitsPosted\x12'\n" +
+	"\x0fcredits_pending\x18\b \x01(\tR\x0ecreditsPending\x12%\n" +
+	"\x0ecredits_posted\x18\t \x01(\tR\rcreditsPostedB\a\n" +
+	"\x05_codeB\x0f\n" +
+	"\r_user_data128B\x0e\n" +
+	"\f_user_data64B\x0e\n" +
+	"\f_user_data32\"\xe9\x01\n" +
+	"\x17AggregateTransfersGroup\x12\x17\n" +
+	"\x04code\x18\x01 \x01(\rH\x00R\x04code\x88\x01\x01\x12\x1b\n" +
+	"\x06ledger\x18\x02 \x01(\rH\x01R\x06ledger\x88\x01\x01\x12$\n" +
+	"\vuser_data32\x18\x03 \x01(\rH\x02R\n" +
+	"userData32\x88\x01\x01\x12\x1b\n" +
+	"\x06bucket\x18\x04 \x01(\x04H\x03R\x06bucket\x88\x01\x01\x12\x14\n" +
+	"\x05count\x18\x05 \x01(\x04R\x05count\x12\x10\n" +
+	"\x03sum\x18\x06 \x01(\tR\x03sumB\a\n" +
+	"\x05_codeB\t\n" +
+	"\a_ledgerB\x0e\n" +
+	"\f_user_data32B\t\n" +
+	"\a_bucket\"m\n" +
+	"\n" +
+	"LedgerInfo\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\rR\x02id\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12\x1a\n" +
+	"\bcurrency\x18\x03 \x01(\tR\bcurrency\x12\x1f\n" +
+	"\vasset_scale\x18\x04 \x01(\rR\n" +
+	"assetScale\"j\n" +
+	"\bCodeInfo\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\rR\x02id\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12 \n" +
+	"\vdescription\x18\x03 \x01(\tR\vdescription\x12\x18\n" +
+	"\aledgers\x18\x04 \x03(\rR\aledgers\"-\n" +
+	"\x05Alias\x12\x14\n" +
+	"\x05alias\x18\x01 \x01(\tR\x05alias\x12\x0e\n" +
+	"\x02id\x18\x02 \x01(\tR\x02id\"|\n" +
+	"\vLedgerTotal\x12\x16\n" +
+	"\x06ledger\x18\x01 \x01(\rR\x06ledger\x12\x16\n" +
+	"\x06amount\x18\x02 \x01(\x04R\x06amount\x12*\n" +
+	"\x0eamount_decimal\x18\x03 \x01(\tH\x00R\ramountDecimal\x88\x01\x01B\x11\n" +
+	"\x0f_amount_decimal\"h\n" +
+	"\x16RestoreLedgerRejection\x12\x12\n" +
+	"\x04kind\x18\x01 \x01(\tR\x04kind\x12\x0e\n" +
+	"\x02id\x18\x02 \x01(\tR\x02id\x12\x12\n" +
+	"\x04code\x18\x03 \x01(\rR\x04code\x12\x16\n" +
+	"\x06result\x18\x04 \x01(\tR\x06result\"\xc8\x03\n" +
 	"\vQueryFilter\x12&\n" +
 	"\fuser_data128\x18\x01 \x01(\tH\x00R\vuserData128\x88\x01\x01\x12$\n" +
 	"\vuser_data64\x18\x02 \x01(\x04H\x01R\n" +
@@ -2222,7 +5258,21 @@ const file_proto_tigerbeetle_proto_rawDesc = "" +
 	"\x06_flags\"@\n" +
 	"\x10QueryFilterFlags\x12\x1f\n" +
 	"\breversed\x18\x01 \x01(\bH\x00R\breversed\x88\x01\x01B\v\n" +
-	"\t_reversed*\xbb\a\n" +
+	"\t_reversed*[\n" +
+	"\x0fAggregateBucket\x12\x17\n" +
+	"\x13AggregateBucketNone\x10\x00\x12\x17\n" +
+	"\x13AggregateBucketHour\x10\x01\x12\x16\n" +
+	"\x12AggregateBucketDay\x10\x02*s\n" +
+	"\aGroupBy\x12\x0f\n" +
+	"\vGroupByCode\x10\x00\x12\x11\n" +
+	"\rGroupByLedger\x10\x01\x12\x16\n" +
+	"\x12GroupByUserData128\x10\x02\x12\x15\n" +
+	"\x11GroupByUserData64\x10\x03\x12\x15\n" +
+	"\x11GroupByUserData32\x10\x04*_\n" +
+	"\rBalanceSource\x12\x18\n" +
+	"\x14BalanceSourceCurrent\x10\x00\x12\x18\n" +
+	"\x14BalanceSourceHistory\x10\x01\x12\x1a\n" +
+	"\x16BalanceSourceTransfers\x10\x02*\xbb\a\n" +
 	"\x13CreateAccountResult\x12\r\n" +
 	"\tAccountOK\x10\x00\x12\x1c\n" +
 	"\x18AccountLinkedEventFailed\x10\x01\x12\x1f\n" +
@@ -2251,7 +5301,7 @@ const file_proto_tigerbeetle_proto_rawDesc = "" +
 	"\x1eAccountCreditsPostedMustBeZero\x10\f\x12\x1e\n" +
 	"\x1aAccountLedgerMustNotBeZero\x10\r\x12\x1c\n" +
 	"\x18AccountCodeMustNotBeZero\x10\x0e\x12/\n" +
-	"+AccountImportedEventTimestampMustNotRegress\x10\x1a*\x98\x15\n" +
+	"+AccountImportedEventTimestampMustNotRegress\x10\x1a*\xfc\x15\n" +
 	"\x14CreateTransferResult\x12\x0e\n" +
 	"\n" +
 	"TransferOK\x10\x00\x12\x1d\n" +
@@ -2323,7 +5373,10 @@ const file_proto_tigerbeetle_proto_rawDesc = "" +
 	"\x18TransferOverflowsCredits\x104\x12\x1c\n" +
 	"\x18TransferOverflowsTimeout\x105\x12\x1a\n" +
 	"\x16TransferExceedsCredits\x106\x12\x19\n" +
-	"\x15TransferExceedsDebits\x1072\xce\x05\n" +
+	"\x15TransferExceedsDebits\x107\x12\x1d\n" +
+	"\x18TransferMinBalanceNotMet\x10\xe8\a\x12\x1f\n" +
+	"\x1aTransferMaxBalanceExceeded\x10\xe9\a\x12\"\n" +
+	"\x1dTransferSpendingLimitExceeded\x10\xea\a2\xbe\r\n" +
 	"\vTigerBeetle\x121\n" +
 	"\x05GetID\x12\x13.proto.GetIDRequest\x1a\x11.proto.GetIDReply\"\x00\x12L\n" +
 	"\x0eCreateAccounts\x12\x1c.proto.CreateAccountsRequest\x1a\x1a.proto.CreateAccountsReply\"\x00\x12O\n" +
@@ -2333,7 +5386,20 @@ const file_proto_tigerbeetle_proto_rawDesc = "" +
 	"\x13GetAccountTransfers\x12!.proto.GetAccountTransfersRequest\x1a\x1f.proto.GetAccountTransfersReply\"\x00\x12X\n" +
 	"\x12GetAccountBalances\x12 .proto.GetAccountBalancesRequest\x1a\x1e.proto.GetAccountBalancesReply\"\x00\x12L\n" +
 	"\x0eQueryTransfers\x12\x1c.proto.QueryTransfersRequest\x1a\x1a.proto.QueryTransfersReply\"\x00\x12I\n" +
-	"\rQueryAccounts\x12\x1b.proto.QueryAccountsRequest\x1a\x19.proto.QueryAccountsReply\"\x00BO\n" +
+	"\rQueryAccounts\x12\x1b.proto.QueryAccountsRequest\x1a\x19.proto.QueryAccountsReply\"\x00\x12I\n" +
+	"\rGetBalancesAt\x12\x1b.proto.GetBalancesAtRequest\x1a\x19.proto.GetBalancesAtReply\"\x00\x12I\n" +
+	"\rLedgerSummary\x12\x1b.proto.LedgerSummaryRequest\x1a\x19.proto.LedgerSummaryReply\"\x00\x12[\n" +
+	"\x13GetAccountStatement\x12!.proto.GetAccountStatementRequest\x1a\x1f.proto.GetAccountStatementReply\"\x00\x12X\n" +
+	"\x12AggregateTransfers\x12 .proto.AggregateTransfersRequest\x1a\x1e.proto.AggregateTransfersReply\"\x00\x12F\n" +
+	"\fExportLedger\x12\x1a.proto.ExportLedgerRequest\x1a\x18.proto.ExportLedgerReply\"\x00\x12I\n" +
+	"\rRestoreLedger\x12\x1b.proto.RestoreLedgerRequest\x1a\x19.proto.RestoreLedgerReply\"\x00\x12I\n" +
+	"\rRegisterAlias\x12\x1b.proto.RegisterAliasRequest\x1a\x19.proto.RegisterAliasReply\"\x00\x12I\n" +
+	"\rLookupAliases\x12\x1b.proto.LookupAliasesRequest\x1a\x19.proto.LookupAliasesReply\"\x00\x12C\n" +
+	"\vListLedgers\x12\x19.proto.ListLedgersRequest\x1a\x17.proto.ListLedgersReply\"\x00\x12d\n" +
+	"\x16CreateCompoundTransfer\x12$.proto.CreateCompoundTransferRequest\x1a\".proto.CreateCompoundTransferReply\"\x00\x12:\n" +
+	"\bExchange\x12\x16.proto.ExchangeRequest\x1a\x14.proto.ExchangeReply\"\x00\x12F\n" +
+	"\fCloseAccount\x12\x1a.proto.CloseAccountRequest\x1a\x18.proto.CloseAccountReply\"\x00\x12I\n" +
+	"\rReopenAccount\x12\x1b.proto.ReopenAccountRequest\x1a\x19.proto.ReopenAccountReply\"\x00BO\n" +
 	"!nl.last.li.tigerbeetle_grpc.protoB\x10TigerBeetleProtoP\x01Z\x16tigerbeetle_grpc/protob\x06proto3"
 
 var (
@@ -2348,85 +5414,174 @@ func file_proto_tigerbeetle_proto_rawDescGZIP() []byte {
 	return file_proto_tigerbeetle_proto_rawDescData
 }
 
-var file_proto_tigerbeetle_proto_enumTypes = make([]protoimpl.EnumInfo, 2)
-var file_proto_tigerbeetle_proto_msgTypes = make([]protoimpl.MessageInfo, 29)
+var file_proto_tigerbeetle_proto_enumTypes = make([]protoimpl.EnumInfo, 5)
+var file_proto_tigerbeetle_proto_msgTypes = make([]protoimpl.MessageInfo, 64)
 var file_proto_tigerbeetle_proto_goTypes = []any{
-	(CreateAccountResult)(0),           // 0: proto.CreateAccountResult
-	(CreateTransferResult)(0),          // 1: proto.CreateTransferResult
-	(*GetIDRequest)(nil),               // 2: proto.GetIDRequest
-	(*GetIDReply)(nil),                 // 3: proto.GetIDReply
-	(*CreateAccountsRequest)(nil),      // 4: proto.CreateAccountsRequest
-	(*CreateAccountsReply)(nil),        // 5: proto.CreateAccountsReply
-	(*CreateAccountsReplyItem)(nil),    // 6: proto.CreateAccountsReplyItem
-	(*CreateTransfersRequest)(nil),     // 7: proto.CreateTransfersRequest
-	(*CreateTransfersReply)(nil),       // 8: proto.CreateTransfersReply
-	(*CreateTransfersReplyItem)(nil),   // 9: proto.CreateTransfersReplyItem
-	(*LookupAccountsRequest)(nil),      // 10: proto.LookupAccountsRequest
-	(*LookupAccountsReply)(nil),        // 11: proto.LookupAccountsReply
-	(*LookupTransfersRequest)(nil),     // 12: proto.LookupTransfersRequest
-	(*LookupTransfersReply)(nil),       // 13: proto.LookupTransfersReply
-	(*GetAccountTransfersRequest)(nil), // 14: proto.GetAccountTransfersRequest
-	(*GetAccountTransfersReply)(nil),   // 15: proto.GetAccountTransfersReply
-	(*GetAccountBalancesRequest)(nil),  // 16: proto.GetAccountBalancesRequest
-	(*GetAccountBalancesReply)(nil),    // 17: proto.GetAccountBalancesReply
-	(*QueryTransfersRequest)(nil),      // 18: proto.QueryTransfersRequest
-	(*QueryTransfersReply)(nil),        // 19: proto.QueryTransfersReply
-	(*QueryAccountsRequest)(nil),       // 20: proto.QueryAccountsRequest
-	(*QueryAccountsReply)(nil),         // 21: proto.QueryAccountsReply
-	(*Account)(nil),                    // 22: proto.Account
-	(*AccountFlags)(nil),               // 23: proto.AccountFlags
-	(*Transfer)(nil),                   // 24: proto.Transfer
-	(*TransferFlags)(nil),              // 25: proto.TransferFlags
-	(*AccountFilter)(nil),              // 26: proto.AccountFilter
-	(*AccountFilterFlags)(nil),         // 27: proto.AccountFilterFlags
-	(*AccountBalance)(nil),             // 28: proto.AccountBalance
-	(*QueryFilter)(nil),                // 29: proto.QueryFilter
-	(*QueryFilterFlags)(nil),           // 30: proto.QueryFilterFlags
+	(AggregateBucket)(0),                  // 0: proto.AggregateBucket
+	(GroupBy)(0),                          // 1: proto.GroupBy
+	(BalanceSource)(0),                    // 2: proto.BalanceSource
+	(CreateAccountResult)(0),              // 3: proto.CreateAccountResult
+	(CreateTransferResult)(0),             // 4: proto.CreateTransferResult
+	(*GetIDRequest)(nil),                  // 5: proto.GetIDRequest
+	(*GetIDReply)(nil),                    // 6: proto.GetIDReply
+	(*CreateAccountsRequest)(nil),         // 7: proto.CreateAccountsRequest
+	(*CreateAccountsReply)(nil),           // 8: proto.CreateAccountsReply
+	(*CreateAccountsReplyItem)(nil),       // 9: proto.CreateAccountsReplyItem
+	(*CreateTransfersRequest)(nil),        // 10: proto.CreateTransfersRequest
+	(*CreateTransfersReply)(nil),          // 11: proto.CreateTransfersReply
+	(*CreateTransfersReplyItem)(nil),      // 12: proto.CreateTransfersReplyItem
+	(*LookupAccountsRequest)(nil),         // 13: proto.LookupAccountsRequest
+	(*LookupAccountsReply)(nil),           // 14: proto.LookupAccountsReply
+	(*LookupTransfersRequest)(nil),        // 15: proto.LookupTransfersRequest
+	(*LookupTransfersReply)(nil),          // 16: proto.LookupTransfersReply
+	(*GetAccountTransfersRequest)(nil),    // 17: proto.GetAccountTransfersRequest
+	(*GetAccountTransfersReply)(nil),      // 18: proto.GetAccountTransfersReply
+	(*GetAccountBalancesRequest)(nil),     // 19: proto.GetAccountBalancesRequest
+	(*GetAccountBalancesReply)(nil),       // 20: proto.GetAccountBalancesReply
+	(*QueryTransfersRequest)(nil),         // 21: proto.QueryTransfersRequest
+	(*QueryTransfersReply)(nil),           // 22: proto.QueryTransfersReply
+	(*QueryAccountsRequest)(nil),          // 23: proto.QueryAccountsRequest
+	(*QueryAccountsReply)(nil),            // 24: proto.QueryAccountsReply
+	(*GetBalancesAtRequest)(nil),          // 25: proto.GetBalancesAtRequest
+	(*GetBalancesAtReply)(nil),            // 26: proto.GetBalancesAtReply
+	(*LedgerSummaryRequest)(nil),          // 27: proto.LedgerSummaryRequest
+	(*GetAccountStatementRequest)(nil),    // 28: proto.GetAccountStatementRequest
+	(*GetAccountStatementReply)(nil),      // 29: proto.GetAccountStatementReply
+	(*AggregateTransfersRequest)(nil),     // 30: proto.AggregateTransfersRequest
+	(*AggregateTransfersReply)(nil),       // 31: proto.AggregateTransfersReply
+	(*LedgerSummaryReply)(nil),            // 32: proto.LedgerSummaryReply
+	(*ExportLedgerRequest)(nil),           // 33: proto.ExportLedgerRequest
+	(*ExportLedgerReply)(nil),             // 34: proto.ExportLedgerReply
+	(*RestoreLedgerRequest)(nil),          // 35: proto.RestoreLedgerRequest
+	(*RestoreLedgerReply)(nil),            // 36: proto.RestoreLedgerReply
+	(*RegisterAliasRequest)(nil),          // 37: proto.RegisterAliasRequest
+	(*RegisterAliasReply)(nil),            // 38: proto.RegisterAliasReply
+	(*ListLedgersRequest)(nil),            // 39: proto.ListLedgersRequest
+	(*ListLedgersReply)(nil),              // 40: proto.ListLedgersReply
+	(*LookupAliasesRequest)(nil),          // 41: proto.LookupAliasesRequest
+	(*LookupAliasesReply)(nil),            // 42: proto.LookupAliasesReply
+	(*CreateCompoundTransferRequest)(nil), // 43: proto.CreateCompoundTransferRequest
+	(*CreateCompoundTransferReply)(nil),   // 44: proto.CreateCompoundTransferReply
+	(*ExchangeRequest)(nil),               // 45: proto.ExchangeRequest
+	(*ExchangeReply)(nil),                 // 46: proto.ExchangeReply
+	(*CloseAccountRequest)(nil),           // 47: proto.CloseAccountRequest
+	(*CloseAccountReply)(nil),             // 48: proto.CloseAccountReply
+	(*ReopenAccountRequest)(nil),          // 49: proto.ReopenAccountRequest
+	(*ReopenAccountReply)(nil),            // 50: proto.ReopenAccountReply
+	(*Account)(nil),                       // 51: proto.Account
+	(*AccountFlags)(nil),                  // 52: proto.AccountFlags
+	(*Transfer)(nil),                      // 53: proto.Transfer
+	(*TransferFlags)(nil),                 // 54: proto.TransferFlags
+	(*AccountFilter)(nil),                 // 55: proto.AccountFilter
+	(*AccountFilterFlags)(nil),            // 56: proto.AccountFilterFlags
+	(*AccountBalance)(nil),                // 57: proto.AccountBalance
+	(*BalanceAt)(nil),                     // 58: proto.BalanceAt
+	(*StatementLine)(nil),                 // 59: proto.StatementLine
+	(*LedgerSummaryRow)(nil),              // 60: proto.LedgerSummaryRow
+	(*AggregateTransfersGroup)(nil),       // 61: proto.AggregateTransfersGroup
+	(*LedgerInfo)(nil),                    // 62: proto.LedgerInfo
+	(*CodeInfo)(nil),                      // 63: proto.CodeInfo
+	(*Alias)(nil),                         // 64: proto.Alias
+	(*LedgerTotal)(nil),                   // 65: proto.LedgerTotal
+	(*RestoreLedgerRejection)(nil),        // 66: proto.RestoreLedgerRejection
+	(*QueryFilter)(nil),                   // 67: proto.QueryFilter
+	(*QueryFilterFlags)(nil),              // 68: proto.QueryFilterFlags
 }
 var file_proto_tigerbeetle_proto_depIdxs = []int32{
-	22, // 0: proto.CreateAccountsRequest.accounts:type_name -> proto.Account
-	6,  // 1: proto.CreateAccountsReply.results:type_name -> proto.CreateAccountsReplyItem
-	0,  // 2: proto.CreateAccountsReplyItem.result:type_name -> proto.CreateAccountResult
-	24, // 3: proto.CreateTransfersRequest.transfers:type_name -> proto.Transfer
-	9,  // 4: proto.CreateTransfersReply.results:type_name -> proto.CreateTransfersReplyItem
-	1,  // 5: proto.CreateTransfersReplyItem.result:type_name -> proto.CreateTransferResult
-	22, // 6: proto.LookupAccountsReply.accounts:type_name -> proto.Account
-	24, // 7: proto.LookupTransfersReply.transfers:type_name -> proto.Transfer
-	26, // 8: proto.GetAccountTransfersRequest.filter:type_name -> proto.AccountFilter
-	24, // 9: proto.GetAccountTransfersReply.transfers:type_name -> proto.Transfer
-	26, // 10: proto.GetAccountBalancesRequest.filter:type_name -> proto.AccountFilter
-	28, // 11: proto.GetAccountBalancesReply.account_balances:type_name -> proto.AccountBalance
-	29, // 12: proto.QueryTransfersRequest.filter:type_name -> proto.QueryFilter
-	24, // 13: proto.QueryTransfersReply.transfers:type_name -> proto.Transfer
-	29, // 14: proto.QueryAccountsRequest.filter:type_name -> proto.QueryFilter
-	22, // 15: proto.QueryAccountsReply.accounts:type_name -> proto.Account
-	23, // 16: proto.Account.flags:type_name -> proto.AccountFlags
-	25, // 17: proto.Transfer.transfer_flags:type_name -> proto.TransferFlags
-	27, // 18: proto.AccountFilter.flags:type_name -> proto.AccountFilterFlags
-	30, // 19: proto.QueryFilter.flags:type_name -> proto.QueryFilterFlags
-	2,  // 20: proto.TigerBeetle.GetID:input_type -> proto.GetIDRequest
-	4,  // 21: proto.TigerBeetle.CreateAccounts:input_type -> proto.CreateAccountsRequest
-	7,  // 22: proto.TigerBeetle.CreateTransfers:input_type -> proto.CreateTransfersRequest
-	10, // 23: proto.TigerBeetle.LookupAccounts:input_type -> proto.LookupAccountsRequest
-	12, // 24: proto.TigerBeetle.LookupTransfers:input_type -> proto.LookupTransfersRequest
-	14, // 25: proto.TigerBeetle.GetAccountTransfers:input_type -> proto.GetAccountTransfersRequest
-	16, // 26: proto.TigerBeetle.GetAccountBalances:input_type -> proto.GetAccountBalancesRequest
-	18, // 27: proto.TigerBeetle.QueryTransfers:input_type -> proto.QueryTransfersRequest
-	20, // 28: proto.TigerBeetle.QueryAccounts:input_type -> proto.QueryAccountsRequest
-	3,  // 29: proto.TigerBeetle.GetID:output_type -> proto.GetIDReply
-	5,  // 30: proto.TigerBeetle.CreateAccounts:output_type -> proto.CreateAccountsReply
-	8,  // 31: proto.TigerBeetle.CreateTransfers:output_type -> proto.CreateTransfersReply
-	11, // 32: proto.TigerBeetle.LookupAccounts:output_type -> proto.LookupAccountsReply
-	13, // 33: proto.TigerBeetle.LookupTransfers:output_type -> proto.LookupTransfersReply
-	15, // 34: proto.TigerBeetle.GetAccountTransfers:output_type -> proto.GetAccountTransfersReply
-	17, // 35: proto.TigerBeetle.GetAccountBalances:output_type -> proto.GetAccountBalancesReply
-	19, // 36: proto.TigerBeetle.QueryTransfers:output_type -> proto.QueryTransfersReply
-	21, // 37: proto.TigerBeetle.QueryAccounts:output_type -> proto.QueryAccountsReply
-	29, // [29:38] is the sub-list for method output_type
-	20, // [20:29] is the sub-list for method input_type
-	20, // [20:20] is the sub-list for extension type_name
-	20, // [20:20] is the sub-list for extension extendee
-	0,  // [0:20] is the sub-list for field type_name
+	51, // 0: proto.CreateAccountsRequest.accounts:type_name -> proto.Account
+	9,  // 1: proto.CreateAccountsReply.results:type_name -> proto.CreateAccountsReplyItem
+	3,  // 2: proto.CreateAccountsReplyItem.result:type_name -> proto.CreateAccountResult
+	53, // 3: proto.CreateTransfersRequest.transfers:type_name -> proto.Transfer
+	12, // 4: proto.CreateTransfersReply.results:type_name -> proto.CreateTransfersReplyItem
+	4,  // 5: proto.CreateTransfersReplyItem.result:type_name -> proto.CreateTransferResult
+	51, // 6: proto.LookupAccountsReply.accounts:type_name -> proto.Account
+	53, // 7: proto.LookupTransfersReply.transfers:type_name -> proto.Transfer
+	55, // 8: proto.GetAccountTransfersRequest.filter:type_name -> proto.AccountFilter
+	53, // 9: proto.GetAccountTransfersReply.transfers:type_name -> proto.Transfer
+	55, // 10: proto.GetAccountBalancesRequest.filter:type_name -> proto.AccountFilter
+	57, // 11: proto.GetAccountBalancesReply.account_balances:type_name -> proto.AccountBalance
+	67, // 12: proto.QueryTransfersRequest.filter:type_name -> proto.QueryFilter
+	53, // 13: proto.QueryTransfersReply.transfers:type_name -> proto.Transfer
+	67, // 14: proto.QueryAccountsRequest.filter:type_name -> proto.QueryFilter
+	51, // 15: proto.QueryAccountsReply.accounts:type_name -> proto.Account
+	58, // 16: proto.GetBalancesAtReply.balances:type_name -> proto.BalanceAt
+	1,  // 17: proto.LedgerSummaryRequest.group_by:type_name -> proto.GroupBy
+	51, // 18: proto.GetAccountStatementReply.account:type_name -> proto.Account
+	58, // 19: proto.GetAccountStatementReply.opening_balance:type_name -> proto.BalanceAt
+	59, // 20: proto.GetAccountStatementReply.lines:type_name -> proto.StatementLine
+	58, // 21: proto.GetAccountStatementReply.closing_balance:type_name -> proto.BalanceAt
+	67, // 22: proto.AggregateTransfersRequest.filter:type_name -> proto.QueryFilter
+	1,  // 23: proto.AggregateTransfersRequest.group_by:type_name -> proto.GroupBy
+	0,  // 24: proto.AggregateTransfersRequest.bucket:type_name -> proto.AggregateBucket
+	61, // 25: proto.AggregateTransfersReply.groups:type_name -> proto.AggregateTransfersGroup
+	60, // 26: proto.LedgerSummaryReply.rows:type_name -> proto.LedgerSummaryRow
+	60, // 27: proto.LedgerSummaryReply.total:type_name -> proto.LedgerSummaryRow
+	66, // 28: proto.RestoreLedgerReply.rejected:type_name -> proto.RestoreLedgerRejection
+	64, // 29: proto.RegisterAliasReply.alias:type_name -> proto.Alias
+	62, // 30: proto.ListLedgersReply.ledgers:type_name -> proto.LedgerInfo
+	63, // 31: proto.ListLedgersReply.codes:type_name -> proto.CodeInfo
+	64, // 32: proto.LookupAliasesReply.aliases:type_name -> proto.Alias
+	53, // 33: proto.CreateCompoundTransferRequest.legs:type_name -> proto.Transfer
+	65, // 34: proto.CreateCompoundTransferRequest.totals:type_name -> proto.LedgerTotal
+	4,  // 35: proto.CreateCompoundTransferReply.result:type_name -> proto.CreateTransferResult
+	4,  // 36: proto.ExchangeReply.result:type_name -> proto.CreateTransferResult
+	4,  // 37: proto.CloseAccountReply.result:type_name -> proto.CreateTransferResult
+	4,  // 38: proto.ReopenAccountReply.result:type_name -> proto.CreateTransferResult
+	52, // 39: proto.Account.flags:type_name -> proto.AccountFlags
+	54, // 40: proto.Transfer.transfer_flags:type_name -> proto.TransferFlags
+	56, // 41: proto.AccountFilter.flags:type_name -> proto.AccountFilterFlags
+	2,  // 42: proto.BalanceAt.source:type_name -> proto.BalanceSource
+	53, // 43: proto.StatementLine.transfer:type_name -> proto.Transfer
+	68, // 44: proto.QueryFilter.flags:type_name -> proto.QueryFilterFlags
+	5,  // 45: proto.TigerBeetle.GetID:input_type -> proto.GetIDRequest
+	7,  // 46: proto.TigerBeetle.CreateAccounts:input_type -> proto.CreateAccountsRequest
+	10, // 47: proto.TigerBeetle.CreateTransfers:input_type -> proto.CreateTransfersRequest
+	13, // 48: proto.TigerBeetle.LookupAccounts:input_type -> proto.LookupAccountsRequest
+	15, // 49: proto.TigerBeetle.LookupTransfers:input_type -> proto.LookupTransfersRequest
+	17, // 50: proto.TigerBeetle.GetAccountTransfers:input_type -> proto.GetAccountTransfersRequest
+	19, // 51: proto.TigerBeetle.GetAccountBalances:input_type -> proto.GetAccountBalancesRequest
+	21, // 52: proto.TigerBeetle.QueryTransfers:input_type -> proto.QueryTransfersRequest
+	23, // 53: proto.TigerBeetle.QueryAccounts:input_type -> proto.QueryAccountsRequest
+	25, // 54: proto.TigerBeetle.GetBalancesAt:input_type -> proto.GetBalancesAtRequest
+	27, // 55: proto.TigerBeetle.LedgerSummary:input_type -> proto.LedgerSummaryRequest
+	28, // 56: proto.TigerBeetle.GetAccountStatement:input_type -> proto.GetAccountStatementRequest
+	30, // 57: proto.TigerBeetle.AggregateTransfers:input_type -> proto.AggregateTransfersRequest
+	33, // 58: proto.TigerBeetle.ExportLedger:input_type -> proto.ExportLedgerRequest
+	35, // 59: proto.TigerBeetle.RestoreLedger:input_type -> proto.RestoreLedgerRequest
+	37, // 60: proto.TigerBeetle.RegisterAlias:input_type -> proto.RegisterAliasRequest
+	41, // 61: proto.TigerBeetle.LookupAliases:input_type -> proto.LookupAliasesRequest
+	39, // 62: proto.TigerBeetle.ListLedgers:input_type -> proto.ListLedgersRequest
+	43, // 63: proto.TigerBeetle.CreateCompoundTransfer:input_type -> proto.CreateCompoundTransferRequest
+	45, // 64: proto.TigerBeetle.Exchange:input_type -> proto.ExchangeRequest
+	47, // 65: proto.TigerBeetle.CloseAccount:input_type -> proto.CloseAccountRequest
+	49, // 66: proto.TigerBeetle.ReopenAccount:input_type -> proto.ReopenAccountRequest
+	6,  // 67: proto.TigerBeetle.GetID:output_type -> proto.GetIDReply
+	8,  // 68: proto.TigerBeetle.CreateAccounts:output_type -> proto.CreateAccountsReply
+	11, // 69: proto.TigerBeetle.CreateTransfers:output_type -> proto.CreateTransfersReply
+	14, // 70: proto.TigerBeetle.LookupAccounts:output_type -> proto.LookupAccountsReply
+	16, // 71: proto.TigerBeetle.LookupTransfers:output_type -> proto.LookupTransfersReply
+	18, // 72: proto.TigerBeetle.GetAccountTransfers:output_type -> proto.GetAccountTransfersReply
+	20, // 73: proto.TigerBeetle.GetAccountBalances:output_type -> proto.GetAccountBalancesReply
+	22, // 74: proto.TigerBeetle.QueryTransfers:output_type -> proto.QueryTransfersReply
+	24, // 75: proto.TigerBeetle.QueryAccounts:output_type -> proto.QueryAccountsReply
+	26, // 76: proto.TigerBeetle.GetBalancesAt:output_type -> proto.GetBalancesAtReply
+	32, // 77: proto.TigerBeetle.LedgerSummary:output_type -> proto.LedgerSummaryReply
+	29, // 78: proto.TigerBeetle.GetAccountStatement:output_type -> proto.GetAccountStatementReply
+	31, // 79: proto.TigerBeetle.AggregateTransfers:output_type -> proto.AggregateTransfersReply
+	34, // 80: proto.TigerBeetle.ExportLedger:output_type -> proto.ExportLedgerReply
+	36, // 81: proto.TigerBeetle.RestoreLedger:output_type -> proto.RestoreLedgerReply
+	38, // 82: proto.TigerBeetle.RegisterAlias:output_type -> proto.RegisterAliasReply
+	42, // 83: proto.TigerBeetle.LookupAliases:output_type -> proto.LookupAliasesReply
+	40, // 84: proto.TigerBeetle.ListLedgers:output_type -> proto.ListLedgersReply
+	44, // 85: proto.TigerBeetle.CreateCompoundTransfer:output_type -> proto.CreateCompoundTransferReply
+	46, // 86: proto.TigerBeetle.Exchange:output_type -> proto.ExchangeReply
+	48, // 87: proto.TigerBeetle.CloseAccount:output_type -> proto.CloseAccountReply
+	50, // 88: proto.TigerBeetle.ReopenAccount:output_type -> proto.ReopenAccountReply
+	67, // [67:89] is the sub-list for method output_type
+	45, // [45:67] is the sub-list for method input_type
+	45, // [45:45] is the sub-list for extension type_name
+	45, // [45:45] is the sub-list for extension extendee
+	0,  // [0:45] is the sub-list for field type_name
 }
 
 func init() { file_proto_tigerbeetle_proto_init() }
@@ -2434,20 +5589,35 @@ func file_proto_tigerbeetle_proto_init() {
 	if File_proto_tigerbeetle_proto != nil {
 		return
 	}
-	file_proto_tigerbeetle_proto_msgTypes[21].OneofWrappers = []any{}
-	file_proto_tigerbeetle_proto_msgTypes[22].OneofWrappers = []any{}
-	file_proto_tigerbeetle_proto_msgTypes[23].OneofWrappers = []any{}
-	file_proto_tigerbeetle_proto_msgTypes[24].OneofWrappers = []any{}
-	file_proto_tigerbeetle_proto_msgTypes[25].OneofWrappers = []any{}
-	file_proto_tigerbeetle_proto_msgTypes[27].OneofWrappers = []any{}
-	file_proto_tigerbeetle_proto_msgTypes[28].OneofWrappers = []any{}
+	file_proto_tigerbeetle_proto_msgTypes[0].OneofWrappers = []any{}
+	file_proto_tigerbeetle_proto_msgTypes[32].OneofWrappers = []any{}
+	file_proto_tigerbeetle_proto_msgTypes[38].OneofWrappers = []any{}
+	file_proto_tigerbeetle_proto_msgTypes[39].OneofWrappers = []any{}
+	file_proto_tigerbeetle_proto_msgTypes[40].OneofWrappers = []any{}
+	file_proto_tigerbeetle_proto_msgTypes[41].OneofWrappers = []any{}
+	file_proto_tigerbeetle_proto_msgTypes[42].OneofWrappers = []any{}
+	file_proto_tigerbeetle_proto_msgTypes[43].OneofWrappers = []any{}
+	file_proto_tigerbeetle_proto_msgTypes[44].OneofWrappers = []any{}
+	file_proto_tigerbeetle_proto_msgTypes[46].OneofWrappers = []any{}
+	file_proto_tigerbeetle_proto_msgTypes[47].OneofWrappers = []any{}
+	file_proto_tigerbeetle_proto_msgTypes[48].OneofWrappers = []any{}
+	file_proto_tigerbeetle_proto_msgTypes[49].OneofWrappers = []any{}
+	file_proto_tigerbeetle_proto_msgTypes[50].OneofWrappers = []any{}
+	file_proto_tigerbeetle_proto_msgTypes[51].OneofWrappers = []any{}
+	file_proto_tigerbeetle_proto_msgTypes[52].OneofWrappers = []any{}
+	file_proto_tigerbeetle_proto_msgTypes[53].OneofWrappers = []any{}
+	file_proto_tigerbeetle_proto_msgTypes[55].OneofWrappers = []any{}
+	file_proto_tigerbeetle_proto_msgTypes[56].OneofWrappers = []any{}
+	file_proto_tigerbeetle_proto_msgTypes[60].OneofWrappers = []any{}
+	file_proto_tigerbeetle_proto_msgTypes[62].OneofWrappers = []any{}
+	file_proto_tigerbeetle_proto_msgTypes[63].OneofWrappers = []any{}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_proto_tigerbeetle_proto_rawDesc), len(file_proto_tigerbeetle_proto_rawDesc)),
-			NumEnums:      2,
-			NumMessages:   29,
+			NumEnums:      5,
+			NumMessages:   64,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
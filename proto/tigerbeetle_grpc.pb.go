@@ -19,15 +19,28 @@ import (
 const _ = grpc.SupportPackageIsVersion9
 
 const (
-	TigerBeetle_GetID_FullMethodName               = "/proto.TigerBeetle/GetID"
-	TigerBeetle_CreateAccounts_FullMethodName      = "/proto.TigerBeetle/CreateAccounts"
-	TigerBeetle_CreateTransfers_FullMethodName     = "/proto.TigerBeetle/CreateTransfers"
-	TigerBeetle_LookupAccounts_FullMethodName      = "/proto.TigerBeetle/LookupAccounts"
-	TigerBeetle_LookupTransfers_FullMethodName     = "/proto.TigerBeetle/LookupTransfers"
-	TigerBeetle_GetAccountTransfers_FullMethodName = "/proto.TigerBeetle/GetAccountTransfers"
-	TigerBeetle_GetAccountBalances_FullMethodName  = "/proto.TigerBeetle/GetAccountBalances"
-	TigerBeetle_QueryTransfers_FullMethodName      = "/proto.TigerBeetle/QueryTransfers"
-	TigerBeetle_QueryAccounts_FullMethodName       = "/proto.TigerBeetle/QueryAccounts"
+	TigerBeetle_GetID_FullMethodName                  = "/proto.TigerBeetle/GetID"
+	TigerBeetle_CreateAccounts_FullMethodName         = "/proto.TigerBeetle/CreateAccounts"
+	TigerBeetle_CreateTransfers_FullMethodName        = "/proto.TigerBeetle/CreateTransfers"
+	TigerBeetle_LookupAccounts_FullMethodName         = "/proto.TigerBeetle/LookupAccounts"
+	TigerBeetle_LookupTransfers_FullMethodName        = "/proto.TigerBeetle/LookupTransfers"
+	TigerBeetle_GetAccountTransfers_FullMethodName    = "/proto.TigerBeetle/GetAccountTransfers"
+	TigerBeetle_GetAccountBalances_FullMethodName     = "/proto.TigerBeetle/GetAccountBalances"
+	TigerBeetle_QueryTransfers_FullMethodName         = "/proto.TigerBeetle/QueryTransfers"
+	TigerBeetle_QueryAccounts_FullMethodName          = "/proto.TigerBeetle/QueryAccounts"
+	TigerBeetle_GetBalancesAt_FullMethodName          = "/proto.TigerBeetle/GetBalancesAt"
+	TigerBeetle_LedgerSummary_FullMethodName          = "/proto.TigerBeetle/LedgerSummary"
+	TigerBeetle_GetAccountStatement_FullMethodName    = "/proto.TigerBeetle/GetAccountStatement"
+	TigerBeetle_AggregateTransfers_FullMethodName     = "/proto.TigerBeetle/AggregateTransfers"
+	TigerBeetle_ExportLedger_FullMethodName           = "/proto.TigerBeetle/ExportLedger"
+	TigerBeetle_RestoreLedger_FullMethodName          = "/proto.TigerBeetle/RestoreLedger"
+	TigerBeetle_RegisterAlias_FullMethodName          = "/proto.TigerBeetle/RegisterAlias"
+	TigerBeetle_LookupAliases_FullMethodName          = "/proto.TigerBeetle/LookupAliases"
+	TigerBeetle_ListLedgers_FullMethodName            = "/proto.TigerBeetle/ListLedgers"
+	TigerBeetle_CreateCompoundTransfer_FullMethodName = "/proto.TigerBeetle/CreateCompoundTransfer"
+	TigerBeetle_Exchange_FullMethodName               = "/proto.TigerBeetle/Exchange"
+	TigerBeetle_CloseAccount_FullMethodName           = "/proto.TigerBeetle/CloseAccount"
+	TigerBeetle_ReopenAccount_FullMethodName          = "/proto.TigerBeetle/ReopenAccount"
 )
 
 // TigerBeetleClient is the client API for TigerBeetle service.
@@ -43,6 +56,19 @@ type TigerBeetleClient interface {
 	GetAccountBalances(ctx context.Context, in *GetAccountBalancesRequest, opts ...grpc.CallOption) (*GetAccountBalancesReply, error)
 	QueryTransfers(ctx context.Context, in *QueryTransfersRequest, opts ...grpc.CallOption) (*QueryTransfersReply, error)
 	QueryAccounts(ctx context.Context, in *QueryAccountsRequest, opts ...grpc.CallOption) (*QueryAccountsReply, error)
+	GetBalancesAt(ctx context.Context, in *GetBalancesAtRequest, opts ...grpc.CallOption) (*GetBalancesAtReply, error)
+	LedgerSummary(ctx context.Context, in *LedgerSummaryRequest, opts ...grpc.CallOption) (*LedgerSummaryReply, error)
+	GetAccountStatement(ctx context.Context, in *GetAccountStatementRequest, opts ...grpc.CallOption) (*GetAccountStatementReply, error)
+	AggregateTransfers(ctx context.Context, in *AggregateTransfersRequest, opts ...grpc.CallOption) (*AggregateTransfersReply, error)
+	ExportLedger(ctx context.Context, in *ExportLedgerRequest, opts ...grpc.CallOption) (*ExportLedgerReply, error)
+	RestoreLedger(ctx context.Context, in *RestoreLedgerRequest, opts ...grpc.CallOption) (*RestoreLedgerReply, error)
+	RegisterAlias(ctx context.Context, in *RegisterAliasRequest, opts ...grpc.CallOption) (*RegisterAliasReply, error)
+	LookupAliases(ctx context.Context, in *LookupAliasesRequest, opts ...grpc.CallOption) (*LookupAliasesReply, error)
+	ListLedgers(ctx context.Context, in *ListLedgersRequest, opts ...grpc.CallOption) (*ListLedgersReply, error)
+	CreateCompoundTransfer(ctx context.Context, in *CreateCompoundTransferRequest, opts ...grpc.CallOption) (*CreateCompoundTransferReply, error)
+	Exchange(ctx context.Context, in *ExchangeRequest, opts ...grpc.CallOption) (*ExchangeReply, error)
+	CloseAccount(ctx context.Context, in *CloseAccountRequest, opts ...grpc.CallOption) (*CloseAccountReply, error)
+	ReopenAccount(ctx context.Context, in *ReopenAccountRequest, opts ...grpc.CallOption) (*ReopenAccountReply, error)
 }
 
 type tigerBeetleClient struct {
@@ -143,6 +169,136 @@ func (c *tigerBeetleClient) QueryAccounts(ctx context.Context, in *QueryAccounts
 	return out, nil
 }
 
+func (c *tigerBeetleClient) GetBalancesAt(ctx context.Context, in *GetBalancesAtRequest, opts ...grpc.CallOption) (*GetBalancesAtReply, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetBalancesAtReply)
+	err := c.cc.Invoke(ctx, TigerBeetle_GetBalancesAt_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *tigerBeetleClient) LedgerSummary(ctx context.Context, in *LedgerSummaryRequest, opts ...grpc.CallOption) (*LedgerSummaryReply, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(LedgerSummaryReply)
+	err := c.cc.Invoke(ctx, TigerBeetle_LedgerSummary_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *tigerBeetleClient) GetAccountStatement(ctx context.Context, in *GetAccountStatementRequest, opts ...grpc.CallOption) (*GetAccountStatementReply, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetAccountStatementReply)
+	err := c.cc.Invoke(ctx, TigerBeetle_GetAccountStatement_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *tigerBeetleClient) AggregateTransfers(ctx context.Context, in *AggregateTransfersRequest, opts ...grpc.CallOption) (*AggregateTransfersReply, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(AggregateTransfersReply)
+	err := c.cc.Invoke(ctx, TigerBeetle_AggregateTransfers_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *tigerBeetleClient) ExportLedger(ctx context.Context, in *ExportLedgerRequest, opts ...grpc.CallOption) (*ExportLedgerReply, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ExportLedgerReply)
+	err := c.cc.Invoke(ctx, TigerBeetle_ExportLedger_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *tigerBeetleClient) RestoreLedger(ctx context.Context, in *RestoreLedgerRequest, opts ...grpc.CallOption) (*RestoreLedgerReply, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RestoreLedgerReply)
+	err := c.cc.Invoke(ctx, TigerBeetle_RestoreLedger_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *tigerBeetleClient) RegisterAlias(ctx context.Context, in *RegisterAliasRequest, opts ...grpc.CallOption) (*RegisterAliasReply, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RegisterAliasReply)
+	err := c.cc.Invoke(ctx, TigerBeetle_RegisterAlias_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *tigerBeetleClient) LookupAliases(ctx context.Context, in *LookupAliasesRequest, opts ...grpc.CallOption) (*LookupAliasesReply, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(LookupAliasesReply)
+	err := c.cc.Invoke(ctx, TigerBeetle_LookupAliases_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *tigerBeetleClient) ListLedgers(ctx context.Context, in *ListLedgersRequest, opts ...grpc.CallOption) (*ListLedgersReply, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListLedgersReply)
+	err := c.cc.Invoke(ctx, TigerBeetle_ListLedgers_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *tigerBeetleClient) CreateCompoundTransfer(ctx context.Context, in *CreateCompoundTransferRequest, opts ...grpc.CallOption) (*CreateCompoundTransferReply, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CreateCompoundTransferReply)
+	err := c.cc.Invoke(ctx, TigerBeetle_CreateCompoundTransfer_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *tigerBeetleClient) Exchange(ctx context.Context, in *ExchangeRequest, opts ...grpc.CallOption) (*ExchangeReply, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ExchangeReply)
+	err := c.cc.Invoke(ctx, TigerBeetle_Exchange_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *tigerBeetleClient) CloseAccount(ctx context.Context, in *CloseAccountRequest, opts ...grpc.CallOption) (*CloseAccountReply, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CloseAccountReply)
+	err := c.cc.Invoke(ctx, TigerBeetle_CloseAccount_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *tigerBeetleClient) ReopenAccount(ctx context.Context, in *ReopenAccountRequest, opts ...grpc.CallOption) (*ReopenAccountReply, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ReopenAccountReply)
+	err := c.cc.Invoke(ctx, TigerBeetle_ReopenAccount_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // TigerBeetleServer is the server API for TigerBeetle service.
 // All implementations must embed UnimplementedTigerBeetleServer
 // for forward compatibility.
@@ -156,6 +312,19 @@ type TigerBeetleServer interface {
 	GetAccountBalances(context.Context, *GetAccountBalancesRequest) (*GetAccountBalancesReply, error)
 	QueryTransfers(context.Context, *QueryTransfersRequest) (*QueryTransfersReply, error)
 	QueryAccounts(context.Context, *QueryAccountsRequest) (*QueryAccountsReply, error)
+	GetBalancesAt(context.Context, *GetBalancesAtRequest) (*GetBalancesAtReply, error)
+	LedgerSummary(context.Context, *LedgerSummaryRequest) (*LedgerSummaryReply, error)
+	GetAccountStatement(context.Context, *GetAccountStatementRequest) (*GetAccountStatementReply, error)
+	AggregateTransfers(context.Context, *AggregateTransfersRequest) (*AggregateTransfersReply, error)
+	ExportLedger(context.Context, *ExportLedgerRequest) (*ExportLedgerReply, error)
+	RestoreLedger(context.Context, *RestoreLedgerRequest) (*RestoreLedgerReply, error)
+	RegisterAlias(context.Context, *RegisterAliasRequest) (*RegisterAliasReply, error)
+	LookupAliases(context.Context, *LookupAliasesRequest) (*LookupAliasesReply, error)
+	ListLedgers(context.Context, *ListLedgersRequest) (*ListLedgersReply, error)
+	CreateCompoundTransfer(context.Context, *CreateCompoundTransferRequest) (*CreateCompoundTransferReply, error)
+	Exchange(context.Context, *ExchangeRequest) (*ExchangeReply, error)
+	CloseAccount(context.Context, *CloseAccountRequest) (*CloseAccountReply, error)
+	ReopenAccount(context.Context, *ReopenAccountRequest) (*ReopenAccountReply, error)
 	mustEmbedUnimplementedTigerBeetleServer()
 }
 
@@ -193,6 +362,45 @@ func (UnimplementedTigerBeetleServer) QueryTransfers(context.Context, *QueryTran
 func (UnimplementedTigerBeetleServer) QueryAccounts(context.Context, *QueryAccountsRequest) (*QueryAccountsReply, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method QueryAccounts not implemented")
 }
+func (UnimplementedTigerBeetleServer) GetBalancesAt(context.Context, *GetBalancesAtRequest) (*GetBalancesAtReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetBalancesAt not implemented")
+}
+func (UnimplementedTigerBeetleServer) LedgerSummary(context.Context, *LedgerSummaryRequest) (*LedgerSummaryReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method LedgerSummary not implemented")
+}
+func (UnimplementedTigerBeetleServer) GetAccountStatement(context.Context, *GetAccountStatementRequest) (*GetAccountStatementReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetAccountStatement not implemented")
+}
+func (UnimplementedTigerBeetleServer) AggregateTransfers(context.Context, *AggregateTransfersRequest) (*AggregateTransfersReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AggregateTransfers not implemented")
+}
+func (UnimplementedTigerBeetleServer) ExportLedger(context.Context, *ExportLedgerRequest) (*ExportLedgerReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ExportLedger not implemented")
+}
+func (UnimplementedTigerBeetleServer) RestoreLedger(context.Context, *RestoreLedgerRequest) (*RestoreLedgerReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RestoreLedger not implemented")
+}
+func (UnimplementedTigerBeetleServer) RegisterAlias(context.Context, *RegisterAliasRequest) (*RegisterAliasReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RegisterAlias not implemented")
+}
+func (UnimplementedTigerBeetleServer) LookupAliases(context.Context, *LookupAliasesRequest) (*LookupAliasesReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method LookupAliases not implemented")
+}
+func (UnimplementedTigerBeetleServer) ListLedgers(context.Context, *ListLedgersRequest) (*ListLedgersReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListLedgers not implemented")
+}
+func (UnimplementedTigerBeetleServer) CreateCompoundTransfer(context.Context, *CreateCompoundTransferRequest) (*CreateCompoundTransferReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateCompoundTransfer not implemented")
+}
+func (UnimplementedTigerBeetleServer) Exchange(context.Context, *ExchangeRequest) (*ExchangeReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Exchange not implemented")
+}
+func (UnimplementedTigerBeetleServer) CloseAccount(context.Context, *CloseAccountRequest) (*CloseAccountReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CloseAccount not implemented")
+}
+func (UnimplementedTigerBeetleServer) ReopenAccount(context.Context, *ReopenAccountRequest) (*ReopenAccountReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ReopenAccount not implemented")
+}
 func (UnimplementedTigerBeetleServer) mustEmbedUnimplementedTigerBeetleServer() {}
 func (UnimplementedTigerBeetleServer) testEmbeddedByValue()                     {}
 
@@ -376,6 +584,240 @@ func _TigerBeetle_QueryAccounts_Handler(srv interface{}, ctx context.Context, de
 	return interceptor(ctx, in, info, handler)
 }
 
+func _TigerBeetle_GetBalancesAt_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetBalancesAtRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TigerBeetleServer).GetBalancesAt(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TigerBeetle_GetBalancesAt_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TigerBeetleServer).GetBalancesAt(ctx, req.(*GetBalancesAtRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TigerBeetle_LedgerSummary_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(LedgerSummaryRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TigerBeetleServer).LedgerSummary(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TigerBeetle_LedgerSummary_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TigerBeetleServer).LedgerSummary(ctx, req.(*LedgerSummaryRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TigerBeetle_GetAccountStatement_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetAccountStatementRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TigerBeetleServer).GetAccountStatement(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TigerBeetle_GetAccountStatement_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TigerBeetleServer).GetAccountStatement(ctx, req.(*GetAccountStatementRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TigerBeetle_AggregateTransfers_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AggregateTransfersRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TigerBeetleServer).AggregateTransfers(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TigerBeetle_AggregateTransfers_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TigerBeetleServer).AggregateTransfers(ctx, req.(*AggregateTransfersRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TigerBeetle_ExportLedger_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ExportLedgerRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TigerBeetleServer).ExportLedger(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TigerBeetle_ExportLedger_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TigerBeetleServer).ExportLedger(ctx, req.(*ExportLedgerRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TigerBeetle_RestoreLedger_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RestoreLedgerRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TigerBeetleServer).RestoreLedger(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TigerBeetle_RestoreLedger_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TigerBeetleServer).RestoreLedger(ctx, req.(*RestoreLedgerRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TigerBeetle_RegisterAlias_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RegisterAliasRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TigerBeetleServer).RegisterAlias(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TigerBeetle_RegisterAlias_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TigerBeetleServer).RegisterAlias(ctx, req.(*RegisterAliasRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TigerBeetle_LookupAliases_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(LookupAliasesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TigerBeetleServer).LookupAliases(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TigerBeetle_LookupAliases_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TigerBeetleServer).LookupAliases(ctx, req.(*LookupAliasesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TigerBeetle_ListLedgers_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListLedgersRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TigerBeetleServer).ListLedgers(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TigerBeetle_ListLedgers_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TigerBeetleServer).ListLedgers(ctx, req.(*ListLedgersRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TigerBeetle_CreateCompoundTransfer_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateCompoundTransferRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TigerBeetleServer).CreateCompoundTransfer(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TigerBeetle_CreateCompoundTransfer_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TigerBeetleServer).CreateCompoundTransfer(ctx, req.(*CreateCompoundTransferRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TigerBeetle_Exchange_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ExchangeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TigerBeetleServer).Exchange(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TigerBeetle_Exchange_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TigerBeetleServer).Exchange(ctx, req.(*ExchangeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TigerBeetle_CloseAccount_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CloseAccountRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TigerBeetleServer).CloseAccount(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TigerBeetle_CloseAccount_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TigerBeetleServer).CloseAccount(ctx, req.(*CloseAccountRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TigerBeetle_ReopenAccount_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ReopenAccountRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TigerBeetleServer).ReopenAccount(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TigerBeetle_ReopenAccount_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TigerBeetleServer).ReopenAccount(ctx, req.(*ReopenAccountRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // TigerBeetle_ServiceDesc is the grpc.ServiceDesc for TigerBeetle service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -419,6 +861,58 @@ var TigerBeetle_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "QueryAccounts",
 			Handler:    _TigerBeetle_QueryAccounts_Handler,
 		},
+		{
+			MethodName: "GetBalancesAt",
+			Handler:    _TigerBeetle_GetBalancesAt_Handler,
+		},
+		{
+			MethodName: "LedgerSummary",
+			Handler:    _TigerBeetle_LedgerSummary_Handler,
+		},
+		{
+			MethodName: "GetAccountStatement",
+			Handler:    _TigerBeetle_GetAccountStatement_Handler,
+		},
+		{
+			MethodName: "AggregateTransfers",
+			Handler:    _TigerBeetle_AggregateTransfers_Handler,
+		},
+		{
+			MethodName: "ExportLedger",
+			Handler:    _TigerBeetle_ExportLedger_Handler,
+		},
+		{
+			MethodName: "RestoreLedger",
+			Handler:    _TigerBeetle_RestoreLedger_Handler,
+		},
+		{
+			MethodName: "RegisterAlias",
+			Handler:    _TigerBeetle_RegisterAlias_Handler,
+		},
+		{
+			MethodName: "LookupAliases",
+			Handler:    _TigerBeetle_LookupAliases_Handler,
+		},
+		{
+			MethodName: "ListLedgers",
+			Handler:    _TigerBeetle_ListLedgers_Handler,
+		},
+		{
+			MethodName: "CreateCompoundTransfer",
+			Handler:    _TigerBeetle_CreateCompoundTransfer_Handler,
+		},
+		{
+			MethodName: "Exchange",
+			Handler:    _TigerBeetle_Exchange_Handler,
+		},
+		{
+			MethodName: "CloseAccount",
+			Handler:    _TigerBeetle_CloseAccount_Handler,
+		},
+		{
+			MethodName: "ReopenAccount",
+			Handler:    _TigerBeetle_ReopenAccount_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "proto/tigerbeetle.proto",
@@ -0,0 +1,200 @@
+// Package quota rate limits and caps the requests in flight of each client.
+//
+//	default:
+//	  write-transfers: {rate: 100, burst: 200, in_flight: 10}
+//	  read: {rate: 500, in_flight: 50}
+//	clients:
+//	  backend:
+//	    write-transfers: {rate: 1000, burst: 2000, in_flight: 100}
+//
+// Operations are the scopes of the api, read, write-accounts, write-transfers and admin.
+// A client is the name of its api key, the common name of its client certificate, or its ip,
+// and uses the limits of its entry in clients, falling back to default per operation.
+// A rate of 0 or an in_flight of 0 is unlimited.
+package quota
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/lil5/tigerbeetle_api/auth"
+	"github.com/lil5/tigerbeetle_api/metrics"
+	"golang.org/x/time/rate"
+	"gopkg.in/yaml.v3"
+)
+
+var (
+	ErrRateLimited = errors.New("rate limit exceeded")
+	ErrInFlight    = errors.New("too many requests in flight")
+)
+
+// inFlightRetry is the Retry-After of a request rejected for the requests in flight.
+const inFlightRetry = time.Second
+
+// maxIdleBuckets is the number of buckets kept before idle ones are dropped.
+const maxIdleBuckets = 10_000
+
+var operations = []string{string(auth.ScopeRead), string(auth.ScopeWriteAccounts), string(auth.ScopeWriteTransfers), string(auth.ScopeAdmin)}
+
+type Limit struct {
+	// Rate is the number of requests per second
+	Rate float64 `yaml:"rate"`
+	// Burst defaults to the rate, rounded up
+	Burst    int `yaml:"burst"`
+	InFlight int `yaml:"in_flight"`
+}
+
+type Quotas struct {
+	Default map[string]Limit            `yaml:"default"`
+	Clients map[string]map[string]Limit `yaml:"clients"`
+
+	mu      sync.Mutex
+	buckets map[bucketKey]*bucket
+}
+
+type bucketKey struct {
+	client    Client
+	operation string
+}
+
+type bucket struct {
+	limiter  *rate.Limiter
+	inFlight int
+	used     time.Time
+}
+
+func Load(path string) (*Quotas, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return Parse(b)
+}
+
+func Parse(b []byte) (*Quotas, error) {
+	q := &Quotas{buckets: map[bucketKey]*bucket{}}
+	if err := yaml.Unmarshal(b, q); err != nil {
+		return nil, fmt.Errorf("parsing quotas: %w", err)
+	}
+	check := func(where string, limits map[string]Limit) error {
+		for op, l := range limits {
+			if !slices.Contains(operations, op) {
+				return fmt.Errorf("quotas: %s: unknown operation %q", where, op)
+			}
+			if l.Rate < 0 || l.Burst < 0 || l.InFlight < 0 {
+				return fmt.Errorf("quotas: %s: %s: limits must not be negative", where, op)
+			}
+		}
+		return nil
+	}
+	if err := check("default", q.Default); err != nil {
+		return nil, err
+	}
+	for name, limits := range q.Clients {
+		if err := check(name, limits); err != nil {
+			return nil, err
+		}
+	}
+	return q, nil
+}
+
+// Client identifies who a quota applies to.
+type Client struct {
+	// Kind is "key", "cert" or "ip"
+	Kind string
+	Name string
+}
+
+// ClientOf returns the client of a request, the first of its key, client certificate and ip.
+func ClientOf(key *auth.Key, identity, ip string) Client {
+	switch {
+	case key != nil:
+		return Client{Kind: "key", Name: key.Name}
+	case identity != "":
+		return Client{Kind: "cert", Name: identity}
+	}
+	return Client{Kind: "ip", Name: ip}
+}
+
+func (q *Quotas) limit(client Client, operation string) Limit {
+	if l, ok := q.Clients[client.Name][operation]; ok {
+		return l
+	}
+	return q.Default[operation]
+}
+
+// Acquire takes a request of operation from the quota of client.
+// On success release must be called once the request is done,
+// otherwise retryAfter tells when the client may try again.
+func (q *Quotas) Acquire(client Client, operation string) (release func(), retryAfter time.Duration, err error) {
+	l := q.limit(client, operation)
+	if l.Rate == 0 && l.InFlight == 0 {
+		return func() {}, 0, nil
+	}
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	now := time.Now()
+	key := bucketKey{client: client, operation: operation}
+	b, ok := q.buckets[key]
+	if !ok {
+		if len(q.buckets) >= maxIdleBuckets {
+			q.dropIdle(now)
+		}
+		b = &bucket{limiter: rate.NewLimiter(rate.Inf, 0)}
+		if l.Rate > 0 {
+			burst := l.Burst
+			if burst == 0 {
+				burst = int(math.Ceil(l.Rate))
+			}
+			b.limiter = rate.NewLimiter(rate.Limit(l.Rate), burst)
+		}
+		q.buckets[key] = b
+	}
+	b.used = now
+
+	if l.InFlight > 0 && b.inFlight >= l.InFlight {
+		metrics.TotalQuotaRejections.WithLabelValues(operation, "in_flight").Inc()
+		return nil, inFlightRetry, ErrInFlight
+	}
+	r := b.limiter.ReserveN(now, 1)
+	if !r.OK() {
+		metrics.TotalQuotaRejections.WithLabelValues(operation, "rate").Inc()
+		return nil, time.Second, ErrRateLimited
+	}
+	if delay := r.DelayFrom(now); delay > 0 {
+		r.CancelAt(now)
+		metrics.TotalQuotaRejections.WithLabelValues(operation, "rate").Inc()
+		return nil, delay, ErrRateLimited
+	}
+
+	b.inFlight++
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			q.mu.Lock()
+			b.inFlight--
+			q.mu.Unlock()
+		})
+	}, 0, nil
+}
+
+// dropIdle removes the buckets without requests in flight that were not used for a minute,
+// so clients seen once, such as ips, do not pile up.
+func (q *Quotas) dropIdle(now time.Time) {
+	for key, b := range q.buckets {
+		if b.inFlight == 0 && now.Sub(b.used) > time.Minute {
+			delete(q.buckets, key)
+		}
+	}
+}
+
+// RetryAfter formats d as the seconds of a Retry-After header, at least 1.
+func RetryAfter(d time.Duration) string {
+	return fmt.Sprint(max(1, int(math.Ceil(d.Seconds()))))
+}
@@ -0,0 +1,82 @@
+package quota
+
+import (
+	"testing"
+	"time"
+
+	"github.com/lil5/tigerbeetle_api/auth"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAcquire(t *testing.T) {
+	q, err := Parse([]byte(`
+default:
+  write-transfers: {rate: 1, burst: 2}
+  read: {in_flight: 1}
+clients:
+  backend:
+    write-transfers: {rate: 1000}
+`))
+	require.NoError(t, err)
+	ip := ClientOf(nil, "", "10.0.0.1")
+
+	t.Run("should rate limit after the burst", func(t *testing.T) {
+		for range 2 {
+			release, _, err := q.Acquire(ip, "write-transfers")
+			require.NoError(t, err)
+			release()
+		}
+		_, retryAfter, err := q.Acquire(ip, "write-transfers")
+		assert.ErrorIs(t, err, ErrRateLimited)
+		assert.InDelta(t, time.Second, retryAfter, float64(100*time.Millisecond))
+		assert.Equal(t, "1", RetryAfter(retryAfter))
+
+		// Each client has its own bucket.
+		release, _, err := q.Acquire(ClientOf(nil, "", "10.0.0.2"), "write-transfers")
+		require.NoError(t, err)
+		release()
+	})
+
+	t.Run("should use the limits of the client", func(t *testing.T) {
+		backend := ClientOf(&auth.Key{Name: "backend"}, "backend.internal", "10.0.0.1")
+		assert.Equal(t, Client{Kind: "key", Name: "backend"}, backend)
+		for range 10 {
+			release, _, err := q.Acquire(backend, "write-transfers")
+			require.NoError(t, err)
+			release()
+		}
+	})
+
+	t.Run("should cap the requests in flight", func(t *testing.T) {
+		release, _, err := q.Acquire(ip, "read")
+		require.NoError(t, err)
+		_, _, err = q.Acquire(ip, "read")
+		assert.ErrorIs(t, err, ErrInFlight)
+		release()
+		release()
+		release, _, err = q.Acquire(ip, "read")
+		require.NoError(t, err)
+		release()
+	})
+
+	t.Run("should not limit operations without a quota", func(t *testing.T) {
+		for range 10 {
+			release, _, err := q.Acquire(ip, "admin")
+			require.NoError(t, err)
+			release()
+		}
+	})
+}
+
+func TestParseInvalid(t *testing.T) {
+	_, err := Parse([]byte("default:\n  create: {rate: 1}\n"))
+	assert.ErrorContains(t, err, "unknown operation")
+	_, err = Parse([]byte("clients:\n  a:\n    read: {rate: -1}\n"))
+	assert.ErrorContains(t, err, "negative")
+}
+
+func TestLoadExample(t *testing.T) {
+	_, err := Load("../quotas.example.yaml")
+	assert.NoError(t, err)
+}
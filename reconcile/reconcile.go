@@ -0,0 +1,479 @@
+// Package reconcile matches the rows of an external statement file, such as a bank
+// settlement file, to the transfers in TigerBeetle.
+package reconcile
+
+import (
+	"cmp"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/lil5/tigerbeetle_api/config"
+	"github.com/lil5/tigerbeetle_api/metrics"
+	"github.com/samber/lo"
+	tigerbeetle_go "github.com/tigerbeetle/tigerbeetle-go"
+	"github.com/tigerbeetle/tigerbeetle-go/pkg/types"
+)
+
+type MatchBy string
+
+const (
+	MatchByID          MatchBy = "id"
+	MatchByUserData128 MatchBy = "user_data128"
+	MatchByUserData64  MatchBy = "user_data64"
+)
+
+type Status string
+
+const (
+	StatusMatched         Status = "matched"
+	StatusMissingInLedger Status = "missing_in_ledger"
+	StatusMissingInFile   Status = "missing_in_file"
+	StatusAmountMismatch  Status = "amount_mismatch"
+	// StatusInvalid is a row that could not be parsed, Error tells why
+	StatusInvalid Status = "invalid"
+)
+
+var (
+	ErrUnknownMatchBy = errors.New("match_by must be id, user_data128 or user_data64")
+	ErrNoRows         = errors.New("the statement file has no rows")
+	// ErrInvalidFile is the error of a statement file that can not be read at all, such as a missing column.
+	ErrInvalidFile = errors.New("invalid statement file")
+	ErrTooManyRows = errors.New("the statement file has too many rows")
+)
+
+// lookupWorkers is the number of concurrent queries for the rows outside the window.
+const lookupWorkers = 8
+
+const dateLayout = "2006-01-02"
+
+type Options struct {
+	MatchBy MatchBy
+	// Ledger and Code restrict the transfers expected in the file, 0 matches any.
+	Ledger uint32
+	Code   uint16
+	// Columns renames csv header columns to amount, reference and date.
+	Columns map[string]string
+	// Location of the dates in the file, defaults to UTC.
+	Location *time.Location
+	// MaxScan caps the transfers read from the window, 0 is no limit.
+	MaxScan uint64
+	// MaxRows caps the rows of the file, reading stops with ErrTooManyRows past it, 0 is no limit.
+	MaxRows uint64
+}
+
+// Entry is a row of the file, a transfer, or both when they were matched.
+type Entry struct {
+	Status Status `json:"status"`
+	// Row in the file starting at 1 after the header, 0 for missing_in_file
+	Row          uint64 `json:"row,omitempty"`
+	Reference    string `json:"reference"`
+	Date         string `json:"date,omitempty"`
+	FileAmount   string `json:"file_amount,omitempty"`
+	LedgerAmount string `json:"ledger_amount,omitempty"`
+	TransferID   string `json:"transfer_id,omitempty"`
+	Timestamp    uint64 `json:"timestamp,omitempty"`
+	Error        string `json:"error,omitempty"`
+}
+
+type Report struct {
+	// Transfers from TimestampMin up to TimestampMax are expected in the file
+	TimestampMin    uint64 `json:"timestamp_min"`
+	TimestampMax    uint64 `json:"timestamp_max"`
+	Matched         uint64 `json:"matched"`
+	MissingInLedger uint64 `json:"missing_in_ledger"`
+	MissingInFile   uint64 `json:"missing_in_file"`
+	AmountMismatch  uint64 `json:"amount_mismatch"`
+	Invalid         uint64 `json:"invalid"`
+	// Truncated is set when the window held more than MaxScan transfers,
+	// transfers after LastTimestamp are not reported as missing_in_file.
+	Truncated     bool    `json:"truncated"`
+	LastTimestamp uint64  `json:"last_timestamp,omitempty"`
+	Entries       []Entry `json:"entries"`
+}
+
+func (r *Report) add(e Entry) {
+	switch e.Status {
+	case StatusMatched:
+		r.Matched++
+	case StatusMissingInLedger:
+		r.MissingInLedger++
+	case StatusMissingInFile:
+		r.MissingInFile++
+	case StatusAmountMismatch:
+		r.AmountMismatch++
+	case StatusInvalid:
+		r.Invalid++
+	}
+	r.Entries = append(r.Entries, e)
+}
+
+type row struct {
+	Row       uint64
+	Amount    types.Uint128
+	Reference string
+	Key       string
+	Date      time.Time
+}
+
+// key returns the reference of t in the normalised form of the row keys.
+func key(by MatchBy, t types.Transfer) string {
+	switch by {
+	case MatchByID:
+		return t.ID.String()
+	case MatchByUserData128:
+		return t.UserData128.String()
+	default:
+		return strconv.FormatUint(t.UserData64, 10)
+	}
+}
+
+func parseKey(by MatchBy, reference string) (string, error) {
+	switch by {
+	case MatchByUserData64:
+		v, err := strconv.ParseUint(reference, 10, 64)
+		if err != nil {
+			return "", err
+		}
+		return strconv.FormatUint(v, 10), nil
+	default:
+		v, err := types.HexStringToUint128(reference)
+		if err != nil {
+			return "", err
+		}
+		return v.String(), nil
+	}
+}
+
+func decimal(v types.Uint128) string {
+	b := v.BigInt()
+	return b.String()
+}
+
+func parseAmount(s string) (types.Uint128, error) {
+	b, ok := new(big.Int).SetString(s, 10)
+	if !ok || b.Sign() < 0 || b.BitLen() > 128 {
+		return types.Uint128{}, fmt.Errorf("invalid amount %q", s)
+	}
+	return types.BigIntToUint128(*b), nil
+}
+
+func parseDate(s string, loc *time.Location) (time.Time, error) {
+	if t, err := time.ParseInLocation(dateLayout, s, loc); err == nil {
+		return t, nil
+	}
+	return time.Parse(time.RFC3339, s)
+}
+
+// readRows returns the rows of r and an invalid entry for each row that could not be parsed.
+func readRows(r io.Reader, opts Options) ([]row, []Entry, error) {
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = -1
+	header, err := cr.Read()
+	if err != nil {
+		return nil, nil, fmt.Errorf("%w: reading csv header: %w", ErrInvalidFile, err)
+	}
+	index := map[string]int{}
+	for i, col := range header {
+		col = strings.TrimSpace(col)
+		if mapped, ok := opts.Columns[col]; ok {
+			col = mapped
+		}
+		index[strings.ToLower(col)] = i
+	}
+	for _, name := range []string{"amount", "reference", "date"} {
+		if _, ok := index[name]; !ok {
+			return nil, nil, fmt.Errorf("%w: csv column %s is missing", ErrInvalidFile, name)
+		}
+	}
+
+	rows := []row{}
+	invalid := []Entry{}
+	for n := uint64(1); ; n++ {
+		record, err := cr.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, nil, fmt.Errorf("%w: row %d: %w", ErrInvalidFile, n, err)
+		}
+		if opts.MaxRows > 0 && n > opts.MaxRows {
+			return nil, nil, fmt.Errorf("%w: more than %d rows", ErrTooManyRows, opts.MaxRows)
+		}
+		field := func(name string) string {
+			i := index[name]
+			if i >= len(record) {
+				return ""
+			}
+			return strings.TrimSpace(record[i])
+		}
+		reference := field("reference")
+		amount, err := parseAmount(field("amount"))
+		if err != nil {
+			invalid = append(invalid, Entry{Status: StatusInvalid, Row: n, Reference: reference, Error: err.Error()})
+			continue
+		}
+		k, err := parseKey(opts.MatchBy, reference)
+		if err != nil {
+			invalid = append(invalid, Entry{Status: StatusInvalid, Row: n, Reference: reference, Error: fmt.Sprintf("invalid reference %q: %v", reference, err)})
+			continue
+		}
+		date, err := parseDate(field("date"), opts.Location)
+		if err != nil {
+			invalid = append(invalid, Entry{Status: StatusInvalid, Row: n, Reference: reference, Error: err.Error()})
+			continue
+		}
+		rows = append(rows, row{Row: n, Amount: amount, Reference: reference, Key: k, Date: date})
+	}
+	if len(rows) == 0 && len(invalid) == 0 {
+		return nil, nil, ErrNoRows
+	}
+	return rows, invalid, nil
+}
+
+// settles reports whether t moves money in the way a statement shows it.
+// Pending and voiding transfers do not, the posting transfer does.
+func settles(t types.Transfer) bool {
+	flags := t.TransferFlags()
+	return !flags.Pending && !flags.VoidPendingTransfer
+}
+
+// Run reconciles the csv statement r, with the columns amount, reference and date,
+// against the settled transfers from the first date in the file up to the end of the last date.
+// Rows with a reference outside that window are looked up by reference.
+// Rows that can not be parsed are reported as invalid, errors of the file itself wrap ErrInvalidFile.
+// The rows are held in memory, MaxRows bounds them and the lookups of rows outside the window.
+func Run(tb tigerbeetle_go.Client, r io.Reader, opts Options) (*Report, error) {
+	switch opts.MatchBy {
+	case MatchByID, MatchByUserData128, MatchByUserData64:
+	default:
+		return nil, ErrUnknownMatchBy
+	}
+	if opts.Location == nil {
+		opts.Location = time.UTC
+	}
+	rows, invalid, err := readRows(r, opts)
+	if err != nil {
+		return nil, err
+	}
+	if len(rows) == 0 {
+		report := &Report{Entries: []Entry{}}
+		for _, e := range invalid {
+			report.add(e)
+		}
+		return report, nil
+	}
+
+	first := lo.MinBy(rows, func(a, b row) bool { return a.Date.Before(b.Date) }).Date
+	last := lo.MaxBy(rows, func(a, b row) bool { return a.Date.After(b.Date) }).Date
+	fy, fm, fd := first.In(opts.Location).Date()
+	ly, lm, ld := last.In(opts.Location).Date()
+	report := &Report{
+		TimestampMin: uint64(time.Date(fy, fm, fd, 0, 0, 0, 0, opts.Location).UnixNano()),
+		TimestampMax: uint64(time.Date(ly, lm, ld+1, 0, 0, 0, 0, opts.Location).UnixNano()) - 1,
+		Entries:      []Entry{},
+	}
+
+	// Transfers in the window by key, in timestamp order.
+	window := map[string][]types.Transfer{}
+	order := []types.Transfer{}
+	filter := types.QueryFilter{
+		Ledger:       opts.Ledger,
+		Code:         opts.Code,
+		TimestampMin: report.TimestampMin,
+		TimestampMax: report.TimestampMax,
+	}
+	var scanned uint64
+	for {
+		limit := uint64(config.TB_MAX_BATCH_SIZE)
+		if opts.MaxScan > 0 {
+			// One transfer past the cap tells a full window from a truncated one.
+			limit = min(limit, opts.MaxScan-scanned+1)
+		}
+		filter.Limit = uint32(limit)
+		metrics.TotalTbQueryTransfersCall.Inc()
+		res, err := tb.QueryTransfers(filter)
+		if err != nil {
+			return nil, err
+		}
+		full := len(res) == int(limit)
+		if opts.MaxScan > 0 && scanned+uint64(len(res)) > opts.MaxScan {
+			res = res[:opts.MaxScan-scanned]
+			report.Truncated = true
+		}
+		scanned += uint64(len(res))
+		for _, t := range res {
+			report.LastTimestamp = t.Timestamp
+			if !settles(t) {
+				continue
+			}
+			k := key(opts.MatchBy, t)
+			window[k] = append(window[k], t)
+			order = append(order, t)
+		}
+		if !full || report.Truncated {
+			break
+		}
+		filter.TimestampMin = res[len(res)-1].Timestamp + 1
+	}
+
+	outside, err := lookupOutside(tb, rows, window, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, e := range invalid {
+		report.add(e)
+	}
+	used := map[types.Uint128]bool{}
+	for _, rw := range rows {
+		entry := Entry{
+			Row:        rw.Row,
+			Reference:  rw.Reference,
+			Date:       rw.Date.In(opts.Location).Format(dateLayout),
+			FileAmount: decimal(rw.Amount),
+		}
+		candidates := lo.Filter(lo.Flatten([][]types.Transfer{window[rw.Key], outside[rw.Key]}), func(t types.Transfer, _ int) bool {
+			return !used[t.ID]
+		})
+		if len(candidates) == 0 {
+			entry.Status = StatusMissingInLedger
+			report.add(entry)
+			continue
+		}
+		// Prefer a transfer with the same amount when a reference is used more than once.
+		t, ok := lo.Find(candidates, func(t types.Transfer) bool { return t.Amount == rw.Amount })
+		if ok {
+			entry.Status = StatusMatched
+		} else {
+			t = candidates[0]
+			entry.Status = StatusAmountMismatch
+		}
+		used[t.ID] = true
+		entry.LedgerAmount = decimal(t.Amount)
+		entry.TransferID = t.ID.String()
+		entry.Timestamp = t.Timestamp
+		report.add(entry)
+	}
+
+	for _, t := range order {
+		if used[t.ID] {
+			continue
+		}
+		report.add(Entry{
+			Status:       StatusMissingInFile,
+			Reference:    key(opts.MatchBy, t),
+			LedgerAmount: decimal(t.Amount),
+			TransferID:   t.ID.String(),
+			Timestamp:    t.Timestamp,
+		})
+	}
+	return report, nil
+}
+
+// lookupOutside finds the transfers of rows that have no match in the window,
+// by id with LookupTransfers or by user data with QueryTransfers.
+func lookupOutside(tb tigerbeetle_go.Client, rows []row, window map[string][]types.Transfer, opts Options) (map[string][]types.Transfer, error) {
+	missing := lo.Uniq(lo.FilterMap(rows, func(rw row, _ int) (string, bool) {
+		_, ok := window[rw.Key]
+		return rw.Key, !ok
+	}))
+	found := map[string][]types.Transfer{}
+	if len(missing) == 0 {
+		return found, nil
+	}
+
+	if opts.MatchBy == MatchByID {
+		for _, chunk := range lo.Chunk(missing, config.TB_MAX_BATCH_SIZE) {
+			ids := lo.Map(chunk, func(k string, _ int) types.Uint128 {
+				id, _ := types.HexStringToUint128(k)
+				return id
+			})
+			metrics.TotalTbLookupTransfersCall.Inc()
+			res, err := tb.LookupTransfers(ids)
+			if err != nil {
+				return nil, err
+			}
+			for _, t := range res {
+				if settles(t) {
+					found[t.ID.String()] = append(found[t.ID.String()], t)
+				}
+			}
+		}
+		return found, nil
+	}
+
+	// A query filters on a single user data value, the queries run lookupWorkers at a time
+	// and the remaining ones are skipped after the first error.
+	// A zero filter field is not filtered on, zero never identifies a transfer.
+	missing = lo.Without(missing, "0")
+	keys := make(chan string)
+	var mu sync.Mutex
+	var firstErr error
+	var wg sync.WaitGroup
+	for range min(lookupWorkers, len(missing)) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for k := range keys {
+				mu.Lock()
+				failed := firstErr != nil
+				mu.Unlock()
+				if failed {
+					continue
+				}
+				filter := types.QueryFilter{Ledger: opts.Ledger, Code: opts.Code, Limit: config.TB_MAX_BATCH_SIZE}
+				if opts.MatchBy == MatchByUserData128 {
+					filter.UserData128, _ = types.HexStringToUint128(k)
+				} else {
+					filter.UserData64, _ = strconv.ParseUint(k, 10, 64)
+				}
+				metrics.TotalTbQueryTransfersCall.Inc()
+				res, err := tb.QueryTransfers(filter)
+				mu.Lock()
+				if err != nil {
+					firstErr = cmp.Or(firstErr, err)
+				} else {
+					found[k] = lo.Filter(res, func(t types.Transfer, _ int) bool { return settles(t) })
+				}
+				mu.Unlock()
+			}
+		}()
+	}
+	for _, k := range missing {
+		keys <- k
+	}
+	close(keys)
+	wg.Wait()
+	if firstErr != nil {
+		return nil, firstErr
+	}
+	return found, nil
+}
+
+// WriteCSV writes the entries of the report as csv.
+func WriteCSV(w io.Writer, report *Report) error {
+	cw := csv.NewWriter(w)
+	cw.Write([]string{"status", "row", "reference", "date", "file_amount", "ledger_amount", "transfer_id", "timestamp", "error"})
+	for _, e := range report.Entries {
+		cw.Write([]string{
+			string(e.Status),
+			strconv.FormatUint(e.Row, 10),
+			e.Reference,
+			e.Date,
+			e.FileAmount,
+			e.LedgerAmount,
+			e.TransferID,
+			strconv.FormatUint(e.Timestamp, 10),
+			e.Error,
+		})
+	}
+	cw.Flush()
+	return cw.Error()
+}
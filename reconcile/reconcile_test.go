@@ -0,0 +1,188 @@
+package reconcile
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	tigerbeetle_go "github.com/tigerbeetle/tigerbeetle-go"
+	"github.com/tigerbeetle/tigerbeetle-go/pkg/types"
+)
+
+type fakeClient struct {
+	tigerbeetle_go.Client
+	transfers []types.Transfer
+}
+
+func (c *fakeClient) QueryTransfers(filter types.QueryFilter) ([]types.Transfer, error) {
+	res := []types.Transfer{}
+	for _, t := range c.transfers {
+		if filter.UserData64 != 0 && t.UserData64 != filter.UserData64 {
+			continue
+		}
+		if filter.Ledger != 0 && t.Ledger != filter.Ledger {
+			continue
+		}
+		if filter.TimestampMin != 0 && t.Timestamp < filter.TimestampMin {
+			continue
+		}
+		if filter.TimestampMax != 0 && t.Timestamp > filter.TimestampMax {
+			continue
+		}
+		res = append(res, t)
+		if filter.Limit != 0 && len(res) == int(filter.Limit) {
+			break
+		}
+	}
+	return res, nil
+}
+
+func (c *fakeClient) LookupTransfers(ids []types.Uint128) ([]types.Transfer, error) {
+	res := []types.Transfer{}
+	for _, t := range c.transfers {
+		for _, id := range ids {
+			if t.ID == id {
+				res = append(res, t)
+			}
+		}
+	}
+	return res, nil
+}
+
+func ts(date string) uint64 {
+	t, _ := time.Parse(time.RFC3339, date)
+	return uint64(t.UnixNano())
+}
+
+func TestReconcileUserData64(t *testing.T) {
+	client := &fakeClient{transfers: []types.Transfer{
+		{ID: types.ToUint128(1), UserData64: 1001, Amount: types.ToUint128(10), Ledger: 1, Timestamp: ts("2025-01-02T08:00:00Z")},
+		{ID: types.ToUint128(2), UserData64: 1002, Amount: types.ToUint128(20), Ledger: 1, Timestamp: ts("2025-01-02T09:00:00Z")},
+		{ID: types.ToUint128(3), UserData64: 1003, Amount: types.ToUint128(30), Ledger: 1, Timestamp: ts("2025-01-02T10:00:00Z")},
+		// Pending transfers are not expected in the file
+		{ID: types.ToUint128(4), UserData64: 1004, Amount: types.ToUint128(40), Ledger: 1, Timestamp: ts("2025-01-02T11:00:00Z"), Flags: types.TransferFlags{Pending: true}.ToUint16()},
+		// Other ledger
+		{ID: types.ToUint128(5), UserData64: 1005, Amount: types.ToUint128(50), Ledger: 2, Timestamp: ts("2025-01-02T12:00:00Z")},
+		// Settled the day before the file
+		{ID: types.ToUint128(6), UserData64: 1006, Amount: types.ToUint128(60), Ledger: 1, Timestamp: ts("2025-01-01T12:00:00Z")},
+	}}
+	input := strings.Join([]string{
+		"Betrag,Referenz,Datum",
+		"10,1001,2025-01-02",
+		"25,1002,2025-01-02",
+		"70,1007,2025-01-03",
+		"60,1006,2025-01-02",
+	}, "\n")
+
+	report, err := Run(client, strings.NewReader(input), Options{
+		MatchBy: MatchByUserData64,
+		Ledger:  1,
+		Columns: map[string]string{"Betrag": "amount", "Referenz": "reference", "Datum": "date"},
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, ts("2025-01-02T00:00:00Z"), report.TimestampMin)
+	assert.Equal(t, ts("2025-01-04T00:00:00Z")-1, report.TimestampMax)
+	assert.Equal(t, uint64(2), report.Matched)
+	assert.Equal(t, uint64(1), report.AmountMismatch)
+	assert.Equal(t, uint64(1), report.MissingInLedger)
+	assert.Equal(t, uint64(1), report.MissingInFile)
+
+	statuses := map[string]Status{}
+	for _, e := range report.Entries {
+		statuses[e.Reference] = e.Status
+	}
+	assert.Equal(t, map[string]Status{
+		"1001": StatusMatched,
+		"1002": StatusAmountMismatch,
+		"1003": StatusMissingInFile,
+		"1006": StatusMatched,
+		"1007": StatusMissingInLedger,
+	}, statuses)
+}
+
+func TestReconcileID(t *testing.T) {
+	client := &fakeClient{transfers: []types.Transfer{
+		{ID: types.ToUint128(0xa1), Amount: types.ToUint128(10), Timestamp: ts("2025-01-02T08:00:00Z")},
+		{ID: types.ToUint128(0xa2), Amount: types.ToUint128(10), Timestamp: ts("2024-12-31T08:00:00Z")},
+	}}
+	input := "amount,reference,date\n10,A1,2025-01-02\n10,a2,2025-01-02\n10,a1,2025-01-02\n"
+
+	report, err := Run(client, strings.NewReader(input), Options{MatchBy: MatchByID})
+	require.NoError(t, err)
+	assert.Equal(t, uint64(2), report.Matched)
+	// A transfer is only matched once
+	assert.Equal(t, uint64(1), report.MissingInLedger)
+	assert.Equal(t, StatusMissingInLedger, report.Entries[2].Status)
+}
+
+func TestReconcileInvalid(t *testing.T) {
+	_, err := Run(&fakeClient{}, strings.NewReader("amount,reference\n"), Options{MatchBy: MatchByID})
+	assert.ErrorContains(t, err, "date")
+
+	_, err = Run(&fakeClient{}, strings.NewReader("amount,reference,date\n"), Options{MatchBy: MatchByID})
+	assert.ErrorIs(t, err, ErrNoRows)
+
+	report, err := Run(&fakeClient{}, strings.NewReader("amount,reference,date\n1,x,2025-01-01\n"), Options{MatchBy: MatchByUserData64})
+	require.NoError(t, err)
+	assert.Equal(t, uint64(1), report.Invalid)
+	assert.Equal(t, StatusInvalid, report.Entries[0].Status)
+	assert.Contains(t, report.Entries[0].Error, "invalid reference")
+
+	_, err = Run(&fakeClient{}, strings.NewReader(""), Options{MatchBy: "code"})
+	assert.ErrorIs(t, err, ErrUnknownMatchBy)
+}
+
+func TestReconcileMaxScan(t *testing.T) {
+	client := &fakeClient{transfers: []types.Transfer{
+		{ID: types.ToUint128(0xa1), Amount: types.ToUint128(10), Timestamp: ts("2025-01-02T08:00:00Z")},
+		{ID: types.ToUint128(0xa2), Amount: types.ToUint128(10), Timestamp: ts("2025-01-02T09:00:00Z")},
+		{ID: types.ToUint128(0xa3), Amount: types.ToUint128(10), Timestamp: ts("2025-01-02T10:00:00Z")},
+	}}
+	input := "amount,reference,date\n10,a1,2025-01-02\n"
+
+	report, err := Run(client, strings.NewReader(input), Options{MatchBy: MatchByID, MaxScan: 2})
+	require.NoError(t, err)
+	assert.True(t, report.Truncated)
+	assert.Equal(t, ts("2025-01-02T09:00:00Z"), report.LastTimestamp)
+	assert.Equal(t, uint64(1), report.Matched)
+	assert.Equal(t, uint64(1), report.MissingInFile)
+
+	report, err = Run(client, strings.NewReader(input), Options{MatchBy: MatchByID, MaxScan: 3})
+	require.NoError(t, err)
+	assert.False(t, report.Truncated)
+	assert.Equal(t, uint64(2), report.MissingInFile)
+}
+
+func TestReconcileInvalidRows(t *testing.T) {
+	client := &fakeClient{transfers: []types.Transfer{
+		{UserData64: 1001, Amount: types.ToUint128(10), Timestamp: ts("2025-01-02T08:00:00Z")},
+	}}
+	input := "amount,reference,date\n10,1001,2025-01-02\n-5,1002,2025-01-02\n10,1003,02.01.2025\n"
+
+	report, err := Run(client, strings.NewReader(input), Options{MatchBy: MatchByUserData64})
+	require.NoError(t, err)
+	assert.Equal(t, uint64(1), report.Matched)
+	assert.Equal(t, uint64(2), report.Invalid)
+	invalid := []uint64{}
+	for _, e := range report.Entries {
+		if e.Status == StatusInvalid {
+			invalid = append(invalid, e.Row)
+			assert.NotEmpty(t, e.Error)
+		}
+	}
+	assert.Equal(t, []uint64{2, 3}, invalid)
+}
+
+func TestReconcileMaxRows(t *testing.T) {
+	input := "amount,reference,date\n10,1001,2025-01-02\n10,1002,2025-01-02\n10,1003,2025-01-02\n"
+
+	_, err := Run(&fakeClient{}, strings.NewReader(input), Options{MatchBy: MatchByUserData64, MaxRows: 2})
+	assert.ErrorIs(t, err, ErrTooManyRows)
+
+	report, err := Run(&fakeClient{}, strings.NewReader(input), Options{MatchBy: MatchByUserData64, MaxRows: 3})
+	require.NoError(t, err)
+	assert.Equal(t, uint64(3), report.MissingInLedger)
+}
@@ -0,0 +1,198 @@
+// Package registry loads the ledgers and codes in use from a yaml file.
+//
+//	ledgers:
+//	  - id: 1
+//	    name: euro
+//	    currency: EUR
+//	    asset_scale: 2
+//	    control_account: "100"
+//	    operator_account: "101"
+//	codes:
+//	  - id: 10
+//	    name: deposit
+//	    description: Card deposit
+//	    ledgers: [1]
+//	exchanges:
+//	  - liquidity_accounts:
+//	      1: "1000"
+//	      2: "2000"
+//	    code: 20
+//
+// A code without ledgers is allowed on every ledger.
+// An exchange lists the liquidity account on each ledger of a pair, used in both directions.
+package registry
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"os"
+	"slices"
+
+	"gopkg.in/yaml.v3"
+)
+
+var (
+	ErrUnknownLedger  = errors.New("unknown ledger")
+	ErrUnknownCode    = errors.New("unknown code")
+	ErrCodeNotAllowed = errors.New("code is not allowed on ledger")
+	ErrUnknownPair    = errors.New("no exchange between ledgers")
+)
+
+type Ledger struct {
+	ID         uint32 `yaml:"id" json:"id"`
+	Name       string `yaml:"name" json:"name"`
+	Currency   string `yaml:"currency" json:"currency"`
+	AssetScale uint32 `yaml:"asset_scale" json:"asset_scale"`
+	// Accounts used by conditional transfers, the control account has credits_must_not_exceed_debits
+	// and the operator account has no balance limits
+	ControlAccount  string `yaml:"control_account" json:"control_account"`
+	OperatorAccount string `yaml:"operator_account" json:"operator_account"`
+}
+
+type Code struct {
+	ID          uint16   `yaml:"id" json:"id"`
+	Name        string   `yaml:"name" json:"name"`
+	Description string   `yaml:"description" json:"description"`
+	Ledgers     []uint32 `yaml:"ledgers" json:"ledgers"`
+}
+
+type Exchange struct {
+	// Account id or alias per ledger
+	LiquidityAccounts map[uint32]string `yaml:"liquidity_accounts" json:"liquidity_accounts"`
+	// Default code of the exchange transfers
+	Code uint16 `yaml:"code" json:"code"`
+}
+
+type Registry struct {
+	Ledgers   []Ledger   `yaml:"ledgers"`
+	Codes     []Code     `yaml:"codes"`
+	Exchanges []Exchange `yaml:"exchanges"`
+
+	ledgers   map[uint32]*Ledger
+	codes     map[uint16]*Code
+	exchanges map[[2]uint32]*Exchange
+}
+
+func Load(path string) (*Registry, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return Parse(b)
+}
+
+func Parse(b []byte) (*Registry, error) {
+	var r Registry
+	if err := yaml.Unmarshal(b, &r); err != nil {
+		return nil, fmt.Errorf("parsing registry: %w", err)
+	}
+	r.ledgers = map[uint32]*Ledger{}
+	for i := range r.Ledgers {
+		l := &r.Ledgers[i]
+		if l.ID == 0 {
+			return nil, errors.New("registry: ledger id must not be zero")
+		}
+		if _, ok := r.ledgers[l.ID]; ok {
+			return nil, fmt.Errorf("registry: duplicate ledger %d", l.ID)
+		}
+		r.ledgers[l.ID] = l
+	}
+	r.codes = map[uint16]*Code{}
+	for i := range r.Codes {
+		c := &r.Codes[i]
+		if c.ID == 0 {
+			return nil, errors.New("registry: code id must not be zero")
+		}
+		if _, ok := r.codes[c.ID]; ok {
+			return nil, fmt.Errorf("registry: duplicate code %d", c.ID)
+		}
+		for _, ledger := range c.Ledgers {
+			if _, ok := r.ledgers[ledger]; !ok {
+				return nil, fmt.Errorf("registry: code %d: %w %d", c.ID, ErrUnknownLedger, ledger)
+			}
+		}
+		r.codes[c.ID] = c
+	}
+	r.exchanges = map[[2]uint32]*Exchange{}
+	for i := range r.Exchanges {
+		e := &r.Exchanges[i]
+		if len(e.LiquidityAccounts) != 2 {
+			return nil, fmt.Errorf("registry: exchanges[%d]: liquidity_accounts must have two ledgers", i)
+		}
+		for ledger, account := range e.LiquidityAccounts {
+			if _, ok := r.ledgers[ledger]; !ok {
+				return nil, fmt.Errorf("registry: exchanges[%d]: %w %d", i, ErrUnknownLedger, ledger)
+			}
+			if account == "" {
+				return nil, fmt.Errorf("registry: exchanges[%d]: empty liquidity account on ledger %d", i, ledger)
+			}
+		}
+		if _, ok := r.codes[e.Code]; e.Code != 0 && !ok {
+			return nil, fmt.Errorf("registry: exchanges[%d]: %w %d", i, ErrUnknownCode, e.Code)
+		}
+		key := e.pair()
+		if _, ok := r.exchanges[key]; ok {
+			return nil, fmt.Errorf("registry: duplicate exchange between ledgers %d and %d", key[0], key[1])
+		}
+		r.exchanges[key] = e
+	}
+	return &r, nil
+}
+
+func (e *Exchange) pair() [2]uint32 {
+	ledgers := slices.Sorted(maps.Keys(e.LiquidityAccounts))
+	return [2]uint32{ledgers[0], ledgers[1]}
+}
+
+func (r *Registry) Ledger(id uint32) (*Ledger, bool) {
+	l, ok := r.ledgers[id]
+	return l, ok
+}
+
+func (r *Registry) Code(id uint16) (*Code, bool) {
+	c, ok := r.codes[id]
+	return c, ok
+}
+
+// Exchange returns the exchange between two ledgers, in either direction.
+func (r *Registry) Exchange(a, b uint32) (*Exchange, error) {
+	e, ok := r.exchanges[[2]uint32{min(a, b), max(a, b)}]
+	if !ok {
+		return nil, fmt.Errorf("%w %d and %d", ErrUnknownPair, a, b)
+	}
+	return e, nil
+}
+
+// Validate checks that the ledger and code are registered and the code is allowed on the ledger.
+func (r *Registry) Validate(ledger uint32, code uint16) error {
+	if _, ok := r.ledgers[ledger]; !ok {
+		return fmt.Errorf("%w %d", ErrUnknownLedger, ledger)
+	}
+	c, ok := r.codes[code]
+	if !ok {
+		return fmt.Errorf("%w %d", ErrUnknownCode, code)
+	}
+	if len(c.Ledgers) > 0 && !slices.Contains(c.Ledgers, ledger) {
+		return fmt.Errorf("%w: code %d, ledger %d", ErrCodeNotAllowed, code, ledger)
+	}
+	return nil
+}
+
+// LedgerName returns the name of a registered ledger or nil.
+func (r *Registry) LedgerName(id uint32) *string {
+	if l, ok := r.ledgers[id]; ok {
+		name := l.Name
+		return &name
+	}
+	return nil
+}
+
+// CodeName returns the name of a registered code or nil.
+func (r *Registry) CodeName(id uint16) *string {
+	if c, ok := r.codes[id]; ok {
+		name := c.Name
+		return &name
+	}
+	return nil
+}
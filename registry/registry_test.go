@@ -0,0 +1,83 @@
+package registry
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParse(t *testing.T) {
+	r, err := Parse([]byte(`
+ledgers:
+  - id: 1
+    name: euro
+    currency: EUR
+    asset_scale: 2
+  - id: 2
+    name: dollar
+    currency: USD
+    asset_scale: 2
+codes:
+  - id: 1
+    name: customer
+  - id: 10
+    name: deposit
+    ledgers: [1]
+`))
+	require.NoError(t, err)
+
+	l, ok := r.Ledger(1)
+	require.True(t, ok)
+	assert.Equal(t, "EUR", l.Currency)
+	assert.Equal(t, uint32(2), l.AssetScale)
+	assert.Equal(t, "deposit", *r.CodeName(10))
+	assert.Nil(t, r.LedgerName(3))
+
+	assert.NoError(t, r.Validate(1, 1))
+	assert.NoError(t, r.Validate(2, 1))
+	assert.NoError(t, r.Validate(1, 10))
+	assert.ErrorIs(t, r.Validate(2, 10), ErrCodeNotAllowed)
+	assert.ErrorIs(t, r.Validate(3, 1), ErrUnknownLedger)
+	assert.ErrorIs(t, r.Validate(1, 2), ErrUnknownCode)
+}
+
+func TestParseInvalid(t *testing.T) {
+	_, err := Parse([]byte("ledgers:\n  - id: 1\n  - id: 1\n"))
+	assert.ErrorContains(t, err, "duplicate ledger")
+
+	_, err = Parse([]byte("codes:\n  - id: 1\n    ledgers: [5]\n"))
+	assert.ErrorIs(t, err, ErrUnknownLedger)
+
+	_, err = Parse([]byte("codes:\n  - name: zero\n"))
+	assert.ErrorContains(t, err, "must not be zero")
+
+	_, err = Parse([]byte("ledgers:\n  - id: 1\nexchanges:\n  - liquidity_accounts: {1: a}\n"))
+	assert.ErrorContains(t, err, "two ledgers")
+
+	_, err = Parse([]byte("ledgers:\n  - id: 1\nexchanges:\n  - liquidity_accounts: {1: a, 2: b}\n"))
+	assert.ErrorIs(t, err, ErrUnknownLedger)
+}
+
+func TestExchange(t *testing.T) {
+	r, err := Parse([]byte(`
+ledgers:
+  - id: 1
+  - id: 2
+  - id: 3
+exchanges:
+  - liquidity_accounts: {1: "1000", 2: "2000"}
+`))
+	require.NoError(t, err)
+
+	e, err := r.Exchange(2, 1)
+	require.NoError(t, err)
+	assert.Equal(t, "1000", e.LiquidityAccounts[1])
+	_, err = r.Exchange(1, 3)
+	assert.ErrorIs(t, err, ErrUnknownPair)
+}
+
+func TestLoadExample(t *testing.T) {
+	_, err := Load("../registry.example.yaml")
+	assert.NoError(t, err)
+}
@@ -0,0 +1,79 @@
+package rest
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/lil5/tigerbeetle_api/audit"
+	"github.com/lil5/tigerbeetle_api/auth"
+	"github.com/lil5/tigerbeetle_api/certs"
+	"github.com/lil5/tigerbeetle_api/grpc"
+)
+
+// auditWriter holds back the response body until its audit entry is written,
+// so a failed entry can still turn the response into an error.
+type auditWriter struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (w *auditWriter) Write(b []byte) (int, error) {
+	return w.body.Write(b)
+}
+
+func (w *auditWriter) WriteString(s string) (int, error) {
+	return w.body.WriteString(s)
+}
+
+// audited runs the rest of the handlers of c and writes the request and its response to l.
+// The request is kept for json bodies, as read by grpcCall, every body read is hashed.
+// The response is only sent once the entry is written, a failure sends a 500 instead unless AUDIT_FAIL_OPEN is set.
+func audited(c *gin.Context, l *audit.Log) {
+	start := time.Now()
+	w := &auditWriter{ResponseWriter: c.Writer}
+	c.Writer = w
+	body := sha256.New()
+	c.Request.Body = struct {
+		io.Reader
+		io.Closer
+	}{io.TeeReader(c.Request.Body, body), c.Request.Body}
+	c.Next()
+	c.Writer = w.ResponseWriter
+
+	e := audit.Entry{
+		Time:           start.UTC(),
+		ClientCert:     certs.Identity(c.Request.TLS),
+		IP:             c.ClientIP(),
+		Method:         c.Request.Method + " " + c.FullPath(),
+		IdempotencyKey: c.GetHeader("Idempotency-Key"),
+		Status:         strconv.Itoa(w.Status()),
+		LatencyNs:      time.Since(start).Nanoseconds(),
+	}
+	if key := auth.FromContext(c.Request.Context()); key != nil {
+		e.Key = key.Name
+	}
+	if b, ok := c.Get(gin.BodyBytesKey); ok {
+		e.Request = audit.RawJSON(b.([]byte))
+	}
+	if c.Request.ContentLength != 0 {
+		e.RequestSHA256 = hex.EncodeToString(body.Sum(nil))
+	}
+	if w.Status() < http.StatusBadRequest {
+		e.Response = audit.RawJSON(w.body.Bytes())
+	} else {
+		e.Error = w.body.String()
+	}
+	if err := grpc.AppendAudit(l, e); err != nil {
+		c.Header("Content-Type", "text/plain; charset=utf-8")
+		c.Status(http.StatusInternalServerError)
+		c.Writer.WriteString(err.Error())
+		return
+	}
+	c.Writer.Write(w.body.Bytes())
+}
@@ -0,0 +1,63 @@
+package rest
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/lil5/tigerbeetle_api/audit"
+	"github.com/lil5/tigerbeetle_api/auth"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAudited(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	dir := t.TempDir()
+	l, err := audit.Open(dir, 0)
+	require.NoError(t, err)
+	r, err := newEngine()
+	require.NoError(t, err)
+	// Like /import, the body is read without being kept for the entry.
+	r.POST("/import", guard{audit: l}.scope(auth.ScopeAdmin), func(c *gin.Context) {
+		io.Copy(io.Discard, c.Request.Body)
+		c.JSON(http.StatusOK, gin.H{"rows": 1})
+	})
+	body := `{"id":"1","amount":10}` + "\n"
+	post := func() *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/import", strings.NewReader(body))
+		req.Header.Set("Idempotency-Key", "import-1")
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	t.Run("should record the idempotency key and body hash", func(t *testing.T) {
+		w := post()
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.JSONEq(t, `{"rows":1}`, w.Body.String())
+
+		b, err := os.ReadFile(filepath.Join(dir, "audit-000001.log"))
+		require.NoError(t, err)
+		var e audit.Entry
+		require.NoError(t, json.Unmarshal(b, &e))
+		sum := sha256.Sum256([]byte(body))
+		assert.Equal(t, hex.EncodeToString(sum[:]), e.RequestSHA256)
+		assert.Equal(t, "import-1", e.IdempotencyKey)
+	})
+
+	t.Run("should fail the request when the entry is not written", func(t *testing.T) {
+		require.NoError(t, l.Close())
+		w := post()
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+		assert.NotContains(t, w.Body.String(), "rows")
+	})
+}
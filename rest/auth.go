@@ -0,0 +1,43 @@
+package rest
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/lil5/tigerbeetle_api/auth"
+	"github.com/lil5/tigerbeetle_api/certs"
+)
+
+// apiKey returns a middleware that requires an api key with scope in the Authorization header,
+// or a client certificate of such a key. Every request passes when keys is nil.
+func apiKey(keys *auth.Keys, scope auth.Scope) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if keys == nil {
+			return
+		}
+		key, err := keys.AuthenticateRequest(c.GetHeader("Authorization"), certs.Identity(c.Request.TLS))
+		if err != nil {
+			c.Header("WWW-Authenticate", "Bearer")
+			c.String(http.StatusUnauthorized, err.Error())
+			c.Abort()
+			return
+		}
+		if !key.Allows(scope) {
+			c.String(http.StatusForbidden, "api key "+key.Name+" lacks scope "+string(scope))
+			c.Abort()
+			return
+		}
+		c.Request = c.Request.WithContext(auth.WithKey(c.Request.Context(), key))
+	}
+}
+
+// unrestricted rejects callers limited to ledgers, codes or accounts,
+// for routes that use the tigerbeetle client directly instead of grpc.App.
+func unrestricted(c *gin.Context) {
+	if k := auth.FromContext(c.Request.Context()); k != nil && k.Restriction.Restricted() {
+		c.String(http.StatusForbidden, "the caller is limited to ledgers, codes or accounts")
+		c.Abort()
+		return
+	}
+	c.Next()
+}
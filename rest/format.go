@@ -0,0 +1,166 @@
+package rest
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"log/slog"
+	"math/big"
+	"net/http"
+	"strconv"
+	"text/tabwriter"
+
+	"github.com/gin-gonic/gin"
+	"github.com/lil5/tigerbeetle_api/proto"
+)
+
+// format renders a reply in something other than json, selected with the format query parameter.
+type format[Out any] struct {
+	ContentType string
+	Write       func(w io.Writer, out *Out) error
+}
+
+func grpcHandleFormat[In any, Out any](f func(ctx context.Context, in *In) (out *Out, err error), formats map[string]format[Out]) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		name := c.DefaultQuery("format", "json")
+		fm, ok := formats[name]
+		if !ok && name != "json" {
+			c.String(http.StatusBadRequest, "unsupported format: "+name)
+			return
+		}
+		out, ok := grpcCall(c, f)
+		if !ok {
+			return
+		}
+		if name == "json" {
+			c.JSON(http.StatusOK, out)
+			return
+		}
+
+		var buf bytes.Buffer
+		if err := fm.Write(&buf, out); err != nil {
+			errStr := err.Error()
+			slog.Error(errStr)
+			c.String(http.StatusInternalServerError, errStr)
+			return
+		}
+		c.Data(http.StatusOK, fm.ContentType, buf.Bytes())
+	}
+}
+
+func optionalString[T any](v *T, f func(T) string) string {
+	if v == nil {
+		return ""
+	}
+	return f(*v)
+}
+
+func uint32String(v uint32) string { return strconv.FormatUint(uint64(v), 10) }
+func uint64String(v uint64) string { return strconv.FormatUint(v, 10) }
+func identity(v string) string     { return v }
+
+func ledgerSummaryCSV(w io.Writer, out *proto.LedgerSummaryReply) error {
+	cw := csv.NewWriter(w)
+	cw.Write([]string{"ledger", "code", "user_data128", "user_data64", "user_data32", "accounts", "debits_pending", "debits_posted", "credits_pending", "credits_posted"})
+	ledger := uint32String(out.Ledger)
+	for _, row := range out.Rows {
+		cw.Write([]string{
+			ledger,
+			optionalString(row.Code, uint32String),
+			optionalString(row.UserData128, identity),
+			optionalString(row.UserData64, uint64String),
+			optionalString(row.UserData32, uint32String),
+			uint64String(row.Accounts),
+			row.DebitsPending,
+			row.DebitsPosted,
+			row.CreditsPending,
+			row.CreditsPosted,
+		})
+	}
+	if out.Total != nil {
+		cw.Write([]string{
+			ledger, "total", "", "", "",
+			uint64String(out.Total.Accounts),
+			out.Total.DebitsPending,
+			out.Total.DebitsPosted,
+			out.Total.CreditsPending,
+			out.Total.CreditsPosted,
+		})
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+func int64String(v int64) string { return strconv.FormatInt(v, 10) }
+
+// pendingNet returns credits_pending - debits_pending of b, computed without overflow.
+func pendingNet(b *proto.BalanceAt) string {
+	net := new(big.Int).SetUint64(b.CreditsPending)
+	return net.Sub(net, new(big.Int).SetUint64(b.DebitsPending)).String()
+}
+
+func statementLineKind(t *proto.Transfer) string {
+	if t.TransferFlags == nil {
+		return "posted"
+	}
+	switch {
+	case t.TransferFlags.GetPending():
+		return "pending"
+	case t.TransferFlags.GetPostPendingTransfer():
+		return "post_pending"
+	case t.TransferFlags.GetVoidPendingTransfer():
+		return "void_pending"
+	}
+	return "posted"
+}
+
+func accountStatementCSV(w io.Writer, out *proto.GetAccountStatementReply) error {
+	cw := csv.NewWriter(w)
+	cw.Write([]string{"timestamp", "kind", "transfer_id", "debit_account_id", "credit_account_id", "amount", "code", "posted_amount", "pending_amount", "balance", "pending_balance"})
+	balanceRow := func(kind string, b *proto.BalanceAt) {
+		cw.Write([]string{uint64String(b.Timestamp), kind, "", "", "", "", "", "", "", int64String(b.Balance), pendingNet(b)})
+	}
+	balanceRow("opening", out.OpeningBalance)
+	for _, line := range out.Lines {
+		t := line.Transfer
+		cw.Write([]string{
+			uint64String(t.GetTimestamp()),
+			statementLineKind(t),
+			t.Id,
+			t.DebitAccountId,
+			t.CreditAccountId,
+			int64String(t.Amount),
+			uint32String(t.Code),
+			int64String(line.PostedAmount),
+			int64String(line.PendingAmount),
+			int64String(line.Balance),
+			int64String(line.PendingBalance),
+		})
+	}
+	balanceRow("closing", out.ClosingBalance)
+	cw.Flush()
+	return cw.Error()
+}
+
+func accountStatementText(w io.Writer, out *proto.GetAccountStatementReply) error {
+	fmt.Fprintf(w, "Account statement %s\n", out.Account.Id)
+	fmt.Fprintf(w, "Ledger %d, code %d\n", out.Account.Ledger, out.Account.Code)
+	fmt.Fprintf(w, "Period %d - %d\n\n", out.TimestampMin, out.TimestampMax)
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprintln(tw, "Timestamp\tKind\tTransfer\tCounterparty\tPosted\tPending\tBalance\tPending balance\t")
+	fmt.Fprintf(tw, "%d\topening\t\t\t\t\t%d\t%s\t\n", out.OpeningBalance.Timestamp, out.OpeningBalance.Balance, pendingNet(out.OpeningBalance))
+	for _, line := range out.Lines {
+		t := line.Transfer
+		counterparty := t.DebitAccountId
+		if counterparty == out.Account.Id {
+			counterparty = t.CreditAccountId
+		}
+		fmt.Fprintf(tw, "%d\t%s\t%s\t%s\t%d\t%d\t%d\t%d\t\n", t.GetTimestamp(), statementLineKind(t), t.Id, counterparty,
+			line.PostedAmount, line.PendingAmount, line.Balance, line.PendingBalance)
+	}
+	fmt.Fprintf(tw, "%d\tclosing\t\t\t\t\t%d\t%s\t\n", out.ClosingBalance.Timestamp, out.ClosingBalance.Balance, pendingNet(out.ClosingBalance))
+	return tw.Flush()
+}
@@ -0,0 +1,17 @@
+package rest
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/lil5/tigerbeetle_api/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+// idempotencyKeyHeader passes the Idempotency-Key header on as grpc metadata,
+// the same way a grpc client sends it.
+func idempotencyKeyHeader(c *gin.Context) {
+	if key := c.GetHeader("Idempotency-Key"); key != "" {
+		ctx := metadata.NewIncomingContext(c.Request.Context(), metadata.Pairs(grpc.IdempotencyKeyMetadata, key))
+		c.Request = c.Request.WithContext(ctx)
+	}
+	c.Next()
+}
@@ -0,0 +1,67 @@
+package rest
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+	"path/filepath"
+	"regexp"
+
+	"github.com/gin-gonic/gin"
+	"github.com/lil5/tigerbeetle_api/config"
+	"github.com/lil5/tigerbeetle_api/importer"
+	"github.com/lil5/tigerbeetle_api/tracing"
+	tigerbeetle_go "github.com/tigerbeetle/tigerbeetle-go"
+)
+
+var checkpointNameRegexp = regexp.MustCompile(`^[A-Za-z0-9_.-]+$`)
+
+// importHandle streams the request body into TigerBeetle.
+// It is the raw restore path, only for unrestricted admin keys, rows skip the validation of /transfers/create.
+//
+// Query parameters: format (csv or ndjson), imported (true keeps row timestamps),
+// columns (csv column mapping) and checkpoint (a name under IMPORT_CHECKPOINT_DIR
+// so that posting the same body again resumes after the last committed row).
+func importHandle(tb tigerbeetle_go.Client) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		columns, err := importer.ParseColumns(c.Query("columns"))
+		if err != nil {
+			c.String(http.StatusBadRequest, err.Error())
+			return
+		}
+		opts := importer.Options{
+			Kind:     importer.Kind(c.Param("kind")),
+			Format:   importer.Format(c.DefaultQuery("format", string(importer.FormatNDJSON))),
+			Columns:  columns,
+			Imported: c.Query("imported") == "true",
+		}
+		if name := c.Query("checkpoint"); name != "" {
+			if config.Config.ImportCheckpointDir == "" {
+				c.String(http.StatusBadRequest, "checkpoints are disabled, IMPORT_CHECKPOINT_DIR is not set")
+				return
+			}
+			if !checkpointNameRegexp.MatchString(name) {
+				c.String(http.StatusBadRequest, "invalid checkpoint name")
+				return
+			}
+			opts.Checkpoint = filepath.Join(config.Config.ImportCheckpointDir, name+".checkpoint")
+		}
+
+		report, err := importer.Run(tracing.Client(c.Request.Context(), tb), c.Request.Body, opts)
+		if err != nil {
+			slog.Error("import failed", "error", err)
+			// The rows before the limit stay imported, the report tells which.
+			if maxBytes := (*http.MaxBytesError)(nil); errors.As(err, &maxBytes) && report != nil {
+				c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": err.Error(), "report": report})
+				return
+			}
+			if report == nil {
+				c.String(http.StatusBadRequest, err.Error())
+				return
+			}
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error(), "report": report})
+			return
+		}
+		c.JSON(http.StatusOK, report)
+	}
+}
@@ -0,0 +1,55 @@
+package rest
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/lil5/tigerbeetle_api/audit"
+	"github.com/lil5/tigerbeetle_api/auth"
+	"github.com/lil5/tigerbeetle_api/certs"
+	"github.com/lil5/tigerbeetle_api/quota"
+)
+
+// acquireQuota takes operation from the quota of the caller, it must run after apiKey to see the api key.
+// On failure it writes the response and the request must stop.
+func acquireQuota(c *gin.Context, quotas *quota.Quotas, operation string) (release func(), ok bool) {
+	client := quota.ClientOf(auth.FromContext(c.Request.Context()), certs.Identity(c.Request.TLS), c.ClientIP())
+	release, retryAfter, err := quotas.Acquire(client, operation)
+	if err != nil {
+		c.Header("Retry-After", quota.RetryAfter(retryAfter))
+		c.String(http.StatusTooManyRequests, err.Error())
+		c.Abort()
+		return nil, false
+	}
+	return release, true
+}
+
+// guard holds what every route goes through, each may be nil.
+type guard struct {
+	keys   *auth.Keys
+	quotas *quota.Quotas
+	audit  *audit.Log
+}
+
+// scope authenticates the caller for scope, applies the quota of that scope
+// and writes requests of any other scope than read to the audit log.
+func (g guard) scope(scope auth.Scope) gin.HandlerFunc {
+	authenticate := apiKey(g.keys, scope)
+	return func(c *gin.Context) {
+		if authenticate(c); c.IsAborted() {
+			return
+		}
+		if g.quotas != nil {
+			release, ok := acquireQuota(c, g.quotas, string(scope))
+			if !ok {
+				return
+			}
+			defer release()
+		}
+		if g.audit != nil && scope != auth.ScopeRead {
+			audited(c, g.audit)
+			return
+		}
+		c.Next()
+	}
+}
@@ -0,0 +1,51 @@
+package rest
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/lil5/tigerbeetle_api/auth"
+	"github.com/lil5/tigerbeetle_api/config"
+	"github.com/lil5/tigerbeetle_api/quota"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestQuotaClientIP(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	quotas, err := quota.Parse([]byte("default:\n  read: {rate: 0.001, burst: 1}\n"))
+	require.NoError(t, err)
+
+	request := func(r *gin.Engine, forwarded string) int {
+		req := httptest.NewRequest(http.MethodGet, "/id", nil)
+		req.RemoteAddr = "10.0.0.1:1234"
+		req.Header.Set("X-Forwarded-For", forwarded)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w.Code
+	}
+	route := func(t *testing.T) *gin.Engine {
+		r, err := newEngine()
+		require.NoError(t, err)
+		r.GET("/id", guard{quotas: quotas}.scope(auth.ScopeRead), ping)
+		return r
+	}
+
+	t.Run("should ignore a forged X-Forwarded-For", func(t *testing.T) {
+		config.Config.TrustedProxies = nil
+		r := route(t)
+		assert.Equal(t, http.StatusOK, request(r, "192.0.2.1"))
+		assert.Equal(t, http.StatusTooManyRequests, request(r, "192.0.2.2"))
+	})
+
+	t.Run("should use X-Forwarded-For of a trusted proxy", func(t *testing.T) {
+		config.Config.TrustedProxies = []string{"10.0.0.0/8"}
+		t.Cleanup(func() { config.Config.TrustedProxies = nil })
+		r := route(t)
+		assert.Equal(t, http.StatusOK, request(r, "192.0.2.3"))
+		assert.Equal(t, http.StatusOK, request(r, "192.0.2.4"))
+		assert.Equal(t, http.StatusTooManyRequests, request(r, "192.0.2.4"))
+	})
+}
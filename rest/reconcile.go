@@ -0,0 +1,77 @@
+package rest
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/lil5/tigerbeetle_api/config"
+	"github.com/lil5/tigerbeetle_api/importer"
+	"github.com/lil5/tigerbeetle_api/reconcile"
+	"github.com/lil5/tigerbeetle_api/tracing"
+	tigerbeetle_go "github.com/tigerbeetle/tigerbeetle-go"
+)
+
+// reconcileHandle reconciles the csv statement in the request body.
+//
+// Query parameters: match_by (id, user_data128 or user_data64), ledger and code
+// (restrict the transfers expected in the file), columns (csv column mapping),
+// max_scan (capped by RECONCILE_MAX_SCAN) and format (json or csv).
+func reconcileHandle(tb tigerbeetle_go.Client) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		columns, err := importer.ParseColumns(c.Query("columns"))
+		if err != nil {
+			c.String(http.StatusBadRequest, err.Error())
+			return
+		}
+		ledger, err := strconv.ParseUint(c.DefaultQuery("ledger", "0"), 10, 32)
+		if err != nil {
+			c.String(http.StatusBadRequest, "invalid ledger")
+			return
+		}
+		code, err := strconv.ParseUint(c.DefaultQuery("code", "0"), 10, 16)
+		if err != nil {
+			c.String(http.StatusBadRequest, "invalid code")
+			return
+		}
+		maxScan, err := strconv.ParseUint(c.DefaultQuery("max_scan", "0"), 10, 64)
+		if err != nil {
+			c.String(http.StatusBadRequest, "invalid max_scan")
+			return
+		}
+		if maxScan == 0 || maxScan > config.Config.ReconcileMaxScan {
+			maxScan = config.Config.ReconcileMaxScan
+		}
+
+		report, err := reconcile.Run(tracing.Client(c.Request.Context(), tb), c.Request.Body, reconcile.Options{
+			MatchBy: reconcile.MatchBy(c.DefaultQuery("match_by", string(reconcile.MatchByID))),
+			Ledger:  uint32(ledger),
+			Code:    uint16(code),
+			Columns: columns,
+			MaxScan: maxScan,
+			MaxRows: uint64(max(config.Config.MaxRequestItems, 0)),
+		})
+		if maxBytes := (*http.MaxBytesError)(nil); errors.As(err, &maxBytes) {
+			tooLarge(c, maxBytes.Limit)
+			return
+		}
+		if errors.Is(err, reconcile.ErrUnknownMatchBy) || errors.Is(err, reconcile.ErrNoRows) || errors.Is(err, reconcile.ErrInvalidFile) || errors.Is(err, reconcile.ErrTooManyRows) {
+			c.String(http.StatusBadRequest, err.Error())
+			return
+		}
+		if err != nil {
+			slog.Error("reconcile failed", "error", err)
+			c.String(http.StatusInternalServerError, err.Error())
+			return
+		}
+		if c.Query("format") == "csv" {
+			c.Header("Content-Type", "text/csv")
+			c.Status(http.StatusOK)
+			reconcile.WriteCSV(c.Writer, report)
+			return
+		}
+		c.JSON(http.StatusOK, report)
+	}
+}
@@ -2,30 +2,47 @@ package rest
 
 import (
 	"context"
+	"crypto/tls"
+	"errors"
 	"fmt"
 	"log"
 	"log/slog"
 	"net"
 	"net/http"
+	"os"
 
 	"github.com/gin-gonic/gin"
+	"github.com/lil5/tigerbeetle_api/auth"
+	"github.com/lil5/tigerbeetle_api/certs"
 	"github.com/lil5/tigerbeetle_api/config"
 	"github.com/lil5/tigerbeetle_api/grpc"
 	"github.com/lil5/tigerbeetle_api/metrics"
+	"github.com/lil5/tigerbeetle_api/proto"
+	"github.com/lil5/tigerbeetle_api/quota"
+	"github.com/lil5/tigerbeetle_api/tracing"
 	"github.com/prometheus/client_golang/prometheus"
 
 	metrics_prometheus "github.com/slok/go-http-metrics/metrics/prometheus"
 	"github.com/slok/go-http-metrics/middleware"
 	ginmiddleware "github.com/slok/go-http-metrics/middleware/gin"
+	"go.opentelemetry.io/contrib/instrumentation/github.com/gin-gonic/gin/otelgin"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
 func NewServer() {
 	if config.Config.Mode != "development" {
 		gin.SetMode(gin.ReleaseMode)
 	}
+	tracingShutdown, err := tracing.Init(config.Config.TracingExporter, config.Config.TracingEndpoint)
+	if err != nil {
+		slog.Error("unable to start tracing", "err", err)
+		os.Exit(1)
+	}
+	defer tracingShutdown()
 	r, app := Router()
 	defer app.Close()
-	slog.Info("Rest server listening at", "host", config.Config.Host, "port", config.Config.Port)
+	slog.Info("Rest server listening at", "host", config.Config.Host, "port", config.Config.Port, "tls", config.Config.TlsCertFile != "", "mtls", config.Config.TlsClientCAFile != "")
 	defer slog.Info("Server exiting")
 
 	mdlw := middleware.New(middleware.Config{
@@ -35,41 +52,144 @@ func NewServer() {
 	})
 	r.Use(ginmiddleware.Handler("", mdlw))
 
-	prometheusDeferClose := metrics.Register(config.Config.PrometheusAddr)
+	tlsCerts, err := certs.New(config.Config.TlsCertFile, config.Config.TlsKeyFile, config.Config.TlsClientCAFile)
+	if err != nil {
+		slog.Error("unable to load tls certificate", "err", err)
+		os.Exit(1)
+	}
+	var tlsConfig *tls.Config
+	if tlsCerts != nil {
+		defer tlsCerts.Close()
+		tlsConfig = tlsCerts.Config()
+	}
+
+	prometheusDeferClose := metrics.Register(config.Config.PrometheusAddr, tlsConfig)
 	defer prometheusDeferClose()
 
 	addr := fmt.Sprintf("%s:%s", config.Config.Host, config.Config.Port)
+	networkType := "tcp"
 	if config.Config.OnlyIpv4 {
-		server := &http.Server{Handler: r}
-		l, err := net.Listen("tcp4", addr)
-		if err != nil {
-			log.Fatal(err)
-		}
-		server.Serve(l)
+		networkType = "tcp4"
+	}
+	l, err := net.Listen(networkType, addr)
+	if err != nil {
+		log.Fatal(err)
+	}
+	server := &http.Server{Handler: r.Handler(), TLSConfig: tlsConfig}
+	if tlsConfig != nil {
+		err = server.ServeTLS(l, "", "")
 	} else {
-		r.Run(addr)
+		err = server.Serve(l)
+	}
+	if err != nil {
+		slog.Error("Failed to serve", "error", err)
 	}
 }
 
 func Router() (*gin.Engine, *grpc.App) {
 	s := grpc.NewApp()
+	r, err := newEngine()
+	if err != nil {
+		slog.Error("invalid TRUSTED_PROXIES", "err", err)
+		os.Exit(1)
+	}
+	if config.Config.TracingExporter != "" {
+		r.Use(otelgin.Middleware(tracing.ServiceName))
+	}
+	keys, err := grpc.LoadAuth()
+	if err != nil {
+		slog.Error("unable to load auth", "err", err)
+		os.Exit(1)
+	}
+	var quotas *quota.Quotas
+	if config.Config.QuotasFile != "" {
+		quotas, err = quota.Load(config.Config.QuotasFile)
+		if err != nil {
+			slog.Error("unable to load quotas", "err", err)
+			os.Exit(1)
+		}
+	}
+	// The audit log stays open for the life of the process, each entry is synced on write.
+	auditLog, err := grpc.OpenAudit()
+	if err != nil {
+		slog.Error("unable to open audit log", "err", err)
+		os.Exit(1)
+	}
+	g := guard{keys: keys, quotas: quotas, audit: auditLog}
+	read := g.scope(auth.ScopeRead)
+	writeAccounts := g.scope(auth.ScopeWriteAccounts)
+	writeTransfers := g.scope(auth.ScopeWriteTransfers)
+	admin := g.scope(auth.ScopeAdmin)
+
+	r.GET("/id", read, grpcHandle(s.GetID))
+	r.GET("/ping", ping)
+	r.POST("/accounts/create", writeAccounts, idempotencyKeyHeader, grpcHandle(s.CreateAccounts))
+	r.POST("/transfers/create", writeTransfers, idempotencyKeyHeader, grpcHandle(s.CreateTransfers))
+	r.POST("/transfers/compound", writeTransfers, idempotencyKeyHeader, grpcHandle(s.CreateCompoundTransfer))
+	r.POST("/transfers/exchange", writeTransfers, idempotencyKeyHeader, grpcHandle(s.Exchange))
+	r.POST("/accounts/lookup", read, grpcHandle(s.LookupAccounts))
+	r.POST("/transfers/lookup", read, grpcHandle(s.LookupTransfers))
+	r.POST("/account/transfers", read, grpcHandle(s.GetAccountTransfers))
+	r.POST("/account/balances", read, grpcHandle(s.GetAccountBalances))
+	r.POST("/transfers/query", read, grpcHandle(s.QueryTransfers))
+	r.POST("/accounts/query", read, grpcHandle(s.QueryAccounts))
+	r.POST("/accounts/balances", read, grpcHandle(s.GetBalancesAt))
+	r.POST("/ledger/summary", read, grpcHandleFormat(s.LedgerSummary, map[string]format[proto.LedgerSummaryReply]{
+		"csv": {ContentType: "text/csv", Write: ledgerSummaryCSV},
+	}))
+	r.POST("/transfers/aggregate", read, grpcHandle(s.AggregateTransfers))
+	r.POST("/import/:kind", admin, unrestricted, importHandle(s.TB))
+	r.POST("/reconcile", read, unrestricted, reconcileHandle(s.TB))
+	r.POST("/account/close", writeAccounts, idempotencyKeyHeader, grpcHandle(s.CloseAccount))
+	r.POST("/account/reopen", writeAccounts, idempotencyKeyHeader, grpcHandle(s.ReopenAccount))
+	r.POST("/account/statement", read, grpcHandleFormat(s.GetAccountStatement, map[string]format[proto.GetAccountStatementReply]{
+		"csv":  {ContentType: "text/csv", Write: accountStatementCSV},
+		"text": {ContentType: "text/plain; charset=utf-8", Write: accountStatementText},
+	}))
+	r.POST("/admin/export", admin, grpcHandle(s.ExportLedger))
+	r.POST("/admin/restore", admin, grpcHandle(s.RestoreLedger))
+	r.POST("/aliases/register", writeAccounts, grpcHandle(s.RegisterAlias))
+	r.POST("/aliases/lookup", read, grpcHandle(s.LookupAliases))
+	r.GET("/ledgers", read, grpcHandle(s.ListLedgers))
+	return r, s
+}
+
+// newEngine returns the gin engine for the mode, which reads the client ip from
+// X-Forwarded-For and X-Real-IP only when the request comes from one of TRUSTED_PROXIES.
+// Quotas and the audit log use that ip, so a forged header must not change it.
+// Every body is limited to MAX_BODY_SIZE.
+func newEngine() (*gin.Engine, error) {
 	var r *gin.Engine
 	if config.Config.Mode == "development" {
 		r = gin.Default()
 	} else {
 		r = gin.New()
 	}
-	r.GET("/id", grpcHandle(s.GetID))
-	r.GET("/ping", ping)
-	r.POST("/accounts/create", grpcHandle(s.CreateAccounts))
-	r.POST("/transfers/create", grpcHandle(s.CreateTransfers))
-	r.POST("/accounts/lookup", grpcHandle(s.LookupAccounts))
-	r.POST("/transfers/lookup", grpcHandle(s.LookupTransfers))
-	r.POST("/account/transfers", grpcHandle(s.GetAccountTransfers))
-	r.POST("/account/balances", grpcHandle(s.GetAccountBalances))
-	r.POST("/transfers/query", grpcHandle(s.QueryTransfers))
-	r.POST("/accounts/query", grpcHandle(s.QueryAccounts))
-	return r, s
+	if err := r.SetTrustedProxies(config.Config.TrustedProxies); err != nil {
+		return nil, err
+	}
+	r.Use(maxBodySize)
+	return r, nil
+}
+
+// maxBodySize rejects a request whose Content-Length exceeds MAX_BODY_SIZE,
+// reading past it from a body without one fails with a *http.MaxBytesError.
+func maxBodySize(c *gin.Context) {
+	limit := config.Config.MaxBodySize
+	if limit <= 0 {
+		return
+	}
+	if c.Request.ContentLength > limit {
+		tooLarge(c, limit)
+		c.Abort()
+		return
+	}
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, limit)
+}
+
+// tooLarge writes the response of a body over limit.
+func tooLarge(c *gin.Context, limit int64) {
+	c.String(http.StatusRequestEntityTooLarge, fmt.Sprintf("body exceeds the maximum of %d bytes", limit))
 }
 
 func ping(c *gin.Context) {
@@ -78,21 +198,48 @@ func ping(c *gin.Context) {
 
 func grpcHandle[In any, Out any](f func(ctx context.Context, in *In) (out *Out, err error)) gin.HandlerFunc {
 	return func(c *gin.Context) {
-		var in In
-		if err := c.ShouldBindBodyWithJSON(&in); err != nil {
-			errStr := err.Error()
-			slog.Warn(errStr)
-			c.String(http.StatusBadRequest, errStr)
+		out, ok := grpcCall(c, f)
+		if !ok {
 			return
 		}
-		out, err := f(c.Request.Context(), &in)
+
+		c.JSON(http.StatusOK, out)
+	}
+}
+
+// grpcCall binds the json body, calls f and writes an error response on failure.
+func grpcCall[In any, Out any](c *gin.Context, f func(ctx context.Context, in *In) (out *Out, err error)) (*Out, bool) {
+	var in In
+	// GET requests, such as GET /ledgers, may leave out the body, an empty POST body is a bad request.
+	if c.Request.Method != http.MethodGet || c.Request.ContentLength != 0 {
+		_, span := tracing.Start(c.Request.Context(), "decode")
+		err := c.ShouldBindBodyWithJSON(&in)
+		tracing.End(span, err)
 		if err != nil {
+			if maxBytes := (*http.MaxBytesError)(nil); errors.As(err, &maxBytes) {
+				tooLarge(c, maxBytes.Limit)
+				return nil, false
+			}
 			errStr := err.Error()
-			slog.Error(errStr)
-			c.String(http.StatusInternalServerError, errStr)
-			return
+			slog.Warn(errStr)
+			c.String(http.StatusBadRequest, errStr)
+			return nil, false
 		}
-
-		c.JSON(http.StatusOK, out)
 	}
+	out, err := f(c.Request.Context(), &in)
+	switch status.Code(err) {
+	case codes.PermissionDenied:
+		c.String(http.StatusForbidden, status.Convert(err).Message())
+		return nil, false
+	case codes.InvalidArgument:
+		c.String(http.StatusBadRequest, status.Convert(err).Message())
+		return nil, false
+	}
+	if err != nil {
+		errStr := err.Error()
+		slog.Error(errStr)
+		c.String(http.StatusInternalServerError, errStr)
+		return nil, false
+	}
+	return out, true
 }
@@ -0,0 +1,44 @@
+package rest
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/lil5/tigerbeetle_api/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMaxBodySize(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	config.Config.MaxBodySize = 64
+	t.Cleanup(func() { config.Config.MaxBodySize = 0 })
+	r, err := newEngine()
+	require.NoError(t, err)
+	// Rows past the limit are never read, so no transfers are queried.
+	r.POST("/reconcile", reconcileHandle(nil))
+
+	body := "amount,reference,date\n" + strings.Repeat("10,a1,2025-01-02\n", 10)
+	post := func(contentLength int64) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/reconcile", io.NopCloser(strings.NewReader(body)))
+		req.ContentLength = contentLength
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	t.Run("should reject a Content-Length over the limit", func(t *testing.T) {
+		w := post(int64(len(body)))
+		assert.Equal(t, http.StatusRequestEntityTooLarge, w.Code)
+	})
+
+	t.Run("should stop reading a chunked body at the limit", func(t *testing.T) {
+		w := post(-1)
+		assert.Equal(t, http.StatusRequestEntityTooLarge, w.Code)
+		assert.Contains(t, w.Body.String(), "64 bytes")
+	})
+}
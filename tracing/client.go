@@ -0,0 +1,62 @@
+package tracing
+
+import (
+	"context"
+
+	tigerbeetle_go "github.com/tigerbeetle/tigerbeetle-go"
+	"github.com/tigerbeetle/tigerbeetle-go/pkg/types"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Client returns tb with each call traced as a TB.* span, a child of the span of ctx.
+// tb is returned as is when that span is not recording, such as when tracing is disabled.
+func Client(ctx context.Context, tb tigerbeetle_go.Client) tigerbeetle_go.Client {
+	if !trace.SpanFromContext(ctx).IsRecording() {
+		return tb
+	}
+	return &client{Client: tb, ctx: ctx}
+}
+
+type client struct {
+	tigerbeetle_go.Client
+	ctx context.Context
+}
+
+func call[T any](c *client, op string, count int, f func() ([]T, error)) ([]T, error) {
+	_, span := Start(c.ctx, "TB."+op, trace.WithSpanKind(trace.SpanKindClient), trace.WithAttributes(CountKey.Int(count)))
+	res, err := f()
+	End(span, err)
+	return res, err
+}
+
+func (c *client) CreateAccounts(accounts []types.Account) ([]types.AccountEventResult, error) {
+	return call(c, "CreateAccounts", len(accounts), func() ([]types.AccountEventResult, error) { return c.Client.CreateAccounts(accounts) })
+}
+
+func (c *client) CreateTransfers(transfers []types.Transfer) ([]types.TransferEventResult, error) {
+	return call(c, "CreateTransfers", len(transfers), func() ([]types.TransferEventResult, error) { return c.Client.CreateTransfers(transfers) })
+}
+
+func (c *client) LookupAccounts(ids []types.Uint128) ([]types.Account, error) {
+	return call(c, "LookupAccounts", len(ids), func() ([]types.Account, error) { return c.Client.LookupAccounts(ids) })
+}
+
+func (c *client) LookupTransfers(ids []types.Uint128) ([]types.Transfer, error) {
+	return call(c, "LookupTransfers", len(ids), func() ([]types.Transfer, error) { return c.Client.LookupTransfers(ids) })
+}
+
+func (c *client) GetAccountTransfers(filter types.AccountFilter) ([]types.Transfer, error) {
+	return call(c, "GetAccountTransfers", int(filter.Limit), func() ([]types.Transfer, error) { return c.Client.GetAccountTransfers(filter) })
+}
+
+func (c *client) GetAccountBalances(filter types.AccountFilter) ([]types.AccountBalance, error) {
+	return call(c, "GetAccountBalances", int(filter.Limit), func() ([]types.AccountBalance, error) { return c.Client.GetAccountBalances(filter) })
+}
+
+func (c *client) QueryAccounts(filter types.QueryFilter) ([]types.Account, error) {
+	return call(c, "QueryAccounts", int(filter.Limit), func() ([]types.Account, error) { return c.Client.QueryAccounts(filter) })
+}
+
+func (c *client) QueryTransfers(filter types.QueryFilter) ([]types.Transfer, error) {
+	return call(c, "QueryTransfers", int(filter.Limit), func() ([]types.Transfer, error) { return c.Client.QueryTransfers(filter) })
+}
@@ -0,0 +1,62 @@
+package tracing
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	tigerbeetle_go "github.com/tigerbeetle/tigerbeetle-go"
+	"github.com/tigerbeetle/tigerbeetle-go/pkg/types"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+type fakeClient struct {
+	tigerbeetle_go.Client
+}
+
+func (fakeClient) CreateTransfers(transfers []types.Transfer) ([]types.TransferEventResult, error) {
+	return nil, nil
+}
+
+func (fakeClient) LookupAccounts(ids []types.Uint128) ([]types.Account, error) {
+	return nil, errors.New("unavailable")
+}
+
+func TestClient(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+
+	t.Run("should not wrap without a recording span", func(t *testing.T) {
+		tb := Client(context.Background(), fakeClient{})
+		assert.IsType(t, fakeClient{}, tb)
+	})
+
+	ctx, parent := Start(context.Background(), "request")
+	tb := Client(ctx, fakeClient{})
+	_, err := tb.CreateTransfers(make([]types.Transfer, 3))
+	require.NoError(t, err)
+	_, err = tb.LookupAccounts(make([]types.Uint128, 1))
+	require.Error(t, err)
+	parent.End()
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 3)
+	assert.Equal(t, "TB.CreateTransfers", spans[0].Name())
+	assert.Equal(t, parent.SpanContext().SpanID(), spans[0].Parent().SpanID())
+	assert.Contains(t, spans[0].Attributes(), CountKey.Int(3))
+	assert.Equal(t, "TB.LookupAccounts", spans[1].Name())
+	assert.Equal(t, codes.Error, spans[1].Status().Code)
+}
+
+func TestInitUnknownExporter(t *testing.T) {
+	shutdown, err := Init("", "")
+	require.NoError(t, err)
+	shutdown()
+	_, err = Init("zipkin", "")
+	assert.Error(t, err)
+}
@@ -0,0 +1,101 @@
+// Package tracing sets up OpenTelemetry tracing and traces the calls to TigerBeetle.
+//
+// Trace context is propagated in the W3C traceparent and baggage headers, or grpc metadata.
+// Spans are exported over OTLP gRPC or printed to stdout. The standard OTEL_* variables,
+// such as OTEL_SERVICE_NAME and OTEL_TRACES_SAMPLER, apply as well.
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	ExporterOTLP   = "otlp"
+	ExporterStdout = "stdout"
+)
+
+const name = "github.com/lil5/tigerbeetle_api"
+
+// ServiceName is the service of the spans unless OTEL_SERVICE_NAME is set.
+const ServiceName = "tigerbeetle_api"
+
+// Tracer returns the tracer of the api, a no-op until Init installs a provider.
+func Tracer() trace.Tracer {
+	return otel.Tracer(name)
+}
+
+// Init installs a tracer provider exporting to exporter, and W3C propagation. Nothing is traced when exporter is empty.
+// endpoint is the OTLP url, empty for OTEL_EXPORTER_OTLP_ENDPOINT or localhost:4317.
+// The returned function flushes the spans left and must be called before exiting.
+func Init(exporter, endpoint string) (shutdown func(), err error) {
+	var spanExporter sdktrace.SpanExporter
+	switch exporter {
+	case "":
+		return func() {}, nil
+	case ExporterOTLP:
+		opts := []otlptracegrpc.Option{}
+		if endpoint != "" {
+			opts = append(opts, otlptracegrpc.WithEndpointURL(endpoint))
+		}
+		spanExporter, err = otlptracegrpc.New(context.Background(), opts...)
+	case ExporterStdout:
+		spanExporter, err = stdouttrace.New(stdouttrace.WithWriter(os.Stdout))
+	default:
+		return nil, fmt.Errorf("unknown tracing exporter %q, use %s or %s", exporter, ExporterOTLP, ExporterStdout)
+	}
+	if err != nil {
+		return nil, err
+	}
+	res, err := resource.Merge(
+		resource.NewSchemaless(semconv.ServiceName(ServiceName)),
+		resource.Environment(),
+	)
+	if err != nil {
+		return nil, err
+	}
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(spanExporter),
+		sdktrace.WithResource(res),
+	)
+	otel.SetTracerProvider(provider)
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+	return func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if err := provider.Shutdown(ctx); err != nil {
+			slog.Warn("unable to flush spans", "err", err)
+		}
+	}, nil
+}
+
+// Start starts a span of the api as a child of the span of ctx.
+func Start(ctx context.Context, spanName string, opts ...trace.SpanStartOption) (context.Context, trace.Span) {
+	return Tracer().Start(ctx, spanName, opts...)
+}
+
+// End records err on span and ends it.
+func End(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+// CountKey is the attribute of the number of events of a span.
+const CountKey = attribute.Key("tigerbeetle.count")